@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/kkrt-labs/go-utils/common"
+	input "github.com/kkrt-labs/zk-pig/src/prover-input"
+	inputstore "github.com/kkrt-labs/zk-pig/src/store"
+	"github.com/spf13/cobra"
+)
+
+// NewDictCommand creates and returns the dict command
+func NewDictCommand(rootCtx *RootContext) *cobra.Command {
+	var (
+		output          string
+		fromBlockNumber uint64
+		toBlockNumber   uint64
+		maxInputs       int
+		maxSize         int
+		id              uint32
+	)
+
+	cmd := &cobra.Command{
+		Use:   "dict",
+		Short: "Train a zstd dictionary on stored prover inputs.",
+		Long:  "Train a zstd dictionary on the prover inputs stored for a chain and a block range, to be used with --store-content-encoding zstd and --store-zstd-dictionary. Trie nodes and contract bytecodes repeat heavily across blocks so a dictionary noticeably improves the compression of prover inputs. The dictionary ID identifies its version in the zstd frames of compressed prover inputs: when rotating dictionaries keep the previous ones in --store-zstd-dictionaries so prover inputs stored with them can still be loaded. It runs off-line and requires --chain-id to be set.",
+		PostRunE: func(cmd *cobra.Command, _ []string) error {
+			return rootCtx.App.Stop(cmd.Context())
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			chainID := rootCtx.App.ChainID()
+			if chainID == nil {
+				return fmt.Errorf("dict requires --chain-id to be set")
+			}
+
+			catalog := rootCtx.App.ProverInputCatalog()
+			proverInputStore := rootCtx.App.ProverInputStore() // must be declared last so object is constructed on App before calling Start
+			err := rootCtx.App.Start(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			entries, err := catalog.ListProverInputs(cmd.Context(), chainID.Uint64(), fromBlockNumber, toBlockNumber)
+			if err != nil {
+				return err
+			}
+
+			// Samples are prover inputs encoded with the configured content type, as they are before compression
+			contentType := common.Val(rootCtx.App.Config().ProverInputs.ContentType)
+			var samples [][]byte
+			for _, e := range entries {
+				if len(samples) >= maxInputs {
+					break
+				}
+				if e.ContentType != contentType.String() {
+					continue
+				}
+
+				var in *input.ProverInput
+				if e.BlockHash != nil {
+					in, err = proverInputStore.LoadProverInputByHash(cmd.Context(), e.ChainID, e.BlockNumber, *e.BlockHash)
+				} else {
+					in, err = proverInputStore.LoadProverInput(cmd.Context(), e.ChainID, e.BlockNumber)
+				}
+				if err != nil {
+					return fmt.Errorf("failed to load prover input for block %d: %w", e.BlockNumber, err)
+				}
+
+				sample, err := inputstore.EncodeProverInput(in, contentType)
+				if err != nil {
+					return fmt.Errorf("failed to encode prover input for block %d: %w", e.BlockNumber, err)
+				}
+				samples = append(samples, sample)
+			}
+
+			if len(samples) == 0 {
+				return fmt.Errorf("no %s prover input found to train the dictionary on", contentType.String())
+			}
+
+			dict, err := inputstore.TrainZstdDictionary(samples, id, maxSize)
+			if err != nil {
+				return err
+			}
+
+			dictID, err := inputstore.ZstdDictionaryID(dict)
+			if err != nil {
+				return err
+			}
+
+			if err := os.WriteFile(output, dict, 0o644); err != nil {
+				return fmt.Errorf("failed to write dictionary: %w", err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Trained dictionary %d (%d bytes) on %d prover inputs to %s\n", dictID, len(dict), len(samples), output)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "zstd.dict", "Path of the dictionary file to write")
+	cmd.Flags().Uint64Var(&fromBlockNumber, "from-block-number", 0, "First block number of the prover inputs to train on")
+	cmd.Flags().Uint64Var(&toBlockNumber, "to-block-number", math.MaxUint64, "Last block number of the prover inputs to train on (defaults to all blocks)")
+	cmd.Flags().IntVar(&maxInputs, "max-inputs", 100, "Maximum number of prover inputs to train on")
+	cmd.Flags().IntVar(&maxSize, "max-size", 112640, "Maximum size of the dictionary in bytes")
+	cmd.Flags().Uint32Var(&id, "id", 0, "ID of the dictionary stored in the zstd frames of compressed artifacts to identify its version (defaults to a random ID)")
+
+	return cmd
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// NewGCCommand creates and returns the gc command
+func NewGCCommand(rootCtx *RootContext) *cobra.Command {
+	var (
+		dryRun     bool
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Delete stored artifacts according to retention rules.",
+		Long:  "Delete the prover inputs, preflight data, blocks and other artifacts of a chain which are not retained by the retention rules (--gc-keep-last, --gc-keep-newer-than, --gc-keep-modulo) and the preflight data of blocks which prover input exists (--gc-delete-preflight-data). It runs off-line and requires --chain-id to be set.",
+		PostRunE: func(cmd *cobra.Command, _ []string) error {
+			return rootCtx.App.Stop(cmd.Context())
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			chainID := rootCtx.App.ChainID()
+			if chainID == nil {
+				return fmt.Errorf("gc requires --chain-id to be set")
+			}
+
+			gc := rootCtx.App.GarbageCollector() // must be declared last so object is constructed on App before calling Start
+			err := rootCtx.App.Start(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			report, err := gc.Collect(cmd.Context(), chainID.Uint64(), dryRun)
+			if report != nil {
+				if jsonOutput {
+					if encErr := json.NewEncoder(cmd.OutOrStdout()).Encode(report); encErr != nil {
+						return encErr
+					}
+				} else {
+					verb := "Deleted"
+					if report.DryRun {
+						verb = "Would delete"
+						for _, o := range report.Objects {
+							fmt.Fprintf(cmd.OutOrStdout(), "%s\t%d\n", o.Key, o.Size)
+						}
+					}
+					fmt.Fprintf(cmd.OutOrStdout(), "%s %d objects (%d blocks, %d blobs), %d bytes reclaimed\n", verb, len(report.Objects), report.Blocks, report.Blobs, report.Bytes)
+				}
+			}
+
+			return err
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Report the objects that would be deleted and the bytes that would be reclaimed without deleting anything")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output the report as JSON")
+
+	return cmd
+}
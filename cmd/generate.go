@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"math/big"
 
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/kkrt-labs/go-utils/common"
 	"github.com/kkrt-labs/go-utils/ethereum/rpc/jsonrpc"
 	"github.com/spf13/cobra"
 )
@@ -111,27 +114,45 @@ func NewExecuteCommand(rootCtx *RootContext) *cobra.Command {
 	var (
 		ctx         = &ProverInputContext{RootContext: rootCtx}
 		blockNumber string
+		blockHash   string
 	)
 
 	cmd := &cobra.Command{
 		Use:     "execute",
 		Short:   "Execute block by basing on prover inputs previously generated during prepare.",
-		Long:    "Execute block by basing on prover inputs previously generated during prepare. It can be ran off-line in which case it needs --chain-id to be provided.",
+		Long:    "Execute block by basing on prover inputs previously generated during prepare. It can be ran off-line in which case it needs --chain-id to be provided. With --block-hash, it executes the prover input of the block with the given hash (e.g. a reorged block kept by the hash store layout).",
 		PreRunE: preRun(ctx, &blockNumber),
 		PostRunE: func(cmd *cobra.Command, _ []string) error {
 			return ctx.App.Stop(cmd.Context())
 		},
 		RunE: func(cmd *cobra.Command, _ []string) error {
+			var hash *gethcommon.Hash
+			if blockHash != "" {
+				b, err := hexutil.Decode(blockHash)
+				if err != nil || len(b) != gethcommon.HashLength {
+					return fmt.Errorf("invalid block hash: %q", blockHash)
+				}
+				if ctx.blockNumber.Sign() < 0 {
+					return fmt.Errorf("--block-hash requires --block-number to be set to a block number")
+				}
+				hash = common.Ptr(gethcommon.BytesToHash(b))
+			}
+
 			generator := ctx.App.Generator() // must be declared first so object is constructed on App before calling Start
 			err := ctx.App.Start(cmd.Context())
 			if err != nil {
 				return err
 			}
+
+			if hash != nil {
+				return generator.ExecuteByHash(cmd.Context(), ctx.blockNumber, *hash)
+			}
 			return generator.Execute(cmd.Context(), ctx.blockNumber)
 		},
 	}
 
 	cmd.Flags().StringVarP(&blockNumber, "block-number", "b", "latest", "Block number")
+	cmd.Flags().StringVar(&blockHash, "block-hash", "", "Block hash (loads the prover input by hash, requires --block-number)")
 
 	return cmd
 }
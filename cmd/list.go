@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// NewListCommand creates and returns the list command
+func NewListCommand(rootCtx *RootContext) *cobra.Command {
+	var (
+		fromBlockNumber uint64
+		toBlockNumber   uint64
+		jsonOutput      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the prover inputs available in the store.",
+		Long:  "List the prover inputs available in the store for a chain and a block range, with their content type, encoding, size, block hash, zk-pig version and included extensions. It can be ran off-line in which case it needs --chain-id to be provided.",
+		PostRunE: func(cmd *cobra.Command, _ []string) error {
+			return rootCtx.App.Stop(cmd.Context())
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			catalog := rootCtx.App.ProverInputCatalog()
+			generator := rootCtx.App.Generator() // resolves the chain ID (from --chain-id or the chain RPC) on Start
+			err := rootCtx.App.Start(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			entries, err := catalog.ListProverInputs(cmd.Context(), generator.ChainID.Uint64(), fromBlockNumber, toBlockNumber)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(entries)
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "BLOCK\tHASH\tCONTENT TYPE\tENCODING\tSIZE\tVERSION\tINCLUDE")
+			for _, e := range entries {
+				hash := "-"
+				if e.BlockHash != nil {
+					hash = e.BlockHash.Hex()
+				}
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%s\t%s\n", e.BlockNumber, hash, e.ContentType, e.ContentEncoding, e.Size, orDash(e.Version), orDash(e.Include))
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().Uint64Var(&fromBlockNumber, "from-block-number", 0, "First block number of the range to list")
+	cmd.Flags().Uint64Var(&toBlockNumber, "to-block-number", math.MaxUint64, "Last block number of the range to list (defaults to all blocks)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output entries as JSON")
+
+	return cmd
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
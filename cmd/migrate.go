@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/kkrt-labs/go-utils/ethereum/rpc/jsonrpc"
+	"github.com/spf13/cobra"
+)
+
+// NewMigrateCommand creates and returns the migrate command
+func NewMigrateCommand(rootCtx *RootContext) *cobra.Command {
+	var (
+		ctx           = &ProverInputContext{RootContext: rootCtx}
+		blockNumber   string
+		toBlockNumber string
+	)
+
+	cmd := &cobra.Command{
+		Use:     "migrate",
+		Short:   "Upgrade stored prover inputs to the current prover input version.",
+		Long:    "Upgrade stored prover inputs to the current prover input version, in place. Prover inputs already at the current version are skipped. It can be ran off-line in which case it needs --chain-id to be provided. It requires an explicit --block-number and --to-block-number to migrate a range of blocks.",
+		PreRunE: preRun(ctx, &blockNumber),
+		PostRunE: func(cmd *cobra.Command, _ []string) error {
+			return ctx.App.Stop(cmd.Context())
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			generator := ctx.App.Generator() // must be declared first so object is constructed on App before calling Start
+			err := ctx.App.Start(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			to := ctx.blockNumber
+			if toBlockNumber != "" {
+				to, err = jsonrpc.FromBlockNumArg(toBlockNumber)
+				if err != nil {
+					return fmt.Errorf("invalid to block number: %v", err)
+				}
+			}
+
+			// Stored prover inputs are resolved by number, block tags (e.g. "latest") would require a chain RPC
+			if ctx.blockNumber.Sign() < 0 || to.Sign() < 0 {
+				return fmt.Errorf("--block-number and --to-block-number must be explicit block numbers")
+			}
+			if to.Cmp(ctx.blockNumber) < 0 {
+				return fmt.Errorf("--to-block-number %v is lower than --block-number %v", to, ctx.blockNumber)
+			}
+
+			for n := new(big.Int).Set(ctx.blockNumber); n.Cmp(to) <= 0; n.Add(n, big.NewInt(1)) {
+				if err := generator.Migrate(cmd.Context(), n); err != nil {
+					return fmt.Errorf("failed to migrate prover input for block %v: %w", n, err)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&blockNumber, "block-number", "b", "", "Block number")
+	cmd.Flags().StringVar(&toBlockNumber, "to-block-number", "", "Last block number of the range to migrate (defaults to --block-number)")
+	_ = cmd.MarkFlagRequired("block-number")
+
+	return cmd
+}
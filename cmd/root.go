@@ -57,6 +57,11 @@ func NewZkPigCommand() *cobra.Command {
 	rootCmd.AddCommand(NewPreflightCommand(ctx))
 	rootCmd.AddCommand(NewPrepareCommand(ctx))
 	rootCmd.AddCommand(NewExecuteCommand(ctx))
+	rootCmd.AddCommand(NewMigrateCommand(ctx))
+	rootCmd.AddCommand(NewListCommand(ctx))
+	rootCmd.AddCommand(NewGCCommand(ctx))
+	rootCmd.AddCommand(NewDictCommand(ctx))
+	rootCmd.AddCommand(NewValidateCommand(ctx))
 	rootCmd.AddCommand(NewRunCommand(ctx))
 	rootCmd.AddCommand(NewConfigCommand(ctx))
 
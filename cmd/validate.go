@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	input "github.com/kkrt-labs/zk-pig/src/prover-input"
+	"github.com/kkrt-labs/zk-pig/src/prover-input/schema"
+	"github.com/spf13/cobra"
+)
+
+// NewValidateCommand creates and returns the validate command
+func NewValidateCommand(_ *RootContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate FILE...",
+		Short: "Validate JSON prover input files.",
+		Long:  "Validate JSON prover input files against the published prover input JSON Schema (src/prover-input/schema/prover-input.schema.json) and check their structural invariants (parent headers in witness ancestors, unique witness nodes and codes, pre-state codes consistent with code hashes).",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var invalid int
+			for _, path := range args {
+				if err := validateProverInputFile(path); err != nil {
+					invalid++
+					fmt.Fprintf(cmd.OutOrStdout(), "%v: invalid\n%v\n", path, err)
+					continue
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%v: valid\n", path)
+			}
+
+			if invalid > 0 {
+				return fmt.Errorf("%d/%d prover input file(s) are invalid", invalid, len(args))
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func validateProverInputFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if err := schema.Validate(bytes.NewReader(data)); err != nil {
+		return err
+	}
+
+	var in input.ProverInput
+	if err := json.Unmarshal(data, &in); err != nil {
+		return fmt.Errorf("failed to decode prover input: %w", err)
+	}
+
+	return in.Validate()
+}
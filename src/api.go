@@ -0,0 +1,38 @@
+package src
+
+import (
+	"github.com/kkrt-labs/go-utils/app"
+	kkrthttp "github.com/kkrt-labs/go-utils/net/http"
+	"github.com/kkrt-labs/zk-pig/src/api"
+	"github.com/kkrt-labs/zk-pig/src/telemetry"
+)
+
+var (
+	apiComponentName = "api"
+)
+
+// API returns the HTTP API entrypoint
+// It listens on the main entrypoint address (see --main-ep-addr)
+func (a *App) API() *kkrthttp.Entrypoint {
+	return provide(
+		a,
+		apiComponentName,
+		func() (*kkrthttp.Entrypoint, error) {
+			cfg := kkrthttp.DefaultEntrypointConfig()
+			if a.Config().App != nil && a.Config().App.MainEntrypoint != nil {
+				cfg = a.Config().App.MainEntrypoint
+			}
+
+			ep, err := cfg.Entrypoint()
+			if err != nil {
+				return nil, err
+			}
+
+			a.TracerProvider()
+			ep.SetHandler(telemetry.HTTPMiddleware(api.NewHandler(a.ProverInputCatalog(), a.ProverInputStore())))
+
+			return ep, nil
+		},
+		app.WithComponentName(apiComponentName),
+	)
+}
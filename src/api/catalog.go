@@ -0,0 +1,140 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/kkrt-labs/go-utils/log"
+	store "github.com/kkrt-labs/go-utils/store"
+	input "github.com/kkrt-labs/zk-pig/src/prover-input"
+	inputstore "github.com/kkrt-labs/zk-pig/src/store"
+	"go.uber.org/zap"
+)
+
+// ListProverInputsResponse is the response of the prover inputs listing endpoint
+type ListProverInputsResponse struct {
+	ProverInputs []*inputstore.ProverInputEntry `json:"proverInputs"`
+}
+
+// ErrorResponse is the response of an endpoint on failure
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// NewHandler creates the HTTP handler of the zk-pig API
+//
+// It serves
+// - GET /v1/chains/{chainId}/prover-inputs?from=<block>&to=<block> listing the prover inputs available in the store
+// (from defaults to 0, to defaults to the latest block)
+// - GET /v1/chains/{chainId}/prover-inputs/{blockNumber}?hash=<block hash> returning the JSON prover input of a block
+// (loaded by hash if hash is set, so prover inputs of reorged blocks kept by the hash store layout can be fetched)
+func NewHandler(catalog inputstore.ProverInputCatalog, proverInputs inputstore.ProverInputStore) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/chains/{chainId}/prover-inputs", func(w http.ResponseWriter, r *http.Request) {
+		listProverInputs(w, r, catalog)
+	})
+	mux.HandleFunc("GET /v1/chains/{chainId}/prover-inputs/{blockNumber}", func(w http.ResponseWriter, r *http.Request) {
+		getProverInput(w, r, proverInputs)
+	})
+	return mux
+}
+
+func listProverInputs(w http.ResponseWriter, r *http.Request, catalog inputstore.ProverInputCatalog) {
+	chainID, ok := parseChainID(w, r)
+	if !ok {
+		return
+	}
+
+	from, err := parseBlockNumber(r, "from", 0)
+	if err != nil {
+		writeJSON(w, r, http.StatusBadRequest, &ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	to, err := parseBlockNumber(r, "to", math.MaxUint64)
+	if err != nil {
+		writeJSON(w, r, http.StatusBadRequest, &ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	entries, err := catalog.ListProverInputs(r.Context(), chainID, from, to)
+	if err != nil {
+		log.LoggerFromContext(r.Context()).Error("Failed to list prover inputs", zap.Error(err))
+		writeJSON(w, r, http.StatusInternalServerError, &ErrorResponse{Error: "failed to list prover inputs"})
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, &ListProverInputsResponse{ProverInputs: entries})
+}
+
+func getProverInput(w http.ResponseWriter, r *http.Request, proverInputs inputstore.ProverInputStore) {
+	chainID, ok := parseChainID(w, r)
+	if !ok {
+		return
+	}
+
+	blockNumber, err := strconv.ParseUint(r.PathValue("blockNumber"), 10, 64)
+	if err != nil {
+		writeJSON(w, r, http.StatusBadRequest, &ErrorResponse{Error: fmt.Sprintf("invalid block number: %v", r.PathValue("blockNumber"))})
+		return
+	}
+
+	var in *input.ProverInput
+	if hash := r.URL.Query().Get("hash"); hash != "" {
+		b, decodeErr := hexutil.Decode(hash)
+		if decodeErr != nil || len(b) != gethcommon.HashLength {
+			writeJSON(w, r, http.StatusBadRequest, &ErrorResponse{Error: fmt.Sprintf("invalid block hash: %v", hash)})
+			return
+		}
+		in, err = proverInputs.LoadProverInputByHash(r.Context(), chainID, blockNumber, gethcommon.BytesToHash(b))
+	} else {
+		in, err = proverInputs.LoadProverInput(r.Context(), chainID, blockNumber)
+	}
+	if errors.Is(err, store.ErrNotFound) {
+		writeJSON(w, r, http.StatusNotFound, &ErrorResponse{Error: "prover input not found"})
+		return
+	}
+	if err != nil {
+		log.LoggerFromContext(r.Context()).Error("Failed to load prover input", zap.Error(err))
+		writeJSON(w, r, http.StatusInternalServerError, &ErrorResponse{Error: "failed to load prover input"})
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, in)
+}
+
+func parseChainID(w http.ResponseWriter, r *http.Request) (uint64, bool) {
+	chainID, err := strconv.ParseUint(r.PathValue("chainId"), 10, 64)
+	if err != nil {
+		writeJSON(w, r, http.StatusBadRequest, &ErrorResponse{Error: fmt.Sprintf("invalid chain ID: %v", r.PathValue("chainId"))})
+		return 0, false
+	}
+	return chainID, true
+}
+
+func parseBlockNumber(r *http.Request, param string, defaultValue uint64) (uint64, error) {
+	v := r.URL.Query().Get(param)
+	if v == "" {
+		return defaultValue, nil
+	}
+
+	n, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %q block number: %v", param, v)
+	}
+	return n, nil
+}
+
+func writeJSON(w http.ResponseWriter, r *http.Request, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.LoggerFromContext(r.Context()).Error("Failed to write response", zap.Error(err))
+	}
+}
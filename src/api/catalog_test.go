@@ -0,0 +1,118 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	store "github.com/kkrt-labs/go-utils/store"
+	input "github.com/kkrt-labs/zk-pig/src/prover-input"
+	inputstore "github.com/kkrt-labs/zk-pig/src/store"
+	mockstore "github.com/kkrt-labs/zk-pig/src/store/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestListProverInputs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	catalog := mockstore.NewMockProverInputCatalog(ctrl)
+	h := NewHandler(catalog, mockstore.NewMockProverInputStore(ctrl))
+
+	t.Run("Range", func(t *testing.T) {
+		entries := []*inputstore.ProverInputEntry{{ChainID: 1, BlockNumber: 10, ContentType: "application/json", ContentEncoding: "plain", Size: 100}}
+		catalog.EXPECT().ListProverInputs(gomock.Any(), uint64(1), uint64(10), uint64(20)).Return(entries, nil)
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/chains/1/prover-inputs?from=10&to=20", http.NoBody))
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+		var res ListProverInputsResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		assert.Equal(t, entries, res.ProverInputs)
+	})
+
+	t.Run("DefaultRange", func(t *testing.T) {
+		catalog.EXPECT().ListProverInputs(gomock.Any(), uint64(1), uint64(0), uint64(math.MaxUint64)).Return([]*inputstore.ProverInputEntry{}, nil)
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/chains/1/prover-inputs", http.NoBody))
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"proverInputs":[]}`, rec.Body.String())
+	})
+
+	t.Run("InvalidParams", func(t *testing.T) {
+		for _, path := range []string{"/v1/chains/mainnet/prover-inputs", "/v1/chains/1/prover-inputs?from=latest", "/v1/chains/1/prover-inputs?to=-1"} {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, http.NoBody))
+			assert.Equal(t, http.StatusBadRequest, rec.Code, path)
+		}
+	})
+
+	t.Run("CatalogError", func(t *testing.T) {
+		catalog.EXPECT().ListProverInputs(gomock.Any(), uint64(1), uint64(0), uint64(math.MaxUint64)).Return(nil, fmt.Errorf("test error"))
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/chains/1/prover-inputs", http.NoBody))
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
+
+func TestGetProverInput(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	proverInputs := mockstore.NewMockProverInputStore(ctrl)
+	h := NewHandler(mockstore.NewMockProverInputCatalog(ctrl), proverInputs)
+
+	in := &input.ProverInput{
+		Version: input.CurrentVersion,
+		Blocks:  []*input.Block{{Header: &gethtypes.Header{Number: big.NewInt(10), Difficulty: big.NewInt(0)}}},
+	}
+	blockHash := in.Blocks[0].Header.Hash()
+
+	t.Run("ByNumber", func(t *testing.T) {
+		proverInputs.EXPECT().LoadProverInput(gomock.Any(), uint64(1), uint64(10)).Return(in, nil)
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/chains/1/prover-inputs/10", http.NoBody))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var res input.ProverInput
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		assert.Equal(t, blockHash, res.Blocks[0].Header.Hash())
+	})
+
+	t.Run("ByHash", func(t *testing.T) {
+		proverInputs.EXPECT().LoadProverInputByHash(gomock.Any(), uint64(1), uint64(10), blockHash).Return(in, nil)
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/chains/1/prover-inputs/10?hash="+blockHash.Hex(), http.NoBody))
+		require.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		proverInputs.EXPECT().LoadProverInputByHash(gomock.Any(), uint64(1), uint64(10), gethcommon.Hash{0x1}).Return(nil, fmt.Errorf("failed to load: %w", store.ErrNotFound))
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/chains/1/prover-inputs/10?hash="+gethcommon.Hash{0x1}.Hex(), http.NoBody))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("InvalidParams", func(t *testing.T) {
+		for _, path := range []string{"/v1/chains/mainnet/prover-inputs/10", "/v1/chains/1/prover-inputs/latest", "/v1/chains/1/prover-inputs/10?hash=0x12"} {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, http.NoBody))
+			assert.Equal(t, http.StatusBadRequest, rec.Code, path)
+		}
+	})
+}
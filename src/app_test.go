@@ -1,8 +1,21 @@
 package src
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/kkrt-labs/go-utils/common"
+	store "github.com/kkrt-labs/go-utils/store"
+	"github.com/kkrt-labs/zk-pig/src/ethereum"
+	"github.com/kkrt-labs/zk-pig/src/ethereum/op"
+	inputstore "github.com/kkrt-labs/zk-pig/src/store"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -27,3 +40,156 @@ func TestLoad(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotNil(t, app)
 }
+
+func TestAppNetworks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "devnet.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"config": {"chainId": 7331, "londonBlock": 0}, "gasLimit": "0x1c9c380", "difficulty": "0x0", "alloc": {}}`), 0o600))
+
+	cfg := DefaultConfig()
+	cfg.Genesis = common.PtrSlice(path)
+	app, err := NewApp(cfg)
+	require.NoError(t, err)
+
+	networks := app.Networks()
+	require.NoError(t, app.Error())
+	require.Len(t, networks, 1)
+	assert.Equal(t, "devnet", networks[0].Name)
+
+	chainCfg, err := ethereum.GetChainConfig(big.NewInt(7331))
+	require.NoError(t, err)
+	assert.Equal(t, networks[0].Config, chainCfg)
+}
+
+func TestAppNetworksOpStack(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "op-devnet.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"config": {"chainId": 7334, "londonBlock": 0, "regolithTime": 0, "canyonTime": 0, "optimism": {"eip1559Elasticity": 6, "eip1559Denominator": 50}}, "gasLimit": "0x1c9c380", "difficulty": "0x0", "alloc": {}}`), 0o600))
+
+	cfg := DefaultConfig()
+	cfg.Genesis = common.PtrSlice(path)
+	app, err := NewApp(cfg)
+	require.NoError(t, err)
+
+	app.Networks()
+	require.NoError(t, app.Error())
+
+	opCfg := op.GetConfig(big.NewInt(7334))
+	require.NotNil(t, opCfg)
+	assert.True(t, opCfg.IsCanyon(0))
+}
+
+func TestAppNetworksChainIDMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "devnet.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"config": {"chainId": 7332, "londonBlock": 0}, "gasLimit": "0x1c9c380", "difficulty": "0x0", "alloc": {}}`), 0o600))
+
+	cfg := DefaultConfig()
+	cfg.Genesis = common.PtrSlice(path)
+	cfg.Chain.ID = common.Ptr("7333")
+	app, err := NewApp(cfg)
+	require.NoError(t, err)
+
+	app.Networks()
+	assert.ErrorContains(t, app.Error(), "genesis chain ID 7332 does not match configured chain ID 7333")
+}
+
+func TestAppStoreRoutes(t *testing.T) {
+	dir := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.Store.File.Dir = common.Ptr(dir)
+	cfg.Store.Routes.ProverInputs.Backends = common.PtrSlice("none")
+	cfg.Store.Routes.PreflightData.Backends = common.PtrSlice("file")
+	cfg.Store.Routes.Blocks.ContentEncoding = common.Ptr(inputstore.ContentEncodingGzip)
+	app, err := NewApp(cfg)
+	require.NoError(t, err)
+
+	proverInputsStore, preflightDataStore, blocksStore := app.proverInputsStore(), app.preflightDataStore(), app.blocksStore()
+	require.NoError(t, app.Error())
+
+	headers := &store.Headers{ContentType: store.ContentTypeJSON}
+	require.NoError(t, proverInputsStore.Store(context.TODO(), "/1/10/zkpi.json", bytes.NewReader([]byte("{}")), headers))
+	require.NoError(t, preflightDataStore.Store(context.TODO(), "/1/10/preflight.json", bytes.NewReader([]byte("{}")), headers))
+	require.NoError(t, blocksStore.Store(context.TODO(), "/1/blocks/10.json", bytes.NewReader([]byte("{}")), headers))
+
+	assert.NoFileExists(t, filepath.Join(dir, "1", "10", "zkpi.json"))
+	assert.FileExists(t, filepath.Join(dir, "1", "10", "preflight.json"))
+	assert.FileExists(t, filepath.Join(dir, "1", "blocks", "10.json.gz"))
+
+	_, _, err = proverInputsStore.Load(context.TODO(), "/1/10/zkpi.json")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+}
+
+func TestAppStoreFileAndKV(t *testing.T) {
+	dir, dbPath := t.TempDir(), filepath.Join(t.TempDir(), "zkpig.db")
+	cfg := DefaultConfig()
+	cfg.Store.File.Dir = common.Ptr(dir)
+	cfg.Store.KV.Enabled = common.Ptr(true)
+	cfg.Store.KV.Path = common.Ptr(dbPath)
+	cfg.Store.ContentEncoding = common.Ptr(inputstore.ContentEncodingGzip)
+	app, err := NewApp(cfg)
+	require.NoError(t, err)
+
+	s := app.Store()
+	require.NoError(t, app.Error())
+	require.NoError(t, app.Start(context.TODO()))
+
+	data := bytes.Repeat([]byte(`{"state":["0x1234"]}`), 1000)
+	require.NoError(t, s.Store(context.TODO(), "/1/10/zkpi.json", bytes.NewReader(data), &store.Headers{ContentType: store.ContentTypeJSON}))
+	require.NoError(t, app.Stop(context.TODO()))
+
+	// Every enabled backend holds the whole object
+	fromFile, err := os.ReadFile(filepath.Join(dir, "1", "10", "zkpi.json.gz"))
+	require.NoError(t, err)
+
+	kv := inputstore.NewKVStore(dbPath)
+	require.NoError(t, kv.Start(context.TODO()))
+	defer func() { require.NoError(t, kv.Stop(context.TODO())) }()
+	reader, _, err := kv.Load(context.TODO(), "/1/10/zkpi.json.gz")
+	require.NoError(t, err)
+	defer reader.Close()
+	fromKV, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, fromFile)
+	assert.Equal(t, fromFile, fromKV)
+}
+
+func TestAppStoreRoutesDisabledBackend(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Store.Routes.ProverInputs.Backends = common.PtrSlice("s3")
+	app, err := NewApp(cfg)
+	require.NoError(t, err)
+
+	app.proverInputsStore()
+	assert.Error(t, app.Error())
+}
+
+func TestAppStoreZstd(t *testing.T) {
+	dir := t.TempDir()
+	var samples [][]byte
+	for i := 0; i < 16; i++ {
+		samples = append(samples, []byte(fmt.Sprintf(`{"blockNumber":%d,"state":["%s"]}`, i, strings.Repeat("6080604052348015", 32))))
+	}
+	dict, err := inputstore.TrainZstdDictionary(samples, 1, 1024)
+	require.NoError(t, err)
+	dictPath := filepath.Join(dir, "zstd.dict")
+	require.NoError(t, os.WriteFile(dictPath, dict, 0o600))
+
+	cfg := DefaultConfig()
+	cfg.Store.File.Dir = common.Ptr(dir)
+	cfg.Store.ContentEncoding = common.Ptr(inputstore.ContentEncodingZstd)
+	cfg.Store.Zstd.Dictionary = common.Ptr(dictPath)
+	app, err := NewApp(cfg)
+	require.NoError(t, err)
+
+	s := app.Store()
+	require.NoError(t, app.Error())
+
+	require.NoError(t, s.Store(context.TODO(), "/1/10/zkpi.json", bytes.NewReader([]byte(`{"state":["0x1234"]}`)), &store.Headers{ContentType: store.ContentTypeJSON}))
+	assert.FileExists(t, filepath.Join(dir, "1", "10", "zkpi.json.zst"))
+
+	reader, _, err := s.Load(context.TODO(), "/1/10/zkpi.json")
+	require.NoError(t, err)
+	defer reader.Close()
+	b, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, `{"state":["0x1234"]}`, string(b))
+}
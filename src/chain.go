@@ -1,16 +1,24 @@
 package src
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/kkrt-labs/go-utils/app"
 	ethrpc "github.com/kkrt-labs/go-utils/ethereum/rpc"
-	ethjsonrpc "github.com/kkrt-labs/go-utils/ethereum/rpc/jsonrpc"
 	jsonrpc "github.com/kkrt-labs/go-utils/jsonrpc"
 	jsonrpcmrgd "github.com/kkrt-labs/go-utils/jsonrpc/merged"
+	"github.com/kkrt-labs/zk-pig/src/ethereum"
+	"github.com/kkrt-labs/zk-pig/src/ethereum/op"
+	"github.com/kkrt-labs/zk-pig/src/telemetry"
 )
 
 var (
@@ -18,6 +26,9 @@ var (
 	chainRPCComponentName = fmt.Sprintf("%s.rpc", chainComponentName)
 )
 
+// genesisStorePrefix is the prefix of a genesis path that must be loaded from the configured store
+const genesisStorePrefix = "store://"
+
 func (a *App) ChainID() *big.Int {
 	if a.Config().Chain.ID != nil {
 		return a.chainID()
@@ -39,6 +50,93 @@ func (a *App) chainID() *big.Int {
 		})
 }
 
+// Networks returns the custom networks configured by the user from genesis files
+//
+// On construction, the networks are registered in the default chain registry so they are supported the same way as built-in networks.
+func (a *App) Networks() []*ethereum.Network {
+	return provide(
+		a,
+		fmt.Sprintf("%s.networks", chainComponentName),
+		func() ([]*ethereum.Network, error) {
+			var networks []*ethereum.Network
+			if a.Config().Genesis == nil {
+				return networks, nil
+			}
+
+			var genesisChainIDs []string
+			matchesChainID := false
+			chainID := a.ChainID()
+			for _, path := range *a.Config().Genesis {
+				if path == nil || *path == "" {
+					continue
+				}
+
+				n, err := a.loadNetwork(*path)
+				if err != nil {
+					return nil, fmt.Errorf("failed to load genesis %q: %w", *path, err)
+				}
+				genesisChainIDs = append(genesisChainIDs, n.Config.ChainID.String())
+				matchesChainID = matchesChainID || (chainID != nil && chainID.Cmp(n.Config.ChainID) == 0)
+
+				if err := ethereum.Register(n); err != nil {
+					return nil, err
+				}
+
+				networks = append(networks, n)
+			}
+
+			// A genesis provided along with a chain ID must be for that chain (e.g. to catch a genesis of the wrong devnet)
+			if chainID != nil && len(networks) > 0 && !matchesChainID {
+				return nil, fmt.Errorf("genesis chain ID %v does not match configured chain ID %v", strings.Join(genesisChainIDs, ", "), chainID)
+			}
+
+			return networks, nil
+		},
+		app.WithComponentName(chainComponentName),
+	)
+}
+
+func (a *App) loadNetwork(path string) (*ethereum.Network, error) {
+	r, err := a.openGenesis(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	genesis, err := ethereum.ReadGenesis(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+
+	// Genesis of OP Stack chains are in the op-geth format, which carries the OP Stack fork times
+	opCfg, err := op.ReadGenesisConfig(b)
+	if err != nil {
+		return nil, err
+	}
+	if opCfg != nil {
+		op.Register(genesis.Config.ChainID, opCfg)
+	}
+
+	// Network is named after the genesis file (e.g. "devnet.json" -> "devnet")
+	name := strings.TrimSuffix(filepath.Base(strings.TrimPrefix(path, genesisStorePrefix)), filepath.Ext(path))
+
+	return ethereum.NetworkFromGenesis(name, genesis), nil
+}
+
+func (a *App) openGenesis(path string) (io.ReadCloser, error) {
+	if key, ok := strings.CutPrefix(path, genesisStorePrefix); ok {
+		r, _, err := a.Store().Load(context.TODO(), key)
+		return r, err
+	}
+
+	return os.Open(path)
+}
+
 func (a *App) Chain() ethrpc.Client {
 	gCfg := a.Config()
 	if gCfg.Chain != nil && gCfg.Chain.RPC != nil && gCfg.Chain.RPC.URL != nil {
@@ -118,12 +216,26 @@ func (a *App) chainRPCTagged() jsonrpc.Client {
 	)
 }
 
+func (a *App) chainRPCTracing() jsonrpc.Client {
+	return provide(
+		a,
+		fmt.Sprintf("%s.tracing", chainRPCComponentName),
+		func() (jsonrpc.Client, error) {
+			a.TracerProvider()
+
+			remote := a.chainRPCTagged()
+			return telemetry.JSONRPCWithTracing(remote), nil
+		},
+		app.WithComponentName(chainRPCComponentName),
+	)
+}
+
 func (a *App) chainRPC() jsonrpc.Client {
 	return provide(
 		a,
 		chainRPCComponentName,
 		func() (jsonrpc.Client, error) {
-			remote := a.chainRPCTagged()
+			remote := a.chainRPCTracing()
 			remote = jsonrpc.WithVersion("2.0")(remote)
 			remote = jsonrpc.WithIncrementalID()(remote)
 
@@ -133,13 +245,24 @@ func (a *App) chainRPC() jsonrpc.Client {
 	)
 }
 
+// chainOp returns the chain client supporting OP Stack blocks (it behaves as the standard client on other chains)
+func (a *App) chainOp() *op.Client {
+	return provide(
+		a,
+		fmt.Sprintf("%s.op", chainComponentName),
+		func() (*op.Client, error) {
+			return op.NewClient(a.chainRPC()), nil
+		},
+		app.WithComponentName(chainComponentName),
+	)
+}
+
 func (a *App) chainBase() ethrpc.Client {
 	return provide(
 		a,
 		fmt.Sprintf("%s.base", chainComponentName),
 		func() (ethrpc.Client, error) {
-			remote := a.chainRPC()
-			return ethjsonrpc.NewFromClient(remote), nil
+			return a.chainOp(), nil
 		},
 		app.WithComponentName(chainComponentName),
 	)
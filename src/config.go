@@ -4,12 +4,13 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/kkrt-labs/go-utils/app"
 	"github.com/kkrt-labs/go-utils/common"
 	"github.com/kkrt-labs/go-utils/config"
-	store "github.com/kkrt-labs/go-utils/store"
 	"github.com/kkrt-labs/zk-pig/src/steps"
+	inputstore "github.com/kkrt-labs/zk-pig/src/store"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
@@ -21,12 +22,12 @@ func init() {
 				return data, nil
 			}
 
-			if t == reflect.TypeOf(store.ContentType(0)) {
-				return store.ParseContentType(data.(string))
+			if t == reflect.TypeOf(inputstore.ContentType(0)) {
+				return inputstore.ParseContentType(data.(string))
 			}
 
-			if t == reflect.TypeOf(store.ContentEncoding(0)) {
-				return store.ParseContentEncoding(data.(string))
+			if t == reflect.TypeOf(inputstore.ContentEncoding(0)) {
+				return inputstore.ParseContentEncoding(data.(string))
 			}
 
 			if t == reflect.TypeOf(steps.Include(0)) {
@@ -53,19 +54,49 @@ func DefaultConfig() *Config {
 					Credentials: &CredentialsConfig{},
 				},
 			},
-			ContentEncoding: common.Ptr(store.ContentEncodingPlain),
+			KV: &KVStoreConfig{
+				Enabled: common.Ptr(false),
+				Path:    common.Ptr("zkpig.db"),
+			},
+			ContentEncoding: common.Ptr(inputstore.ContentEncodingPlain),
+			Zstd: &ZstdConfig{
+				Level: common.Ptr(3),
+			},
+			Layout: common.Ptr("number"),
+			Keys:   &StoreKeysConfig{},
+			Routes: &StoreRoutesConfig{
+				ProverInputs:  &StoreRouteConfig{},
+				PreflightData: &StoreRouteConfig{},
+				Blocks:        &StoreRouteConfig{},
+			},
 		},
 		Chain: &ChainConfig{
 			RPC: &ChainRPCConfig{},
 		},
 		ProverInputs: &ProverInputsConfig{
-			ContentType: common.Ptr(store.ContentTypeJSON),
+			ContentType: common.Ptr(inputstore.ContentTypeJSON),
+			Manifest: &ManifestConfig{
+				Enabled: common.Ptr(true),
+			},
+			Dedup: common.Ptr(false),
 		},
 		Generator: &GeneratorConfig{
 			StorePreflightData: common.Ptr(false),
 			FilterModulo:       common.Ptr(uint64(5)),
 			IncludeExtensions:  common.Ptr(steps.IncludeAll),
 		},
+		Tracing: &TracingConfig{
+			Insecure:    common.Ptr(false),
+			ServiceName: common.Ptr("zkpig"),
+		},
+		GC: &GCConfig{
+			Interval:            common.Ptr(time.Duration(0)),
+			KeepLast:            common.Ptr(uint64(0)),
+			KeepNewerThan:       common.Ptr(time.Duration(0)),
+			KeepModulo:          common.Ptr(uint64(0)),
+			DeletePreflightData: common.Ptr(false),
+			KeepBlobsNewerThan:  common.Ptr(24 * time.Hour),
+		},
 	}
 }
 
@@ -73,9 +104,12 @@ type Config struct {
 	App          *app.Config         `key:"app" env:"-" flag:"-"`
 	Config       *[]*string          `key:"config" short:"c"`
 	Chain        *ChainConfig        `key:"chain"`
+	Genesis      *[]*string          `key:"genesis,omitempty" env:"GENESIS" flag:"genesis" desc:"Paths to genesis JSON files (chain config and alloc) registering custom chains (prefix with \"store://\" to load from the store)"`
 	Store        *StoreConfig        `key:"store"`
 	ProverInputs *ProverInputsConfig `key:"inputs" env:"INPUTS" flag:"inputs"`
 	Generator    *GeneratorConfig    `key:"generator" env:"-" flag:"-"`
+	Tracing      *TracingConfig      `key:"tracing"`
+	GC           *GCConfig           `key:"gc"`
 }
 
 func (cfg *Config) Load(v *viper.Viper) error {
@@ -122,9 +156,39 @@ type ChainRPCConfig struct {
 }
 
 type StoreConfig struct {
-	File            *FileStoreConfig       `key:"file,omitempty"`
-	S3              *S3StoreConfig         `key:"s3,omitempty" env:"AWS_S3" flag:"aws-s3"`
-	ContentEncoding *store.ContentEncoding `key:"content-encoding" env:"CONTENT_ENCODING" flag:"content-encoding" desc:"Content encoding (e.g. gzip)"`
+	File            *FileStoreConfig            `key:"file,omitempty"`
+	S3              *S3StoreConfig              `key:"s3,omitempty" env:"AWS_S3" flag:"aws-s3"`
+	KV              *KVStoreConfig              `key:"kv,omitempty"`
+	ContentEncoding *inputstore.ContentEncoding `key:"content-encoding" env:"CONTENT_ENCODING" flag:"content-encoding" desc:"Content encoding (e.g. \"gzip\" \"zstd\")"`
+	Zstd            *ZstdConfig                 `key:"zstd" env:"ZSTD" flag:"zstd"`
+	Layout          *string                     `key:"layout" env:"LAYOUT" flag:"layout" desc:"Layout of the paths of prover inputs and preflight data (\"number\" stores at /<chainID>/<number>/ and \"hash\" stores at /<chainID>/<number>/<hash>/ with a latest pointer)"`
+	Keys            *StoreKeysConfig            `key:"keys" env:"KEYS" flag:"keys"`
+	Routes          *StoreRoutesConfig          `key:"routes" env:"ROUTES" flag:"routes"`
+}
+
+// StoreRoutesConfig routes each type of artifact to its own stores and content encoding
+type StoreRoutesConfig struct {
+	ProverInputs  *StoreRouteConfig `key:"prover-inputs" env:"PROVER_INPUTS" flag:"prover-inputs"`
+	PreflightData *StoreRouteConfig `key:"preflight-data" env:"PREFLIGHT_DATA" flag:"preflight-data"`
+	Blocks        *StoreRouteConfig `key:"blocks" env:"BLOCKS" flag:"blocks"`
+}
+
+type StoreRouteConfig struct {
+	Backends        *[]*string                  `key:"backends,omitempty" env:"BACKENDS" flag:"backends" desc:"Stores receiving the artifacts among \"file\" \"s3\" and \"kv\" or \"none\" to not store them (defaults to all enabled stores)"`
+	ContentEncoding *inputstore.ContentEncoding `key:"content-encoding,omitempty" env:"CONTENT_ENCODING" flag:"content-encoding" desc:"Content encoding of the artifacts (defaults to the store content encoding)"`
+}
+
+// ZstdConfig configures the zstd content encoding
+type ZstdConfig struct {
+	Level        *int       `key:"level" env:"LEVEL" flag:"level" desc:"Zstd compression level from 1 (fastest) to 22 (best compression)"`
+	Dictionary   *string    `key:"dictionary,omitempty" env:"DICTIONARY" flag:"dictionary" desc:"Path to a zstd dictionary trained on prior prover inputs (see \"zkpig dict\") compressing stored artifacts"`
+	Dictionaries *[]*string `key:"dictionaries,omitempty" env:"DICTIONARIES" flag:"dictionaries" desc:"Paths to previous zstd dictionaries still used to decompress artifacts stored with them"`
+}
+
+type StoreKeysConfig struct {
+	ProverInput   *string `key:"prover-input" env:"PROVER_INPUT" flag:"prover-input" desc:"Key template of prover inputs overriding the layout (e.g. \"chain={chainID}/date={date}/{number}/zkpi.{ext}\") with placeholders {chainID} {number} {paddedNumber} {hash} {date} and {ext}"`
+	PreflightData *string `key:"preflight-data" env:"PREFLIGHT_DATA" flag:"preflight-data" desc:"Key template of preflight data overriding the layout (e.g. \"chain={chainID}/date={date}/{number}/preflight.json\")"`
+	Block         *string `key:"block" env:"BLOCK" flag:"block" desc:"Key template of blocks (e.g. \"chain={chainID}/blocks/{paddedNumber}.json\")"`
 }
 
 type FileStoreConfig struct {
@@ -132,6 +196,11 @@ type FileStoreConfig struct {
 	Dir     *string `key:"dir" desc:"Path to local data directory"`
 }
 
+type KVStoreConfig struct {
+	Enabled *bool   `key:"enabled" desc:"Enable embedded key-value store (keeps all artifacts in a single database file)"`
+	Path    *string `key:"path" desc:"Path to the embedded key-value database file"`
+}
+
 type S3StoreConfig struct {
 	Enabled  *bool              `key:"enabled" desc:"Enable S3 store"`
 	Provider *AWSProviderConfig `key:"provider" env:"PROVIDER" flag:"provider"`
@@ -150,11 +219,36 @@ type CredentialsConfig struct {
 }
 
 type ProverInputsConfig struct {
-	ContentType *store.ContentType `key:"content-type" env:"CONTENT_TYPE" flag:"content-type" desc:"Content type (e.g. json)"`
+	ContentType *inputstore.ContentType `key:"content-type" env:"CONTENT_TYPE" flag:"content-type" desc:"Content type (e.g. \"application/json\" \"application/protobuf\" \"application/protobuf-chunked\" \"application/ssz\")"`
+	Manifest    *ManifestConfig         `key:"manifest" env:"MANIFEST" flag:"manifest"`
+	Dedup       *bool                   `key:"dedup" env:"DEDUP" flag:"dedup" desc:"Store the witness state nodes and codes once across blocks (content-addressed by keccak hash) and only their references in each prover input"`
+}
+
+type ManifestConfig struct {
+	Enabled      *bool   `key:"enabled" env:"ENABLED" flag:"enabled" desc:"Write an integrity manifest next to every stored prover input and verify it before decoding loaded prover inputs"`
+	SigningKey   *string `key:"signing-key" env:"SIGNING_KEY" flag:"signing-key" desc:"Hex encoded ed25519 private key (or seed) used to sign the manifests"`
+	VerifyingKey *string `key:"verifying-key" env:"VERIFYING_KEY" flag:"verifying-key" desc:"Hex encoded ed25519 public key. If set loaded prover inputs must have a manifest signed with the matching private key"`
 }
 
 type GeneratorConfig struct {
 	StorePreflightData *bool          `key:"store-preflight-data" env:"STORE_PREFLIGHT_DATA" flag:"store-preflight-data" desc:"Store intermediate preflight data when generating prover inputs"`
-	IncludeExtensions  *steps.Include `key:"include" env:"INCLUDE_EXTENSIONS" flag:"include-extensions" desc:"Optional extended data to include in the generated prover input (e.g. \"accessList\" \"preState\" \"stateDiffs\" \"committed\" \"all\")"`
+	IncludeExtensions  *steps.Include `key:"include" env:"INCLUDE_EXTENSIONS" flag:"include-extensions" desc:"Optional extended data to include in the generated prover input (e.g. \"accessList\" \"preState\" \"stateDiffs\" \"committed\" \"stats\" \"all\")"`
 	FilterModulo       *uint64        `key:"filter-modulo" env:"FILTER_MODULO" flag:"filter-modulo" desc:"Generate prover input for blocks which number is divisible by the given modulo"`
+	VerifyReceipts     *bool          `key:"verify-receipts" env:"VERIFY_RECEIPTS" flag:"verify-receipts" desc:"After execution compare receipts with the canonical receipts fetched from the chain RPC (requires eth_getBlockReceipts)"`
+	Trace              *string        `key:"trace" env:"TRACE" flag:"trace" desc:"Store the execution trace of prepare and execute next to the prover input (one of \"call\" or \"opcode\")"`
+}
+
+type TracingConfig struct {
+	Endpoint    *string `key:"endpoint,omitempty" desc:"OTLP HTTP endpoint to export OpenTelemetry traces to (e.g. \"localhost:4318\") (traces are not exported if empty)"`
+	Insecure    *bool   `key:"insecure" desc:"Export traces over plain HTTP instead of HTTPS"`
+	ServiceName *string `key:"service-name" env:"SERVICE_NAME" flag:"service-name" desc:"Service name attached to exported traces"`
+}
+
+type GCConfig struct {
+	Interval            *time.Duration `key:"interval" desc:"Interval between garbage collections of stored artifacts run by the daemon (disabled if 0)"`
+	KeepLast            *uint64        `key:"keep-last" env:"KEEP_LAST" flag:"keep-last" desc:"Keep the artifacts of the last N stored blocks (0 to disable)"`
+	KeepNewerThan       *time.Duration `key:"keep-newer-than" env:"KEEP_NEWER_THAN" flag:"keep-newer-than" desc:"Keep the artifacts of blocks stored more recently than the given age (e.g. \"72h\") (0 to disable)"`
+	KeepModulo          *uint64        `key:"keep-modulo" env:"KEEP_MODULO" flag:"keep-modulo" desc:"Always keep the artifacts of blocks which number is divisible by the given modulo (0 to disable)"`
+	DeletePreflightData *bool          `key:"delete-preflight-data" env:"DELETE_PREFLIGHT_DATA" flag:"delete-preflight-data" desc:"Delete preflight data once the prover input of the block exists"`
+	KeepBlobsNewerThan  *time.Duration `key:"keep-blobs-newer-than" env:"KEEP_BLOBS_NEWER_THAN" flag:"keep-blobs-newer-than" desc:"Keep the blobs of deduplicated prover inputs stored more recently than the given age so prover inputs being stored by other processes keep their blobs (must exceed 1h) (0 to disable)"`
 }
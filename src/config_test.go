@@ -10,8 +10,8 @@ import (
 	"github.com/kkrt-labs/go-utils/config"
 	"github.com/kkrt-labs/go-utils/log"
 	kkrthttp "github.com/kkrt-labs/go-utils/net/http"
-	store "github.com/kkrt-labs/go-utils/store"
 	"github.com/kkrt-labs/zk-pig/src/steps"
+	inputstore "github.com/kkrt-labs/zk-pig/src/store"
 	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -46,17 +46,46 @@ func TestViperConfig(t *testing.T) {
 	v.Set("app.stop-timeout", "20s")
 	v.Set("chain.id", "1")
 	v.Set("chain.rpc.url", "https://test.com")
+	v.Set("genesis", "genesis.json")
 	v.Set("store.file.dir", "testdata")
+	v.Set("store.kv.enabled", true)
+	v.Set("store.kv.path", "testdata/zkpig.db")
 	v.Set("store.s3.provider.region", "us-east-1")
 	v.Set("store.s3.provider.credentials.access-key", "test-access-key")
 	v.Set("store.s3.provider.credentials.secret-key", "test-secret-key")
 	v.Set("store.s3.bucket", "test-bucket")
 	v.Set("store.s3.prefix", "test-prefix")
 	v.Set("store.content-encoding", "gzip")
+	v.Set("store.zstd.level", 19)
+	v.Set("store.zstd.dictionary", "testdata/zstd-v2.dict")
+	v.Set("store.zstd.dictionaries", "testdata/zstd-v1.dict")
+	v.Set("store.layout", "hash")
+	v.Set("store.keys.prover-input", "chain={chainID}/{number}/zkpi.{ext}")
+	v.Set("store.keys.preflight-data", "chain={chainID}/{number}/preflight.json")
+	v.Set("store.keys.block", "chain={chainID}/blocks/{number}.json")
+	v.Set("store.routes.prover-inputs.backends", "s3")
+	v.Set("store.routes.prover-inputs.content-encoding", "gzip")
+	v.Set("store.routes.preflight-data.backends", "file")
+	v.Set("store.routes.blocks.backends", "none")
 	v.Set("inputs.content-type", "application/protobuf")
+	v.Set("inputs.manifest.enabled", false)
+	v.Set("inputs.manifest.signing-key", "0x01")
+	v.Set("inputs.manifest.verifying-key", "0x02")
+	v.Set("inputs.dedup", true)
 	v.Set("generator.store-preflight-data", "true")
 	v.Set("generator.filter-modulo", "15")
 	v.Set("generator.include", "preState,accessList")
+	v.Set("generator.verify-receipts", "true")
+	v.Set("generator.trace", "call")
+	v.Set("tracing.endpoint", "localhost:4318")
+	v.Set("tracing.insecure", "true")
+	v.Set("tracing.service-name", "test-zkpig")
+	v.Set("gc.interval", "1h")
+	v.Set("gc.keep-last", "1000")
+	v.Set("gc.keep-newer-than", "72h")
+	v.Set("gc.keep-modulo", "100")
+	v.Set("gc.delete-preflight-data", "true")
+	v.Set("gc.keep-blobs-newer-than", "2h")
 
 	cfg := new(Config)
 	err := cfg.Unmarshal(v)
@@ -114,6 +143,7 @@ func TestViperConfig(t *testing.T) {
 				URL: common.Ptr("https://test.com"),
 			},
 		},
+		Genesis: common.PtrSlice("genesis.json"),
 		Store: &StoreConfig{
 			File: &FileStoreConfig{
 				Dir: common.Ptr("testdata"),
@@ -129,15 +159,63 @@ func TestViperConfig(t *testing.T) {
 				Bucket: common.Ptr("test-bucket"),
 				Prefix: common.Ptr("test-prefix"),
 			},
-			ContentEncoding: common.Ptr(store.ContentEncodingGzip),
+			KV: &KVStoreConfig{
+				Enabled: common.Ptr(true),
+				Path:    common.Ptr("testdata/zkpig.db"),
+			},
+			ContentEncoding: common.Ptr(inputstore.ContentEncodingGzip),
+			Zstd: &ZstdConfig{
+				Level:        common.Ptr(19),
+				Dictionary:   common.Ptr("testdata/zstd-v2.dict"),
+				Dictionaries: common.PtrSlice("testdata/zstd-v1.dict"),
+			},
+			Layout: common.Ptr("hash"),
+			Keys: &StoreKeysConfig{
+				ProverInput:   common.Ptr("chain={chainID}/{number}/zkpi.{ext}"),
+				PreflightData: common.Ptr("chain={chainID}/{number}/preflight.json"),
+				Block:         common.Ptr("chain={chainID}/blocks/{number}.json"),
+			},
+			Routes: &StoreRoutesConfig{
+				ProverInputs: &StoreRouteConfig{
+					Backends:        common.PtrSlice("s3"),
+					ContentEncoding: common.Ptr(inputstore.ContentEncodingGzip),
+				},
+				PreflightData: &StoreRouteConfig{
+					Backends: common.PtrSlice("file"),
+				},
+				Blocks: &StoreRouteConfig{
+					Backends: common.PtrSlice("none"),
+				},
+			},
 		},
 		ProverInputs: &ProverInputsConfig{
-			ContentType: common.Ptr(store.ContentTypeProtobuf),
+			ContentType: common.Ptr(inputstore.ContentTypeProtobuf),
+			Manifest: &ManifestConfig{
+				Enabled:      common.Ptr(false),
+				SigningKey:   common.Ptr("0x01"),
+				VerifyingKey: common.Ptr("0x02"),
+			},
+			Dedup: common.Ptr(true),
 		},
 		Generator: &GeneratorConfig{
 			StorePreflightData: common.Ptr(true),
 			FilterModulo:       common.Ptr(uint64(15)),
 			IncludeExtensions:  common.Ptr(steps.IncludePreState | steps.IncludeAccessList),
+			VerifyReceipts:     common.Ptr(true),
+			Trace:              common.Ptr("call"),
+		},
+		Tracing: &TracingConfig{
+			Endpoint:    common.Ptr("localhost:4318"),
+			Insecure:    common.Ptr(true),
+			ServiceName: common.Ptr("test-zkpig"),
+		},
+		GC: &GCConfig{
+			Interval:            common.Ptr(time.Hour),
+			KeepLast:            common.Ptr(uint64(1000)),
+			KeepNewerThan:       common.Ptr(72 * time.Hour),
+			KeepModulo:          common.Ptr(uint64(100)),
+			DeletePreflightData: common.Ptr(true),
+			KeepBlobsNewerThan:  common.Ptr(2 * time.Hour),
 		},
 	}
 	assert.Equal(t, expectedCfg, cfg)
@@ -196,6 +274,7 @@ func TestEnv(t *testing.T) {
 				URL: common.Ptr("https://test.com"),
 			},
 		},
+		Genesis: common.PtrSlice("genesis.json"),
 		Store: &StoreConfig{
 			File: &FileStoreConfig{
 				Dir: common.Ptr("testdata"),
@@ -211,57 +290,134 @@ func TestEnv(t *testing.T) {
 				Bucket: common.Ptr("test-bucket"),
 				Prefix: common.Ptr("test-prefix"),
 			},
-			ContentEncoding: common.Ptr(store.ContentEncodingGzip),
+			KV: &KVStoreConfig{
+				Enabled: common.Ptr(true),
+				Path:    common.Ptr("testdata/zkpig.db"),
+			},
+			ContentEncoding: common.Ptr(inputstore.ContentEncodingGzip),
+			Zstd: &ZstdConfig{
+				Level:        common.Ptr(19),
+				Dictionary:   common.Ptr("testdata/zstd-v2.dict"),
+				Dictionaries: common.PtrSlice("testdata/zstd-v1.dict"),
+			},
+			Layout: common.Ptr("hash"),
+			Keys: &StoreKeysConfig{
+				ProverInput:   common.Ptr("chain={chainID}/{number}/zkpi.{ext}"),
+				PreflightData: common.Ptr("chain={chainID}/{number}/preflight.json"),
+				Block:         common.Ptr("chain={chainID}/blocks/{number}.json"),
+			},
+			Routes: &StoreRoutesConfig{
+				ProverInputs: &StoreRouteConfig{
+					Backends:        common.PtrSlice("s3"),
+					ContentEncoding: common.Ptr(inputstore.ContentEncodingGzip),
+				},
+				PreflightData: &StoreRouteConfig{
+					Backends: common.PtrSlice("file"),
+				},
+				Blocks: &StoreRouteConfig{
+					Backends: common.PtrSlice("none"),
+				},
+			},
 		},
 		ProverInputs: &ProverInputsConfig{
-			ContentType: common.Ptr(store.ContentTypeProtobuf),
+			ContentType: common.Ptr(inputstore.ContentTypeProtobuf),
+			Manifest: &ManifestConfig{
+				Enabled:      common.Ptr(false),
+				SigningKey:   common.Ptr("0x01"),
+				VerifyingKey: common.Ptr("0x02"),
+			},
+			Dedup: common.Ptr(true),
 		},
 		Generator: &GeneratorConfig{
 			StorePreflightData: common.Ptr(true),
 			FilterModulo:       common.Ptr(uint64(15)),
 			IncludeExtensions:  common.Ptr(steps.IncludePreState | steps.IncludeAccessList),
+			VerifyReceipts:     common.Ptr(true),
+			Trace:              common.Ptr("call"),
+		},
+		Tracing: &TracingConfig{
+			Endpoint:    common.Ptr("localhost:4318"),
+			Insecure:    common.Ptr(true),
+			ServiceName: common.Ptr("test-zkpig"),
+		},
+		GC: &GCConfig{
+			Interval:            common.Ptr(time.Hour),
+			KeepLast:            common.Ptr(uint64(1000)),
+			KeepNewerThan:       common.Ptr(72 * time.Hour),
+			KeepModulo:          common.Ptr(uint64(100)),
+			DeletePreflightData: common.Ptr(true),
+			KeepBlobsNewerThan:  common.Ptr(2 * time.Hour),
 		},
 	}).Env()
 	require.NoError(t, err)
 	assert.Equal(t, map[string]string{
-		"MAIN_EP_ADDR":                             "localhost:8881",
-		"MAIN_EP_HTTP_READ_TIMEOUT":                "40s",
-		"MAIN_EP_HTTP_READ_HEADER_TIMEOUT":         "41s",
-		"MAIN_EP_HTTP_WRITE_TIMEOUT":               "42s",
-		"MAIN_EP_HTTP_IDLE_TIMEOUT":                "43s",
-		"MAIN_EP_NET_KEEP_ALIVE":                   "44s",
-		"MAIN_EP_NET_KEEP_ALIVE_PROBE_ENABLE":      "true",
-		"MAIN_EP_NET_KEEP_ALIVE_PROBE_IDLE":        "45s",
-		"MAIN_EP_NET_KEEP_ALIVE_PROBE_INTERVAL":    "46s",
-		"MAIN_EP_NET_KEEP_ALIVE_PROBE_COUNT":       "47",
-		"MAIN_EP_HTTP_MAX_HEADER_BYTES":            "40000",
-		"HEALTHZ_EP_ADDR":                          "localhost:8882",
-		"HEALTHZ_EP_HTTP_READ_TIMEOUT":             "50s",
-		"HEALTHZ_EP_HTTP_READ_HEADER_TIMEOUT":      "51s",
-		"HEALTHZ_EP_HTTP_WRITE_TIMEOUT":            "52s",
-		"HEALTHZ_EP_HTTP_IDLE_TIMEOUT":             "53s",
-		"HEALTHZ_EP_NET_KEEP_ALIVE":                "54s",
-		"HEALTHZ_EP_NET_KEEP_ALIVE_PROBE_ENABLE":   "true",
-		"HEALTHZ_EP_NET_KEEP_ALIVE_PROBE_IDLE":     "55s",
-		"HEALTHZ_EP_NET_KEEP_ALIVE_PROBE_INTERVAL": "56s",
-		"HEALTHZ_EP_NET_KEEP_ALIVE_PROBE_COUNT":    "57",
-		"HEALTHZ_EP_HTTP_MAX_HEADER_BYTES":         "50000",
-		"LOG_LEVEL":                                "info",
-		"START_TIMEOUT":                            "10s",
-		"STOP_TIMEOUT":                             "20s",
-		"CHAIN_ID":                                 "1",
-		"CHAIN_RPC_URL":                            "https://test.com",
-		"STORE_FILE_DIR":                           "testdata",
-		"STORE_AWS_S3_PROVIDER_REGION":             "us-east-1",
-		"STORE_AWS_S3_PROVIDER_ACCESS_KEY":         "test-access-key",
-		"STORE_AWS_S3_PROVIDER_SECRET_KEY":         "test-secret-key",
-		"STORE_AWS_S3_BUCKET":                      "test-bucket",
-		"STORE_AWS_S3_PREFIX":                      "test-prefix",
-		"STORE_CONTENT_ENCODING":                   "gzip",
-		"INPUTS_CONTENT_TYPE":                      "application/protobuf",
-		"STORE_PREFLIGHT_DATA":                     "true",
-		"FILTER_MODULO":                            "15",
-		"INCLUDE_EXTENSIONS":                       "accessList,preState",
+		"MAIN_EP_ADDR":                                "localhost:8881",
+		"MAIN_EP_HTTP_READ_TIMEOUT":                   "40s",
+		"MAIN_EP_HTTP_READ_HEADER_TIMEOUT":            "41s",
+		"MAIN_EP_HTTP_WRITE_TIMEOUT":                  "42s",
+		"MAIN_EP_HTTP_IDLE_TIMEOUT":                   "43s",
+		"MAIN_EP_NET_KEEP_ALIVE":                      "44s",
+		"MAIN_EP_NET_KEEP_ALIVE_PROBE_ENABLE":         "true",
+		"MAIN_EP_NET_KEEP_ALIVE_PROBE_IDLE":           "45s",
+		"MAIN_EP_NET_KEEP_ALIVE_PROBE_INTERVAL":       "46s",
+		"MAIN_EP_NET_KEEP_ALIVE_PROBE_COUNT":          "47",
+		"MAIN_EP_HTTP_MAX_HEADER_BYTES":               "40000",
+		"HEALTHZ_EP_ADDR":                             "localhost:8882",
+		"HEALTHZ_EP_HTTP_READ_TIMEOUT":                "50s",
+		"HEALTHZ_EP_HTTP_READ_HEADER_TIMEOUT":         "51s",
+		"HEALTHZ_EP_HTTP_WRITE_TIMEOUT":               "52s",
+		"HEALTHZ_EP_HTTP_IDLE_TIMEOUT":                "53s",
+		"HEALTHZ_EP_NET_KEEP_ALIVE":                   "54s",
+		"HEALTHZ_EP_NET_KEEP_ALIVE_PROBE_ENABLE":      "true",
+		"HEALTHZ_EP_NET_KEEP_ALIVE_PROBE_IDLE":        "55s",
+		"HEALTHZ_EP_NET_KEEP_ALIVE_PROBE_INTERVAL":    "56s",
+		"HEALTHZ_EP_NET_KEEP_ALIVE_PROBE_COUNT":       "57",
+		"HEALTHZ_EP_HTTP_MAX_HEADER_BYTES":            "50000",
+		"LOG_LEVEL":                                   "info",
+		"START_TIMEOUT":                               "10s",
+		"STOP_TIMEOUT":                                "20s",
+		"CHAIN_ID":                                    "1",
+		"CHAIN_RPC_URL":                               "https://test.com",
+		"GENESIS":                                     "genesis.json",
+		"STORE_FILE_DIR":                              "testdata",
+		"STORE_KV_ENABLED":                            "true",
+		"STORE_KV_PATH":                               "testdata/zkpig.db",
+		"STORE_AWS_S3_PROVIDER_REGION":                "us-east-1",
+		"STORE_AWS_S3_PROVIDER_ACCESS_KEY":            "test-access-key",
+		"STORE_AWS_S3_PROVIDER_SECRET_KEY":            "test-secret-key",
+		"STORE_AWS_S3_BUCKET":                         "test-bucket",
+		"STORE_AWS_S3_PREFIX":                         "test-prefix",
+		"STORE_CONTENT_ENCODING":                      "gzip",
+		"STORE_ZSTD_LEVEL":                            "19",
+		"STORE_ZSTD_DICTIONARY":                       "testdata/zstd-v2.dict",
+		"STORE_ZSTD_DICTIONARIES":                     "testdata/zstd-v1.dict",
+		"STORE_LAYOUT":                                "hash",
+		"STORE_KEYS_PROVER_INPUT":                     "chain={chainID}/{number}/zkpi.{ext}",
+		"STORE_KEYS_PREFLIGHT_DATA":                   "chain={chainID}/{number}/preflight.json",
+		"STORE_KEYS_BLOCK":                            "chain={chainID}/blocks/{number}.json",
+		"STORE_ROUTES_PROVER_INPUTS_BACKENDS":         "s3",
+		"STORE_ROUTES_PROVER_INPUTS_CONTENT_ENCODING": "gzip",
+		"STORE_ROUTES_PREFLIGHT_DATA_BACKENDS":        "file",
+		"STORE_ROUTES_BLOCKS_BACKENDS":                "none",
+		"INPUTS_CONTENT_TYPE":                         "application/protobuf",
+		"INPUTS_MANIFEST_ENABLED":                     "false",
+		"INPUTS_MANIFEST_SIGNING_KEY":                 "0x01",
+		"INPUTS_MANIFEST_VERIFYING_KEY":               "0x02",
+		"INPUTS_DEDUP":                                "true",
+		"STORE_PREFLIGHT_DATA":                        "true",
+		"FILTER_MODULO":                               "15",
+		"INCLUDE_EXTENSIONS":                          "accessList,preState",
+		"VERIFY_RECEIPTS":                             "true",
+		"TRACE":                                       "call",
+		"TRACING_ENDPOINT":                            "localhost:4318",
+		"TRACING_INSECURE":                            "true",
+		"TRACING_SERVICE_NAME":                        "test-zkpig",
+		"GC_INTERVAL":                                 "1h0m0s",
+		"GC_KEEP_LAST":                                "1000",
+		"GC_KEEP_NEWER_THAN":                          "72h0m0s",
+		"GC_KEEP_MODULO":                              "100",
+		"GC_DELETE_PREFLIGHT_DATA":                    "true",
+		"GC_KEEP_BLOBS_NEWER_THAN":                    "2h0m0s",
 	}, env)
 }
 
@@ -272,69 +428,100 @@ func TestFlagsUsage(t *testing.T) {
 	err := AddFlags(v, set)
 	require.NoError(t, err)
 
-	expectedUsage := `      --chain-id string                                   Chain ID (decimal) [env: CHAIN_ID]
-      --chain-rpc-url string                              Chain JSON-RPC URL [env: CHAIN_RPC_URL]
-  -c, --config strings                                     [env: CONFIG] (default [config.yaml,config.yml])
-      --filter-modulo uint                                Generate prover input for blocks which number is divisible by the given modulo [env: FILTER_MODULO] (default 5)
-      --healthz-ep-addr string                            healthz entrypoint: TCP Address to listen on [env: HEALTHZ_EP_ADDR] (default ":8081")
-      --healthz-ep-http-idle-timeout string               healthz entrypoint: Maximum duration to wait for the next request when keep-alives are enabled (zero uses the value of read timeout) [env: HEALTHZ_EP_HTTP_IDLE_TIMEOUT] (default "30s")
-      --healthz-ep-http-max-header-bytes int              healthz entrypoint: Maximum number of bytes the server will read parsing the request header's keys and values [env: HEALTHZ_EP_HTTP_MAX_HEADER_BYTES] (default 1048576)
-      --healthz-ep-http-read-header-timeout string        healthz entrypoint: Maximum duration for reading request headers (zero uses the value of read timeout) [env: HEALTHZ_EP_HTTP_READ_HEADER_TIMEOUT] (default "30s")
-      --healthz-ep-http-read-timeout string               healthz entrypoint: Maximum duration for reading the entire request including the body (zero means no timeout) [env: HEALTHZ_EP_HTTP_READ_TIMEOUT] (default "30s")
-      --healthz-ep-http-write-timeout string              healthz entrypoint: Maximum duration before timing out writes of the response (zero means no timeout) [env: HEALTHZ_EP_HTTP_WRITE_TIMEOUT] (default "30s")
-      --healthz-ep-net-keep-alive string                  healthz entrypoint: Keep alive period for network connections accepted by this entrypoint [env: HEALTHZ_EP_NET_KEEP_ALIVE] (default "-1s")
-      --healthz-ep-net-keep-alive-probe-count int         healthz entrypoint: Maximum number of keep-alive probes that can go unanswered before dropping a connection [env: HEALTHZ_EP_NET_KEEP_ALIVE_PROBE_COUNT] (default 9)
-      --healthz-ep-net-keep-alive-probe-enable            healthz entrypoint: Enable keep alive probes [env: HEALTHZ_EP_NET_KEEP_ALIVE_PROBE_ENABLE]
-      --healthz-ep-net-keep-alive-probe-idle string       healthz entrypoint: Time that the connection must be idle before the first keep-alive probe is sent [env: HEALTHZ_EP_NET_KEEP_ALIVE_PROBE_IDLE] (default "15s")
-      --healthz-ep-net-keep-alive-probe-interval string   healthz entrypoint: Time between keep-alive probes [env: HEALTHZ_EP_NET_KEEP_ALIVE_PROBE_INTERVAL] (default "15s")
-      --include-extensions string                         Optionnal extended data to include in the generated prover input (e.g. "accessList" "preState" "stateDiffs" "committed" "all") [env: INCLUDE_EXTENSIONS] (default "all")
-      --inputs-content-type string                        Content type (e.g. json) [env: INPUTS_CONTENT_TYPE] (default "application/json")
-      --log-enable-caller                                 Enable caller [env: LOG_ENABLE_CALLER]
-      --log-enable-stacktrace                             Enable automatic stacktrace capturing [env: LOG_ENABLE_STACKTRACE]
-      --log-encoding-caller-encoder string                Encoding: Primitive representation for the log caller (e.g. 'full' [env: LOG_ENCODING_CALLER_ENCODER] (default "short")
-      --log-encoding-caller-key string                    Encoding: Key for the log caller (if empty [env: LOG_ENCODING_CALLER_KEY] (default "caller")
-      --log-encoding-console-separator string             Encoding: Field separator used by the console encoder [env: LOG_ENCODING_CONSOLE_SEPARATOR] (default "\t")
-      --log-encoding-duration-encoder string              Encoding: Primitive representation for the log duration (e.g. 'string' [env: LOG_ENCODING_DURATION_ENCODER] (default "s")
-      --log-encoding-function-key string                  Encoding: Key for the log function (if empty [env: LOG_ENCODING_FUNCTION_KEY]
-      --log-encoding-level-encoder string                 Encoding: Primitive representation for the log level (e.g. 'capital' [env: LOG_ENCODING_LEVEL_ENCODER] (default "capitalColor")
-      --log-encoding-level-key string                     Encoding: Key for the log level (if empty [env: LOG_ENCODING_LEVEL_KEY] (default "level")
-      --log-encoding-line-ending string                   Encoding: Line ending [env: LOG_ENCODING_LINE_ENDING] (default "\n")
-      --log-encoding-message-key string                   Encoding: Key for the log message (if empty [env: LOG_ENCODING_MESSAGE_KEY] (default "msg")
-      --log-encoding-name-encoder string                  Encoding: Primitive representation for the log logger name (e.g. 'full' [env: LOG_ENCODING_NAME_ENCODER] (default "full")
-      --log-encoding-name-key string                      Encoding: Key for the log logger name (if empty [env: LOG_ENCODING_NAME_KEY] (default "logger")
-      --log-encoding-skip-line-ending                     Encoding: Skip the line ending [env: LOG_ENCODING_SKIP_LINE_ENDING]
-      --log-encoding-stacktrace-key string                Encoding: Key for the log stacktrace (if empty [env: LOG_ENCODING_STACKTRACE_KEY] (default "stacktrace")
-      --log-encoding-time-encoder string                  Encoding: Primitive representation for the log timestamp (e.g. 'rfc3339nano' [env: LOG_ENCODING_TIME_ENCODER] (default "rfc3339")
-      --log-encoding-time-key string                      Encoding: Key for the log timestamp (if empty [env: LOG_ENCODING_TIME_KEY] (default "ts")
-      --log-err-output strings                            List of URLs to write internal logger errors to [env: LOG_ERROR_OUTPUT_PATHS] (default [stderr])
-      --log-format string                                 Log format [env: LOG_FORMAT] (default "text")
-      --log-level string                                  Minimum enabled logging level [env: LOG_LEVEL] (default "info")
-      --log-output strings                                List of URLs or file paths to write logging output to [env: LOG_OUTPUT_PATHS] (default [stderr])
-      --log-sampling-initial int                          Sampling: Number of log entries with the same level and message to log before dropping entries [env: LOG_SAMPLING_INITIAL] (default 100)
-      --log-sampling-thereafter int                       Sampling: After the initial number of entries [env: LOG_SAMPLING_THEREAFTER] (default 100)
-      --main-ep-addr string                               main entrypoint: TCP Address to listen on [env: MAIN_EP_ADDR] (default ":8080")
-      --main-ep-http-idle-timeout string                  main entrypoint: Maximum duration to wait for the next request when keep-alives are enabled (zero uses the value of read timeout) [env: MAIN_EP_HTTP_IDLE_TIMEOUT] (default "30s")
-      --main-ep-http-max-header-bytes int                 main entrypoint: Maximum number of bytes the server will read parsing the request header's keys and values [env: MAIN_EP_HTTP_MAX_HEADER_BYTES] (default 1048576)
-      --main-ep-http-read-header-timeout string           main entrypoint: Maximum duration for reading request headers (zero uses the value of read timeout) [env: MAIN_EP_HTTP_READ_HEADER_TIMEOUT] (default "30s")
-      --main-ep-http-read-timeout string                  main entrypoint: Maximum duration for reading the entire request including the body (zero means no timeout) [env: MAIN_EP_HTTP_READ_TIMEOUT] (default "30s")
-      --main-ep-http-write-timeout string                 main entrypoint: Maximum duration before timing out writes of the response (zero means no timeout) [env: MAIN_EP_HTTP_WRITE_TIMEOUT] (default "30s")
-      --main-ep-net-keep-alive string                     main entrypoint: Keep alive period for network connections accepted by this entrypoint [env: MAIN_EP_NET_KEEP_ALIVE] (default "-1s")
-      --main-ep-net-keep-alive-probe-count int            main entrypoint: Maximum number of keep-alive probes that can go unanswered before dropping a connection [env: MAIN_EP_NET_KEEP_ALIVE_PROBE_COUNT] (default 9)
-      --main-ep-net-keep-alive-probe-enable               main entrypoint: Enable keep alive probes [env: MAIN_EP_NET_KEEP_ALIVE_PROBE_ENABLE]
-      --main-ep-net-keep-alive-probe-idle string          main entrypoint: Time that the connection must be idle before the first keep-alive probe is sent [env: MAIN_EP_NET_KEEP_ALIVE_PROBE_IDLE] (default "15s")
-      --main-ep-net-keep-alive-probe-interval string      main entrypoint: Time between keep-alive probes [env: MAIN_EP_NET_KEEP_ALIVE_PROBE_INTERVAL] (default "15s")
-      --start-timeout string                              Start timeout [env: START_TIMEOUT] (default "10s")
-      --stop-timeout string                               Stop timeout [env: STOP_TIMEOUT] (default "10s")
-      --store-aws-s3-bucket string                        AWS S3 bucket [env: STORE_AWS_S3_BUCKET]
-      --store-aws-s3-enabled                              Enable S3 store [env: STORE_AWS_S3_ENABLED]
-      --store-aws-s3-prefix string                        AWS S3 bucket key prefix [env: STORE_AWS_S3_PREFIX]
-      --store-aws-s3-provider-access-key string           AWS access key [env: STORE_AWS_S3_PROVIDER_ACCESS_KEY]
-      --store-aws-s3-provider-region string               AWS region [env: STORE_AWS_S3_PROVIDER_REGION]
-      --store-aws-s3-provider-secret-key string           AWS secret key [env: STORE_AWS_S3_PROVIDER_SECRET_KEY]
-      --store-content-encoding string                     Content encoding (e.g. gzip) [env: STORE_CONTENT_ENCODING] (default "plain")
-      --store-file-dir string                             Path to local data directory [env: STORE_FILE_DIR] (default "data")
-      --store-file-enabled                                Enable file store [env: STORE_FILE_ENABLED] (default true)
-      --store-preflight-data                              Store intermediate preflight data when generating prover inputs [env: STORE_PREFLIGHT_DATA]
+	expectedUsage := `      --chain-id string                                       Chain ID (decimal) [env: CHAIN_ID]
+      --chain-rpc-url string                                  Chain JSON-RPC URL [env: CHAIN_RPC_URL]
+  -c, --config strings                                         [env: CONFIG] (default [config.yaml,config.yml])
+      --filter-modulo uint                                    Generate prover input for blocks which number is divisible by the given modulo [env: FILTER_MODULO] (default 5)
+      --gc-delete-preflight-data                              Delete preflight data once the prover input of the block exists [env: GC_DELETE_PREFLIGHT_DATA]
+      --gc-interval string                                    Interval between garbage collections of stored artifacts run by the daemon (disabled if 0) [env: GC_INTERVAL] (default "0s")
+      --gc-keep-blobs-newer-than string                       Keep the blobs of deduplicated prover inputs stored more recently than the given age so prover inputs being stored by other processes keep their blobs (must exceed 1h) (0 to disable) [env: GC_KEEP_BLOBS_NEWER_THAN] (default "24h0m0s")
+      --gc-keep-last uint                                     Keep the artifacts of the last N stored blocks (0 to disable) [env: GC_KEEP_LAST]
+      --gc-keep-modulo uint                                   Always keep the artifacts of blocks which number is divisible by the given modulo (0 to disable) [env: GC_KEEP_MODULO]
+      --gc-keep-newer-than string                             Keep the artifacts of blocks stored more recently than the given age (e.g. "72h") (0 to disable) [env: GC_KEEP_NEWER_THAN] (default "0s")
+      --genesis strings                                       Paths to genesis JSON files (chain config and alloc) registering custom chains (prefix with "store://" to load from the store) [env: GENESIS]
+      --healthz-ep-addr string                                healthz entrypoint: TCP Address to listen on [env: HEALTHZ_EP_ADDR] (default ":8081")
+      --healthz-ep-http-idle-timeout string                   healthz entrypoint: Maximum duration to wait for the next request when keep-alives are enabled (zero uses the value of read timeout) [env: HEALTHZ_EP_HTTP_IDLE_TIMEOUT] (default "30s")
+      --healthz-ep-http-max-header-bytes int                  healthz entrypoint: Maximum number of bytes the server will read parsing the request header's keys and values [env: HEALTHZ_EP_HTTP_MAX_HEADER_BYTES] (default 1048576)
+      --healthz-ep-http-read-header-timeout string            healthz entrypoint: Maximum duration for reading request headers (zero uses the value of read timeout) [env: HEALTHZ_EP_HTTP_READ_HEADER_TIMEOUT] (default "30s")
+      --healthz-ep-http-read-timeout string                   healthz entrypoint: Maximum duration for reading the entire request including the body (zero means no timeout) [env: HEALTHZ_EP_HTTP_READ_TIMEOUT] (default "30s")
+      --healthz-ep-http-write-timeout string                  healthz entrypoint: Maximum duration before timing out writes of the response (zero means no timeout) [env: HEALTHZ_EP_HTTP_WRITE_TIMEOUT] (default "30s")
+      --healthz-ep-net-keep-alive string                      healthz entrypoint: Keep alive period for network connections accepted by this entrypoint [env: HEALTHZ_EP_NET_KEEP_ALIVE] (default "-1s")
+      --healthz-ep-net-keep-alive-probe-count int             healthz entrypoint: Maximum number of keep-alive probes that can go unanswered before dropping a connection [env: HEALTHZ_EP_NET_KEEP_ALIVE_PROBE_COUNT] (default 9)
+      --healthz-ep-net-keep-alive-probe-enable                healthz entrypoint: Enable keep alive probes [env: HEALTHZ_EP_NET_KEEP_ALIVE_PROBE_ENABLE]
+      --healthz-ep-net-keep-alive-probe-idle string           healthz entrypoint: Time that the connection must be idle before the first keep-alive probe is sent [env: HEALTHZ_EP_NET_KEEP_ALIVE_PROBE_IDLE] (default "15s")
+      --healthz-ep-net-keep-alive-probe-interval string       healthz entrypoint: Time between keep-alive probes [env: HEALTHZ_EP_NET_KEEP_ALIVE_PROBE_INTERVAL] (default "15s")
+      --include-extensions string                             Optional extended data to include in the generated prover input (e.g. "accessList" "preState" "stateDiffs" "committed" "stats" "all") [env: INCLUDE_EXTENSIONS] (default "all")
+      --inputs-content-type string                            Content type (e.g. "application/json" "application/protobuf" "application/protobuf-chunked" "application/ssz") [env: INPUTS_CONTENT_TYPE] (default "application/json")
+      --inputs-dedup                                          Store the witness state nodes and codes once across blocks (content-addressed by keccak hash) and only their references in each prover input [env: INPUTS_DEDUP]
+      --inputs-manifest-enabled                               Write an integrity manifest next to every stored prover input and verify it before decoding loaded prover inputs [env: INPUTS_MANIFEST_ENABLED] (default true)
+      --inputs-manifest-signing-key string                    Hex encoded ed25519 private key (or seed) used to sign the manifests [env: INPUTS_MANIFEST_SIGNING_KEY]
+      --inputs-manifest-verifying-key string                  Hex encoded ed25519 public key. If set loaded prover inputs must have a manifest signed with the matching private key [env: INPUTS_MANIFEST_VERIFYING_KEY]
+      --log-enable-caller                                     Enable caller [env: LOG_ENABLE_CALLER]
+      --log-enable-stacktrace                                 Enable automatic stacktrace capturing [env: LOG_ENABLE_STACKTRACE]
+      --log-encoding-caller-encoder string                    Encoding: Primitive representation for the log caller (e.g. 'full' [env: LOG_ENCODING_CALLER_ENCODER] (default "short")
+      --log-encoding-caller-key string                        Encoding: Key for the log caller (if empty [env: LOG_ENCODING_CALLER_KEY] (default "caller")
+      --log-encoding-console-separator string                 Encoding: Field separator used by the console encoder [env: LOG_ENCODING_CONSOLE_SEPARATOR] (default "\t")
+      --log-encoding-duration-encoder string                  Encoding: Primitive representation for the log duration (e.g. 'string' [env: LOG_ENCODING_DURATION_ENCODER] (default "s")
+      --log-encoding-function-key string                      Encoding: Key for the log function (if empty [env: LOG_ENCODING_FUNCTION_KEY]
+      --log-encoding-level-encoder string                     Encoding: Primitive representation for the log level (e.g. 'capital' [env: LOG_ENCODING_LEVEL_ENCODER] (default "capitalColor")
+      --log-encoding-level-key string                         Encoding: Key for the log level (if empty [env: LOG_ENCODING_LEVEL_KEY] (default "level")
+      --log-encoding-line-ending string                       Encoding: Line ending [env: LOG_ENCODING_LINE_ENDING] (default "\n")
+      --log-encoding-message-key string                       Encoding: Key for the log message (if empty [env: LOG_ENCODING_MESSAGE_KEY] (default "msg")
+      --log-encoding-name-encoder string                      Encoding: Primitive representation for the log logger name (e.g. 'full' [env: LOG_ENCODING_NAME_ENCODER] (default "full")
+      --log-encoding-name-key string                          Encoding: Key for the log logger name (if empty [env: LOG_ENCODING_NAME_KEY] (default "logger")
+      --log-encoding-skip-line-ending                         Encoding: Skip the line ending [env: LOG_ENCODING_SKIP_LINE_ENDING]
+      --log-encoding-stacktrace-key string                    Encoding: Key for the log stacktrace (if empty [env: LOG_ENCODING_STACKTRACE_KEY] (default "stacktrace")
+      --log-encoding-time-encoder string                      Encoding: Primitive representation for the log timestamp (e.g. 'rfc3339nano' [env: LOG_ENCODING_TIME_ENCODER] (default "rfc3339")
+      --log-encoding-time-key string                          Encoding: Key for the log timestamp (if empty [env: LOG_ENCODING_TIME_KEY] (default "ts")
+      --log-err-output strings                                List of URLs to write internal logger errors to [env: LOG_ERROR_OUTPUT_PATHS] (default [stderr])
+      --log-format string                                     Log format [env: LOG_FORMAT] (default "text")
+      --log-level string                                      Minimum enabled logging level [env: LOG_LEVEL] (default "info")
+      --log-output strings                                    List of URLs or file paths to write logging output to [env: LOG_OUTPUT_PATHS] (default [stderr])
+      --log-sampling-initial int                              Sampling: Number of log entries with the same level and message to log before dropping entries [env: LOG_SAMPLING_INITIAL] (default 100)
+      --log-sampling-thereafter int                           Sampling: After the initial number of entries [env: LOG_SAMPLING_THEREAFTER] (default 100)
+      --main-ep-addr string                                   main entrypoint: TCP Address to listen on [env: MAIN_EP_ADDR] (default ":8080")
+      --main-ep-http-idle-timeout string                      main entrypoint: Maximum duration to wait for the next request when keep-alives are enabled (zero uses the value of read timeout) [env: MAIN_EP_HTTP_IDLE_TIMEOUT] (default "30s")
+      --main-ep-http-max-header-bytes int                     main entrypoint: Maximum number of bytes the server will read parsing the request header's keys and values [env: MAIN_EP_HTTP_MAX_HEADER_BYTES] (default 1048576)
+      --main-ep-http-read-header-timeout string               main entrypoint: Maximum duration for reading request headers (zero uses the value of read timeout) [env: MAIN_EP_HTTP_READ_HEADER_TIMEOUT] (default "30s")
+      --main-ep-http-read-timeout string                      main entrypoint: Maximum duration for reading the entire request including the body (zero means no timeout) [env: MAIN_EP_HTTP_READ_TIMEOUT] (default "30s")
+      --main-ep-http-write-timeout string                     main entrypoint: Maximum duration before timing out writes of the response (zero means no timeout) [env: MAIN_EP_HTTP_WRITE_TIMEOUT] (default "30s")
+      --main-ep-net-keep-alive string                         main entrypoint: Keep alive period for network connections accepted by this entrypoint [env: MAIN_EP_NET_KEEP_ALIVE] (default "-1s")
+      --main-ep-net-keep-alive-probe-count int                main entrypoint: Maximum number of keep-alive probes that can go unanswered before dropping a connection [env: MAIN_EP_NET_KEEP_ALIVE_PROBE_COUNT] (default 9)
+      --main-ep-net-keep-alive-probe-enable                   main entrypoint: Enable keep alive probes [env: MAIN_EP_NET_KEEP_ALIVE_PROBE_ENABLE]
+      --main-ep-net-keep-alive-probe-idle string              main entrypoint: Time that the connection must be idle before the first keep-alive probe is sent [env: MAIN_EP_NET_KEEP_ALIVE_PROBE_IDLE] (default "15s")
+      --main-ep-net-keep-alive-probe-interval string          main entrypoint: Time between keep-alive probes [env: MAIN_EP_NET_KEEP_ALIVE_PROBE_INTERVAL] (default "15s")
+      --start-timeout string                                  Start timeout [env: START_TIMEOUT] (default "10s")
+      --stop-timeout string                                   Stop timeout [env: STOP_TIMEOUT] (default "10s")
+      --store-aws-s3-bucket string                            AWS S3 bucket [env: STORE_AWS_S3_BUCKET]
+      --store-aws-s3-enabled                                  Enable S3 store [env: STORE_AWS_S3_ENABLED]
+      --store-aws-s3-prefix string                            AWS S3 bucket key prefix [env: STORE_AWS_S3_PREFIX]
+      --store-aws-s3-provider-access-key string               AWS access key [env: STORE_AWS_S3_PROVIDER_ACCESS_KEY]
+      --store-aws-s3-provider-region string                   AWS region [env: STORE_AWS_S3_PROVIDER_REGION]
+      --store-aws-s3-provider-secret-key string               AWS secret key [env: STORE_AWS_S3_PROVIDER_SECRET_KEY]
+      --store-content-encoding string                         Content encoding (e.g. "gzip" "zstd") [env: STORE_CONTENT_ENCODING] (default "plain")
+      --store-file-dir string                                 Path to local data directory [env: STORE_FILE_DIR] (default "data")
+      --store-file-enabled                                    Enable file store [env: STORE_FILE_ENABLED] (default true)
+      --store-keys-block string                               Key template of blocks (e.g. "chain={chainID}/blocks/{paddedNumber}.json") [env: STORE_KEYS_BLOCK]
+      --store-keys-preflight-data string                      Key template of preflight data overriding the layout (e.g. "chain={chainID}/date={date}/{number}/preflight.json") [env: STORE_KEYS_PREFLIGHT_DATA]
+      --store-keys-prover-input string                        Key template of prover inputs overriding the layout (e.g. "chain={chainID}/date={date}/{number}/zkpi.{ext}") with placeholders {chainID} {number} {paddedNumber} {hash} {date} and {ext} [env: STORE_KEYS_PROVER_INPUT]
+      --store-kv-enabled                                      Enable embedded key-value store (keeps all artifacts in a single database file) [env: STORE_KV_ENABLED]
+      --store-kv-path string                                  Path to the embedded key-value database file [env: STORE_KV_PATH] (default "zkpig.db")
+      --store-layout string                                   Layout of the paths of prover inputs and preflight data ("number" stores at /<chainID>/<number>/ and "hash" stores at /<chainID>/<number>/<hash>/ with a latest pointer) [env: STORE_LAYOUT] (default "number")
+      --store-preflight-data                                  Store intermediate preflight data when generating prover inputs [env: STORE_PREFLIGHT_DATA]
+      --store-routes-blocks-backends strings                  Stores receiving the artifacts among "file" "s3" and "kv" or "none" to not store them (defaults to all enabled stores) [env: STORE_ROUTES_BLOCKS_BACKENDS]
+      --store-routes-blocks-content-encoding string           Content encoding of the artifacts (defaults to the store content encoding) [env: STORE_ROUTES_BLOCKS_CONTENT_ENCODING] (default "plain")
+      --store-routes-preflight-data-backends strings          Stores receiving the artifacts among "file" "s3" and "kv" or "none" to not store them (defaults to all enabled stores) [env: STORE_ROUTES_PREFLIGHT_DATA_BACKENDS]
+      --store-routes-preflight-data-content-encoding string   Content encoding of the artifacts (defaults to the store content encoding) [env: STORE_ROUTES_PREFLIGHT_DATA_CONTENT_ENCODING] (default "plain")
+      --store-routes-prover-inputs-backends strings           Stores receiving the artifacts among "file" "s3" and "kv" or "none" to not store them (defaults to all enabled stores) [env: STORE_ROUTES_PROVER_INPUTS_BACKENDS]
+      --store-routes-prover-inputs-content-encoding string    Content encoding of the artifacts (defaults to the store content encoding) [env: STORE_ROUTES_PROVER_INPUTS_CONTENT_ENCODING] (default "plain")
+      --store-zstd-dictionaries strings                       Paths to previous zstd dictionaries still used to decompress artifacts stored with them [env: STORE_ZSTD_DICTIONARIES]
+      --store-zstd-dictionary string                          Path to a zstd dictionary trained on prior prover inputs (see "zkpig dict") compressing stored artifacts [env: STORE_ZSTD_DICTIONARY]
+      --store-zstd-level int                                  Zstd compression level from 1 (fastest) to 22 (best compression) [env: STORE_ZSTD_LEVEL] (default 3)
+      --trace string                                          Store the execution trace of prepare and execute next to the prover input (one of "call" or "opcode") [env: TRACE]
+      --tracing-endpoint string                               OTLP HTTP endpoint to export OpenTelemetry traces to (e.g. "localhost:4318") (traces are not exported if empty) [env: TRACING_ENDPOINT]
+      --tracing-insecure                                      Export traces over plain HTTP instead of HTTPS [env: TRACING_INSECURE]
+      --tracing-service-name string                           Service name attached to exported traces [env: TRACING_SERVICE_NAME] (default "zkpig")
+      --verify-receipts                                       After execution compare receipts with the canonical receipts fetched from the chain RPC (requires eth_getBlockReceipts) [env: VERIFY_RECEIPTS]
 `
 
 	expectedRaws := strings.Split(expectedUsage, "\n")
@@ -397,6 +584,7 @@ func TestAddFlagsAndLoadEnv(t *testing.T) {
 				URL: common.Ptr("https://test.com"),
 			},
 		},
+		Genesis: common.PtrSlice("genesis.json"),
 		Store: &StoreConfig{
 			File: &FileStoreConfig{
 				Enabled: common.Ptr(false),
@@ -414,15 +602,63 @@ func TestAddFlagsAndLoadEnv(t *testing.T) {
 				Bucket: common.Ptr("test-bucket"),
 				Prefix: common.Ptr("test-prefix"),
 			},
-			ContentEncoding: common.Ptr(store.ContentEncodingGzip),
+			KV: &KVStoreConfig{
+				Enabled: common.Ptr(true),
+				Path:    common.Ptr("testdata/zkpig.db"),
+			},
+			ContentEncoding: common.Ptr(inputstore.ContentEncodingGzip),
+			Zstd: &ZstdConfig{
+				Level:        common.Ptr(19),
+				Dictionary:   common.Ptr("testdata/zstd-v2.dict"),
+				Dictionaries: common.PtrSlice("testdata/zstd-v1.dict"),
+			},
+			Layout: common.Ptr("hash"),
+			Keys: &StoreKeysConfig{
+				ProverInput:   common.Ptr("chain={chainID}/{number}/zkpi.{ext}"),
+				PreflightData: common.Ptr("chain={chainID}/{number}/preflight.json"),
+				Block:         common.Ptr("chain={chainID}/blocks/{number}.json"),
+			},
+			Routes: &StoreRoutesConfig{
+				ProverInputs: &StoreRouteConfig{
+					Backends:        common.PtrSlice("s3"),
+					ContentEncoding: common.Ptr(inputstore.ContentEncodingGzip),
+				},
+				PreflightData: &StoreRouteConfig{
+					Backends: common.PtrSlice("file"),
+				},
+				Blocks: &StoreRouteConfig{
+					Backends: common.PtrSlice("none"),
+				},
+			},
 		},
 		ProverInputs: &ProverInputsConfig{
-			ContentType: common.Ptr(store.ContentTypeJSON),
+			ContentType: common.Ptr(inputstore.ContentTypeJSON),
+			Manifest: &ManifestConfig{
+				Enabled:      common.Ptr(true),
+				SigningKey:   common.Ptr("0x01"),
+				VerifyingKey: common.Ptr("0x02"),
+			},
+			Dedup: common.Ptr(true),
 		},
 		Generator: &GeneratorConfig{
 			StorePreflightData: common.Ptr(true),
 			FilterModulo:       common.Ptr(uint64(15)),
 			IncludeExtensions:  common.Ptr(steps.IncludePreState | steps.IncludeAccessList),
+			VerifyReceipts:     common.Ptr(true),
+			Trace:              common.Ptr("call"),
+		},
+		Tracing: &TracingConfig{
+			Endpoint:    common.Ptr("localhost:4318"),
+			Insecure:    common.Ptr(true),
+			ServiceName: common.Ptr("test-zkpig"),
+		},
+		GC: &GCConfig{
+			Interval:            common.Ptr(time.Hour),
+			KeepLast:            common.Ptr(uint64(1000)),
+			KeepNewerThan:       common.Ptr(72 * time.Hour),
+			KeepModulo:          common.Ptr(uint64(100)),
+			DeletePreflightData: common.Ptr(true),
+			KeepBlobsNewerThan:  common.Ptr(2 * time.Hour),
 		},
 	}
 
@@ -2,36 +2,60 @@ package ethereum
 
 import (
 	"fmt"
+	"math/big"
 
+	gethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	gethstate "github.com/ethereum/go-ethereum/core/state"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/params"
 )
 
 // NewChain creates a new core.HeaderChain instance
+//
+// The chain only relies on the headers available in the database (e.g. witness ancestors) and the chain config,
+// it does not require the genesis state (so it works for chains which genesis alloc is unknown).
 func NewChain(cfg *params.ChainConfig, stateDB gethstate.Database) (*core.HeaderChain, error) {
-	genesis, err := GetDefaultGenesis(cfg.ChainID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get default genesis for chain %q: %v", cfg.ChainID.String(), err)
-	}
+	db := stateDB.TrieDB().Disk()
 
-	// Setup the genesis block, to avoid error on core.NewHeaderChain
-	_, err = genesis.Commit(stateDB.TrieDB().Disk(), stateDB.TrieDB())
-	if err != nil {
-		return nil, fmt.Errorf("failed to apply genesis block: %v", err)
-	}
+	// core.NewHeaderChain requires a canonical genesis header
+	WritePlaceholderGenesis(db)
 
 	// Create consensus engine
-	engine, err := ethconfig.CreateConsensusEngine(cfg, stateDB.TrieDB().Disk())
+	engine, err := ethconfig.CreateConsensusEngine(cfg, db)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create consensus engine: %v", err)
 	}
 
-	hc, err := core.NewHeaderChain(stateDB.TrieDB().Disk(), cfg, engine, nil)
+	hc, err := core.NewHeaderChain(db, cfg, engine, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create header chain: %v", err)
 	}
 
 	return hc, nil
 }
+
+// PlaceholderGenesisHeader is the header written as canonical genesis by NewChain, when the database has none
+//
+// It is not part of any real chain: block execution only walks headers through parent hashes, so it is never reached.
+var PlaceholderGenesisHeader = &gethtypes.Header{
+	UncleHash:   gethtypes.EmptyUncleHash,
+	Root:        gethtypes.EmptyRootHash,
+	TxHash:      gethtypes.EmptyTxsHash,
+	ReceiptHash: gethtypes.EmptyReceiptsHash,
+	Difficulty:  new(big.Int),
+	Number:      new(big.Int),
+}
+
+// WritePlaceholderGenesis writes PlaceholderGenesisHeader as canonical genesis, if the database has no canonical genesis
+func WritePlaceholderGenesis(db ethdb.Database) {
+	if rawdb.ReadCanonicalHash(db, 0) != (gethcommon.Hash{}) {
+		return
+	}
+
+	rawdb.WriteHeader(db, PlaceholderGenesisHeader)
+	rawdb.WriteCanonicalHash(db, PlaceholderGenesisHeader.Hash(), 0)
+}
@@ -1,38 +1,51 @@
 package ethereum
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/params"
 )
 
-// ChainConfigs are supported chain configurations.
-var chainConfigs = map[string]*params.ChainConfig{
-	params.MainnetChainConfig.ChainID.String(): params.MainnetChainConfig,
-	params.SepoliaChainConfig.ChainID.String(): params.SepoliaChainConfig,
-	params.HoleskyChainConfig.ChainID.String(): params.HoleskyChainConfig,
+// GetChainConfig returns the chain config of a chain registered in the DefaultRegistry
+func GetChainConfig(chainID *big.Int) (*params.ChainConfig, error) {
+	return DefaultRegistry.ChainConfig(chainID)
 }
 
-func GetChainConfig(chainID *big.Int) (*params.ChainConfig, error) {
-	cfg, ok := chainConfigs[chainID.String()]
-	if !ok {
-		return nil, fmt.Errorf("unsupported chain: %q", chainID.String())
-	}
-	return cfg, nil
+// GetDefaultGenesis returns the genesis of a chain registered in the DefaultRegistry
+func GetDefaultGenesis(chainID *big.Int) (*core.Genesis, error) {
+	return DefaultRegistry.Genesis(chainID)
 }
 
-var defaultGenesis = map[string]*core.Genesis{
-	params.MainnetChainConfig.ChainID.String(): core.DefaultGenesisBlock(),
-	params.SepoliaChainConfig.ChainID.String(): core.DefaultSepoliaGenesisBlock(),
-	params.HoleskyChainConfig.ChainID.String(): core.DefaultHoleskyGenesisBlock(),
+// Register registers a network in the DefaultRegistry
+func Register(n *Network) error {
+	return DefaultRegistry.Register(n)
 }
 
-func GetDefaultGenesis(chainID *big.Int) (*core.Genesis, error) {
-	genesis, ok := defaultGenesis[chainID.String()]
-	if !ok {
-		return nil, fmt.Errorf("unsupported chain: %q", chainID.String())
+// RegisterGenesis registers a custom chain from its genesis (chain config and alloc) in the DefaultRegistry
+//
+// If a chain with the same chain ID is already registered, it is overridden (e.g. for shadow forks).
+func RegisterGenesis(genesis *core.Genesis) error {
+	if genesis.Config == nil || genesis.Config.ChainID == nil {
+		return fmt.Errorf("genesis is missing chain config or chain ID")
+	}
+
+	return Register(NetworkFromGenesis("", genesis))
+}
+
+// ReadGenesis decodes a genesis JSON file (as used by geth init)
+func ReadGenesis(r io.Reader) (*core.Genesis, error) {
+	genesis := new(core.Genesis)
+	if err := json.NewDecoder(r).Decode(genesis); err != nil {
+		return nil, fmt.Errorf("failed to decode genesis: %w", err)
 	}
+
+	if genesis.Config == nil || genesis.Config.ChainID == nil {
+		return nil, fmt.Errorf("genesis is missing chain config or chain ID")
+	}
+
 	return genesis, nil
 }
@@ -2,9 +2,15 @@ package ethereum
 
 import (
 	"math/big"
+	"strings"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	gethstate "github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/ethereum/go-ethereum/triedb/hashdb"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestChainConfigAndGenesis(t *testing.T) {
@@ -24,6 +30,10 @@ func TestChainConfigAndGenesis(t *testing.T) {
 			desc:    "holesky",
 			chainID: big.NewInt(17000),
 		},
+		{
+			desc:    "hoodi",
+			chainID: big.NewInt(560048),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -38,3 +48,59 @@ func TestChainConfigAndGenesis(t *testing.T) {
 		})
 	}
 }
+
+const testGenesis = `{
+	"config": {
+		"chainId": 1337,
+		"homesteadBlock": 0,
+		"eip150Block": 0,
+		"eip155Block": 0,
+		"eip158Block": 0,
+		"byzantiumBlock": 0,
+		"constantinopleBlock": 0,
+		"petersburgBlock": 0,
+		"istanbulBlock": 0,
+		"berlinBlock": 0,
+		"londonBlock": 0,
+		"mergeNetsplitBlock": 0,
+		"shanghaiTime": 0,
+		"cancunTime": 0,
+		"terminalTotalDifficulty": 0,
+		"blobSchedule": {
+			"cancun": {"target": 3, "max": 6, "baseFeeUpdateFraction": 3338477}
+		}
+	},
+	"difficulty": "0x0",
+	"gasLimit": "0x1c9c380",
+	"alloc": {
+		"0x0000000000000000000000000000000000000001": {"balance": "0xde0b6b3a7640000"}
+	}
+}`
+
+func TestRegisterGenesis(t *testing.T) {
+	chainID := big.NewInt(1337)
+
+	_, err := GetChainConfig(chainID)
+	require.Error(t, err)
+
+	genesis, err := ReadGenesis(strings.NewReader(testGenesis))
+	require.NoError(t, err)
+	require.NoError(t, RegisterGenesis(genesis))
+
+	cfg, err := GetChainConfig(chainID)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), *cfg.CancunTime)
+
+	gen, err := GetDefaultGenesis(chainID)
+	require.NoError(t, err)
+	assert.Len(t, gen.Alloc, 1)
+
+	trieDB := triedb.NewDatabase(rawdb.NewMemoryDatabase(), &triedb.Config{HashDB: &hashdb.Config{}})
+	_, err = NewChain(cfg, gethstate.NewDatabase(trieDB, nil))
+	assert.NoError(t, err)
+}
+
+func TestReadGenesisMissingChainID(t *testing.T) {
+	_, err := ReadGenesis(strings.NewReader(`{"alloc": {}}`))
+	assert.Error(t, err)
+}
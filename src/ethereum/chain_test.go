@@ -4,11 +4,15 @@ import (
 	"math/big"
 	"testing"
 
+	gethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	gethstate "github.com/ethereum/go-ethereum/core/state"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/triedb"
 	"github.com/ethereum/go-ethereum/triedb/hashdb"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewChain(t *testing.T) {
@@ -19,6 +23,7 @@ func TestNewChain(t *testing.T) {
 		{desc: "mainnet", chainID: big.NewInt(1)},
 		{desc: "sepolia", chainID: big.NewInt(11155111)},
 		{desc: "holesky", chainID: big.NewInt(17000)},
+		{desc: "hoodi", chainID: big.NewInt(560048)},
 	}
 
 	for _, tc := range testCases {
@@ -32,3 +37,20 @@ func TestNewChain(t *testing.T) {
 		})
 	}
 }
+
+func TestNewChainWithoutGenesis(t *testing.T) {
+	cfg := &params.ChainConfig{ChainID: big.NewInt(123456), LondonBlock: big.NewInt(0), TerminalTotalDifficulty: big.NewInt(0), Ethash: new(params.EthashConfig)}
+
+	parent := &gethtypes.Header{Number: big.NewInt(99), Difficulty: big.NewInt(0), Root: gethcommon.Hash{0x1}}
+	trieDB := triedb.NewDatabase(rawdb.NewMemoryDatabase(), &triedb.Config{HashDB: &hashdb.Config{}})
+	WriteHeaders(trieDB.Disk(), parent)
+
+	hc, err := NewChain(cfg, gethstate.NewDatabase(trieDB, nil))
+	require.NoError(t, err)
+	assert.Equal(t, parent.Hash(), hc.GetHeader(parent.Hash(), 99).Hash())
+	assert.Equal(t, PlaceholderGenesisHeader.Hash(), hc.GetHeaderByNumber(0).Hash())
+
+	// Creating a chain on a database which already has a genesis keeps it
+	_, err = NewChain(cfg, gethstate.NewDatabase(trieDB, nil))
+	require.NoError(t, err)
+}
@@ -42,7 +42,7 @@ func decodeHeaderNumberAndHash(key []byte) (uint64, gethcommon.Hash, bool) {
 
 // Get retrieves the value for a key.
 // It intercepts the key to check if it is a header key.
-// - If the key is a header key, it fetches the header from the remote RPC server.
+// - If the key is a header key missing from the underlying ethdb.Database, it fetches the header from the remote RPC server.
 // - Otherwise, it calls the underlying ethdb.Database.Get method.
 func (db *Database) Get(key []byte) ([]byte, error) {
 	// Decode the header number and hash from the key
@@ -51,6 +51,10 @@ func (db *Database) Get(key []byte) ([]byte, error) {
 		return db.Database.Get(key)
 	}
 
+	if b, err := db.Database.Get(key); err == nil {
+		return b, nil
+	}
+
 	// Fetch the header from the remote RPC server
 	// Note: We use the context.TODO() because the ethdb.Database.Get method does not accept a context.
 	header, err := db.remote.HeaderByHash(db.ctx, hash)
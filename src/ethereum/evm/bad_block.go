@@ -0,0 +1,71 @@
+package evm
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"runtime"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	gethparams "github.com/ethereum/go-ethereum/params"
+	gethversion "github.com/ethereum/go-ethereum/version"
+)
+
+// GethVersion is the version of go-ethereum used for block execution
+var GethVersion = fmt.Sprintf("%d.%d.%d-%s", gethversion.Major, gethversion.Minor, gethversion.Patch, gethversion.Meta)
+
+// BadBlockReport is a structured report of a block which processing or validation failed.
+// It contains the necessary data to triage the failure after the fact.
+type BadBlockReport struct {
+	ChainID      *big.Int           `json:"chainId"`
+	Header       *types.Header      `json:"header"`
+	Transactions types.Transactions `json:"transactions"`
+	Error        string             `json:"error"`
+	Receipts     types.Receipts     `json:"receipts"`    // Receipts of the block if processing succeeded and validation failed (nil if processing failed)
+	Version      string             `json:"version"`     // zk-pig version (set by the reporter)
+	GethVersion  string             `json:"gethVersion"` // go-ethereum version
+	GoVersion    string             `json:"goVersion"`   // Go runtime version
+	Platform     string             `json:"platform"`    // OS and architecture
+}
+
+// NewBadBlockReport creates a BadBlockReport for a block which processing or validation failed with err
+func NewBadBlockReport(chainCfg *gethparams.ChainConfig, block *types.Block, res *core.ProcessResult, err error) *BadBlockReport {
+	report := &BadBlockReport{
+		Header:       block.Header(),
+		Transactions: block.Transactions(),
+		GethVersion:  GethVersion,
+		GoVersion:    runtime.Version(),
+		Platform:     fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
+	}
+
+	if chainCfg != nil {
+		report.ChainID = chainCfg.ChainID
+	}
+
+	if err != nil {
+		report.Error = err.Error()
+	}
+
+	if res != nil {
+		report.Receipts = res.Receipts
+	}
+
+	return report
+}
+
+// WithBadBlockReporter is an executor decorator that reports bad blocks to the given function
+// It only applies to executions which parameters do not already set a Reporter
+func WithBadBlockReporter(report func(ctx context.Context, r *BadBlockReport)) ExecutorDecorator {
+	return func(executor Executor) Executor {
+		return ExecutorFunc(func(ctx context.Context, params *ExecParams) (*core.ProcessResult, error) {
+			if params.Reporter == nil {
+				params.Reporter = func(r *BadBlockReport) {
+					report(ctx, r)
+				}
+			}
+
+			return executor.Execute(ctx, params)
+		})
+	}
+}
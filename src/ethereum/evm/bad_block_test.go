@@ -0,0 +1,55 @@
+package evm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"runtime"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBadBlockReport(t *testing.T) {
+	block := gethtypes.NewBlockWithHeader(&gethtypes.Header{Number: big.NewInt(10), Difficulty: big.NewInt(0)})
+	res := &core.ProcessResult{Receipts: gethtypes.Receipts{{Status: gethtypes.ReceiptStatusSuccessful, Logs: []*gethtypes.Log{}}}}
+
+	report := NewBadBlockReport(&params.ChainConfig{ChainID: big.NewInt(1)}, block, res, errors.New("invalid gas used"))
+	assert.Equal(t, big.NewInt(1), report.ChainID)
+	assert.Equal(t, block.Hash(), report.Header.Hash())
+	assert.Equal(t, "invalid gas used", report.Error)
+	assert.Equal(t, res.Receipts, report.Receipts)
+	assert.Equal(t, GethVersion, report.GethVersion)
+	assert.Equal(t, runtime.Version(), report.GoVersion)
+
+	b, err := json.Marshal(report)
+	require.NoError(t, err)
+
+	decoded := new(BadBlockReport)
+	require.NoError(t, json.Unmarshal(b, decoded))
+	assert.Equal(t, report.Error, decoded.Error)
+	assert.Equal(t, report.Header.Hash(), decoded.Header.Hash())
+}
+
+func TestWithBadBlockReporter(t *testing.T) {
+	block := gethtypes.NewBlockWithHeader(&gethtypes.Header{Number: big.NewInt(10)})
+	var executor Executor = ExecutorFunc(func(_ context.Context, params *ExecParams) (*core.ProcessResult, error) {
+		params.Reporter(NewBadBlockReport(nil, params.Block, nil, errors.New("test error")))
+		return nil, errors.New("test error")
+	})
+
+	var reports []*BadBlockReport
+	executor = WithBadBlockReporter(func(_ context.Context, r *BadBlockReport) {
+		reports = append(reports, r)
+	})(executor)
+
+	_, err := executor.Execute(context.TODO(), &ExecParams{Block: block})
+	require.Error(t, err)
+	require.Len(t, reports, 1)
+	assert.Equal(t, "test error", reports[0].Error)
+}
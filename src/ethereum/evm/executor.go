@@ -3,7 +3,6 @@ package evm
 import (
 	"context"
 	"fmt"
-	"runtime"
 
 	"github.com/ethereum/go-ethereum/core"
 	gethstate "github.com/ethereum/go-ethereum/core/state"
@@ -11,18 +10,25 @@ import (
 	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
-	gethparams "github.com/ethereum/go-ethereum/params"
 )
 
 // ExecParams are the parameters for an EVM execution.
 type ExecParams struct {
-	VMConfig *vm.Config // VM configuration
-	Block    *types.Block
-	Validate bool // Whether to the validate the block at the end of execution
-	Commit   bool // Whether to commit the state changes
-	State    *gethstate.StateDB
-	Chain    *core.HeaderChain
-	Reporter func(error)
+	VMConfig  *vm.Config // VM configuration
+	Block     *types.Block
+	Validate  bool // Whether to the validate the block at the end of execution
+	Commit    bool // Whether to commit the state changes
+	State     *gethstate.StateDB
+	Chain     *core.HeaderChain
+	Processor Processor             // Block processor (defaults to Ethereum L1 rules)
+	Reporter  func(*BadBlockReport) // Called with a report if block processing or validation fails
+}
+
+func (params *ExecParams) processor() Processor {
+	if params.Processor != nil {
+		return params.Processor
+	}
+	return NewEthereumProcessor()
 }
 
 // Executor is an interface for executing EVM blocks.
@@ -102,12 +108,10 @@ func (e *executor) Execute(ctx context.Context, params *ExecParams) (res *core.P
 }
 
 func (e *executor) processBlock(_ context.Context, params *ExecParams) (*core.ProcessResult, error) {
-	processor := core.NewStateProcessor(params.Chain.Config(), params.Chain)
-
-	res, err := processor.Process(params.Block, params.State, *params.VMConfig)
+	res, err := params.processor().Process(params)
 	if err != nil {
 		if params.Reporter != nil {
-			params.Reporter(summarizeBadBlockError(params.Chain.Config(), params.Block, res, err))
+			params.Reporter(NewBadBlockReport(params.Chain.Config(), params.Block, res, err))
 		}
 		return nil, fmt.Errorf("block processing failed: %v", err)
 	}
@@ -115,43 +119,12 @@ func (e *executor) processBlock(_ context.Context, params *ExecParams) (*core.Pr
 }
 
 func (e *executor) validateBlock(_ context.Context, params *ExecParams, res *core.ProcessResult) error {
-	validator := core.NewBlockValidator(params.Chain.Config(), nil)
-	err := validator.ValidateState(params.Block, params.State, res, false)
-	if params.Reporter != nil {
-		params.Reporter(summarizeBadBlockError(params.Chain.Config(), params.Block, res, err))
-	}
+	err := params.processor().Validate(params, res)
 	if err != nil {
+		if params.Reporter != nil {
+			params.Reporter(NewBadBlockReport(params.Chain.Config(), params.Block, res, err))
+		}
 		return fmt.Errorf("block validation failed: %v", err)
 	}
 	return nil
 }
-
-// summarizeBadBlockError generates a human-readable summary of a bad block.
-func summarizeBadBlockError(chainCfg *gethparams.ChainConfig, block *types.Block, res *core.ProcessResult, err error) error {
-	var receipts types.Receipts
-	if res != nil {
-		receipts = res.Receipts
-	}
-
-	var receiptString string
-	for i, receipt := range receipts {
-		receiptString += fmt.Sprintf("\n  %d: cumulative: %v gas: %v contract: %v status: %v tx: %v logs: %v bloom: %x state: %x",
-			i, receipt.CumulativeGasUsed, receipt.GasUsed, receipt.ContractAddress.Hex(),
-			receipt.Status, receipt.TxHash.Hex(), receipt.Logs, receipt.Bloom, receipt.PostState)
-	}
-
-	version := ""
-	vcs := ""
-	platform := fmt.Sprintf("%s %s %s %s", version, runtime.Version(), runtime.GOARCH, runtime.GOOS)
-	if vcs != "" {
-		vcs = fmt.Sprintf("\nVCS: %s", vcs)
-	}
-
-	return fmt.Errorf(`########## BAD BLOCK #########
-Block: %v (%#x)
-Error: %v
-Chain config: %#v
-Platform: %v%v
-Receipts: %v
-##############################`, block.Number(), block.Hash(), err, platform, vcs, chainCfg, receiptString)
-}
@@ -46,8 +46,8 @@ func WithLog() ExecutorDecorator {
 		return ExecutorFunc(func(ctx context.Context, params *ExecParams) (*core.ProcessResult, error) {
 			logger := log.LoggerFromContext(ctx)
 
-			// Set tracing logger
-			params.VMConfig.Tracer = NewLoggerTracer(logger).Hooks()
+			// Set tracing logger (composing with the tracer already set, if any)
+			params.VMConfig.Tracer = MuxHooks(params.VMConfig.Tracer, NewLoggerTracer(logger).Hooks())
 
 			logger.Debug("Execute block...")
 			res, err := executor.Execute(ctx, params)
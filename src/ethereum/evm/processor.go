@@ -0,0 +1,31 @@
+package evm
+
+import (
+	"github.com/ethereum/go-ethereum/core"
+)
+
+// Processor processes and validates blocks according to the rules of an execution layer (e.g. Ethereum L1, OP Stack L2).
+type Processor interface {
+	// Process applies the block transactions on the given state
+	Process(params *ExecParams) (*core.ProcessResult, error)
+
+	// Validate validates the result of the block processing and the final state against the block header
+	Validate(params *ExecParams, res *core.ProcessResult) error
+}
+
+type ethereumProcessor struct{}
+
+// NewEthereumProcessor creates a Processor that follows Ethereum L1 rules.
+//
+// It is the Processor used when ExecParams do not provide one.
+func NewEthereumProcessor() Processor {
+	return &ethereumProcessor{}
+}
+
+func (p *ethereumProcessor) Process(params *ExecParams) (*core.ProcessResult, error) {
+	return core.NewStateProcessor(params.Chain.Config(), params.Chain).Process(params.Block, params.State, *params.VMConfig)
+}
+
+func (p *ethereumProcessor) Validate(params *ExecParams, res *core.ProcessResult) error {
+	return core.NewBlockValidator(params.Chain.Config(), nil).ValidateState(params.Block, params.State, res, false)
+}
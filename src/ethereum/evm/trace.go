@@ -0,0 +1,331 @@
+package evm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	gethvm "github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/eth/tracers/logger"
+	_ "github.com/ethereum/go-ethereum/eth/tracers/native" // register native tracers
+	gethparams "github.com/ethereum/go-ethereum/params"
+)
+
+// TraceMode is the kind of execution trace to record
+type TraceMode string
+
+const (
+	// TraceModeCall records the call tree of every transaction (as geth "callTracer")
+	TraceModeCall TraceMode = "call"
+	// TraceModeOpcode records the opcode struct logs of every transaction (as geth default struct logger)
+	TraceModeOpcode TraceMode = "opcode"
+)
+
+// ParseTraceMode parses a trace mode
+func ParseTraceMode(s string) (TraceMode, error) {
+	switch mode := TraceMode(s); mode {
+	case TraceModeCall, TraceModeOpcode:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid trace mode %q (expected %q or %q)", s, TraceModeCall, TraceModeOpcode)
+	}
+}
+
+// Trace is the execution trace of a block
+type Trace struct {
+	ChainID      *big.Int        `json:"chainId"`
+	BlockNumber  *big.Int        `json:"blockNumber"`
+	BlockHash    gethcommon.Hash `json:"blockHash"`
+	Timestamp    uint64          `json:"timestamp"`
+	Mode         TraceMode       `json:"mode"`
+	Transactions []*TxTrace      `json:"transactions"`
+	Error        string          `json:"error,omitempty"` // Error of the block execution (if any)
+}
+
+// TxTrace is the execution trace of a transaction
+// Result is formatted as the result of debug_traceTransaction with the corresponding tracer
+type TxTrace struct {
+	TxHash gethcommon.Hash `json:"txHash"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"` // Error of the tracer (if any)
+}
+
+// BlockTracer records the execution trace of every transaction of a block
+// It instantiates a fresh transaction tracer on every transaction start and collects its result on transaction end
+type BlockTracer struct {
+	mode     TraceMode
+	chainCfg *gethparams.ChainConfig
+	block    *gethtypes.Block
+
+	txIndex int
+	current *tracers.Tracer
+	trace   *Trace
+}
+
+// NewBlockTracer creates a new block tracer
+func NewBlockTracer(mode TraceMode, chainCfg *gethparams.ChainConfig, block *gethtypes.Block) *BlockTracer {
+	return &BlockTracer{
+		mode:     mode,
+		chainCfg: chainCfg,
+		block:    block,
+		trace: &Trace{
+			ChainID:      chainCfg.ChainID,
+			BlockNumber:  block.Number(),
+			BlockHash:    block.Hash(),
+			Timestamp:    block.Time(),
+			Mode:         mode,
+			Transactions: make([]*TxTrace, 0),
+		},
+	}
+}
+
+// Trace returns the trace recorded so far
+func (t *BlockTracer) Trace() *Trace {
+	return t.trace
+}
+
+func (t *BlockTracer) newTxTracer(tx *gethtypes.Transaction) (*tracers.Tracer, error) {
+	switch t.mode {
+	case TraceModeCall:
+		return tracers.DefaultDirectory.New(
+			"callTracer",
+			&tracers.Context{
+				BlockHash:   t.block.Hash(),
+				BlockNumber: t.block.Number(),
+				TxIndex:     t.txIndex,
+				TxHash:      tx.Hash(),
+			},
+			nil,
+			t.chainCfg,
+		)
+	case TraceModeOpcode:
+		l := logger.NewStructLogger(nil)
+		return &tracers.Tracer{
+			Hooks:     l.Hooks(),
+			GetResult: l.GetResult,
+			Stop:      l.Stop,
+		}, nil
+	default:
+		return nil, fmt.Errorf("invalid trace mode %q", t.mode)
+	}
+}
+
+// OnTxStart instantiates a tracer for the transaction
+func (t *BlockTracer) OnTxStart(vm *tracing.VMContext, tx *gethtypes.Transaction, from gethcommon.Address) {
+	txTrace := &TxTrace{TxHash: tx.Hash()}
+	t.trace.Transactions = append(t.trace.Transactions, txTrace)
+
+	tracer, err := t.newTxTracer(tx)
+	if err != nil {
+		txTrace.Error = err.Error()
+		return
+	}
+	t.current = tracer
+
+	if t.current.OnTxStart != nil {
+		t.current.OnTxStart(vm, tx, from)
+	}
+}
+
+// OnTxEnd collects the result of the transaction tracer
+func (t *BlockTracer) OnTxEnd(receipt *gethtypes.Receipt, err error) {
+	defer func() {
+		t.current = nil
+		t.txIndex++
+	}()
+
+	// Transactions that are not go-ethereum transactions (e.g. OP Stack deposits) are identified by their receipt
+	txTrace := t.trace.Transactions[len(t.trace.Transactions)-1]
+	if receipt != nil {
+		txTrace.TxHash = receipt.TxHash
+	}
+
+	if t.current == nil {
+		return
+	}
+
+	if t.current.OnTxEnd != nil {
+		t.current.OnTxEnd(receipt, err)
+	}
+
+	res, resErr := t.current.GetResult()
+	if resErr != nil {
+		txTrace.Error = resErr.Error()
+		return
+	}
+	txTrace.Result = res
+}
+
+// OnEnter forwards to the transaction tracer
+func (t *BlockTracer) OnEnter(depth int, typ byte, from, to gethcommon.Address, input []byte, gas uint64, value *big.Int) {
+	if t.current != nil && t.current.OnEnter != nil {
+		t.current.OnEnter(depth, typ, from, to, input, gas, value)
+	}
+}
+
+// OnExit forwards to the transaction tracer
+func (t *BlockTracer) OnExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	if t.current != nil && t.current.OnExit != nil {
+		t.current.OnExit(depth, output, gasUsed, err, reverted)
+	}
+}
+
+// OnOpcode forwards to the transaction tracer
+func (t *BlockTracer) OnOpcode(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error) {
+	if t.current != nil && t.current.OnOpcode != nil {
+		t.current.OnOpcode(pc, op, gas, cost, scope, rData, depth, err)
+	}
+}
+
+// OnFault forwards to the transaction tracer
+func (t *BlockTracer) OnFault(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, depth int, err error) {
+	if t.current != nil && t.current.OnFault != nil {
+		t.current.OnFault(pc, op, gas, cost, scope, depth, err)
+	}
+}
+
+// OnLog forwards to the transaction tracer
+func (t *BlockTracer) OnLog(l *gethtypes.Log) {
+	if t.current != nil && t.current.OnLog != nil {
+		t.current.OnLog(l)
+	}
+}
+
+// Hooks returns the block tracer hooks
+func (t *BlockTracer) Hooks() *tracing.Hooks {
+	return &tracing.Hooks{
+		OnTxStart: t.OnTxStart,
+		OnTxEnd:   t.OnTxEnd,
+		OnEnter:   t.OnEnter,
+		OnExit:    t.OnExit,
+		OnOpcode:  t.OnOpcode,
+		OnFault:   t.OnFault,
+		OnLog:     t.OnLog,
+	}
+}
+
+// WithTrace is an executor decorator that records the execution trace of the block in the given mode
+// and passes it to the given function once the block has been executed (including on failure)
+// It composes with the tracer already set on the VM config (if any)
+func WithTrace(mode TraceMode, report func(ctx context.Context, trace *Trace)) ExecutorDecorator {
+	return func(executor Executor) Executor {
+		return ExecutorFunc(func(ctx context.Context, params *ExecParams) (*core.ProcessResult, error) {
+			if params.VMConfig == nil {
+				params.VMConfig = &gethvm.Config{}
+			}
+
+			tracer := NewBlockTracer(mode, params.Chain.Config(), params.Block)
+			params.VMConfig.Tracer = MuxHooks(params.VMConfig.Tracer, tracer.Hooks())
+
+			res, err := executor.Execute(ctx, params)
+
+			trace := tracer.Trace()
+			if err != nil {
+				trace.Error = err.Error()
+			}
+			report(ctx, trace)
+
+			return res, err
+		})
+	}
+}
+
+// MuxHooks combines several tracing hooks into a single one calling each of them in order
+// It multiplexes block, transaction, call frame, opcode, log and system call events (nil hooks are ignored)
+func MuxHooks(hooks ...*tracing.Hooks) *tracing.Hooks {
+	var hs []*tracing.Hooks
+	for _, h := range hooks {
+		if h != nil {
+			hs = append(hs, h)
+		}
+	}
+	if len(hs) == 1 {
+		return hs[0]
+	}
+
+	return &tracing.Hooks{
+		OnBlockStart: func(event tracing.BlockEvent) {
+			for _, h := range hs {
+				if h.OnBlockStart != nil {
+					h.OnBlockStart(event)
+				}
+			}
+		},
+		OnBlockEnd: func(err error) {
+			for _, h := range hs {
+				if h.OnBlockEnd != nil {
+					h.OnBlockEnd(err)
+				}
+			}
+		},
+		OnTxStart: func(vm *tracing.VMContext, tx *gethtypes.Transaction, from gethcommon.Address) {
+			for _, h := range hs {
+				if h.OnTxStart != nil {
+					h.OnTxStart(vm, tx, from)
+				}
+			}
+		},
+		OnTxEnd: func(receipt *gethtypes.Receipt, err error) {
+			for _, h := range hs {
+				if h.OnTxEnd != nil {
+					h.OnTxEnd(receipt, err)
+				}
+			}
+		},
+		OnEnter: func(depth int, typ byte, from, to gethcommon.Address, input []byte, gas uint64, value *big.Int) {
+			for _, h := range hs {
+				if h.OnEnter != nil {
+					h.OnEnter(depth, typ, from, to, input, gas, value)
+				}
+			}
+		},
+		OnExit: func(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+			for _, h := range hs {
+				if h.OnExit != nil {
+					h.OnExit(depth, output, gasUsed, err, reverted)
+				}
+			}
+		},
+		OnOpcode: func(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error) {
+			for _, h := range hs {
+				if h.OnOpcode != nil {
+					h.OnOpcode(pc, op, gas, cost, scope, rData, depth, err)
+				}
+			}
+		},
+		OnFault: func(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, depth int, err error) {
+			for _, h := range hs {
+				if h.OnFault != nil {
+					h.OnFault(pc, op, gas, cost, scope, depth, err)
+				}
+			}
+		},
+		OnLog: func(l *gethtypes.Log) {
+			for _, h := range hs {
+				if h.OnLog != nil {
+					h.OnLog(l)
+				}
+			}
+		},
+		OnSystemCallStart: func() {
+			for _, h := range hs {
+				if h.OnSystemCallStart != nil {
+					h.OnSystemCallStart()
+				}
+			}
+		},
+		OnSystemCallEnd: func() {
+			for _, h := range hs {
+				if h.OnSystemCallEnd != nil {
+					h.OnSystemCallEnd()
+				}
+			}
+		},
+	}
+}
@@ -0,0 +1,175 @@
+package evm
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	gethstate "github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/holiman/uint256"
+	"github.com/kkrt-labs/go-utils/common"
+	"github.com/kkrt-labs/zk-pig/src/ethereum"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTraceMode(t *testing.T) {
+	mode, err := ParseTraceMode("call")
+	require.NoError(t, err)
+	assert.Equal(t, TraceModeCall, mode)
+
+	mode, err = ParseTraceMode("opcode")
+	require.NoError(t, err)
+	assert.Equal(t, TraceModeOpcode, mode)
+
+	_, err = ParseTraceMode("prestate")
+	require.Error(t, err)
+}
+
+func testTraceParams(t *testing.T) *ExecParams {
+	chainCfg := &params.ChainConfig{
+		ChainID:                 big.NewInt(1),
+		HomesteadBlock:          big.NewInt(0),
+		EIP150Block:             big.NewInt(0),
+		EIP155Block:             big.NewInt(0),
+		EIP158Block:             big.NewInt(0),
+		ByzantiumBlock:          big.NewInt(0),
+		ConstantinopleBlock:     big.NewInt(0),
+		PetersburgBlock:         big.NewInt(0),
+		IstanbulBlock:           big.NewInt(0),
+		MuirGlacierBlock:        big.NewInt(0),
+		BerlinBlock:             big.NewInt(0),
+		LondonBlock:             big.NewInt(0),
+		ArrowGlacierBlock:       big.NewInt(0),
+		GrayGlacierBlock:        big.NewInt(0),
+		MergeNetsplitBlock:      big.NewInt(0),
+		ShanghaiTime:            common.Ptr(uint64(0)),
+		TerminalTotalDifficulty: big.NewInt(0),
+		Ethash:                  new(params.EthashConfig),
+	}
+
+	stateDB := gethstate.NewDatabase(triedb.NewDatabase(rawdb.NewMemoryDatabase(), nil), nil)
+	preState, err := gethstate.New(gethtypes.EmptyRootHash, stateDB)
+	require.NoError(t, err)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	preState.AddBalance(crypto.PubkeyToAddress(key.PublicKey), uint256.NewInt(params.Ether), tracing.BalanceChangeUnspecified)
+
+	// Contract storing 1 at slot 0 (PUSH1 0x01 PUSH1 0x00 SSTORE STOP)
+	contract := gethcommon.Address{0xc}
+	preState.SetCode(contract, []byte{0x60, 0x01, 0x60, 0x00, 0x55, 0x00})
+
+	hc, err := ethereum.NewChain(chainCfg, stateDB)
+	require.NoError(t, err)
+
+	tx, err := gethtypes.SignNewTx(key, gethtypes.LatestSignerForChainID(chainCfg.ChainID), &gethtypes.DynamicFeeTx{
+		ChainID:   chainCfg.ChainID,
+		GasTipCap: big.NewInt(params.GWei),
+		GasFeeCap: big.NewInt(2 * params.GWei),
+		Gas:       100000,
+		To:        &contract,
+	})
+	require.NoError(t, err)
+
+	header := &gethtypes.Header{
+		ParentHash: hc.GetHeaderByNumber(0).Hash(),
+		Number:     big.NewInt(1),
+		GasLimit:   30_000_000,
+		Time:       10,
+		BaseFee:    big.NewInt(params.GWei),
+		Difficulty: new(big.Int),
+	}
+	block := gethtypes.NewBlock(header, &gethtypes.Body{Transactions: gethtypes.Transactions{tx}}, nil, trie.NewStackTrie(nil))
+
+	return &ExecParams{
+		VMConfig: &vm.Config{},
+		Block:    block,
+		State:    preState,
+		Chain:    hc,
+	}
+}
+
+func TestWithTrace(t *testing.T) {
+	testCases := []struct {
+		mode  TraceMode
+		check func(t *testing.T, res json.RawMessage)
+	}{
+		{
+			mode: TraceModeCall,
+			check: func(t *testing.T, res json.RawMessage) {
+				var frame struct {
+					Type string             `json:"type"`
+					To   gethcommon.Address `json:"to"`
+				}
+				require.NoError(t, json.Unmarshal(res, &frame))
+				assert.Equal(t, "CALL", frame.Type)
+				assert.Equal(t, gethcommon.Address{0xc}, frame.To)
+			},
+		},
+		{
+			mode: TraceModeOpcode,
+			check: func(t *testing.T, res json.RawMessage) {
+				var result struct {
+					Failed     bool `json:"failed"`
+					StructLogs []struct {
+						Op string `json:"op"`
+					} `json:"structLogs"`
+				}
+				require.NoError(t, json.Unmarshal(res, &result))
+				assert.False(t, result.Failed)
+				require.Len(t, result.StructLogs, 4)
+				assert.Equal(t, "SSTORE", result.StructLogs[2].Op)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.mode), func(t *testing.T) {
+			var traces []*Trace
+			executor := NewExecutor()
+			executor = WithTrace(tc.mode, func(_ context.Context, trace *Trace) {
+				traces = append(traces, trace)
+			})(executor)
+			executor = WithLog()(executor)
+
+			params := testTraceParams(t)
+			_, err := executor.Execute(context.TODO(), params)
+			require.NoError(t, err)
+
+			require.Len(t, traces, 1)
+			trace := traces[0]
+			assert.Equal(t, tc.mode, trace.Mode)
+			assert.Equal(t, big.NewInt(1), trace.ChainID)
+			assert.Equal(t, params.Block.Hash(), trace.BlockHash)
+			assert.Empty(t, trace.Error)
+			require.Len(t, trace.Transactions, 1)
+			assert.Equal(t, params.Block.Transactions()[0].Hash(), trace.Transactions[0].TxHash)
+			assert.Empty(t, trace.Transactions[0].Error)
+			tc.check(t, trace.Transactions[0].Result)
+		})
+	}
+}
+
+func TestBlockTracerTxHashFromReceipt(t *testing.T) {
+	params := testTraceParams(t)
+	tracer := NewBlockTracer(TraceModeOpcode, params.Chain.Config(), params.Block)
+
+	// Transactions that are not go-ethereum transactions (e.g. OP Stack deposits) are traced with a stand-in transaction
+	standIn := gethtypes.NewTx(&gethtypes.LegacyTx{Gas: 21000, To: &gethcommon.Address{0xc}, Value: new(big.Int)})
+	tracer.OnTxStart(&tracing.VMContext{BlockNumber: big.NewInt(1)}, standIn, gethcommon.Address{0xd})
+	tracer.OnTxEnd(&gethtypes.Receipt{TxHash: gethcommon.Hash{0x7e}}, nil)
+
+	require.Len(t, tracer.Trace().Transactions, 1)
+	assert.Equal(t, gethcommon.Hash{0x7e}, tracer.Trace().Transactions[0].TxHash)
+}
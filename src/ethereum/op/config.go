@@ -0,0 +1,106 @@
+package op
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sync"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// OP Stack predeploys used during block execution
+var (
+	L1BlockAddress      = gethcommon.HexToAddress("0x4200000000000000000000000000000000000015")
+	BaseFeeVaultAddress = gethcommon.HexToAddress("0x4200000000000000000000000000000000000019")
+	L1FeeVaultAddress   = gethcommon.HexToAddress("0x420000000000000000000000000000000000001a")
+
+	OperatorFeeVaultAddress = gethcommon.HexToAddress("0x420000000000000000000000000000000000001b")
+)
+
+// Config is the OP Stack specific configuration of a chain (it complements the params.ChainConfig of the chain)
+//
+// Only the hardforks that impact block execution are listed.
+// Hardforks after Isthmus are not supported, they are listed so their blocks are rejected rather than executed with Isthmus rules.
+type Config struct {
+	RegolithTime *uint64 `json:"regolithTime,omitempty"`
+	CanyonTime   *uint64 `json:"canyonTime,omitempty"`
+	EcotoneTime  *uint64 `json:"ecotoneTime,omitempty"`
+	FjordTime    *uint64 `json:"fjordTime,omitempty"`
+	GraniteTime  *uint64 `json:"graniteTime,omitempty"`
+	HoloceneTime *uint64 `json:"holoceneTime,omitempty"`
+	IsthmusTime  *uint64 `json:"isthmusTime,omitempty"`
+	JovianTime   *uint64 `json:"jovianTime,omitempty"`
+}
+
+func isTimestampForked(fork *uint64, time uint64) bool {
+	return fork != nil && *fork <= time
+}
+
+// IsRegolith returns whether time is either equal to the Regolith fork time or greater
+func (c *Config) IsRegolith(time uint64) bool { return isTimestampForked(c.RegolithTime, time) }
+
+// IsCanyon returns whether time is either equal to the Canyon fork time or greater
+func (c *Config) IsCanyon(time uint64) bool { return isTimestampForked(c.CanyonTime, time) }
+
+// IsEcotone returns whether time is either equal to the Ecotone fork time or greater
+func (c *Config) IsEcotone(time uint64) bool { return isTimestampForked(c.EcotoneTime, time) }
+
+// IsFjord returns whether time is either equal to the Fjord fork time or greater
+func (c *Config) IsFjord(time uint64) bool { return isTimestampForked(c.FjordTime, time) }
+
+// IsGranite returns whether time is either equal to the Granite fork time or greater
+func (c *Config) IsGranite(time uint64) bool { return isTimestampForked(c.GraniteTime, time) }
+
+// IsHolocene returns whether time is either equal to the Holocene fork time or greater
+func (c *Config) IsHolocene(time uint64) bool { return isTimestampForked(c.HoloceneTime, time) }
+
+// IsIsthmus returns whether time is either equal to the Isthmus fork time or greater
+func (c *Config) IsIsthmus(time uint64) bool { return isTimestampForked(c.IsthmusTime, time) }
+
+// IsJovian returns whether time is either equal to the Jovian fork time or greater
+func (c *Config) IsJovian(time uint64) bool { return isTimestampForked(c.JovianTime, time) }
+
+// ReadGenesisConfig reads the OP Stack config of a genesis JSON file in the op-geth format (i.e. with OP Stack fork times and an "optimism" field in its config)
+//
+// It returns nil if the genesis is not the one of an OP Stack chain.
+func ReadGenesisConfig(genesis []byte) (*Config, error) {
+	var g struct {
+		Config *struct {
+			Config
+			Optimism json.RawMessage `json:"optimism"`
+		} `json:"config"`
+	}
+	if err := json.Unmarshal(genesis, &g); err != nil {
+		return nil, fmt.Errorf("failed to decode OP Stack config: %w", err)
+	}
+
+	if g.Config == nil || g.Config.Optimism == nil {
+		return nil, nil
+	}
+
+	return &g.Config.Config, nil
+}
+
+var (
+	configsMux sync.RWMutex
+	configs    = make(map[string]*Config)
+)
+
+// Register registers the OP Stack config of a chain
+//
+// If a config is already registered for the chain ID, it is overridden.
+func Register(chainID *big.Int, cfg *Config) {
+	configsMux.Lock()
+	defer configsMux.Unlock()
+	configs[chainID.String()] = cfg
+}
+
+// GetConfig returns the OP Stack config registered for a chain
+//
+// It returns nil if the chain is not an OP Stack chain.
+func GetConfig(chainID *big.Int) *Config {
+	configsMux.RLock()
+	defer configsMux.RUnlock()
+	return configs[chainID.String()]
+}
@@ -0,0 +1,35 @@
+package op
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadGenesisConfig(t *testing.T) {
+	cfg, err := ReadGenesisConfig([]byte(`{"config": {"chainId": 10, "regolithTime": 0, "canyonTime": 10, "optimism": {"eip1559Elasticity": 6, "eip1559Denominator": 50}}}`))
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+	assert.True(t, cfg.IsRegolith(0))
+	assert.False(t, cfg.IsCanyon(9))
+	assert.True(t, cfg.IsCanyon(10))
+	assert.Nil(t, cfg.EcotoneTime)
+
+	// Not an OP Stack chain
+	cfg, err = ReadGenesisConfig([]byte(`{"config": {"chainId": 1}}`))
+	require.NoError(t, err)
+	assert.Nil(t, cfg)
+
+	_, err = ReadGenesisConfig([]byte(`{"config": 1}`))
+	assert.Error(t, err)
+}
+
+func TestRegister(t *testing.T) {
+	assert.Nil(t, GetConfig(big.NewInt(901)))
+
+	cfg := new(Config)
+	Register(big.NewInt(901), cfg)
+	assert.Equal(t, cfg, GetConfig(big.NewInt(901)))
+}
@@ -0,0 +1,163 @@
+package op
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// DepositTxType is the EIP-2718 type of OP Stack deposit transactions
+const DepositTxType = 0x7e
+
+// DepositTx is an OP Stack deposit transaction (L1 to L2 message, including the L1 info system transaction)
+//
+// Deposit transactions are not signed, they are always placed first in a block and they do not pay L2 fees.
+type DepositTx struct {
+	SourceHash          gethcommon.Hash     // Uniquely identifies the source of the deposit
+	From                gethcommon.Address  // Exposed through the signature-less transaction
+	To                  *gethcommon.Address `rlp:"nil"` // Nil means contract creation
+	Mint                *big.Int            `rlp:"nil"` // Minted on L2, locked on L1 (nil if no minting)
+	Value               *big.Int            // Transferred from L2 balance, executed after Mint (if any)
+	Gas                 uint64              // Gas limit
+	IsSystemTransaction bool                // Field indicating if this transaction is exempt from the L2 gas limit
+	Data                []byte              // Normal Tx data
+}
+
+// MarshalBinary returns the EIP-2718 encoding of the transaction
+func (tx *DepositTx) MarshalBinary() ([]byte, error) {
+	b, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{DepositTxType}, b...), nil
+}
+
+// UnmarshalBinary decodes the EIP-2718 encoding of the transaction
+func (tx *DepositTx) UnmarshalBinary(b []byte) error {
+	if len(b) == 0 || b[0] != DepositTxType {
+		return fmt.Errorf("not a deposit transaction")
+	}
+	if err := rlp.DecodeBytes(b[1:], tx); err != nil {
+		return err
+	}
+	if tx.Mint != nil && tx.Mint.Sign() == 0 {
+		// nil and zero mint have the same encoding
+		tx.Mint = nil
+	}
+	if tx.Value == nil {
+		tx.Value = new(big.Int)
+	}
+	return nil
+}
+
+// tracingTx returns the go-ethereum transaction passed to tracers on deposit start (tracing.Hooks.OnTxStart)
+//
+// Deposits can not be represented as go-ethereum transactions, so it is a legacy transaction with the nonce, recipient, value,
+// gas and data of the deposit. Its hash is not the deposit hash, which tracers get from the receipt on deposit end.
+func (tx *DepositTx) tracingTx(nonce uint64) *gethtypes.Transaction {
+	return gethtypes.NewTx(&gethtypes.LegacyTx{
+		Nonce:    nonce,
+		GasPrice: new(big.Int),
+		Gas:      tx.Gas,
+		To:       tx.To,
+		Value:    tx.Value,
+		Data:     tx.Data,
+	})
+}
+
+// Hash returns the transaction hash
+func (tx *DepositTx) Hash() gethcommon.Hash {
+	b, _ := tx.MarshalBinary()
+	return crypto.Keccak256Hash(b)
+}
+
+type depositTxJSON struct {
+	Type       hexutil.Uint64      `json:"type"`
+	SourceHash *gethcommon.Hash    `json:"sourceHash"`
+	From       *gethcommon.Address `json:"from"`
+	To         *gethcommon.Address `json:"to"`
+	Mint       *hexutil.Big        `json:"mint"`
+	Value      *hexutil.Big        `json:"value"`
+	Gas        *hexutil.Uint64     `json:"gas"`
+	IsSystemTx bool                `json:"isSystemTx"`
+	Input      *hexutil.Bytes      `json:"input"`
+	Hash       *gethcommon.Hash    `json:"hash,omitempty"`
+}
+
+// MarshalJSON encodes the transaction in the format of OP Stack JSON-RPC nodes
+func (tx *DepositTx) MarshalJSON() ([]byte, error) {
+	hash := tx.Hash()
+	gas := hexutil.Uint64(tx.Gas)
+	input := hexutil.Bytes(tx.Data)
+	if input == nil {
+		input = hexutil.Bytes{}
+	}
+	value := tx.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	return json.Marshal(&depositTxJSON{
+		Type:       DepositTxType,
+		SourceHash: &tx.SourceHash,
+		From:       &tx.From,
+		To:         tx.To,
+		Mint:       (*hexutil.Big)(tx.Mint),
+		Value:      (*hexutil.Big)(value),
+		Gas:        &gas,
+		IsSystemTx: tx.IsSystemTransaction,
+		Input:      &input,
+		Hash:       &hash,
+	})
+}
+
+// UnmarshalJSON decodes the transaction from the format of OP Stack JSON-RPC nodes
+func (tx *DepositTx) UnmarshalJSON(b []byte) error {
+	var dec depositTxJSON
+	if err := json.Unmarshal(b, &dec); err != nil {
+		return err
+	}
+
+	if dec.Type != DepositTxType {
+		return fmt.Errorf("not a deposit transaction (type %v)", dec.Type)
+	}
+	if dec.SourceHash == nil {
+		return errors.New("missing required field 'sourceHash' in deposit transaction")
+	}
+	if dec.From == nil {
+		return errors.New("missing required field 'from' in deposit transaction")
+	}
+	if dec.Gas == nil {
+		return errors.New("missing required field 'gas' in deposit transaction")
+	}
+	if dec.Input == nil {
+		return errors.New("missing required field 'input' in deposit transaction")
+	}
+
+	*tx = DepositTx{
+		SourceHash:          *dec.SourceHash,
+		From:                *dec.From,
+		To:                  dec.To,
+		Mint:                (*big.Int)(dec.Mint),
+		Value:               new(big.Int),
+		Gas:                 uint64(*dec.Gas),
+		IsSystemTransaction: dec.IsSystemTx,
+		Data:                *dec.Input,
+	}
+	if dec.Value != nil {
+		tx.Value = (*big.Int)(dec.Value)
+	}
+
+	if dec.Hash != nil && *dec.Hash != tx.Hash() {
+		return fmt.Errorf("deposit transaction hash mismatch (expected %v, got %v)", dec.Hash.Hex(), tx.Hash().Hex())
+	}
+
+	return nil
+}
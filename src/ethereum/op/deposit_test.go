@@ -0,0 +1,88 @@
+package op
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDepositTx(t *testing.T) {
+	var testCases = []struct {
+		desc string
+		tx   *DepositTx
+	}{
+		{
+			desc: "call with mint",
+			tx: &DepositTx{
+				SourceHash:          gethcommon.Hash{0x1},
+				From:                gethcommon.Address{0x2},
+				To:                  &gethcommon.Address{0x3},
+				Mint:                big.NewInt(1000),
+				Value:               big.NewInt(10),
+				Gas:                 100000,
+				IsSystemTransaction: true,
+				Data:                []byte{0x4, 0x5},
+			},
+		},
+		{
+			desc: "creation without mint",
+			tx: &DepositTx{
+				SourceHash: gethcommon.Hash{0x1},
+				From:       gethcommon.Address{0x2},
+				Value:      big.NewInt(1),
+				Gas:        100000,
+				Data:       []byte{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			b, err := tc.tx.MarshalBinary()
+			require.NoError(t, err)
+			assert.Equal(t, byte(DepositTxType), b[0])
+
+			decoded := new(DepositTx)
+			require.NoError(t, decoded.UnmarshalBinary(b))
+			assert.Equal(t, tc.tx, decoded)
+
+			j, err := json.Marshal(tc.tx)
+			require.NoError(t, err)
+
+			decoded = new(DepositTx)
+			require.NoError(t, json.Unmarshal(j, decoded))
+			assert.Equal(t, tc.tx, decoded)
+			assert.Equal(t, tc.tx.Hash(), decoded.Hash())
+		})
+	}
+}
+
+func TestDepositTxUnmarshalJSONInvalid(t *testing.T) {
+	var testCases = []struct {
+		desc string
+		data string
+	}{
+		{
+			desc: "not a deposit",
+			data: `{"type":"0x2","sourceHash":"0x0000000000000000000000000000000000000000000000000000000000000001","from":"0x0000000000000000000000000000000000000002","gas":"0x1","input":"0x"}`,
+		},
+		{
+			desc: "missing source hash",
+			data: `{"type":"0x7e","from":"0x0000000000000000000000000000000000000002","gas":"0x1","input":"0x"}`,
+		},
+		{
+			desc: "hash mismatch",
+			data: `{"type":"0x7e","sourceHash":"0x0000000000000000000000000000000000000000000000000000000000000001","from":"0x0000000000000000000000000000000000000002","gas":"0x1","input":"0x","hash":"0x0000000000000000000000000000000000000000000000000000000000000001"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.Error(t, json.Unmarshal([]byte(tc.data), new(DepositTx)))
+		})
+	}
+}
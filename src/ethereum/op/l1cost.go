@@ -0,0 +1,245 @@
+package op
+
+import (
+	"bytes"
+	"math/big"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+)
+
+// L1Block predeploy storage slots
+var (
+	l1BaseFeeSlot     = gethcommon.BigToHash(big.NewInt(1))
+	l1FeeScalarsSlot  = gethcommon.BigToHash(big.NewInt(3)) // Ecotone: base fee scalar and blob base fee scalar
+	l1OverheadSlot    = gethcommon.BigToHash(big.NewInt(5)) // Bedrock
+	l1ScalarSlot      = gethcommon.BigToHash(big.NewInt(6)) // Bedrock
+	l1BlobBaseFeeSlot = gethcommon.BigToHash(big.NewInt(7)) // Ecotone
+
+	operatorFeeParamsSlot = gethcommon.BigToHash(big.NewInt(8)) // Isthmus: operator fee scalar and constant
+)
+
+const (
+	// Offsets of the Ecotone scalars in l1FeeScalarsSlot (counted from the end of the slot)
+	baseFeeScalarSlotOffset     = 12
+	blobBaseFeeScalarSlotOffset = 8
+	scalarSectionStart          = 32 - baseFeeScalarSlotOffset - 4
+
+	// Offsets of the Isthmus operator fee scalar (uint32) and constant (uint64) in operatorFeeParamsSlot
+	operatorFeeScalarStart   = 20
+	operatorFeeConstantStart = 24
+)
+
+var (
+	sixteen          = big.NewInt(16)
+	oneMillion       = big.NewInt(1_000_000)
+	ecotoneDivisor   = big.NewInt(16 * 1_000_000)
+	fjordDivisor     = big.NewInt(1_000_000_000_000)
+	l1CostIntercept  = big.NewInt(-42_585_600)
+	l1CostFastlzCoef = big.NewInt(836_500)
+	minTxSizeScaled  = big.NewInt(100 * 1_000_000)
+)
+
+// StorageReader reads contract storage (e.g. *state.StateDB)
+type StorageReader interface {
+	GetState(addr gethcommon.Address, slot gethcommon.Hash) gethcommon.Hash
+}
+
+// RollupCostData is the data of a transaction used to compute its L1 data fee
+type RollupCostData struct {
+	Zeroes, Ones uint64
+	FastLzSize   uint64
+}
+
+// NewRollupCostData computes the rollup cost data of an EIP-2718 encoded transaction
+func NewRollupCostData(data []byte) RollupCostData {
+	var out RollupCostData
+	for _, b := range data {
+		if b == 0 {
+			out.Zeroes++
+		} else {
+			out.Ones++
+		}
+	}
+	out.FastLzSize = uint64(FlzCompressLen(data))
+	return out
+}
+
+// L1CostFunc computes the L1 data fee of a transaction
+type L1CostFunc func(data RollupCostData) *big.Int
+
+// NewL1CostFunc returns the L1 cost function of a block
+//
+// It must be created after the L1 info deposit transaction of the block has been applied on the state,
+// as it reads the L1 fee parameters from the L1Block predeploy.
+func NewL1CostFunc(cfg *Config, time uint64, st StorageReader) L1CostFunc {
+	l1BaseFee := st.GetState(L1BlockAddress, l1BaseFeeSlot).Big()
+
+	if !cfg.IsEcotone(time) {
+		return newL1CostFuncBedrock(l1BaseFee, st.GetState(L1BlockAddress, l1OverheadSlot).Big(), st.GetState(L1BlockAddress, l1ScalarSlot).Big())
+	}
+
+	l1BlobBaseFee := st.GetState(L1BlockAddress, l1BlobBaseFeeSlot).Big()
+	l1FeeScalars := st.GetState(L1BlockAddress, l1FeeScalarsSlot).Bytes()
+
+	// On the Ecotone activation block, the L1 info deposit transaction still has the Bedrock format
+	// (so Ecotone parameters are not set yet), in which case the Bedrock cost function is used
+	if l1BlobBaseFee.BitLen() == 0 && bytes.Equal(make([]byte, 8), l1FeeScalars[scalarSectionStart:scalarSectionStart+8]) {
+		return newL1CostFuncBedrock(l1BaseFee, st.GetState(L1BlockAddress, l1OverheadSlot).Big(), st.GetState(L1BlockAddress, l1ScalarSlot).Big())
+	}
+
+	baseFeeScalar := new(big.Int).SetBytes(l1FeeScalars[scalarSectionStart : scalarSectionStart+4])
+	blobBaseFeeScalar := new(big.Int).SetBytes(l1FeeScalars[scalarSectionStart+4 : scalarSectionStart+8])
+
+	if cfg.IsFjord(time) {
+		return newL1CostFuncFjord(l1BaseFee, l1BlobBaseFee, baseFeeScalar, blobBaseFeeScalar)
+	}
+
+	return newL1CostFuncEcotone(l1BaseFee, l1BlobBaseFee, baseFeeScalar, blobBaseFeeScalar)
+}
+
+// OperatorCostFunc computes the operator fee of a transaction from its gas
+type OperatorCostFunc func(gas uint64) *uint256.Int
+
+// NewOperatorCostFunc returns the operator cost function of a block (zero before Isthmus)
+//
+// As NewL1CostFunc, it must be created after the L1 info deposit transaction of the block has been applied on the state.
+// fee = gas * operatorFeeScalar / 1e6 + operatorFeeConstant
+func NewOperatorCostFunc(cfg *Config, time uint64, st StorageReader) OperatorCostFunc {
+	if !cfg.IsIsthmus(time) {
+		return func(uint64) *uint256.Int { return new(uint256.Int) }
+	}
+
+	operatorFeeParams := st.GetState(L1BlockAddress, operatorFeeParamsSlot)
+	scalar := new(uint256.Int).SetBytes(operatorFeeParams[operatorFeeScalarStart:operatorFeeConstantStart])
+	constant := new(uint256.Int).SetBytes(operatorFeeParams[operatorFeeConstantStart:])
+
+	return func(gas uint64) *uint256.Int {
+		fee := new(uint256.Int).Mul(uint256.NewInt(gas), scalar)
+		fee.Div(fee, uint256.NewInt(1_000_000))
+		return fee.Add(fee, constant)
+	}
+}
+
+// calldataGas is the L1 gas consumed by the transaction calldata
+func (d RollupCostData) calldataGas() *big.Int {
+	return new(big.Int).SetUint64(d.Zeroes*4 + d.Ones*16)
+}
+
+// fee = (calldataGas + overhead) * l1BaseFee * scalar / 1e6
+func newL1CostFuncBedrock(l1BaseFee, overhead, scalar *big.Int) L1CostFunc {
+	return func(data RollupCostData) *big.Int {
+		fee := new(big.Int).Add(data.calldataGas(), overhead)
+		fee.Mul(fee, l1BaseFee)
+		fee.Mul(fee, scalar)
+		return fee.Div(fee, oneMillion)
+	}
+}
+
+// fee = calldataGas * (16 * l1BaseFee * baseFeeScalar + l1BlobBaseFee * blobBaseFeeScalar) / 16e6
+func newL1CostFuncEcotone(l1BaseFee, l1BlobBaseFee, baseFeeScalar, blobBaseFeeScalar *big.Int) L1CostFunc {
+	return func(data RollupCostData) *big.Int {
+		fee := new(big.Int).Mul(data.calldataGas(), l1FeeScaled(l1BaseFee, l1BlobBaseFee, baseFeeScalar, blobBaseFeeScalar))
+		return fee.Div(fee, ecotoneDivisor)
+	}
+}
+
+// fee = max(minTxSize, intercept + fastlzCoef * fastlzSize) * (16 * l1BaseFee * baseFeeScalar + l1BlobBaseFee * blobBaseFeeScalar) / 1e12
+func newL1CostFuncFjord(l1BaseFee, l1BlobBaseFee, baseFeeScalar, blobBaseFeeScalar *big.Int) L1CostFunc {
+	return func(data RollupCostData) *big.Int {
+		estimatedSize := new(big.Int).Mul(l1CostFastlzCoef, new(big.Int).SetUint64(data.FastLzSize))
+		estimatedSize.Add(estimatedSize, l1CostIntercept)
+		if estimatedSize.Cmp(minTxSizeScaled) < 0 {
+			estimatedSize.Set(minTxSizeScaled)
+		}
+
+		fee := new(big.Int).Mul(estimatedSize, l1FeeScaled(l1BaseFee, l1BlobBaseFee, baseFeeScalar, blobBaseFeeScalar))
+		return fee.Div(fee, fjordDivisor)
+	}
+}
+
+func l1FeeScaled(l1BaseFee, l1BlobBaseFee, baseFeeScalar, blobBaseFeeScalar *big.Int) *big.Int {
+	calldataCostPerByte := new(big.Int).Mul(l1BaseFee, baseFeeScalar)
+	calldataCostPerByte.Mul(calldataCostPerByte, sixteen)
+	blobCostPerByte := new(big.Int).Mul(l1BlobBaseFee, blobBaseFeeScalar)
+	return calldataCostPerByte.Add(calldataCostPerByte, blobCostPerByte)
+}
+
+// FlzCompressLen returns the length of the data after FastLZ (level 1) compression,
+// as computed by the Fjord L1 cost function (port of Solady LibZip.flzCompress)
+func FlzCompressLen(ib []byte) uint32 {
+	n := uint32(0)
+	ht := make([]uint32, 8192)
+	u24 := func(i uint32) uint32 {
+		return uint32(ib[i]) | (uint32(ib[i+1]) << 8) | (uint32(ib[i+2]) << 16)
+	}
+	cmp := func(p, q, e uint32) uint32 {
+		l := uint32(0)
+		for e -= q; l < e; l++ {
+			if ib[p+l] != ib[q+l] {
+				e = 0
+			}
+		}
+		return l
+	}
+	literals := func(r uint32) {
+		n += 0x21 * (r / 0x20)
+		r %= 0x20
+		if r != 0 {
+			n += r + 1
+		}
+	}
+	match := func(l uint32) {
+		l--
+		n += 3 * (l / 262)
+		if l%262 >= 6 {
+			n += 3
+		} else {
+			n += 2
+		}
+	}
+	hash := func(v uint32) uint32 {
+		return ((2654435769 * v) >> 19) & 0x1fff
+	}
+	setNextHash := func(ip uint32) uint32 {
+		ht[hash(u24(ip))] = ip
+		return ip + 1
+	}
+
+	a := uint32(0)
+	ipLimit := uint32(0)
+	if len(ib) > 13 {
+		ipLimit = uint32(len(ib)) - 13 //nolint:gosec // transactions are smaller than 4GB
+	}
+	for ip := a + 2; ip < ipLimit; {
+		var r, d uint32
+		for {
+			s := u24(ip)
+			h := hash(s)
+			r = ht[h]
+			ht[h] = ip
+			d = ip - r
+			if ip >= ipLimit {
+				break
+			}
+			ip++
+			if d <= 0x1fff && s == u24(r) {
+				break
+			}
+		}
+		if ip >= ipLimit {
+			break
+		}
+		ip--
+		if ip > a {
+			literals(ip - a)
+		}
+		l := cmp(r+3, ip+3, ipLimit+9)
+		match(l)
+		ip = setNextHash(setNextHash(ip + l))
+		a = ip
+	}
+	literals(uint32(len(ib)) - a) //nolint:gosec // transactions are smaller than 4GB
+
+	return n
+}
@@ -0,0 +1,145 @@
+package op
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/kkrt-labs/go-utils/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type storage map[gethcommon.Hash]gethcommon.Hash
+
+func (s storage) GetState(addr gethcommon.Address, slot gethcommon.Hash) gethcommon.Hash {
+	if addr != L1BlockAddress {
+		return gethcommon.Hash{}
+	}
+	return s[slot]
+}
+
+func ecotoneScalars(baseFeeScalar, blobBaseFeeScalar uint32) gethcommon.Hash {
+	var h gethcommon.Hash
+	big.NewInt(int64(baseFeeScalar)).FillBytes(h[scalarSectionStart : scalarSectionStart+4])
+	big.NewInt(int64(blobBaseFeeScalar)).FillBytes(h[scalarSectionStart+4 : scalarSectionStart+8])
+	return h
+}
+
+func operatorFeeParams(scalar uint32, constant uint64) gethcommon.Hash {
+	var h gethcommon.Hash
+	big.NewInt(int64(scalar)).FillBytes(h[operatorFeeScalarStart:operatorFeeConstantStart])
+	new(big.Int).SetUint64(constant).FillBytes(h[operatorFeeConstantStart:])
+	return h
+}
+
+func TestOperatorCostFunc(t *testing.T) {
+	st := storage{operatorFeeParamsSlot: operatorFeeParams(2_000_000, 1000)}
+
+	// No operator fee before Isthmus
+	preIsthmus := NewOperatorCostFunc(&Config{RegolithTime: common.Ptr(uint64(0)), IsthmusTime: common.Ptr(uint64(20))}, 10, st)
+	assert.Equal(t, uint64(0), preIsthmus(21000).Uint64())
+
+	// fee = 21000 * 2e6 / 1e6 + 1000
+	isthmus := NewOperatorCostFunc(&Config{RegolithTime: common.Ptr(uint64(0)), IsthmusTime: common.Ptr(uint64(0))}, 10, st)
+	assert.Equal(t, uint64(43000), isthmus(21000).Uint64())
+
+	// Operator fee parameters are unset until the first Isthmus L1 info deposit
+	unset := NewOperatorCostFunc(&Config{RegolithTime: common.Ptr(uint64(0)), IsthmusTime: common.Ptr(uint64(0))}, 10, storage{})
+	assert.Equal(t, uint64(0), unset(21000).Uint64())
+}
+
+func TestL1CostFunc(t *testing.T) {
+	data := RollupCostData{Zeroes: 10, Ones: 100, FastLzSize: 200} // calldata gas = 10*4 + 100*16 = 1640
+
+	var testCases = []struct {
+		desc     string
+		cfg      *Config
+		storage  storage
+		expected *big.Int
+	}{
+		{
+			desc: "bedrock",
+			cfg:  &Config{RegolithTime: common.Ptr(uint64(0))},
+			storage: storage{
+				l1BaseFeeSlot:  gethcommon.BigToHash(big.NewInt(1000)),
+				l1OverheadSlot: gethcommon.BigToHash(big.NewInt(360)),
+				l1ScalarSlot:   gethcommon.BigToHash(big.NewInt(2_000_000)),
+			},
+			expected: big.NewInt((1640 + 360) * 1000 * 2), // (calldataGas + overhead) * l1BaseFee * scalar / 1e6
+		},
+		{
+			desc: "ecotone activation block",
+			cfg:  &Config{RegolithTime: common.Ptr(uint64(0)), EcotoneTime: common.Ptr(uint64(0))},
+			storage: storage{
+				l1BaseFeeSlot:  gethcommon.BigToHash(big.NewInt(1000)),
+				l1OverheadSlot: gethcommon.BigToHash(big.NewInt(360)),
+				l1ScalarSlot:   gethcommon.BigToHash(big.NewInt(2_000_000)),
+			},
+			expected: big.NewInt((1640 + 360) * 1000 * 2),
+		},
+		{
+			desc: "ecotone",
+			cfg:  &Config{RegolithTime: common.Ptr(uint64(0)), EcotoneTime: common.Ptr(uint64(0))},
+			storage: storage{
+				l1BaseFeeSlot:     gethcommon.BigToHash(big.NewInt(1000)),
+				l1BlobBaseFeeSlot: gethcommon.BigToHash(big.NewInt(10)),
+				l1FeeScalarsSlot:  ecotoneScalars(1_000_000, 1_600_000),
+			},
+			expected: big.NewInt(1640 * (16*1000*1_000_000 + 10*1_600_000) / 16_000_000),
+		},
+		{
+			desc: "fjord",
+			cfg:  &Config{RegolithTime: common.Ptr(uint64(0)), EcotoneTime: common.Ptr(uint64(0)), FjordTime: common.Ptr(uint64(0))},
+			storage: storage{
+				l1BaseFeeSlot:     gethcommon.BigToHash(big.NewInt(1000)),
+				l1BlobBaseFeeSlot: gethcommon.BigToHash(big.NewInt(10)),
+				l1FeeScalarsSlot:  ecotoneScalars(1_000_000, 1_600_000),
+			},
+			// estimated size = -42_585_600 + 836_500 * 200 = 124_714_400
+			expected: big.NewInt(124_714_400 * (16*1000*1_000_000 + 10*1_600_000) / 1_000_000_000_000),
+		},
+		{
+			desc: "fjord minimum size",
+			cfg:  &Config{RegolithTime: common.Ptr(uint64(0)), EcotoneTime: common.Ptr(uint64(0)), FjordTime: common.Ptr(uint64(0))},
+			storage: storage{
+				l1BaseFeeSlot:     gethcommon.BigToHash(big.NewInt(1000)),
+				l1BlobBaseFeeSlot: gethcommon.BigToHash(big.NewInt(10)),
+				l1FeeScalarsSlot:  ecotoneScalars(1_000_000, 1_600_000),
+			},
+			expected: big.NewInt(100_000_000 * (16*1000*1_000_000 + 10*1_600_000) / 1_000_000_000_000),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			d := data
+			if tc.desc == "fjord minimum size" {
+				d.FastLzSize = 10
+			}
+			assert.Equal(t, tc.expected, NewL1CostFunc(tc.cfg, 0, tc.storage)(d))
+		})
+	}
+}
+
+func TestFlzCompressLen(t *testing.T) {
+	assert.Equal(t, uint32(0), FlzCompressLen(nil))
+	assert.Equal(t, uint32(6), FlzCompressLen([]byte("hello")))
+
+	// Repetitive data compresses well
+	assert.Less(t, FlzCompressLen(bytes.Repeat([]byte{0x1, 0x2, 0x3, 0x4}, 256)), uint32(64))
+
+	// Non repetitive data does not compress
+	incompressible := make([]byte, 256)
+	for i := range incompressible {
+		incompressible[i] = byte(i)
+	}
+	assert.GreaterOrEqual(t, FlzCompressLen(incompressible), uint32(256))
+}
+
+func TestNewRollupCostData(t *testing.T) {
+	d := NewRollupCostData([]byte{0x0, 0x1, 0x0, 0x2})
+	assert.Equal(t, uint64(2), d.Zeroes)
+	assert.Equal(t, uint64(2), d.Ones)
+	assert.Equal(t, uint64(5), d.FastLzSize)
+}
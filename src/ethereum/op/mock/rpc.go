@@ -0,0 +1,58 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kkrt-labs/zk-pig/src/ethereum/op (interfaces: DepositReader)
+//
+// Generated by this command:
+//
+//	mockgen -destination=./mock/rpc.go -package=mockop github.com/kkrt-labs/zk-pig/src/ethereum/op DepositReader
+//
+
+// Package mockop is a generated GoMock package.
+package mockop
+
+import (
+	context "context"
+	reflect "reflect"
+
+	common "github.com/ethereum/go-ethereum/common"
+	op "github.com/kkrt-labs/zk-pig/src/ethereum/op"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDepositReader is a mock of DepositReader interface.
+type MockDepositReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockDepositReaderMockRecorder
+	isgomock struct{}
+}
+
+// MockDepositReaderMockRecorder is the mock recorder for MockDepositReader.
+type MockDepositReaderMockRecorder struct {
+	mock *MockDepositReader
+}
+
+// NewMockDepositReader creates a new mock instance.
+func NewMockDepositReader(ctrl *gomock.Controller) *MockDepositReader {
+	mock := &MockDepositReader{ctrl: ctrl}
+	mock.recorder = &MockDepositReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDepositReader) EXPECT() *MockDepositReaderMockRecorder {
+	return m.recorder
+}
+
+// DepositsByHash mocks base method.
+func (m *MockDepositReader) DepositsByHash(ctx context.Context, hash common.Hash) ([]*op.DepositTx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DepositsByHash", ctx, hash)
+	ret0, _ := ret[0].([]*op.DepositTx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DepositsByHash indicates an expected call of DepositsByHash.
+func (mr *MockDepositReaderMockRecorder) DepositsByHash(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DepositsByHash", reflect.TypeOf((*MockDepositReader)(nil).DepositsByHash), ctx, hash)
+}
@@ -0,0 +1,297 @@
+package op
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	gethstate "github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/holiman/uint256"
+	"github.com/kkrt-labs/zk-pig/src/ethereum/evm"
+)
+
+type processor struct {
+	cfg      *Config
+	deposits []*DepositTx
+
+	// Nonces of the deposit transactions, populated during processing (needed to compute the receipt root)
+	depositNonces []uint64
+}
+
+// NewProcessor creates an evm.Processor that follows OP Stack rules (Regolith onwards):
+// - deposit transactions are applied first (they mint ETH, skip nonce and fee checks and never pay fees)
+// - other transactions pay the L1 data fee (computed from the L1Block predeploy) to the L1FeeVault
+// - from Isthmus, other transactions pay the operator fee (computed from the L1Block predeploy) to the OperatorFeeVault
+// - base fees are paid to the BaseFeeVault instead of being burnt
+//
+// Blocks after Isthmus (Jovian onwards) are rejected.
+//
+// As deposit transactions can not be represented as go-ethereum transactions, they are provided separately from the block.
+// A processor must be used for a single block.
+func NewProcessor(cfg *Config, deposits []*DepositTx) evm.Processor {
+	return &processor{
+		cfg:      cfg,
+		deposits: deposits,
+	}
+}
+
+func (p *processor) Process(params *evm.ExecParams) (*core.ProcessResult, error) {
+	if !p.cfg.IsRegolith(params.Block.Time()) {
+		return nil, fmt.Errorf("pre-Regolith OP Stack blocks are not supported")
+	}
+	if p.cfg.IsJovian(params.Block.Time()) {
+		return nil, fmt.Errorf("OP Stack blocks from Jovian are not supported")
+	}
+
+	var (
+		block     = params.Block
+		header    = block.Header()
+		blockHash = block.Hash()
+		chainCfg  = params.Chain.Config()
+		statedb   = params.State
+		usedGas   = new(uint64)
+		receipts  gethtypes.Receipts
+		allLogs   []*gethtypes.Log
+		gp        = new(core.GasPool).AddGas(block.GasLimit())
+	)
+
+	var tracingStateDB = vm.StateDB(statedb)
+	if hooks := params.VMConfig.Tracer; hooks != nil {
+		tracingStateDB = gethstate.NewHookedState(statedb, hooks)
+	}
+	vmenv := vm.NewEVM(core.NewEVMBlockContext(header, params.Chain, nil), tracingStateDB, chainCfg, *params.VMConfig)
+
+	// Apply pre-execution system calls
+	if beaconRoot := block.BeaconRoot(); beaconRoot != nil {
+		core.ProcessBeaconBlockRoot(*beaconRoot, vmenv)
+	}
+	if chainCfg.IsPrague(block.Number(), block.Time()) {
+		core.ProcessParentBlockHash(block.ParentHash(), vmenv)
+	}
+
+	// Apply deposit transactions
+	p.depositNonces = make([]uint64, len(p.deposits))
+	for i, dep := range p.deposits {
+		statedb.SetTxContext(dep.Hash(), i)
+
+		receipt, err := p.applyDeposit(vmenv, statedb, gp, header, blockHash, dep, usedGas, i)
+		if err != nil {
+			return nil, fmt.Errorf("could not apply deposit tx %d [%v]: %w", i, dep.Hash().Hex(), err)
+		}
+		receipts = append(receipts, receipt)
+		allLogs = append(allLogs, receipt.Logs...)
+	}
+
+	// Apply other transactions
+	var (
+		signer       = gethtypes.MakeSigner(chainCfg, header.Number, header.Time)
+		l1Cost       L1CostFunc
+		operatorCost OperatorCostFunc
+	)
+	for j, tx := range block.Transactions() {
+		i := len(p.deposits) + j
+
+		// L1 fee parameters are set by the L1 info deposit transaction, so they are read after deposits are applied
+		if l1Cost == nil {
+			l1Cost = NewL1CostFunc(p.cfg, header.Time, statedb)
+			operatorCost = NewOperatorCostFunc(p.cfg, header.Time, statedb)
+		}
+
+		msg, err := core.TransactionToMessage(tx, signer, header.BaseFee)
+		if err != nil {
+			return nil, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
+		}
+		statedb.SetTxContext(tx.Hash(), i)
+
+		receipt, err := p.applyTransaction(vmenv, statedb, gp, header, blockHash, tx, msg, l1Cost, operatorCost, usedGas)
+		if err != nil {
+			return nil, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
+		}
+		receipts = append(receipts, receipt)
+		allLogs = append(allLogs, receipt.Logs...)
+	}
+
+	// OP Stack has no execution layer requests, but blocks commit to an empty list of requests from Isthmus (Prague)
+	var requests [][]byte
+	if header.RequestsHash != nil {
+		requests = [][]byte{}
+	}
+
+	// Finalize the block, applying any consensus engine specific extras
+	params.Chain.Engine().Finalize(params.Chain, header, tracingStateDB, block.Body())
+
+	return &core.ProcessResult{
+		Receipts: receipts,
+		Requests: requests,
+		Logs:     allLogs,
+		GasUsed:  *usedGas,
+	}, nil
+}
+
+func (p *processor) applyDeposit(vmenv *vm.EVM, statedb *gethstate.StateDB, gp *core.GasPool, header *gethtypes.Header, blockHash gethcommon.Hash, dep *DepositTx, usedGas *uint64, i int) (receipt *gethtypes.Receipt, err error) {
+	nonce := statedb.GetNonce(dep.From)
+	p.depositNonces[i] = nonce
+
+	// Deposits are traced as other transactions (as core.ApplyTransactionWithEVM does)
+	if hooks := vmenv.Config.Tracer; hooks != nil {
+		if hooks.OnTxStart != nil {
+			hooks.OnTxStart(vmenv.GetVMContext(), dep.tracingTx(nonce), dep.From)
+		}
+		if hooks.OnTxEnd != nil {
+			defer func() { hooks.OnTxEnd(receipt, err) }()
+		}
+	}
+
+	msg := &core.Message{
+		From:             dep.From,
+		To:               dep.To,
+		Nonce:            nonce,
+		Value:            dep.Value,
+		GasLimit:         dep.Gas,
+		GasPrice:         new(big.Int),
+		GasFeeCap:        new(big.Int),
+		GasTipCap:        new(big.Int),
+		Data:             dep.Data,
+		SkipNonceChecks:  true,
+		SkipFromEOACheck: true,
+	}
+	if msg.Value == nil {
+		msg.Value = new(big.Int)
+	}
+
+	// Mint is applied even if the deposit fails
+	if dep.Mint != nil {
+		statedb.AddBalance(dep.From, uint256.MustFromBig(dep.Mint), tracing.BalanceChangeUnspecified)
+	}
+	snapshot := statedb.Snapshot()
+
+	// Deposits do not pay fees (NoBaseFee with zero gas prices skips the base fee check and the coinbase payment)
+	noBaseFee := vmenv.Config.NoBaseFee
+	vmenv.Config.NoBaseFee = true
+	result, err := core.ApplyMessage(vmenv, msg, gp)
+	vmenv.Config.NoBaseFee = noBaseFee
+
+	if err != nil {
+		if errors.Is(err, core.ErrGasLimitReached) {
+			return nil, err
+		}
+
+		// Failed deposits are still included: state changes are reverted, but nonce is incremented and all gas is used
+		statedb.RevertToSnapshot(snapshot)
+		statedb.SetNonce(dep.From, nonce+1, tracing.NonceChangeUnspecified)
+		result = &core.ExecutionResult{UsedGas: dep.Gas, Err: fmt.Errorf("failed deposit: %w", err)}
+	}
+	statedb.Finalise(true)
+	*usedGas += result.UsedGas
+
+	txHash := dep.Hash()
+	receipt = &gethtypes.Receipt{
+		Type:              DepositTxType,
+		Status:            gethtypes.ReceiptStatusSuccessful,
+		CumulativeGasUsed: *usedGas,
+		TxHash:            txHash,
+		GasUsed:           result.UsedGas,
+		BlockHash:         blockHash,
+		BlockNumber:       header.Number,
+		TransactionIndex:  uint(i), //nolint:gosec // index is positive
+	}
+	if result.Failed() {
+		receipt.Status = gethtypes.ReceiptStatusFailed
+	}
+	if dep.To == nil {
+		receipt.ContractAddress = crypto.CreateAddress(dep.From, nonce)
+	}
+	receipt.Logs = statedb.GetLogs(txHash, header.Number.Uint64(), blockHash)
+	receipt.Bloom = gethtypes.CreateBloom(receipt)
+
+	return receipt, nil
+}
+
+func (p *processor) applyTransaction(vmenv *vm.EVM, statedb *gethstate.StateDB, gp *core.GasPool, header *gethtypes.Header, blockHash gethcommon.Hash, tx *gethtypes.Transaction, msg *core.Message, l1Cost L1CostFunc, operatorCost OperatorCostFunc, usedGas *uint64) (*gethtypes.Receipt, error) {
+	b, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	// Charge L1 data fee
+	l1Fee, overflow := uint256.FromBig(l1Cost(NewRollupCostData(b)))
+	if overflow {
+		return nil, fmt.Errorf("L1 fee overflow")
+	}
+	if balance := statedb.GetBalance(msg.From); balance.Cmp(l1Fee) < 0 {
+		return nil, fmt.Errorf("%w: address %v have %v want %v (L1 fee)", core.ErrInsufficientFunds, msg.From.Hex(), balance, l1Fee)
+	}
+	statedb.SubBalance(msg.From, l1Fee, tracing.BalanceChangeUnspecified)
+
+	// Charge the operator fee of the gas limit, the fee of unused gas is refunded after execution
+	operatorFee := operatorCost(msg.GasLimit)
+	if balance := statedb.GetBalance(msg.From); balance.Cmp(operatorFee) < 0 {
+		return nil, fmt.Errorf("%w: address %v have %v want %v (operator fee)", core.ErrInsufficientFunds, msg.From.Hex(), balance, operatorFee)
+	}
+	statedb.SubBalance(msg.From, operatorFee, tracing.BalanceChangeUnspecified)
+
+	receipt, err := core.ApplyTransactionWithEVM(msg, gp, statedb, header.Number, blockHash, tx, usedGas, vmenv)
+	if err != nil {
+		return nil, err
+	}
+
+	usedOperatorFee := operatorCost(receipt.GasUsed)
+	statedb.AddBalance(msg.From, new(uint256.Int).Sub(operatorFee, usedOperatorFee), tracing.BalanceChangeUnspecified)
+
+	// Pay fee vaults
+	statedb.AddBalance(L1FeeVaultAddress, l1Fee, tracing.BalanceChangeUnspecified)
+	statedb.AddBalance(OperatorFeeVaultAddress, usedOperatorFee, tracing.BalanceChangeUnspecified)
+	baseFee := new(uint256.Int).Mul(uint256.MustFromBig(header.BaseFee), uint256.NewInt(receipt.GasUsed))
+	statedb.AddBalance(BaseFeeVaultAddress, baseFee, tracing.BalanceChangeUnspecified)
+	statedb.Finalise(true)
+
+	return receipt, nil
+}
+
+func (p *processor) Validate(params *evm.ExecParams, res *core.ProcessResult) error {
+	if res == nil {
+		return errors.New("nil ProcessResult value")
+	}
+
+	header := params.Block.Header()
+	if header.GasUsed != res.GasUsed {
+		return fmt.Errorf("invalid gas used (remote: %d local: %d)", header.GasUsed, res.GasUsed)
+	}
+
+	if bloom := gethtypes.MergeBloom(res.Receipts); bloom != header.Bloom {
+		return fmt.Errorf("invalid bloom (remote: %x  local: %x)", header.Bloom, bloom)
+	}
+
+	receiptSha := gethtypes.DeriveSha(p.receipts(header, res.Receipts), trie.NewStackTrie(nil))
+	if receiptSha != header.ReceiptHash {
+		return fmt.Errorf("invalid receipt root hash (remote: %x local: %x)", header.ReceiptHash, receiptSha)
+	}
+
+	if header.RequestsHash != nil {
+		if reqhash := gethtypes.CalcRequestsHash(res.Requests); reqhash != *header.RequestsHash {
+			return fmt.Errorf("invalid requests hash (remote: %x local: %x)", *header.RequestsHash, reqhash)
+		}
+	}
+
+	if root := params.State.IntermediateRoot(params.Chain.Config().IsEIP158(header.Number)); header.Root != root {
+		return fmt.Errorf("invalid merkle root (remote: %x local: %x) dberr: %w", header.Root, root, params.State.Error())
+	}
+
+	return nil
+}
+
+func (p *processor) receipts(header *gethtypes.Header, receipts gethtypes.Receipts) *Receipts {
+	rs := &Receipts{Receipts: receipts}
+	if p.cfg.IsCanyon(header.Time) {
+		rs.DepositNonces = p.depositNonces
+		rs.DepositReceiptVersion = CanyonDepositReceiptVersion
+	}
+	return rs
+}
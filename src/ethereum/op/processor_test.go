@@ -0,0 +1,202 @@
+package op
+
+import (
+	"math/big"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	gethstate "github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/holiman/uint256"
+	"github.com/kkrt-labs/go-utils/common"
+	"github.com/kkrt-labs/zk-pig/src/ethereum"
+	"github.com/kkrt-labs/zk-pig/src/ethereum/evm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessor(t *testing.T) {
+	chainCfg := &params.ChainConfig{
+		ChainID:                 big.NewInt(10),
+		HomesteadBlock:          big.NewInt(0),
+		EIP150Block:             big.NewInt(0),
+		EIP155Block:             big.NewInt(0),
+		EIP158Block:             big.NewInt(0),
+		ByzantiumBlock:          big.NewInt(0),
+		ConstantinopleBlock:     big.NewInt(0),
+		PetersburgBlock:         big.NewInt(0),
+		IstanbulBlock:           big.NewInt(0),
+		MuirGlacierBlock:        big.NewInt(0),
+		BerlinBlock:             big.NewInt(0),
+		LondonBlock:             big.NewInt(0),
+		ArrowGlacierBlock:       big.NewInt(0),
+		GrayGlacierBlock:        big.NewInt(0),
+		MergeNetsplitBlock:      big.NewInt(0),
+		ShanghaiTime:            common.Ptr(uint64(0)),
+		TerminalTotalDifficulty: big.NewInt(0),
+		Ethash:                  new(params.EthashConfig),
+	}
+	opCfg := &Config{
+		RegolithTime: common.Ptr(uint64(0)),
+		CanyonTime:   common.Ptr(uint64(0)),
+		IsthmusTime:  common.Ptr(uint64(0)),
+	}
+
+	// Prepare pre-state
+	stateDB := gethstate.NewDatabase(triedb.NewDatabase(rawdb.NewMemoryDatabase(), nil), nil)
+	preState, err := gethstate.New(gethtypes.EmptyRootHash, stateDB)
+	require.NoError(t, err)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	preState.AddBalance(sender, uint256.NewInt(params.Ether), tracing.BalanceChangeUnspecified)
+
+	// L1Block predeploy must not be empty, otherwise it is deleted with its storage on state finalisation
+	preState.SetCode(L1BlockAddress, []byte{0x00})
+	preState.SetState(L1BlockAddress, l1BaseFeeSlot, gethcommon.BigToHash(big.NewInt(1000)))
+	preState.SetState(L1BlockAddress, l1OverheadSlot, gethcommon.BigToHash(big.NewInt(188)))
+	preState.SetState(L1BlockAddress, l1ScalarSlot, gethcommon.BigToHash(big.NewInt(684000)))
+	preState.SetState(L1BlockAddress, operatorFeeParamsSlot, operatorFeeParams(1_500_000, 7))
+
+	hc, err := ethereum.NewChain(chainCfg, stateDB)
+	require.NoError(t, err)
+
+	// Prepare block
+	var (
+		depositor       = gethcommon.Address{0xd1}
+		failedDepositor = gethcommon.Address{0xd2}
+		recipient       = gethcommon.Address{0xe1}
+		coinbase        = gethcommon.Address{0xc0}
+		baseFee         = big.NewInt(params.GWei)
+	)
+	deposits := []*DepositTx{
+		{
+			SourceHash: gethcommon.Hash{0x1},
+			From:       depositor,
+			To:         &recipient,
+			Mint:       big.NewInt(params.Ether),
+			Value:      big.NewInt(params.Ether / 2),
+			Gas:        50000,
+			Data:       []byte{},
+		},
+		{
+			SourceHash: gethcommon.Hash{0x2},
+			From:       failedDepositor,
+			To:         &recipient,
+			Mint:       big.NewInt(params.GWei),
+			Value:      big.NewInt(params.Ether), // more than minted
+			Gas:        30000,
+			Data:       []byte{},
+		},
+	}
+
+	tx, err := gethtypes.SignNewTx(key, gethtypes.LatestSignerForChainID(chainCfg.ChainID), &gethtypes.DynamicFeeTx{
+		ChainID:   chainCfg.ChainID,
+		Nonce:     0,
+		GasTipCap: big.NewInt(params.GWei),
+		GasFeeCap: big.NewInt(2 * params.GWei),
+		Gas:       21000,
+		To:        &recipient,
+		Value:     big.NewInt(1000),
+	})
+	require.NoError(t, err)
+
+	header := &gethtypes.Header{
+		ParentHash: hc.GetHeaderByNumber(0).Hash(),
+		Coinbase:   coinbase,
+		Number:     big.NewInt(1),
+		GasLimit:   30_000_000,
+		Time:       10,
+		BaseFee:    baseFee,
+		Difficulty: new(big.Int),
+	}
+	block := gethtypes.NewBlock(header, &gethtypes.Body{Transactions: gethtypes.Transactions{tx}}, nil, trie.NewStackTrie(nil))
+
+	// Record the transactions seen by tracers
+	var (
+		tracedStarts   int
+		tracedReceipts []*gethtypes.Receipt
+	)
+	hooks := &tracing.Hooks{
+		OnTxStart: func(_ *tracing.VMContext, _ *gethtypes.Transaction, _ gethcommon.Address) { tracedStarts++ },
+		OnTxEnd:   func(receipt *gethtypes.Receipt, _ error) { tracedReceipts = append(tracedReceipts, receipt) },
+	}
+
+	execParams := &evm.ExecParams{
+		VMConfig: &vm.Config{Tracer: hooks},
+		Block:    block,
+		State:    preState,
+		Chain:    hc,
+	}
+	p := NewProcessor(opCfg, deposits)
+
+	// Process block
+	res, err := p.Process(execParams)
+	require.NoError(t, err)
+	require.Len(t, res.Receipts, 3)
+
+	assert.Equal(t, uint8(DepositTxType), res.Receipts[0].Type)
+	assert.Equal(t, gethtypes.ReceiptStatusSuccessful, res.Receipts[0].Status)
+	assert.Equal(t, uint64(21000), res.Receipts[0].GasUsed)
+	assert.Equal(t, uint8(DepositTxType), res.Receipts[1].Type)
+	assert.Equal(t, gethtypes.ReceiptStatusFailed, res.Receipts[1].Status)
+	assert.Equal(t, uint64(30000), res.Receipts[1].GasUsed)
+	assert.Equal(t, gethtypes.ReceiptStatusSuccessful, res.Receipts[2].Status)
+	assert.Equal(t, uint64(21000+30000+21000), res.GasUsed)
+
+	// Deposits are traced as the other transactions
+	assert.Equal(t, 3, tracedStarts)
+	assert.Equal(t, res.Receipts, gethtypes.Receipts(tracedReceipts))
+
+	// Deposits mint, transfer and increment nonces
+	assert.Equal(t, uint256.NewInt(params.Ether/2), preState.GetBalance(depositor))
+	assert.Equal(t, uint64(1), preState.GetNonce(depositor))
+	assert.Equal(t, uint256.NewInt(params.GWei), preState.GetBalance(failedDepositor))
+	assert.Equal(t, uint64(1), preState.GetNonce(failedDepositor))
+	assert.Equal(t, uint256.NewInt(params.Ether/2+1000), preState.GetBalance(recipient))
+
+	// Fees are paid to the vaults and coinbase
+	b, err := tx.MarshalBinary()
+	require.NoError(t, err)
+	l1Fee := NewL1CostFunc(opCfg, header.Time, preState)(NewRollupCostData(b))
+	require.Positive(t, l1Fee.Sign())
+	assert.Equal(t, uint256.MustFromBig(l1Fee), preState.GetBalance(L1FeeVaultAddress))
+	operatorFee := uint64(21000*1_500_000/1_000_000 + 7) // the operator fee of unused gas is refunded
+	assert.Equal(t, uint256.NewInt(operatorFee), preState.GetBalance(OperatorFeeVaultAddress))
+	spent := new(big.Int).Add(l1Fee, big.NewInt(int64(operatorFee+21000*2*params.GWei+1000)))
+	assert.Equal(t, uint256.MustFromBig(new(big.Int).Sub(big.NewInt(params.Ether), spent)), preState.GetBalance(sender))
+	assert.Equal(t, uint256.NewInt(21000*params.GWei), preState.GetBalance(BaseFeeVaultAddress))
+	assert.Equal(t, uint256.NewInt(21000*params.GWei), preState.GetBalance(coinbase))
+
+	// Validate block
+	header.GasUsed = res.GasUsed
+	header.Bloom = gethtypes.MergeBloom(res.Receipts)
+	header.ReceiptHash = gethtypes.DeriveSha(p.(*processor).receipts(header, res.Receipts), trie.NewStackTrie(nil))
+	header.Root = preState.IntermediateRoot(true)
+	execParams.Block = block.WithSeal(header)
+	require.NoError(t, p.Validate(execParams, res))
+
+	header.GasUsed++
+	execParams.Block = block.WithSeal(header)
+	require.Error(t, p.Validate(execParams, res))
+}
+
+func TestProcessorPreRegolith(t *testing.T) {
+	block := gethtypes.NewBlockWithHeader(&gethtypes.Header{Number: big.NewInt(1), Time: 10})
+	_, err := NewProcessor(&Config{RegolithTime: common.Ptr(uint64(20))}, nil).Process(&evm.ExecParams{Block: block})
+	require.Error(t, err)
+}
+
+func TestProcessorJovian(t *testing.T) {
+	block := gethtypes.NewBlockWithHeader(&gethtypes.Header{Number: big.NewInt(1), Time: 10})
+	_, err := NewProcessor(&Config{RegolithTime: common.Ptr(uint64(0)), JovianTime: common.Ptr(uint64(10))}, nil).Process(&evm.ExecParams{Block: block})
+	require.Error(t, err)
+}
@@ -0,0 +1,67 @@
+package op
+
+import (
+	"bytes"
+
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// CanyonDepositReceiptVersion is the version of deposit receipts from Canyon
+const CanyonDepositReceiptVersion uint64 = 1
+
+var (
+	receiptStatusFailedRLP     = []byte{}
+	receiptStatusSuccessfulRLP = []byte{0x01}
+)
+
+// depositReceiptRLP is the consensus encoding of a deposit receipt
+type depositReceiptRLP struct {
+	PostStateOrStatus     []byte
+	CumulativeGasUsed     uint64
+	Bloom                 gethtypes.Bloom
+	Logs                  []*gethtypes.Log
+	DepositNonce          *uint64 `rlp:"optional"` // Only from Canyon
+	DepositReceiptVersion *uint64 `rlp:"optional"` // Only from Canyon
+}
+
+// Receipts is a list of OP Stack receipts, that can be used to compute a receipt root (it implements types.DerivableList)
+type Receipts struct {
+	Receipts gethtypes.Receipts
+
+	// From Canyon, deposit receipts commit to the deposit nonce and to the receipt version
+	// (deposit receipts come first, so DepositNonces[i] is the nonce of the i-th receipt)
+	DepositNonces         []uint64
+	DepositReceiptVersion uint64
+}
+
+// Len returns the number of receipts
+func (rs *Receipts) Len() int {
+	return len(rs.Receipts)
+}
+
+// EncodeIndex encodes the i'th receipt to w
+func (rs *Receipts) EncodeIndex(i int, w *bytes.Buffer) {
+	r := rs.Receipts[i]
+	if r.Type != DepositTxType {
+		rs.Receipts.EncodeIndex(i, w)
+		return
+	}
+
+	data := &depositReceiptRLP{
+		PostStateOrStatus: receiptStatusSuccessfulRLP,
+		CumulativeGasUsed: r.CumulativeGasUsed,
+		Bloom:             r.Bloom,
+		Logs:              r.Logs,
+	}
+	if r.Status == gethtypes.ReceiptStatusFailed {
+		data.PostStateOrStatus = receiptStatusFailedRLP
+	}
+	if rs.DepositReceiptVersion != 0 && i < len(rs.DepositNonces) {
+		data.DepositNonce = &rs.DepositNonces[i]
+		data.DepositReceiptVersion = &rs.DepositReceiptVersion
+	}
+
+	w.WriteByte(DepositTxType)
+	_ = rlp.Encode(w, data)
+}
@@ -0,0 +1,167 @@
+package op
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	geth "github.com/ethereum/go-ethereum"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	ethrpc "github.com/kkrt-labs/go-utils/ethereum/rpc"
+	ethjsonrpc "github.com/kkrt-labs/go-utils/ethereum/rpc/jsonrpc"
+	"github.com/kkrt-labs/go-utils/jsonrpc"
+)
+
+//go:generate mockgen -destination=./mock/rpc.go -package=mockop github.com/kkrt-labs/zk-pig/src/ethereum/op DepositReader
+
+// DepositReader reads the deposit transactions of OP Stack blocks
+type DepositReader interface {
+	// DepositsByHash returns the deposit transactions of a block, in block order
+	DepositsByHash(ctx context.Context, hash gethcommon.Hash) ([]*DepositTx, error)
+}
+
+// Client is an Ethereum RPC client which supports blocks of OP Stack chains
+//
+// Deposit transactions are not supported by go-ethereum transaction types, so blocks are returned without them
+// (as expected by the OP Stack processor) and deposits are read separately with DepositsByHash.
+//
+// On chains which are not OP Stack chains, it behaves as the standard client.
+type Client struct {
+	ethrpc.Client
+	remote jsonrpc.Client
+}
+
+// NewClient creates a client from a JSON-RPC client
+func NewClient(remote jsonrpc.Client) *Client {
+	return &Client{
+		Client: ethjsonrpc.NewFromClient(remote),
+		remote: remote,
+	}
+}
+
+// BlockByHash returns the block with the given hash, without its deposit transactions
+func (c *Client) BlockByHash(ctx context.Context, hash gethcommon.Hash) (*gethtypes.Block, error) {
+	block, _, err := c.getBlock(ctx, "eth_getBlockByHash", hash, true)
+	return block, err
+}
+
+// BlockByNumber returns the block with the given number, without its deposit transactions
+//
+// If number is nil, the latest known block is returned.
+func (c *Client) BlockByNumber(ctx context.Context, number *big.Int) (*gethtypes.Block, error) {
+	block, _, err := c.getBlock(ctx, "eth_getBlockByNumber", ethjsonrpc.ToBlockNumArg(number), true)
+	return block, err
+}
+
+// DepositsByHash returns the deposit transactions of the block with the given hash
+func (c *Client) DepositsByHash(ctx context.Context, hash gethcommon.Hash) ([]*DepositTx, error) {
+	_, deposits, err := c.getBlock(ctx, "eth_getBlockByHash", hash, true)
+	return deposits, err
+}
+
+func (c *Client) getBlock(ctx context.Context, method string, params ...any) (*gethtypes.Block, []*DepositTx, error) {
+	var raw json.RawMessage
+	if err := c.remote.Call(ctx, &jsonrpc.Request{Method: method, Params: params}, &raw); err != nil {
+		return nil, nil, err
+	}
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil, nil, geth.NotFound
+	}
+
+	raw, deposits, err := SplitDeposits(raw)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	block := new(ethrpc.Block)
+	if err := json.Unmarshal(raw, block); err != nil {
+		return nil, nil, err
+	}
+
+	// Quick-verify transaction and uncle lists (as the standard client does)
+	if block.UncleHash == gethtypes.EmptyUncleHash && len(block.Uncles) > 0 {
+		return nil, nil, fmt.Errorf("server returned non-empty uncle list but block header indicates no uncles")
+	}
+	if block.UncleHash != gethtypes.EmptyUncleHash && len(block.Uncles) == 0 {
+		return nil, nil, fmt.Errorf("server returned empty uncle list but block header indicates uncles")
+	}
+	if block.TxRoot == gethtypes.EmptyRootHash && len(block.Transactions)+len(deposits) > 0 {
+		return nil, nil, fmt.Errorf("server returned non-empty transaction list but block header indicates no transactions")
+	}
+	if block.TxRoot != gethtypes.EmptyRootHash && len(block.Transactions)+len(deposits) == 0 {
+		return nil, nil, fmt.Errorf("server returned empty transaction list but block header indicates transactions")
+	}
+	for i, tx := range block.Transactions {
+		if tx.Transaction == nil {
+			return nil, nil, fmt.Errorf("server returned undecodable transaction at index %d", i)
+		}
+	}
+
+	return block.Block(), deposits, nil
+}
+
+// SplitDeposits removes the deposit transactions from a JSON-RPC block (as returned by eth_getBlockByHash with full transactions)
+//
+// It returns the block without its deposits, and the deposits in block order.
+func SplitDeposits(rawBlock json.RawMessage) (json.RawMessage, []*DepositTx, error) {
+	var block map[string]json.RawMessage
+	if err := json.Unmarshal(rawBlock, &block); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode block: %w", err)
+	}
+
+	rawTxs, ok := block["transactions"]
+	if !ok {
+		return rawBlock, nil, nil
+	}
+
+	var txs []json.RawMessage
+	if err := json.Unmarshal(rawTxs, &txs); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode block transactions: %w", err)
+	}
+
+	var (
+		deposits []*DepositTx
+		others   = make([]json.RawMessage, 0, len(txs))
+	)
+	for i, rawTx := range txs {
+		var typed struct {
+			Type *hexutil.Uint64 `json:"type"`
+		}
+		// Transactions given as hashes are kept as is
+		if err := json.Unmarshal(rawTx, &typed); err != nil || typed.Type == nil || *typed.Type != DepositTxType {
+			others = append(others, rawTx)
+			continue
+		}
+
+		if len(others) > 0 {
+			return nil, nil, fmt.Errorf("deposit transaction at index %d follows non-deposit transactions", i)
+		}
+
+		deposit := new(DepositTx)
+		if err := deposit.UnmarshalJSON(rawTx); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode deposit transaction at index %d: %w", i, err)
+		}
+		deposits = append(deposits, deposit)
+	}
+
+	if len(deposits) == 0 {
+		return rawBlock, nil, nil
+	}
+
+	b, err := json.Marshal(others)
+	if err != nil {
+		return nil, nil, err
+	}
+	block["transactions"] = b
+
+	b, err = json.Marshal(block)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return b, deposits, nil
+}
@@ -0,0 +1,149 @@
+package op
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	geth "github.com/ethereum/go-ethereum"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	ethrpc "github.com/kkrt-labs/go-utils/ethereum/rpc"
+	"github.com/kkrt-labs/go-utils/jsonrpc"
+	jsonrpcmock "github.com/kkrt-labs/go-utils/jsonrpc/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// testRPCBlock returns the JSON-RPC encoding of a block with a deposit followed by a regular transaction
+func testRPCBlock(t *testing.T) (json.RawMessage, *gethtypes.Block, *DepositTx) {
+	chainCfg := params.AllDevChainProtocolChanges
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	tx, err := gethtypes.SignNewTx(key, gethtypes.LatestSigner(chainCfg), &gethtypes.DynamicFeeTx{
+		ChainID:   chainCfg.ChainID,
+		Gas:       21000,
+		GasFeeCap: big.NewInt(1),
+		To:        &gethcommon.Address{0xe1},
+		Value:     big.NewInt(1),
+	})
+	require.NoError(t, err)
+
+	deposit := &DepositTx{
+		SourceHash:          gethcommon.Hash{0x1},
+		From:                gethcommon.Address{0xd1},
+		To:                  &gethcommon.Address{0xe1},
+		Value:               big.NewInt(0),
+		Gas:                 1000000,
+		IsSystemTransaction: false,
+		Data:                []byte{},
+	}
+
+	header := &gethtypes.Header{
+		Number:      big.NewInt(10),
+		Difficulty:  new(big.Int),
+		GasLimit:    30000000,
+		UncleHash:   gethtypes.EmptyUncleHash,
+		TxHash:      gethcommon.Hash{0xaa}, // root of deposit + regular transaction
+		ReceiptHash: gethtypes.EmptyReceiptsHash,
+		BaseFee:     big.NewInt(1),
+	}
+	block := gethtypes.NewBlockWithHeader(header).WithBody(gethtypes.Body{Transactions: gethtypes.Transactions{tx}})
+
+	b, err := json.Marshal(new(ethrpc.Block).FromBlock(block, chainCfg))
+	require.NoError(t, err)
+
+	var raw map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(b, &raw))
+	var txs []json.RawMessage
+	require.NoError(t, json.Unmarshal(raw["transactions"], &txs))
+	depositJSON, err := json.Marshal(deposit)
+	require.NoError(t, err)
+	raw["transactions"], err = json.Marshal(append([]json.RawMessage{depositJSON}, txs...))
+	require.NoError(t, err)
+
+	b, err = json.Marshal(raw)
+	require.NoError(t, err)
+
+	return b, block, deposit
+}
+
+func TestSplitDeposits(t *testing.T) {
+	raw, block, deposit := testRPCBlock(t)
+
+	rest, deposits, err := SplitDeposits(raw)
+	require.NoError(t, err)
+	require.Len(t, deposits, 1)
+	assert.Equal(t, deposit.Hash(), deposits[0].Hash())
+
+	rpcBlock := new(ethrpc.Block)
+	require.NoError(t, json.Unmarshal(rest, rpcBlock))
+	require.Len(t, rpcBlock.Transactions, 1)
+	assert.Equal(t, block.Transactions()[0].Hash(), rpcBlock.Transactions[0].Hash())
+
+	// Blocks without deposit are left untouched
+	rest, deposits, err = SplitDeposits(rest)
+	require.NoError(t, err)
+	assert.Empty(t, deposits)
+	require.NoError(t, json.Unmarshal(rest, rpcBlock))
+	assert.Len(t, rpcBlock.Transactions, 1)
+}
+
+func TestSplitDepositsInvalidOrder(t *testing.T) {
+	raw, _, _ := testRPCBlock(t)
+
+	var block map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(raw, &block))
+	var txs []json.RawMessage
+	require.NoError(t, json.Unmarshal(block["transactions"], &txs))
+	block["transactions"], _ = json.Marshal([]json.RawMessage{txs[1], txs[0]})
+	raw, _ = json.Marshal(block)
+
+	_, _, err := SplitDeposits(raw)
+	assert.ErrorContains(t, err, "deposit transaction at index 1 follows non-deposit transactions")
+}
+
+func hasMethod(method string) gomock.Matcher {
+	return gomock.Cond(func(req *jsonrpc.Request) bool { return req.Method == method })
+}
+
+func TestClient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	remote := jsonrpcmock.NewMockClient(ctrl)
+	c := NewClient(remote)
+
+	raw, block, deposit := testRPCBlock(t)
+	returnBlock := func(_ context.Context, _ *jsonrpc.Request, res any) error {
+		*res.(*json.RawMessage) = raw
+		return nil
+	}
+
+	remote.EXPECT().Call(gomock.Any(), hasMethod("eth_getBlockByNumber"), gomock.Any()).DoAndReturn(returnBlock)
+	b, err := c.BlockByNumber(context.TODO(), big.NewInt(10))
+	require.NoError(t, err)
+	assert.Equal(t, block.Hash(), b.Hash())
+	require.Len(t, b.Transactions(), 1)
+	assert.Equal(t, block.Transactions()[0].Hash(), b.Transactions()[0].Hash())
+
+	remote.EXPECT().Call(gomock.Any(), hasMethod("eth_getBlockByHash"), gomock.Any()).DoAndReturn(returnBlock)
+	deposits, err := c.DepositsByHash(context.TODO(), block.Hash())
+	require.NoError(t, err)
+	require.Len(t, deposits, 1)
+	assert.Equal(t, deposit.Hash(), deposits[0].Hash())
+
+	remote.EXPECT().Call(gomock.Any(), hasMethod("eth_getBlockByHash"), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *jsonrpc.Request, res any) error {
+			*res.(*json.RawMessage) = json.RawMessage("null")
+			return nil
+		},
+	)
+	_, err = c.BlockByHash(context.TODO(), block.Hash())
+	assert.ErrorIs(t, err, geth.NotFound)
+}
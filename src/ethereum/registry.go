@@ -0,0 +1,135 @@
+package ethereum
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Network is a chain supported by zk-pig
+type Network struct {
+	Name    string
+	Config  *params.ChainConfig
+	Genesis func() *core.Genesis // called lazily, as building the genesis alloc of public networks is expensive
+}
+
+// Registry holds the networks supported by zk-pig, indexed by chain ID
+//
+// It is safe for concurrent use.
+type Registry struct {
+	mux      sync.RWMutex
+	networks map[string]*Network
+	genesis  map[string]*core.Genesis // cache of built genesis
+}
+
+// NewRegistry creates a new registry with the given networks
+func NewRegistry(networks ...*Network) *Registry {
+	r := &Registry{
+		networks: make(map[string]*Network),
+		genesis:  make(map[string]*core.Genesis),
+	}
+	for _, n := range networks {
+		_ = r.Register(n)
+	}
+	return r
+}
+
+// Register adds a network to the registry
+//
+// If a network with the same chain ID is already registered, it is overridden (e.g. for shadow forks).
+func (r *Registry) Register(n *Network) error {
+	if n.Config == nil || n.Config.ChainID == nil {
+		return fmt.Errorf("network %q is missing chain config or chain ID", n.Name)
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.networks[n.Config.ChainID.String()] = n
+	delete(r.genesis, n.Config.ChainID.String())
+
+	return nil
+}
+
+// Network returns the network registered for the given chain ID
+func (r *Registry) Network(chainID *big.Int) (*Network, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	n, ok := r.networks[chainID.String()]
+	if !ok {
+		return nil, fmt.Errorf("unsupported chain: %q", chainID.String())
+	}
+	return n, nil
+}
+
+// ChainConfig returns the chain config of the network registered for the given chain ID
+func (r *Registry) ChainConfig(chainID *big.Int) (*params.ChainConfig, error) {
+	n, err := r.Network(chainID)
+	if err != nil {
+		return nil, err
+	}
+	return n.Config, nil
+}
+
+// Genesis returns the genesis of the network registered for the given chain ID
+func (r *Registry) Genesis(chainID *big.Int) (*core.Genesis, error) {
+	n, err := r.Network(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.Genesis == nil {
+		return nil, fmt.Errorf("no genesis for chain: %q", chainID.String())
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	genesis, ok := r.genesis[chainID.String()]
+	if !ok {
+		genesis = n.Genesis()
+		r.genesis[chainID.String()] = genesis
+	}
+
+	return genesis, nil
+}
+
+// Networks returns all registered networks, sorted by chain ID
+func (r *Registry) Networks() []*Network {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	networks := make([]*Network, 0, len(r.networks))
+	for _, n := range r.networks {
+		networks = append(networks, n)
+	}
+	sort.Slice(networks, func(i, j int) bool {
+		return networks[i].Config.ChainID.Cmp(networks[j].Config.ChainID) < 0
+	})
+
+	return networks
+}
+
+// NetworkFromGenesis creates a network from a genesis
+func NetworkFromGenesis(name string, genesis *core.Genesis) *Network {
+	return &Network{
+		Name:    name,
+		Config:  genesis.Config,
+		Genesis: func() *core.Genesis { return genesis },
+	}
+}
+
+// DefaultRegistry is the registry of networks supported by zk-pig.
+//
+// It comes with all the public networks provided by go-ethereum.
+var DefaultRegistry = NewRegistry(
+	&Network{Name: "mainnet", Config: params.MainnetChainConfig, Genesis: core.DefaultGenesisBlock},
+	&Network{Name: "sepolia", Config: params.SepoliaChainConfig, Genesis: core.DefaultSepoliaGenesisBlock},
+	&Network{Name: "holesky", Config: params.HoleskyChainConfig, Genesis: core.DefaultHoleskyGenesisBlock},
+	&Network{Name: "hoodi", Config: params.HoodiChainConfig, Genesis: core.DefaultHoodiGenesisBlock},
+)
@@ -0,0 +1,62 @@
+package ethereum
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	var calls int
+	r := NewRegistry(&Network{
+		Name:   "test",
+		Config: &params.ChainConfig{ChainID: big.NewInt(10)},
+		Genesis: func() *core.Genesis {
+			calls++
+			return &core.Genesis{}
+		},
+	})
+
+	_, err := r.ChainConfig(big.NewInt(11))
+	require.Error(t, err)
+
+	cfg, err := r.ChainConfig(big.NewInt(10))
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(10), cfg.ChainID)
+
+	// Genesis is built once
+	_, err = r.Genesis(big.NewInt(10))
+	require.NoError(t, err)
+	_, err = r.Genesis(big.NewInt(10))
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+
+	// Registering a network with the same chain ID overrides it
+	require.NoError(t, r.Register(NetworkFromGenesis("override", &core.Genesis{Config: &params.ChainConfig{ChainID: big.NewInt(10)}})))
+	n, err := r.Network(big.NewInt(10))
+	require.NoError(t, err)
+	assert.Equal(t, "override", n.Name)
+
+	require.NoError(t, r.Register(&Network{Name: "other", Config: &params.ChainConfig{ChainID: big.NewInt(2)}}))
+	networks := r.Networks()
+	require.Len(t, networks, 2)
+	assert.Equal(t, "other", networks[0].Name)
+	assert.Equal(t, "override", networks[1].Name)
+
+	_, err = r.Genesis(big.NewInt(2))
+	assert.Error(t, err)
+
+	assert.Error(t, r.Register(&Network{Name: "invalid"}))
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	var names []string
+	for _, n := range DefaultRegistry.Networks() {
+		names = append(names, n.Name)
+	}
+	assert.Subset(t, names, []string{"mainnet", "sepolia", "holesky", "hoodi"})
+}
@@ -1,13 +1,17 @@
 package src
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/kkrt-labs/go-utils/app"
 	"github.com/kkrt-labs/go-utils/common"
+	"github.com/kkrt-labs/go-utils/log"
 	"github.com/kkrt-labs/zk-pig/src/ethereum/evm"
 	"github.com/kkrt-labs/zk-pig/src/generator"
 	"github.com/kkrt-labs/zk-pig/src/steps"
+	inputstore "github.com/kkrt-labs/zk-pig/src/store"
+	"go.uber.org/zap"
 )
 
 var (
@@ -20,6 +24,7 @@ func (a *App) PreflightEVM() evm.Executor {
 		fmt.Sprintf("%s.preflight.evm", zkpigComponentName),
 		func() (evm.Executor, error) {
 			vm := evm.NewExecutor()
+			vm = evm.WithBadBlockReporter(a.reportBadBlock)(vm)
 			vm = evm.WithLog()(vm)
 			vm = evm.WithTags(vm)
 			return vm, nil
@@ -27,12 +32,48 @@ func (a *App) PreflightEVM() evm.Executor {
 	)
 }
 
+// reportBadBlock persists the report of a block which processing or validation failed
+func (a *App) reportBadBlock(ctx context.Context, report *evm.BadBlockReport) {
+	report.Version = Version
+	if err := a.BadBlockStore().StoreBadBlockReport(ctx, report); err != nil {
+		log.LoggerFromContext(ctx).Error("Failed to store bad block report", zap.Error(err))
+	}
+}
+
+// withTrace decorates the executor so it stores the execution traces of the given step (if enabled)
+func (a *App) withTrace(vm evm.Executor, step inputstore.TraceStep) (evm.Executor, error) {
+	gCfg := a.Config()
+	if gCfg.Generator == nil || common.Val(gCfg.Generator.Trace) == "" {
+		return vm, nil
+	}
+
+	mode, err := evm.ParseTraceMode(common.Val(gCfg.Generator.Trace))
+	if err != nil {
+		return nil, err
+	}
+
+	return evm.WithTrace(mode, a.storeTrace(step))(vm), nil
+}
+
+// storeTrace returns a function persisting the execution traces of a step
+func (a *App) storeTrace(step inputstore.TraceStep) func(ctx context.Context, trace *evm.Trace) {
+	return func(ctx context.Context, trace *evm.Trace) {
+		if err := a.TraceStore().StoreTrace(ctx, step, trace); err != nil {
+			log.LoggerFromContext(ctx).Error("Failed to store execution trace", zap.Error(err))
+		}
+	}
+}
+
 func (a *App) PreflightBase() steps.Preflight {
 	return provide(
 		a,
 		fmt.Sprintf("%s.preflight.base", zkpigComponentName),
 		func() (steps.Preflight, error) {
-			return steps.NewPreflightFromEvm(a.PreflightEVM(), a.Chain()), nil
+			chain := a.Chain()
+			if chain == nil {
+				return steps.NewPreflightFromEvm(a.PreflightEVM(), nil), nil
+			}
+			return steps.NewPreflightFromEvm(a.PreflightEVM(), chain, steps.WithDepositReader(a.chainOp())), nil
 		},
 	)
 }
@@ -53,6 +94,11 @@ func (a *App) PreparerEVM() evm.Executor {
 		fmt.Sprintf("%s.preparer.evm", zkpigComponentName),
 		func() (evm.Executor, error) {
 			vm := evm.NewExecutor()
+			vm = evm.WithBadBlockReporter(a.reportBadBlock)(vm)
+			vm, err := a.withTrace(vm, inputstore.TraceStepPrepare)
+			if err != nil {
+				return nil, err
+			}
 			vm = evm.WithLog()(vm)
 			vm = evm.WithTags(vm)
 			return vm, nil
@@ -70,11 +116,25 @@ func (a *App) PreparerBase() steps.Preparer {
 			if gCfg.Generator != nil && gCfg.Generator.IncludeExtensions != nil {
 				include = *gCfg.Generator.IncludeExtensions
 			}
-			return steps.NewPreparerFromEvm(a.PreparerEVM(), steps.WithDataInclude(include))
+			return steps.NewPreparerFromEvm(
+				a.PreparerEVM(),
+				steps.WithDataInclude(include),
+				steps.WithAccessReporter(a.reportAccesses),
+			)
 		},
 	)
 }
 
+// reportAccesses records the accesses of a prepared block in the generator metrics
+func (a *App) reportAccesses(ctx context.Context, counts *steps.AccessCounts) {
+	a.Generator().ReportAccesses(ctx, counts)
+}
+
+// reportProverInputSize records the size of a stored prover input in the generator metrics
+func (a *App) reportProverInputSize(ctx context.Context, contentType inputstore.ContentType, size int) {
+	a.Generator().ReportProverInputSize(ctx, contentType, size)
+}
+
 func (a *App) Preparer() steps.Preparer {
 	return provide(
 		a,
@@ -91,6 +151,11 @@ func (a *App) ExecutorEVM() evm.Executor {
 		fmt.Sprintf("%s.executor.evm", zkpigComponentName),
 		func() (evm.Executor, error) {
 			vm := evm.NewExecutor()
+			vm = evm.WithBadBlockReporter(a.reportBadBlock)(vm)
+			vm, err := a.withTrace(vm, inputstore.TraceStepExecute)
+			if err != nil {
+				return nil, err
+			}
 			vm = evm.WithLog()(vm)
 			vm = evm.WithTags(vm)
 			return vm, nil
@@ -118,11 +183,41 @@ func (a *App) Executor() steps.Executor {
 	)
 }
 
+func (a *App) VerifierBase() steps.Verifier {
+	return provide(
+		a,
+		fmt.Sprintf("%s.verifier.base", zkpigComponentName),
+		func() (steps.Verifier, error) {
+			return steps.NewReceiptsVerifier(a.chainRPC()), nil
+		},
+	)
+}
+
+// Verifier returns the verifier of execution results
+// It returns nil if receipts verification is disabled or if no chain RPC is configured
+func (a *App) Verifier() steps.Verifier {
+	gCfg := a.Config()
+	if gCfg.Generator == nil || !common.Val(gCfg.Generator.VerifyReceipts) || a.Chain() == nil {
+		return nil
+	}
+
+	return provide(
+		a,
+		fmt.Sprintf("%s.verifier", zkpigComponentName),
+		func() (steps.Verifier, error) {
+			return steps.VerifierWithTags(a.VerifierBase()), nil
+		},
+	)
+}
+
 func (a *App) Generator() *generator.Generator {
 	return provide(
 		a,
 		fmt.Sprintf("%s.base", zkpigComponentName),
 		func() (*generator.Generator, error) {
+			a.Networks() // register custom networks (if any) before chain configs are looked up
+			a.TracerProvider()
+
 			return generator.NewGenerator(
 				&generator.Config{
 					ChainID:            a.ChainID(),
@@ -130,6 +225,7 @@ func (a *App) Generator() *generator.Generator {
 					Preflighter:        a.Preflight(),
 					Preparer:           a.Preparer(),
 					Executor:           a.Executor(),
+					Verifier:           a.Verifier(),
 					PreflightDataStore: a.PreflightDataStore(),
 					ProverInputStore:   a.ProverInputStore(),
 				},
@@ -145,16 +241,19 @@ func (a *App) Daemon() *generator.Daemon {
 		fmt.Sprintf("%s.daemon", zkpigComponentName),
 		func() (*generator.Daemon, error) {
 			a.app.EnableHealthzEntrypoint()
+			a.API()
 
 			filter := generator.NoFilter()
 			if a.Config().Generator != nil && a.Config().Generator.FilterModulo != nil {
 				filter = generator.FilterByBlockNumberModulo(common.Val(a.Config().Generator.FilterModulo))
 			}
 
-			return generator.NewDaemon(
-				a.Generator(),
-				generator.WithFilter(filter),
-			), nil
+			opts := []generator.DaemonOption{generator.WithFilter(filter)}
+			if a.Config().GC != nil && common.Val(a.Config().GC.Interval) > 0 {
+				opts = append(opts, generator.WithGarbageCollector(a.GarbageCollector(), common.Val(a.Config().GC.Interval)))
+			}
+
+			return generator.NewDaemon(a.Generator(), opts...), nil
 		},
 		app.WithComponentName(zkpigComponentName), // override component name
 	)
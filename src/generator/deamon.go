@@ -3,11 +3,13 @@ package generator
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	gethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/kkrt-labs/go-utils/log"
 	"github.com/kkrt-labs/go-utils/tag"
+	"github.com/kkrt-labs/zk-pig/src/store"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 )
@@ -21,9 +23,16 @@ type Daemon struct {
 	cancelRun context.CancelFunc
 
 	latestBlockNumber prometheus.Gauge
+	gcReclaimedBytes  prometheus.Counter
 
 	fetchInterval time.Duration
 	filter        BlockFilter
+
+	head atomic.Pointer[gethtypes.Block] // Latest chain head fetched
+
+	gc         store.GarbageCollector
+	gcInterval time.Duration
+	gcFrom     uint64 // Lowest block number which artifacts may be deleted by the next garbage collection
 }
 
 type DaemonOption func(*Daemon)
@@ -35,6 +44,15 @@ func WithFetchInterval(interval time.Duration) DaemonOption {
 	}
 }
 
+// WithGarbageCollector periodically deletes the stored artifacts which are not retained by the garbage collector.
+// Each collection only lists the blocks between the end of the previous collection and the chain head.
+func WithGarbageCollector(gc store.GarbageCollector, interval time.Duration) DaemonOption {
+	return func(d *Daemon) {
+		d.gc = gc
+		d.gcInterval = interval
+	}
+}
+
 func NewDaemon(gen *Generator, opts ...DaemonOption) *Daemon {
 	d := &Daemon{
 		Generator:     gen,
@@ -70,14 +88,23 @@ func (d *Daemon) SetMetrics(system, subsystem string, _ ...*tag.Tag) {
 		Subsystem: subsystem,
 		Help:      "Latest block number",
 	})
+
+	d.gcReclaimedBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Name:      "gc_reclaimed_bytes",
+		Namespace: system,
+		Subsystem: subsystem,
+		Help:      "Bytes reclaimed by the garbage collection of stored artifacts",
+	})
 }
 
 func (d *Daemon) Describe(ch chan<- *prometheus.Desc) {
 	d.latestBlockNumber.Describe(ch)
+	d.gcReclaimedBytes.Describe(ch)
 }
 
 func (d *Daemon) Collect(ch chan<- prometheus.Metric) {
 	d.latestBlockNumber.Collect(ch)
+	d.gcReclaimedBytes.Collect(ch)
 }
 
 func (d *Daemon) run(runCtx context.Context) {
@@ -91,6 +118,14 @@ func (d *Daemon) run(runCtx context.Context) {
 		d.processLatest(runCtx)
 		d.wg.Done()
 	}()
+
+	if d.gc != nil && d.gcInterval > 0 {
+		d.wg.Add(1)
+		go func() {
+			d.collectGarbage(runCtx)
+			d.wg.Done()
+		}()
+	}
 }
 
 func (d *Daemon) Stop(_ context.Context) error {
@@ -125,6 +160,7 @@ func (d *Daemon) listenLatest(runCtx context.Context) {
 				}
 			}
 			latest = block
+			d.head.Store(block)
 			d.latestBlockNumber.Set(float64(block.Number().Uint64()))
 		}
 
@@ -167,3 +203,38 @@ func (d *Daemon) processLatest(runCtx context.Context) {
 		}
 	}
 }
+
+// collectGarbage periodically deletes the stored artifacts which are not retained.
+func (d *Daemon) collectGarbage(runCtx context.Context) {
+	ticker := time.NewTicker(d.gcInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-d.stop:
+			return
+		}
+
+		head := d.head.Load()
+		if head == nil {
+			continue
+		}
+
+		logger := log.LoggerFromContext(runCtx)
+		report, err := d.gc.CollectRange(runCtx, d.ChainID.Uint64(), d.gcFrom, head.Number().Uint64(), false)
+		if err != nil {
+			logger.Error("Failed to collect garbage", zap.Error(err))
+		}
+		if report != nil {
+			d.gcFrom = report.NextBlock
+			d.gcReclaimedBytes.Add(float64(report.Bytes))
+			logger.Info(
+				"Collected garbage",
+				zap.Int("objects", len(report.Objects)),
+				zap.Int("blocks", report.Blocks),
+				zap.Int64("bytes", report.Bytes),
+			)
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 	input "github.com/kkrt-labs/zk-pig/src/prover-input"
 	"github.com/kkrt-labs/zk-pig/src/steps"
 	mocksteps "github.com/kkrt-labs/zk-pig/src/steps/mock"
+	"github.com/kkrt-labs/zk-pig/src/store"
 	mockstore "github.com/kkrt-labs/zk-pig/src/store/mock"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -83,3 +84,56 @@ func TestDaemon(t *testing.T) {
 	err = daemon.Stop(context.TODO())
 	require.NoError(t, err)
 }
+
+func TestDaemonGarbageCollector(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ethrpc := mockethrpc.NewMockClient(ctrl)
+	gc := mockstore.NewMockGarbageCollector(ctrl)
+
+	generator, err := NewGenerator(&Config{
+		ChainID:            big.NewInt(1),
+		RPC:                ethrpc,
+		Preflighter:        mocksteps.NewMockPreflight(ctrl),
+		Preparer:           mocksteps.NewMockPreparer(ctrl),
+		Executor:           mocksteps.NewMockExecutor(ctrl),
+		ProverInputStore:   mockstore.NewMockProverInputStore(ctrl),
+		PreflightDataStore: mockstore.NewMockPreflightDataStore(ctrl),
+	})
+	require.NoError(t, err)
+	generator.SetMetrics("test", "test")
+
+	daemon := NewDaemon(
+		generator,
+		WithFilter(FilterByBlockNumberModulo(2)), // skip generation for the test block
+		WithFetchInterval(100*time.Second),
+		WithGarbageCollector(gc, 10*time.Millisecond),
+	)
+	daemon.SetMetrics("test", "test")
+
+	ethrpc.EXPECT().BlockByNumber(gomock.Any(), nil).Return(gethtypes.NewBlockWithHeader(&gethtypes.Header{Number: big.NewInt(1)}), nil)
+
+	// Later collections start from the next block reported by the previous one
+	collected := make(chan struct{})
+	gomock.InOrder(
+		gc.EXPECT().CollectRange(gomock.Any(), uint64(1), uint64(0), uint64(1), false).Return(&store.GCReport{Bytes: 10, NextBlock: 1}, nil),
+		gc.EXPECT().CollectRange(gomock.Any(), uint64(1), uint64(1), uint64(1), false).DoAndReturn(func(_ context.Context, _, _, _ uint64, _ bool) (*store.GCReport, error) {
+			close(collected)
+			return &store.GCReport{NextBlock: 1}, nil
+		}),
+		gc.EXPECT().CollectRange(gomock.Any(), uint64(1), uint64(1), uint64(1), false).Return(&store.GCReport{NextBlock: 1}, nil).AnyTimes(),
+	)
+
+	err = daemon.Start(context.TODO())
+	require.NoError(t, err)
+
+	select {
+	case <-collected:
+	case <-time.After(time.Second):
+		t.Fatal("garbage collection did not run")
+	}
+
+	err = daemon.Stop(context.TODO())
+	require.NoError(t, err)
+}
@@ -2,18 +2,27 @@ package generator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"time"
 
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
 	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/kkrt-labs/go-utils/app/svc"
 	ethrpc "github.com/kkrt-labs/go-utils/ethereum/rpc"
+	"github.com/kkrt-labs/go-utils/log"
 	"github.com/kkrt-labs/go-utils/tag"
 	input "github.com/kkrt-labs/zk-pig/src/prover-input"
 	"github.com/kkrt-labs/zk-pig/src/steps"
 	inputstore "github.com/kkrt-labs/zk-pig/src/store"
+	"github.com/kkrt-labs/zk-pig/src/telemetry"
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
 )
 
 type step int
@@ -26,6 +35,7 @@ const (
 	StoreProverInputStep
 	LoadProverInputStep
 	ExecuteStep
+	VerifyStep
 	FinalStep
 	ErrorStep
 )
@@ -38,6 +48,7 @@ var stepNames = []string{
 	"storeProverInput",
 	"loadProverInput",
 	"execute",
+	"verify",
 	"final",
 	"error",
 }
@@ -46,6 +57,11 @@ func (s step) String() string {
 	return stepNames[s]
 }
 
+// startSpan starts the span of a generation step
+func startSpan(ctx context.Context, s step) (context.Context, trace.Span) {
+	return telemetry.Tracer().Start(ctx, fmt.Sprintf("generator.%s", s))
+}
+
 type Config struct {
 	ChainID *big.Int
 	RPC     ethrpc.Client
@@ -53,6 +69,7 @@ type Config struct {
 	Preflighter steps.Preflight
 	Preparer    steps.Preparer
 	Executor    steps.Executor
+	Verifier    steps.Verifier // Optional, verifies execution results against the chain
 
 	PreflightDataStore inputstore.PreflightDataStore
 	ProverInputStore   inputstore.ProverInputStore
@@ -68,6 +85,7 @@ type Generator struct {
 	Preflighter steps.Preflight
 	Preparer    steps.Preparer
 	Executor    steps.Executor
+	Verifier    steps.Verifier
 
 	PreflightDataStore inputstore.PreflightDataStore
 	ProverInputStore   inputstore.ProverInputStore
@@ -79,6 +97,11 @@ type Generator struct {
 	countOfBlocksPerStep  *prometheus.GaugeVec
 	generationTimePerStep *prometheus.HistogramVec
 	generateErrorCount    *prometheus.GaugeVec
+	witnessItems          *prometheus.HistogramVec
+	witnessBytes          *prometheus.HistogramVec
+	proverInputBytes      *prometheus.HistogramVec
+	accessedAccounts      prometheus.Counter
+	accessedSlots         prometheus.Counter
 
 	*svc.Tagged
 }
@@ -90,6 +113,7 @@ func NewGenerator(cfg *Config) (*Generator, error) {
 		Preflighter:               cfg.Preflighter,
 		Preparer:                  cfg.Preparer,
 		Executor:                  cfg.Executor,
+		Verifier:                  cfg.Verifier,
 		PreflightDataStore:        cfg.PreflightDataStore,
 		ProverInputStore:          cfg.ProverInputStore,
 		storePreflightDataEnabled: cfg.StorePreflightDataEnabled,
@@ -116,6 +140,8 @@ func (s *Generator) Start(ctx context.Context) error {
 
 var (
 	generationTimeBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 25, 50, 100, 250, 500}
+	witnessItemsBuckets   = prometheus.ExponentialBuckets(1, 4, 10)    // 1 to 262144 items
+	sizeBuckets           = prometheus.ExponentialBuckets(1024, 4, 10) // 1KiB to 256MiB
 )
 
 func (s *Generator) SetMetrics(system, subsystem string, _ ...*tag.Tag) {
@@ -155,6 +181,44 @@ func (s *Generator) SetMetrics(system, subsystem string, _ ...*tag.Tag) {
 		Subsystem: subsystem,
 		Help:      "Count of errors during the generation of prover input",
 	}, []string{"step"})
+
+	s.witnessItems = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:      "witness_items",
+		Namespace: system,
+		Subsystem: subsystem,
+		Help:      "Number of items per component of the prover input witness of a block",
+		Buckets:   witnessItemsBuckets,
+	}, []string{"component"})
+
+	s.witnessBytes = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:      "witness_bytes",
+		Namespace: system,
+		Subsystem: subsystem,
+		Help:      "Total size per component of the prover input witness of a block (in bytes)",
+		Buckets:   sizeBuckets,
+	}, []string{"component"})
+
+	s.proverInputBytes = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:      "prover_input_bytes",
+		Namespace: system,
+		Subsystem: subsystem,
+		Help:      "Size of the stored prover input of a block per content type (in bytes, before compression)",
+		Buckets:   sizeBuckets,
+	}, []string{"content_type"})
+
+	s.accessedAccounts = prometheus.NewCounter(prometheus.CounterOpts{
+		Name:      "accessed_accounts",
+		Namespace: system,
+		Subsystem: subsystem,
+		Help:      "Count of accounts accessed during the execution of prepared blocks",
+	})
+
+	s.accessedSlots = prometheus.NewCounter(prometheus.CounterOpts{
+		Name:      "accessed_storage_slots",
+		Namespace: system,
+		Subsystem: subsystem,
+		Help:      "Count of storage slots accessed during the execution of prepared blocks",
+	})
 }
 
 func (s *Generator) Describe(ch chan<- *prometheus.Desc) {
@@ -163,6 +227,11 @@ func (s *Generator) Describe(ch chan<- *prometheus.Desc) {
 	s.countOfBlocksPerStep.Describe(ch)
 	s.generationTimePerStep.Describe(ch)
 	s.generateErrorCount.Describe(ch)
+	s.witnessItems.Describe(ch)
+	s.witnessBytes.Describe(ch)
+	s.proverInputBytes.Describe(ch)
+	s.accessedAccounts.Describe(ch)
+	s.accessedSlots.Describe(ch)
 }
 
 func (s *Generator) Collect(ch chan<- prometheus.Metric) {
@@ -171,6 +240,11 @@ func (s *Generator) Collect(ch chan<- prometheus.Metric) {
 	s.countOfBlocksPerStep.Collect(ch)
 	s.generationTimePerStep.Collect(ch)
 	s.generateErrorCount.Collect(ch)
+	s.witnessItems.Collect(ch)
+	s.witnessBytes.Collect(ch)
+	s.proverInputBytes.Collect(ch)
+	s.accessedAccounts.Collect(ch)
+	s.accessedSlots.Collect(ch)
 }
 
 func (s *Generator) Generate(ctx context.Context, blockNumber *big.Int) (*input.ProverInput, error) {
@@ -200,6 +274,22 @@ func (s *Generator) Generate(ctx context.Context, blockNumber *big.Int) (*input.
 }
 
 func (s *Generator) generate(ctx context.Context, block *gethtypes.Block) (*input.ProverInput, error) {
+	ctx, span := telemetry.Tracer().Start(
+		ctx,
+		"generator.generate",
+		trace.WithAttributes(
+			attribute.String("chain.id", s.ChainID.String()),
+			attribute.Int64("block.number", block.Number().Int64()),
+			attribute.String("block.hash", block.Hash().Hex()),
+		),
+	)
+	in, err := s.runGenerate(ctx, block)
+	telemetry.End(span, err)
+
+	return in, err
+}
+
+func (s *Generator) runGenerate(ctx context.Context, block *gethtypes.Block) (*input.ProverInput, error) {
 	s.blocks.WithLabelValues(block.Number().String()).Inc()
 	defer s.blocks.DeleteLabelValues(block.Number().String())
 
@@ -229,7 +319,7 @@ func (s *Generator) generate(ctx context.Context, block *gethtypes.Block) (*inpu
 		return nil, err
 	}
 
-	err = s.execute(ctx, in)
+	res, err := s.execute(ctx, in)
 	if err != nil {
 		s.generationTime.
 			WithLabelValues(ExecuteStep.String()).
@@ -237,6 +327,16 @@ func (s *Generator) generate(ctx context.Context, block *gethtypes.Block) (*inpu
 		return nil, err
 	}
 
+	if s.Verifier != nil {
+		err = s.verify(ctx, in, res)
+		if err != nil {
+			s.generationTime.
+				WithLabelValues(VerifyStep.String()).
+				Observe(time.Since(start).Seconds())
+			return nil, err
+		}
+	}
+
 	err = s.storeProverInput(ctx, in)
 	if err != nil {
 		s.generationTime.
@@ -317,6 +417,17 @@ func (s *Generator) Prepare(ctx context.Context, blockNumber *big.Int) (*input.P
 }
 
 func (s *Generator) Execute(ctx context.Context, blockNumber *big.Int) error {
+	return s.executeStored(ctx, blockNumber, nil)
+}
+
+// ExecuteByHash executes the stored prover input of the block with the given number and hash
+// It executes the prover inputs of competing blocks at the same height, which are kept by the hash store layout.
+func (s *Generator) ExecuteByHash(ctx context.Context, blockNumber *big.Int, blockHash gethcommon.Hash) error {
+	return s.executeStored(ctx, blockNumber, &blockHash)
+}
+
+// executeStored executes a stored prover input, loaded by hash if blockHash is set and by number otherwise
+func (s *Generator) executeStored(ctx context.Context, blockNumber *big.Int, blockHash *gethcommon.Hash) error {
 	ctx = s.Context(ctx)
 	ctx = tag.WithTags(
 		ctx,
@@ -324,7 +435,7 @@ func (s *Generator) Execute(ctx context.Context, blockNumber *big.Int) error {
 		tag.Key("block.number").Int64(blockNumber.Int64()),
 	)
 
-	in, err := s.loadProverInput(ctx, blockNumber)
+	in, err := s.loadProverInput(ctx, blockNumber, blockHash)
 	if err != nil {
 		return err
 	}
@@ -335,11 +446,44 @@ func (s *Generator) Execute(ctx context.Context, blockNumber *big.Int) error {
 		tag.Key("block.hash").String(in.Blocks[0].Header.Hash().Hex()),
 	)
 
-	err = s.execute(ctx, in)
+	res, err := s.execute(ctx, in)
 	if err != nil {
 		return err
 	}
 
+	if s.Verifier != nil {
+		return s.verify(ctx, in, res)
+	}
+
+	return nil
+}
+
+// Migrate upgrades the stored prover input of the given block to the current prover input version.
+// It is re-stored in place (same key and content type) if it was at an older version.
+func (s *Generator) Migrate(ctx context.Context, blockNumber *big.Int) error {
+	if s.ChainID == nil {
+		return ErrChainNotConfigured
+	}
+
+	ctx = s.Context(ctx)
+	ctx = tag.WithTags(
+		ctx,
+		tag.Key("chain.id").String(s.ChainID.String()),
+		tag.Key("block.number").Int64(blockNumber.Int64()),
+	)
+
+	from, err := s.ProverInputStore.MigrateProverInput(ctx, s.ChainID.Uint64(), blockNumber.Uint64())
+	if err != nil {
+		return fmt.Errorf("failed to migrate prover input: %w", err)
+	}
+
+	// Prover inputs already at the current version are left untouched
+	if from == input.CurrentVersion {
+		log.LoggerFromContext(ctx).Debug("Prover input already at current version", zap.String("version", from))
+		return nil
+	}
+
+	log.LoggerFromContext(ctx).Info("Migrated prover input", zap.String("from", from), zap.String("to", input.CurrentVersion))
 	return nil
 }
 
@@ -347,8 +491,10 @@ func (s *Generator) preflight(ctx context.Context, block *gethtypes.Block) (*ste
 	s.countOfBlocksPerStep.WithLabelValues(PreflightStep.String()).Inc()
 	defer s.countOfBlocksPerStep.WithLabelValues(PreflightStep.String()).Dec()
 
+	ctx, span := startSpan(ctx, PreflightStep)
 	start := time.Now()
 	data, err := s.runPreflight(ctx, block)
+	telemetry.End(span, err)
 	if err != nil {
 		s.generateErrorCount.WithLabelValues(PreflightStep.String()).Inc()
 		s.countOfBlocksPerStep.WithLabelValues(ErrorStep.String()).Inc()
@@ -371,46 +517,146 @@ func (s *Generator) prepare(ctx context.Context, data *steps.PreflightData) (*in
 	s.countOfBlocksPerStep.WithLabelValues(PrepareStep.String()).Inc()
 	defer s.countOfBlocksPerStep.WithLabelValues(PrepareStep.String()).Dec()
 
+	ctx, span := startSpan(ctx, PrepareStep)
 	start := time.Now()
 	in, err := s.runPrepare(ctx, data)
+	telemetry.End(span, err)
 	s.generationTimePerStep.WithLabelValues(PrepareStep.String()).Observe(time.Since(start).Seconds())
 
 	if err != nil {
 		s.generateErrorCount.WithLabelValues(PrepareStep.String()).Inc()
 		s.countOfBlocksPerStep.WithLabelValues(ErrorStep.String()).Inc()
+
+		var execErr *steps.ExecutionError
+		if errors.As(err, &execErr) {
+			// The prover input could not be prepared, so the block is identified from the preflight data
+			failed := &input.ProverInput{
+				ChainConfig: data.ChainConfig,
+				Blocks:      []*input.Block{{Header: data.Block.Header.Header()}},
+			}
+			err = s.verifyFailedExecution(ctx, failed, execErr.Result, err)
+		}
+	} else {
+		s.observeProverInput(in)
 	}
 
 	return in, err
 }
 
+// observeProverInput records the witness composition metrics of a prepared prover input
+// The size of the prover input is recorded when it is stored (see ReportProverInputSize)
+func (s *Generator) observeProverInput(in *input.ProverInput) {
+	if in.Witness != nil {
+		s.witnessItems.WithLabelValues("state").Observe(float64(len(in.Witness.State)))
+		s.witnessBytes.WithLabelValues("state").Observe(float64(totalSize(in.Witness.State)))
+		s.witnessItems.WithLabelValues("codes").Observe(float64(len(in.Witness.Codes)))
+		s.witnessBytes.WithLabelValues("codes").Observe(float64(totalSize(in.Witness.Codes)))
+
+		ancestorsSize := 0
+		for _, header := range in.Witness.Ancestors {
+			b, err := rlp.EncodeToBytes(header)
+			if err != nil {
+				continue
+			}
+			ancestorsSize += len(b)
+		}
+		s.witnessItems.WithLabelValues("ancestors").Observe(float64(len(in.Witness.Ancestors)))
+		s.witnessBytes.WithLabelValues("ancestors").Observe(float64(ancestorsSize))
+	}
+}
+
+func totalSize(items [][]byte) int {
+	size := 0
+	for _, item := range items {
+		size += len(item)
+	}
+	return size
+}
+
+// ReportProverInputSize records the size of a stored prover input in the given content type
+// It is meant to be passed to the prover input store (see inputstore.WithSizeReporter), so only the bytes actually written are measured
+func (s *Generator) ReportProverInputSize(_ context.Context, contentType inputstore.ContentType, size int) {
+	s.proverInputBytes.WithLabelValues(contentType.String()).Observe(float64(size))
+}
+
+// ReportAccesses records the number of accounts and storage slots accessed during the execution of a prepared block
+// It is meant to be passed to the preparer (see steps.WithAccessReporter)
+func (s *Generator) ReportAccesses(_ context.Context, counts *steps.AccessCounts) {
+	s.accessedAccounts.Add(float64(counts.Accounts))
+	s.accessedSlots.Add(float64(counts.Slots))
+}
+
 func (s *Generator) runPrepare(ctx context.Context, data *steps.PreflightData) (*input.ProverInput, error) {
 	in, err := s.Preparer.Prepare(ctx, data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to prepare prover inputs: %v", err)
+		return nil, fmt.Errorf("failed to prepare prover inputs: %w", err)
 	}
 	return in, nil
 }
 
-func (s *Generator) execute(ctx context.Context, in *input.ProverInput) error {
+func (s *Generator) execute(ctx context.Context, in *input.ProverInput) (*core.ProcessResult, error) {
 	s.countOfBlocksPerStep.WithLabelValues(ExecuteStep.String()).Inc()
 	defer s.countOfBlocksPerStep.WithLabelValues(ExecuteStep.String()).Dec()
 
+	ctx, span := startSpan(ctx, ExecuteStep)
 	start := time.Now()
-	err := s.runExecute(ctx, in)
+	res, err := s.runExecute(ctx, in)
+	telemetry.End(span, err)
 	s.generationTimePerStep.WithLabelValues(ExecuteStep.String()).Observe(time.Since(start).Seconds())
 
 	if err != nil {
 		s.generateErrorCount.WithLabelValues(ExecuteStep.String()).Inc()
 		s.countOfBlocksPerStep.WithLabelValues(ErrorStep.String()).Inc()
+		err = s.verifyFailedExecution(ctx, in, res, err)
+	}
+
+	return res, err
+}
+
+func (s *Generator) runExecute(ctx context.Context, in *input.ProverInput) (*core.ProcessResult, error) {
+	res, err := s.Executor.Execute(ctx, in)
+	if err != nil {
+		return res, fmt.Errorf("failed to execute block by basing on prover inputs: %w", err)
+	}
+	return res, nil
+}
+
+// verifyFailedExecution compares the receipts of a failed execution with the canonical receipts (if a verifier is configured)
+// When the block could be processed but not validated, the receipts mismatch is joined to err so it tells which transaction diverged.
+func (s *Generator) verifyFailedExecution(ctx context.Context, in *input.ProverInput, res *core.ProcessResult, err error) error {
+	if s.Verifier == nil || res == nil {
+		return err
+	}
+
+	if verifyErr := s.verify(ctx, in, res); verifyErr != nil {
+		return errors.Join(err, verifyErr)
+	}
+
+	return err
+}
+
+func (s *Generator) verify(ctx context.Context, in *input.ProverInput, res *core.ProcessResult) error {
+	s.countOfBlocksPerStep.WithLabelValues(VerifyStep.String()).Inc()
+	defer s.countOfBlocksPerStep.WithLabelValues(VerifyStep.String()).Dec()
+
+	ctx, span := startSpan(ctx, VerifyStep)
+	start := time.Now()
+	err := s.runVerify(ctx, in, res)
+	telemetry.End(span, err)
+	s.generationTimePerStep.WithLabelValues(VerifyStep.String()).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		s.generateErrorCount.WithLabelValues(VerifyStep.String()).Inc()
+		s.countOfBlocksPerStep.WithLabelValues(ErrorStep.String()).Inc()
 	}
 
 	return err
 }
 
-func (s *Generator) runExecute(ctx context.Context, in *input.ProverInput) error {
-	_, err := s.Executor.Execute(ctx, in)
+func (s *Generator) runVerify(ctx context.Context, in *input.ProverInput, res *core.ProcessResult) error {
+	err := s.Verifier.Verify(ctx, in, res)
 	if err != nil {
-		return fmt.Errorf("failed to execute block by basing on prover inputs: %v", err)
+		return fmt.Errorf("failed to verify execution against chain: %w", err)
 	}
 	return nil
 }
@@ -423,8 +669,10 @@ func (s *Generator) loadPreflightData(ctx context.Context, blockNumber *big.Int)
 		return nil, ErrChainNotConfigured
 	}
 
+	ctx, span := startSpan(ctx, LoadPreflightDataStep)
 	start := time.Now()
 	data, err := s.runLoadPreflightData(ctx, blockNumber)
+	telemetry.End(span, err)
 	s.generationTimePerStep.WithLabelValues(LoadPreflightDataStep.String()).Observe(time.Since(start).Seconds())
 
 	if err != nil {
@@ -447,8 +695,10 @@ func (s *Generator) storePreflightData(ctx context.Context, data *steps.Prefligh
 	s.countOfBlocksPerStep.WithLabelValues(StorePreflightDataStep.String()).Inc()
 	defer s.countOfBlocksPerStep.WithLabelValues(StorePreflightDataStep.String()).Dec()
 
+	ctx, span := startSpan(ctx, StorePreflightDataStep)
 	start := time.Now()
 	err := s.runStorePreflightData(ctx, data)
+	telemetry.End(span, err)
 	s.generationTimePerStep.WithLabelValues(StorePreflightDataStep.String()).Observe(time.Since(start).Seconds())
 
 	if err != nil {
@@ -467,7 +717,7 @@ func (s *Generator) runStorePreflightData(ctx context.Context, data *steps.Prefl
 	return nil
 }
 
-func (s *Generator) loadProverInput(ctx context.Context, blockNumber *big.Int) (*input.ProverInput, error) {
+func (s *Generator) loadProverInput(ctx context.Context, blockNumber *big.Int, blockHash *gethcommon.Hash) (*input.ProverInput, error) {
 	s.countOfBlocksPerStep.WithLabelValues(LoadProverInputStep.String()).Inc()
 	defer s.countOfBlocksPerStep.WithLabelValues(LoadProverInputStep.String()).Dec()
 
@@ -475,8 +725,10 @@ func (s *Generator) loadProverInput(ctx context.Context, blockNumber *big.Int) (
 		return nil, ErrChainNotConfigured
 	}
 
+	ctx, span := startSpan(ctx, LoadProverInputStep)
 	start := time.Now()
-	in, err := s.runLoadProverInput(ctx, blockNumber)
+	in, err := s.runLoadProverInput(ctx, blockNumber, blockHash)
+	telemetry.End(span, err)
 	s.generationTimePerStep.WithLabelValues(LoadProverInputStep.String()).Observe(time.Since(start).Seconds())
 
 	if err != nil {
@@ -487,8 +739,16 @@ func (s *Generator) loadProverInput(ctx context.Context, blockNumber *big.Int) (
 	return in, err
 }
 
-func (s *Generator) runLoadProverInput(ctx context.Context, blockNumber *big.Int) (*input.ProverInput, error) {
-	in, err := s.ProverInputStore.LoadProverInput(ctx, s.ChainID.Uint64(), blockNumber.Uint64())
+func (s *Generator) runLoadProverInput(ctx context.Context, blockNumber *big.Int, blockHash *gethcommon.Hash) (*input.ProverInput, error) {
+	var (
+		in  *input.ProverInput
+		err error
+	)
+	if blockHash != nil {
+		in, err = s.ProverInputStore.LoadProverInputByHash(ctx, s.ChainID.Uint64(), blockNumber.Uint64(), *blockHash)
+	} else {
+		in, err = s.ProverInputStore.LoadProverInput(ctx, s.ChainID.Uint64(), blockNumber.Uint64())
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to load prover input: %v", err)
 	}
@@ -499,8 +759,10 @@ func (s *Generator) storeProverInput(ctx context.Context, in *input.ProverInput)
 	s.countOfBlocksPerStep.WithLabelValues(StoreProverInputStep.String()).Inc()
 	defer s.countOfBlocksPerStep.WithLabelValues(StoreProverInputStep.String()).Dec()
 
+	ctx, span := startSpan(ctx, StoreProverInputStep)
 	start := time.Now()
 	err := s.runStoreProverInput(ctx, in)
+	telemetry.End(span, err)
 	s.generationTimePerStep.WithLabelValues(StoreProverInputStep.String()).Observe(time.Since(start).Seconds())
 
 	if err != nil {
@@ -2,19 +2,33 @@ package generator
 
 import (
 	"context"
+	"fmt"
 	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/core"
 	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/kkrt-labs/go-utils/app/svc"
+	ethrpctypes "github.com/kkrt-labs/go-utils/ethereum/rpc"
 	mockethrpc "github.com/kkrt-labs/go-utils/ethereum/rpc/mock"
 	"github.com/kkrt-labs/go-utils/tag"
 	input "github.com/kkrt-labs/zk-pig/src/prover-input"
 	"github.com/kkrt-labs/zk-pig/src/steps"
 	mocksteps "github.com/kkrt-labs/zk-pig/src/steps/mock"
+	inputstore "github.com/kkrt-labs/zk-pig/src/store"
 	mockstore "github.com/kkrt-labs/zk-pig/src/store/mock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
 
 	"go.uber.org/mock/gomock"
 )
@@ -96,6 +110,29 @@ func TestGenerator(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("ExecuteByHash#NoError", func(t *testing.T) {
+		blockHash := testInput.Blocks[0].Header.Hash()
+		loadInputCall := proverInputStore.EXPECT().LoadProverInputByHash(gomock.Any(), uint64(1), uint64(1), blockHash).Return(testInput, nil)
+		executor.EXPECT().Execute(gomock.Any(), testInput).Return(nil, nil).After(loadInputCall)
+
+		err := generator.ExecuteByHash(context.TODO(), big.NewInt(1), blockHash)
+		require.NoError(t, err)
+	})
+
+	t.Run("Migrate#NoError", func(t *testing.T) {
+		proverInputStore.EXPECT().MigrateProverInput(gomock.Any(), uint64(1), uint64(1)).Return(input.VersionUnversioned, nil)
+
+		err := generator.Migrate(context.TODO(), big.NewInt(1))
+		require.NoError(t, err)
+	})
+
+	t.Run("Migrate#CurrentVersion", func(t *testing.T) {
+		proverInputStore.EXPECT().MigrateProverInput(gomock.Any(), uint64(1), uint64(1)).Return(input.CurrentVersion, nil)
+
+		err := generator.Migrate(context.TODO(), big.NewInt(1))
+		require.NoError(t, err)
+	})
+
 	t.Run("Generate#NoError", func(t *testing.T) {
 		rpcCall := ethrpc.EXPECT().BlockByNumber(gomock.Any(), big.NewInt(1)).Return(testBlock, nil)
 		preflightCall := preflighter.EXPECT().Preflight(gomock.Any(), testBlock).Return(testData, nil).After(rpcCall)
@@ -188,3 +225,211 @@ func TestGeneratorWithRPCNotConfigured(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestGeneratorWithVerifier(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ethrpc := mockethrpc.NewMockClient(ctrl)
+
+	preflighter := mocksteps.NewMockPreflight(ctrl)
+	preparer := mocksteps.NewMockPreparer(ctrl)
+	executor := mocksteps.NewMockExecutor(ctrl)
+	verifier := mocksteps.NewMockVerifier(ctrl)
+
+	proverInputStore := mockstore.NewMockProverInputStore(ctrl)
+	preflightDataStore := mockstore.NewMockPreflightDataStore(ctrl)
+
+	generator, err := NewGenerator(&Config{
+		RPC:                ethrpc,
+		Preflighter:        preflighter,
+		Preparer:           preparer,
+		Executor:           executor,
+		Verifier:           verifier,
+		ProverInputStore:   proverInputStore,
+		PreflightDataStore: preflightDataStore,
+	})
+	require.NoError(t, err)
+
+	ethrpc.EXPECT().ChainID(gomock.Any()).Return(big.NewInt(1), nil)
+	generator.SetMetrics("test", "generator")
+	err = generator.Start(context.TODO())
+	require.NoError(t, err)
+
+	testBlock := gethtypes.NewBlockWithHeader(&gethtypes.Header{Number: big.NewInt(1)})
+	testData := new(steps.PreflightData)
+	testInput := &input.ProverInput{
+		Blocks: []*input.Block{
+			{
+				Header: testBlock.Header(),
+			},
+		},
+	}
+	testResult := new(core.ProcessResult)
+
+	t.Run("Execute#NoError", func(t *testing.T) {
+		loadInputCall := proverInputStore.EXPECT().LoadProverInput(gomock.Any(), uint64(1), uint64(1)).Return(testInput, nil)
+		executeCall := executor.EXPECT().Execute(gomock.Any(), testInput).Return(testResult, nil).After(loadInputCall)
+		verifier.EXPECT().Verify(gomock.Any(), testInput, testResult).Return(nil).After(executeCall)
+
+		err := generator.Execute(context.TODO(), big.NewInt(1))
+		require.NoError(t, err)
+	})
+
+	t.Run("Generate#VerifyError", func(t *testing.T) {
+		rpcCall := ethrpc.EXPECT().BlockByNumber(gomock.Any(), big.NewInt(1)).Return(testBlock, nil)
+		preflightCall := preflighter.EXPECT().Preflight(gomock.Any(), testBlock).Return(testData, nil).After(rpcCall)
+		prepareCall := preparer.EXPECT().Prepare(gomock.Any(), testData).Return(testInput, nil).After(preflightCall)
+		executeCall := executor.EXPECT().Execute(gomock.Any(), testInput).Return(testResult, nil).After(prepareCall)
+		verifier.EXPECT().Verify(gomock.Any(), testInput, testResult).Return(&steps.ReceiptsMismatchError{}).After(executeCall)
+
+		_, err := generator.Generate(context.TODO(), big.NewInt(1))
+		var mismatchErr *steps.ReceiptsMismatchError
+		assert.ErrorAs(t, err, &mismatchErr)
+	})
+
+	t.Run("Execute#ValidationError", func(t *testing.T) {
+		loadInputCall := proverInputStore.EXPECT().LoadProverInput(gomock.Any(), uint64(1), uint64(1)).Return(testInput, nil)
+		executeErr := &steps.ExecutionError{Result: testResult, Err: fmt.Errorf("invalid receipt root hash")}
+		executeCall := executor.EXPECT().Execute(gomock.Any(), testInput).Return(testResult, executeErr).After(loadInputCall)
+		verifier.EXPECT().Verify(gomock.Any(), testInput, testResult).Return(&steps.ReceiptsMismatchError{}).After(executeCall)
+
+		err := generator.Execute(context.TODO(), big.NewInt(1))
+		var mismatchErr *steps.ReceiptsMismatchError
+		assert.ErrorAs(t, err, &mismatchErr)
+		assert.ErrorContains(t, err, "invalid receipt root hash")
+	})
+
+	t.Run("Execute#ProcessingError", func(t *testing.T) {
+		// No receipts to compare when the block could not be processed
+		loadInputCall := proverInputStore.EXPECT().LoadProverInput(gomock.Any(), uint64(1), uint64(1)).Return(testInput, nil)
+		executor.EXPECT().Execute(gomock.Any(), testInput).Return(nil, fmt.Errorf("block processing failed")).After(loadInputCall)
+
+		err := generator.Execute(context.TODO(), big.NewInt(1))
+		assert.ErrorContains(t, err, "block processing failed")
+	})
+
+	t.Run("Generate#PrepareValidationError", func(t *testing.T) {
+		data := &steps.PreflightData{
+			Block:       new(ethrpctypes.Block).FromBlock(testBlock, params.MainnetChainConfig),
+			ChainConfig: params.MainnetChainConfig,
+		}
+		rpcCall := ethrpc.EXPECT().BlockByNumber(gomock.Any(), big.NewInt(1)).Return(testBlock, nil)
+		preflightCall := preflighter.EXPECT().Preflight(gomock.Any(), testBlock).Return(data, nil).After(rpcCall)
+		prepareErr := &steps.ExecutionError{Result: testResult, Err: fmt.Errorf("invalid gas used")}
+		prepareCall := preparer.EXPECT().Prepare(gomock.Any(), data).Return(nil, prepareErr).After(preflightCall)
+		verifier.EXPECT().Verify(gomock.Any(), gomock.Any(), testResult).DoAndReturn(
+			func(_ context.Context, in *input.ProverInput, _ *core.ProcessResult) error {
+				assert.Equal(t, testBlock.Hash(), in.Blocks[0].Header.Hash())
+				return &steps.ReceiptsMismatchError{}
+			},
+		).After(prepareCall)
+
+		_, err := generator.Generate(context.TODO(), big.NewInt(1))
+		var mismatchErr *steps.ReceiptsMismatchError
+		assert.ErrorAs(t, err, &mismatchErr)
+		assert.ErrorContains(t, err, "invalid gas used")
+	})
+}
+
+func TestGeneratorWitnessMetrics(t *testing.T) {
+	generator, err := NewGenerator(&Config{})
+	require.NoError(t, err)
+	generator.SetMetrics("test", "generator")
+
+	header := &gethtypes.Header{Number: big.NewInt(1)}
+	headerRLP, err := rlp.EncodeToBytes(header)
+	require.NoError(t, err)
+
+	in := &input.ProverInput{
+		ChainConfig: &params.ChainConfig{ChainID: big.NewInt(1)},
+		Blocks:      []*input.Block{{Header: header}},
+		Witness: &input.Witness{
+			State:     [][]byte{{0x1, 0x2}, {0x3}},
+			Codes:     [][]byte{{0x60, 0x00, 0x60}},
+			Ancestors: []*gethtypes.Header{header},
+		},
+	}
+	generator.observeProverInput(in)
+
+	for _, tc := range []struct {
+		component string
+		items     float64
+		bytes     float64
+	}{
+		{"state", 2, 3},
+		{"codes", 1, 3},
+		{"ancestors", 1, float64(len(headerRLP))},
+	} {
+		items := generator.witnessItems.WithLabelValues(tc.component).(prometheus.Histogram)
+		assert.Equal(t, tc.items, histogramSum(t, items), tc.component)
+		bytes := generator.witnessBytes.WithLabelValues(tc.component).(prometheus.Histogram)
+		assert.Equal(t, tc.bytes, histogramSum(t, bytes), tc.component)
+	}
+
+	// Only the stored content type is measured
+	generator.ReportProverInputSize(context.TODO(), inputstore.ContentTypeProtobuf, 2048)
+	size := generator.proverInputBytes.WithLabelValues("application/protobuf").(prometheus.Histogram)
+	assert.Equal(t, float64(2048), histogramSum(t, size))
+	assert.Equal(t, 1, testutil.CollectAndCount(generator.proverInputBytes))
+
+	generator.ReportAccesses(context.TODO(), &steps.AccessCounts{Accounts: 3, Slots: 5})
+	generator.ReportAccesses(context.TODO(), &steps.AccessCounts{Accounts: 1, Slots: 2})
+	assert.Equal(t, float64(4), testutil.ToFloat64(generator.accessedAccounts))
+	assert.Equal(t, float64(7), testutil.ToFloat64(generator.accessedSlots))
+}
+
+func histogramSum(t *testing.T, h prometheus.Histogram) float64 {
+	m := new(dto.Metric)
+	require.NoError(t, h.Write(m))
+	return m.GetHistogram().GetSampleSum()
+}
+
+func TestGeneratorSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(tp)
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	preflighter := mocksteps.NewMockPreflight(ctrl)
+	preparer := mocksteps.NewMockPreparer(ctrl)
+	executor := mocksteps.NewMockExecutor(ctrl)
+	proverInputStore := mockstore.NewMockProverInputStore(ctrl)
+
+	generator, err := NewGenerator(&Config{
+		ChainID:          big.NewInt(1),
+		Preflighter:      preflighter,
+		Preparer:         preparer,
+		Executor:         executor,
+		ProverInputStore: proverInputStore,
+	})
+	require.NoError(t, err)
+	generator.SetMetrics("test", "generator")
+
+	testBlock := gethtypes.NewBlockWithHeader(&gethtypes.Header{Number: big.NewInt(1)})
+	testData := new(steps.PreflightData)
+	testInput := &input.ProverInput{ChainConfig: &params.ChainConfig{ChainID: big.NewInt(1)}}
+
+	preflighter.EXPECT().Preflight(gomock.Any(), testBlock).Return(testData, nil)
+	preparer.EXPECT().Prepare(gomock.Any(), testData).Return(testInput, nil)
+	executor.EXPECT().Execute(gomock.Any(), testInput).Return(nil, fmt.Errorf("test error"))
+
+	_, err = generator.generate(context.TODO(), testBlock)
+	require.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 4)
+
+	root := spans[3]
+	assert.Equal(t, "generator.generate", root.Name())
+	assert.Equal(t, codes.Error, root.Status().Code)
+	for i, name := range []string{"generator.preflight", "generator.prepare", "generator.execute"} {
+		assert.Equal(t, name, spans[i].Name())
+		assert.Equal(t, root.SpanContext().SpanID(), spans[i].Parent().SpanID(), name)
+	}
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, codes.Error, spans[2].Status().Code)
+}
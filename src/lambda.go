@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/kkrt-labs/zk-pig/src/telemetry"
 )
 
 // StartLambdaWithApp is an utility function to facilitate the creation of a lambda function with an app.
@@ -13,22 +14,22 @@ func StartLambdaWithApp(ctx context.Context, createHandler func(ctx context.Cont
 		return err
 	}
 
-	handler := createHandler(ctx, app)
+	// Options apply to the handler function while the app context is set on the wrapping handler
+	// that continues the trace propagated in the event (if any)
+	handler := telemetry.LambdaHandler(createHandler(ctx, app), opts...)
 
 	err = app.Start(ctx)
 	if err != nil {
 		return err
 	}
 
-	opts = append(
-		opts,
+	lambda.StartWithOptions(
+		handler,
 		lambda.WithContext(app.Context(ctx)),
 		lambda.WithEnableSIGTERM(func() {
 			err = app.Stop(ctx)
 		}),
 	)
 
-	lambda.StartWithOptions(handler, opts...)
-
 	return err
 }
@@ -8,16 +8,19 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	gethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/kkrt-labs/zk-pig/src/ethereum/op"
 )
 
 // ProverInput contains the data expected by an EVM prover engine to execute & prove the block.
 // It contains the minimal partial state & chain data necessary for processing the block and validating the final state.
 type ProverInput struct {
-	Version     string              `json:"version"`         // Prover Input version
-	Blocks      []*Block            `json:"blocks"`          // Block to execute
-	Witness     *Witness            `json:"witness"`         // Ancestors of the block that are accessed during the block execution
-	ChainConfig *params.ChainConfig `json:"chainConfig"`     // Chain configuration
-	Extra       *Extra              `json:"extra,omitempty"` // Extra data
+	Version     string              `json:"version"`           // Prover Input version
+	Blocks      []*Block            `json:"blocks"`            // Block to execute
+	Witness     *Witness            `json:"witness"`           // Ancestors of the block that are accessed during the block execution
+	ChainConfig *params.ChainConfig `json:"chainConfig"`       // Chain configuration
+	OpStack     *op.Config          `json:"opStack,omitempty"` // OP Stack configuration (only set for OP Stack chains)
+	Extra       *Extra              `json:"extra,omitempty"`   // Extra data
+
 }
 
 type Witness struct {
@@ -73,6 +76,7 @@ type Block struct {
 	Transactions []*gethtypes.Transaction `json:"transaction"`
 	Uncles       []*gethtypes.Header      `json:"uncles"`
 	Withdrawals  []*gethtypes.Withdrawal  `json:"withdrawals"`
+	Deposits     []*op.DepositTx          `json:"deposits,omitempty"` // OP Stack deposit transactions, executed before the block transactions
 }
 
 func (b *Block) Block() *gethtypes.Block {
@@ -93,6 +97,7 @@ type Extra struct {
 	Committed  [][]byte                             // Nodes committed during block execution
 	StateDiffs []*StateDiff                         // State diffs for accounts that have changes during block execution
 	PreState   map[gethcommon.Address]*AccountState // Pre-state for accounts that have changes during block execution
+	Stats      *ExecutionStats                      // Opcode and precompile usage statistics of the block execution
 }
 
 type extraMarshaling struct {
@@ -100,6 +105,7 @@ type extraMarshaling struct {
 	Committed  []hexutil.Bytes                      `json:"committed,omitempty"`
 	StateDiffs []*StateDiff                         `json:"stateDiffs,omitempty"`
 	PreState   map[gethcommon.Address]*AccountState `json:"preState,omitempty"`
+	Stats      *ExecutionStats                      `json:"stats,omitempty"`
 }
 
 func (e *Extra) MarshalJSON() ([]byte, error) {
@@ -108,6 +114,7 @@ func (e *Extra) MarshalJSON() ([]byte, error) {
 		Committed:  bytesToHex(e.Committed),
 		StateDiffs: e.StateDiffs,
 		PreState:   e.PreState,
+		Stats:      e.Stats,
 	})
 }
 
@@ -120,6 +127,7 @@ func (e *Extra) UnmarshalJSON(b []byte) error {
 	e.Committed = hexToBytes(m.Committed)
 	e.StateDiffs = m.StateDiffs
 	e.PreState = m.PreState
+	e.Stats = m.Stats
 
 	return nil
 }
@@ -220,3 +228,18 @@ func (a *AccountState) UnmarshalJSON(b []byte) error {
 
 	return nil
 }
+
+// ExecutionStats contains opcode and precompile usage statistics of a block execution.
+// It enables to forecast the cost of proving the block before proving it.
+type ExecutionStats struct {
+	Opcodes      map[string]uint64 `json:"opcodes"`      // Number of executions per opcode name (e.g. "KECCAK256")
+	Precompiles  map[string]uint64 `json:"precompiles"`  // Number of calls per precompile name (e.g. "ecrecover")
+	Transactions []*TxStats        `json:"transactions"` // Statistics of every transaction of the block
+}
+
+// TxStats contains the execution statistics of a transaction.
+type TxStats struct {
+	TxHash  gethcommon.Hash `json:"txHash"`
+	GasUsed uint64          `json:"gasUsed"` // Gas used by the transaction
+	Steps   uint64          `json:"steps"`   // Number of opcodes executed by the transaction
+}
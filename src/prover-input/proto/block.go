@@ -1,6 +1,8 @@
 package proto
 
 import (
+	"math/big"
+
 	gethcommon "github.com/ethereum/go-ethereum/common"
 	gethtypes "github.com/ethereum/go-ethereum/core/types"
 	input "github.com/kkrt-labs/zk-pig/src/prover-input"
@@ -16,6 +18,7 @@ func BlockToProto(b *input.Block) *Block {
 		Transactions: TransactionsToProto(b.Transactions),
 		Uncles:       HeadersToProto(b.Uncles), // we assume a post-merge
 		Withdrawals:  WithdrawalsToProto(b.Withdrawals),
+		Deposits:     DepositsToProto(b.Deposits),
 	}
 }
 
@@ -29,6 +32,7 @@ func BlockFromProto(b *Block) *input.Block {
 		Transactions: TransactionsFromProto(b.Transactions),
 		Uncles:       HeadersFromProto(b.Uncles), // we assume a post-merge
 		Withdrawals:  WithdrawalsFromProto(b.Withdrawals),
+		Deposits:     DepositsFromProto(b.Deposits),
 	}
 }
 
@@ -139,8 +143,8 @@ func HeaderFromProto(h *Header) *gethtypes.Header {
 		TxHash:           gethcommon.BytesToHash(h.GetTransactionsRoot()),
 		ReceiptHash:      gethcommon.BytesToHash(h.GetReceiptsRoot()),
 		Bloom:            gethtypes.Bloom(h.GetLogsBloom()),
-		Difficulty:       bytesToBigInt(h.Difficulty),
-		Number:           bytesToBigInt(h.Number),
+		Difficulty:       new(big.Int).SetBytes(h.GetDifficulty()), // Zero values are not distinguished from unset values
+		Number:           new(big.Int).SetBytes(h.GetNumber()),
 		GasLimit:         h.GetGasLimit(),
 		GasUsed:          h.GetGasUsed(),
 		Time:             h.GetTimestamp(),
@@ -30,7 +30,7 @@ func TestBlock(t *testing.T) {
 		{
 			desc: "block with zero values",
 			block: &input.Block{
-				Header:       &gethtypes.Header{},
+				Header:       &gethtypes.Header{Difficulty: big.NewInt(0), Number: big.NewInt(0)},
 				Transactions: []*gethtypes.Transaction{},
 				Uncles:       []*gethtypes.Header{},
 				Withdrawals:  []*gethtypes.Withdrawal{},
@@ -40,13 +40,14 @@ func TestBlock(t *testing.T) {
 			desc: "block with non-zero values",
 			block: &input.Block{
 				Header: &gethtypes.Header{
-					Number: big.NewInt(1),
+					Difficulty: big.NewInt(0),
+					Number:     big.NewInt(1),
 				},
 				Transactions: []*gethtypes.Transaction{
 					gethtypes.NewTx(&gethtypes.LegacyTx{}),
 				},
 				Uncles: []*gethtypes.Header{
-					{},
+					{Difficulty: big.NewInt(0), Number: big.NewInt(0)},
 				},
 				Withdrawals: []*gethtypes.Withdrawal{
 					{},
@@ -71,8 +72,9 @@ func TestBlock(t *testing.T) {
 
 func TestHeader(t *testing.T) {
 	type testCase struct {
-		desc   string
-		header *gethtypes.Header
+		desc     string
+		header   *gethtypes.Header
+		expected *gethtypes.Header // Defaults to header
 	}
 
 	testCases := []testCase{
@@ -83,6 +85,8 @@ func TestHeader(t *testing.T) {
 		{
 			desc:   "empty header",
 			header: &gethtypes.Header{},
+			// Difficulty and number are required, so unset values are decoded as zero
+			expected: &gethtypes.Header{Difficulty: big.NewInt(0), Number: big.NewInt(0)},
 		},
 		{
 			desc: "header with zeros",
@@ -140,9 +144,13 @@ func TestHeader(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
+			expected := tc.expected
+			if expected == nil {
+				expected = tc.header
+			}
 			protoHeader := HeaderToProto(tc.header)
 			headerFromProto := HeaderFromProto(protoHeader)
-			assert.Equal(t, tc.header, headerFromProto)
+			assert.Equal(t, expected, headerFromProto)
 		})
 	}
 }
@@ -31,9 +31,11 @@ func ChainConfigToProto(c *params.ChainConfig) *ChainConfig {
 		ShanghaiTime:            c.ShanghaiTime,
 		CancunTime:              c.CancunTime,
 		PragueTime:              c.PragueTime,
+		OsakaTime:               c.OsakaTime,
 		VerkleTime:              c.VerkleTime,
 		TerminalTotalDifficulty: bigIntToBytes(c.TerminalTotalDifficulty),
 		DepositContractAddress:  c.DepositContractAddress.Bytes(),
+		EnableVerkleAtGenesis:   &(c.EnableVerkleAtGenesis),
 		Ethash: func() []byte {
 			if c.Ethash != nil {
 				return []byte("Ethash")
@@ -71,9 +73,11 @@ func ChainConfigFromProto(c *ChainConfig) *params.ChainConfig {
 		ShanghaiTime:            c.ShanghaiTime,
 		CancunTime:              c.CancunTime,
 		PragueTime:              c.PragueTime,
+		OsakaTime:               c.OsakaTime,
 		VerkleTime:              c.VerkleTime,
 		TerminalTotalDifficulty: bytesToBigInt(c.GetTerminalTotalDifficulty()),
 		DepositContractAddress:  gethcommon.BytesToAddress(c.GetDepositContractAddress()),
+		EnableVerkleAtGenesis:   c.GetEnableVerkleAtGenesis(),
 		Clique:                  CliqueConfigFromProto(c.GetClique()),
 		BlobScheduleConfig:      BlobScheduleConfigFromProto(c.GetBlobSchedule()),
 	}
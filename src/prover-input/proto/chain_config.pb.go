@@ -48,6 +48,8 @@ type ChainConfig struct {
 	Ethash                  []byte                 `protobuf:"bytes,24,opt,name=ethash,proto3,oneof" json:"ethash,omitempty"`
 	Clique                  *CliqueConfig          `protobuf:"bytes,25,opt,name=clique,proto3,oneof" json:"clique,omitempty"`
 	BlobSchedule            *BlobScheduleConfig    `protobuf:"bytes,26,opt,name=blob_schedule,json=blobSchedule,proto3,oneof" json:"blob_schedule,omitempty"`
+	OsakaTime               *uint64                `protobuf:"varint,27,opt,name=osaka_time,json=osakaTime,proto3,oneof" json:"osaka_time,omitempty"`
+	EnableVerkleAtGenesis   *bool                  `protobuf:"varint,28,opt,name=enable_verkle_at_genesis,json=enableVerkleAtGenesis,proto3,oneof" json:"enable_verkle_at_genesis,omitempty"`
 	unknownFields           protoimpl.UnknownFields
 	sizeCache               protoimpl.SizeCache
 }
@@ -264,6 +266,20 @@ func (x *ChainConfig) GetBlobSchedule() *BlobScheduleConfig {
 	return nil
 }
 
+func (x *ChainConfig) GetOsakaTime() uint64 {
+	if x != nil && x.OsakaTime != nil {
+		return *x.OsakaTime
+	}
+	return 0
+}
+
+func (x *ChainConfig) GetEnableVerkleAtGenesis() bool {
+	if x != nil && x.EnableVerkleAtGenesis != nil {
+		return *x.EnableVerkleAtGenesis
+	}
+	return false
+}
+
 type CliqueConfig struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Period        uint64                 `protobuf:"varint,1,opt,name=period,proto3" json:"period,omitempty"`
@@ -444,13 +460,114 @@ func (x *BlobConfig) GetUpdateFraction() uint64 {
 	return 0
 }
 
+// OpStackConfig is the OP Stack specific configuration of a chain
+type OpStackConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RegolithTime  *uint64                `protobuf:"varint,1,opt,name=regolith_time,json=regolithTime,proto3,oneof" json:"regolith_time,omitempty"`
+	CanyonTime    *uint64                `protobuf:"varint,2,opt,name=canyon_time,json=canyonTime,proto3,oneof" json:"canyon_time,omitempty"`
+	EcotoneTime   *uint64                `protobuf:"varint,3,opt,name=ecotone_time,json=ecotoneTime,proto3,oneof" json:"ecotone_time,omitempty"`
+	FjordTime     *uint64                `protobuf:"varint,4,opt,name=fjord_time,json=fjordTime,proto3,oneof" json:"fjord_time,omitempty"`
+	GraniteTime   *uint64                `protobuf:"varint,5,opt,name=granite_time,json=graniteTime,proto3,oneof" json:"granite_time,omitempty"`
+	HoloceneTime  *uint64                `protobuf:"varint,6,opt,name=holocene_time,json=holoceneTime,proto3,oneof" json:"holocene_time,omitempty"`
+	IsthmusTime   *uint64                `protobuf:"varint,7,opt,name=isthmus_time,json=isthmusTime,proto3,oneof" json:"isthmus_time,omitempty"`
+	JovianTime    *uint64                `protobuf:"varint,8,opt,name=jovian_time,json=jovianTime,proto3,oneof" json:"jovian_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OpStackConfig) Reset() {
+	*x = OpStackConfig{}
+	mi := &file_src_prover_input_proto_chain_config_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpStackConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpStackConfig) ProtoMessage() {}
+
+func (x *OpStackConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_src_prover_input_proto_chain_config_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpStackConfig.ProtoReflect.Descriptor instead.
+func (*OpStackConfig) Descriptor() ([]byte, []int) {
+	return file_src_prover_input_proto_chain_config_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *OpStackConfig) GetRegolithTime() uint64 {
+	if x != nil && x.RegolithTime != nil {
+		return *x.RegolithTime
+	}
+	return 0
+}
+
+func (x *OpStackConfig) GetCanyonTime() uint64 {
+	if x != nil && x.CanyonTime != nil {
+		return *x.CanyonTime
+	}
+	return 0
+}
+
+func (x *OpStackConfig) GetEcotoneTime() uint64 {
+	if x != nil && x.EcotoneTime != nil {
+		return *x.EcotoneTime
+	}
+	return 0
+}
+
+func (x *OpStackConfig) GetFjordTime() uint64 {
+	if x != nil && x.FjordTime != nil {
+		return *x.FjordTime
+	}
+	return 0
+}
+
+func (x *OpStackConfig) GetGraniteTime() uint64 {
+	if x != nil && x.GraniteTime != nil {
+		return *x.GraniteTime
+	}
+	return 0
+}
+
+func (x *OpStackConfig) GetHoloceneTime() uint64 {
+	if x != nil && x.HoloceneTime != nil {
+		return *x.HoloceneTime
+	}
+	return 0
+}
+
+func (x *OpStackConfig) GetIsthmusTime() uint64 {
+	if x != nil && x.IsthmusTime != nil {
+		return *x.IsthmusTime
+	}
+	return 0
+}
+
+func (x *OpStackConfig) GetJovianTime() uint64 {
+	if x != nil && x.JovianTime != nil {
+		return *x.JovianTime
+	}
+	return 0
+}
+
 var File_src_prover_input_proto_chain_config_proto protoreflect.FileDescriptor
 
 var file_src_prover_input_proto_chain_config_proto_rawDesc = []byte{
 	0x0a, 0x29, 0x73, 0x72, 0x63, 0x2f, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x72, 0x2d, 0x69, 0x6e, 0x70,
 	0x75, 0x74, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x5f, 0x63,
 	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x69, 0x6e, 0x70,
-	0x75, 0x74, 0x22, 0xa6, 0x0d, 0x0a, 0x0b, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x43, 0x6f, 0x6e, 0x66,
+	0x75, 0x74, 0x22, 0xb4, 0x0e, 0x0a, 0x0b, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x43, 0x6f, 0x6e, 0x66,
 	0x69, 0x67, 0x12, 0x19, 0x0a, 0x08, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01,
 	0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x64, 0x12, 0x2c, 0x0a,
 	0x0f, 0x68, 0x6f, 0x6d, 0x65, 0x73, 0x74, 0x65, 0x61, 0x64, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b,
@@ -526,67 +643,105 @@ var file_src_prover_input_proto_chain_config_proto_rawDesc = []byte{
 	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x2e, 0x42, 0x6c, 0x6f, 0x62,
 	0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x48, 0x18,
 	0x52, 0x0c, 0x62, 0x6c, 0x6f, 0x62, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x88, 0x01,
-	0x01, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x68, 0x6f, 0x6d, 0x65, 0x73, 0x74, 0x65, 0x61, 0x64, 0x5f,
-	0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x64, 0x61, 0x6f, 0x5f, 0x66, 0x6f,
-	0x72, 0x6b, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x42, 0x13, 0x0a, 0x11, 0x5f, 0x64, 0x61, 0x6f,
-	0x5f, 0x66, 0x6f, 0x72, 0x6b, 0x5f, 0x73, 0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x42, 0x0f, 0x0a,
-	0x0d, 0x5f, 0x65, 0x69, 0x70, 0x31, 0x35, 0x30, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x42, 0x0f,
-	0x0a, 0x0d, 0x5f, 0x65, 0x69, 0x70, 0x31, 0x35, 0x35, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x42,
-	0x0f, 0x0a, 0x0d, 0x5f, 0x65, 0x69, 0x70, 0x31, 0x35, 0x38, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b,
-	0x42, 0x12, 0x0a, 0x10, 0x5f, 0x62, 0x79, 0x7a, 0x61, 0x6e, 0x74, 0x69, 0x75, 0x6d, 0x5f, 0x62,
-	0x6c, 0x6f, 0x63, 0x6b, 0x42, 0x17, 0x0a, 0x15, 0x5f, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x61, 0x6e,
-	0x74, 0x69, 0x6e, 0x6f, 0x70, 0x6c, 0x65, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x42, 0x13, 0x0a,
-	0x11, 0x5f, 0x70, 0x65, 0x74, 0x65, 0x72, 0x73, 0x62, 0x75, 0x72, 0x67, 0x5f, 0x62, 0x6c, 0x6f,
-	0x63, 0x6b, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x62, 0x75, 0x6c, 0x5f,
-	0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x42, 0x15, 0x0a, 0x13, 0x5f, 0x6d, 0x75, 0x69, 0x72, 0x5f, 0x67,
-	0x6c, 0x61, 0x63, 0x69, 0x65, 0x72, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x42, 0x0f, 0x0a, 0x0d,
-	0x5f, 0x62, 0x65, 0x72, 0x6c, 0x69, 0x6e, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x42, 0x0f, 0x0a,
-	0x0d, 0x5f, 0x6c, 0x6f, 0x6e, 0x64, 0x6f, 0x6e, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x42, 0x16,
-	0x0a, 0x14, 0x5f, 0x61, 0x72, 0x72, 0x6f, 0x77, 0x5f, 0x67, 0x6c, 0x61, 0x63, 0x69, 0x65, 0x72,
-	0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x42, 0x15, 0x0a, 0x13, 0x5f, 0x67, 0x72, 0x61, 0x79, 0x5f,
-	0x67, 0x6c, 0x61, 0x63, 0x69, 0x65, 0x72, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x42, 0x17, 0x0a,
-	0x15, 0x5f, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x5f, 0x6e, 0x65, 0x74, 0x73, 0x70, 0x6c, 0x69, 0x74,
-	0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x73, 0x68, 0x61, 0x6e, 0x67,
-	0x68, 0x61, 0x69, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x63, 0x61, 0x6e,
-	0x63, 0x75, 0x6e, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x70, 0x72, 0x61,
-	0x67, 0x75, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x76, 0x65, 0x72,
-	0x6b, 0x6c, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x42, 0x1c, 0x0a, 0x1a, 0x5f, 0x74, 0x65, 0x72,
-	0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x64, 0x69, 0x66, 0x66,
-	0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x42, 0x1b, 0x0a, 0x19, 0x5f, 0x64, 0x65, 0x70, 0x6f, 0x73,
-	0x69, 0x74, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x5f, 0x61, 0x64, 0x64, 0x72,
-	0x65, 0x73, 0x73, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x65, 0x74, 0x68, 0x61, 0x73, 0x68, 0x42, 0x09,
-	0x0a, 0x07, 0x5f, 0x63, 0x6c, 0x69, 0x71, 0x75, 0x65, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x62, 0x6c,
-	0x6f, 0x62, 0x5f, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x22, 0x3c, 0x0a, 0x0c, 0x43,
-	0x6c, 0x69, 0x71, 0x75, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x70,
-	0x65, 0x72, 0x69, 0x6f, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x70, 0x65, 0x72,
-	0x69, 0x6f, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x04, 0x52, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x22, 0xfd, 0x01, 0x0a, 0x12, 0x42, 0x6c,
-	0x6f, 0x62, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
-	0x12, 0x2e, 0x0a, 0x06, 0x63, 0x61, 0x6e, 0x63, 0x75, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x11, 0x2e, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x2e, 0x42, 0x6c, 0x6f, 0x62, 0x43, 0x6f, 0x6e,
-	0x66, 0x69, 0x67, 0x48, 0x00, 0x52, 0x06, 0x63, 0x61, 0x6e, 0x63, 0x75, 0x6e, 0x88, 0x01, 0x01,
-	0x12, 0x2e, 0x0a, 0x06, 0x70, 0x72, 0x61, 0x67, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x11, 0x2e, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x2e, 0x42, 0x6c, 0x6f, 0x62, 0x43, 0x6f, 0x6e,
-	0x66, 0x69, 0x67, 0x48, 0x01, 0x52, 0x06, 0x70, 0x72, 0x61, 0x67, 0x75, 0x65, 0x88, 0x01, 0x01,
-	0x12, 0x2c, 0x0a, 0x05, 0x6f, 0x73, 0x61, 0x6b, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x11, 0x2e, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x2e, 0x42, 0x6c, 0x6f, 0x62, 0x43, 0x6f, 0x6e, 0x66,
-	0x69, 0x67, 0x48, 0x02, 0x52, 0x05, 0x6f, 0x73, 0x61, 0x6b, 0x61, 0x88, 0x01, 0x01, 0x12, 0x2e,
-	0x0a, 0x06, 0x76, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11,
+	0x01, 0x12, 0x22, 0x0a, 0x0a, 0x6f, 0x73, 0x61, 0x6b, 0x61, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18,
+	0x1b, 0x20, 0x01, 0x28, 0x04, 0x48, 0x19, 0x52, 0x09, 0x6f, 0x73, 0x61, 0x6b, 0x61, 0x54, 0x69,
+	0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x3c, 0x0a, 0x18, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x5f,
+	0x76, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x61, 0x74, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x73, 0x69,
+	0x73, 0x18, 0x1c, 0x20, 0x01, 0x28, 0x08, 0x48, 0x1a, 0x52, 0x15, 0x65, 0x6e, 0x61, 0x62, 0x6c,
+	0x65, 0x56, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x41, 0x74, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73,
+	0x88, 0x01, 0x01, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x68, 0x6f, 0x6d, 0x65, 0x73, 0x74, 0x65, 0x61,
+	0x64, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x64, 0x61, 0x6f, 0x5f,
+	0x66, 0x6f, 0x72, 0x6b, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x42, 0x13, 0x0a, 0x11, 0x5f, 0x64,
+	0x61, 0x6f, 0x5f, 0x66, 0x6f, 0x72, 0x6b, 0x5f, 0x73, 0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x42,
+	0x0f, 0x0a, 0x0d, 0x5f, 0x65, 0x69, 0x70, 0x31, 0x35, 0x30, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b,
+	0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x65, 0x69, 0x70, 0x31, 0x35, 0x35, 0x5f, 0x62, 0x6c, 0x6f, 0x63,
+	0x6b, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x65, 0x69, 0x70, 0x31, 0x35, 0x38, 0x5f, 0x62, 0x6c, 0x6f,
+	0x63, 0x6b, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x62, 0x79, 0x7a, 0x61, 0x6e, 0x74, 0x69, 0x75, 0x6d,
+	0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x42, 0x17, 0x0a, 0x15, 0x5f, 0x63, 0x6f, 0x6e, 0x73, 0x74,
+	0x61, 0x6e, 0x74, 0x69, 0x6e, 0x6f, 0x70, 0x6c, 0x65, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x42,
+	0x13, 0x0a, 0x11, 0x5f, 0x70, 0x65, 0x74, 0x65, 0x72, 0x73, 0x62, 0x75, 0x72, 0x67, 0x5f, 0x62,
+	0x6c, 0x6f, 0x63, 0x6b, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x62, 0x75,
+	0x6c, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x42, 0x15, 0x0a, 0x13, 0x5f, 0x6d, 0x75, 0x69, 0x72,
+	0x5f, 0x67, 0x6c, 0x61, 0x63, 0x69, 0x65, 0x72, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x42, 0x0f,
+	0x0a, 0x0d, 0x5f, 0x62, 0x65, 0x72, 0x6c, 0x69, 0x6e, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x42,
+	0x0f, 0x0a, 0x0d, 0x5f, 0x6c, 0x6f, 0x6e, 0x64, 0x6f, 0x6e, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b,
+	0x42, 0x16, 0x0a, 0x14, 0x5f, 0x61, 0x72, 0x72, 0x6f, 0x77, 0x5f, 0x67, 0x6c, 0x61, 0x63, 0x69,
+	0x65, 0x72, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x42, 0x15, 0x0a, 0x13, 0x5f, 0x67, 0x72, 0x61,
+	0x79, 0x5f, 0x67, 0x6c, 0x61, 0x63, 0x69, 0x65, 0x72, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x42,
+	0x17, 0x0a, 0x15, 0x5f, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x5f, 0x6e, 0x65, 0x74, 0x73, 0x70, 0x6c,
+	0x69, 0x74, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x73, 0x68, 0x61,
+	0x6e, 0x67, 0x68, 0x61, 0x69, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x63,
+	0x61, 0x6e, 0x63, 0x75, 0x6e, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x70,
+	0x72, 0x61, 0x67, 0x75, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x76,
+	0x65, 0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x42, 0x1c, 0x0a, 0x1a, 0x5f, 0x74,
+	0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x64, 0x69,
+	0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x42, 0x1b, 0x0a, 0x19, 0x5f, 0x64, 0x65, 0x70,
+	0x6f, 0x73, 0x69, 0x74, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x5f, 0x61, 0x64,
+	0x64, 0x72, 0x65, 0x73, 0x73, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x65, 0x74, 0x68, 0x61, 0x73, 0x68,
+	0x42, 0x09, 0x0a, 0x07, 0x5f, 0x63, 0x6c, 0x69, 0x71, 0x75, 0x65, 0x42, 0x10, 0x0a, 0x0e, 0x5f,
+	0x62, 0x6c, 0x6f, 0x62, 0x5f, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x42, 0x0d, 0x0a,
+	0x0b, 0x5f, 0x6f, 0x73, 0x61, 0x6b, 0x61, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x42, 0x1b, 0x0a, 0x19,
+	0x5f, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x76, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x61,
+	0x74, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x22, 0x3c, 0x0a, 0x0c, 0x43, 0x6c, 0x69,
+	0x71, 0x75, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x65, 0x72,
+	0x69, 0x6f, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x70, 0x65, 0x72, 0x69, 0x6f,
+	0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x22, 0xfd, 0x01, 0x0a, 0x12, 0x42, 0x6c, 0x6f, 0x62,
+	0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x2e,
+	0x0a, 0x06, 0x63, 0x61, 0x6e, 0x63, 0x75, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x2e, 0x42, 0x6c, 0x6f, 0x62, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x48, 0x00, 0x52, 0x06, 0x63, 0x61, 0x6e, 0x63, 0x75, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x2e,
+	0x0a, 0x06, 0x70, 0x72, 0x61, 0x67, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11,
 	0x2e, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x2e, 0x42, 0x6c, 0x6f, 0x62, 0x43, 0x6f, 0x6e, 0x66, 0x69,
-	0x67, 0x48, 0x03, 0x52, 0x06, 0x76, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x42, 0x09,
-	0x0a, 0x07, 0x5f, 0x63, 0x61, 0x6e, 0x63, 0x75, 0x6e, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x70, 0x72,
-	0x61, 0x67, 0x75, 0x65, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x6f, 0x73, 0x61, 0x6b, 0x61, 0x42, 0x09,
-	0x0a, 0x07, 0x5f, 0x76, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x22, 0x5f, 0x0a, 0x0a, 0x42, 0x6c, 0x6f,
-	0x62, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65,
-	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12,
-	0x10, 0x0a, 0x03, 0x6d, 0x61, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x6d, 0x61,
-	0x78, 0x12, 0x27, 0x0a, 0x0f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x66, 0x72, 0x61, 0x63,
-	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x75, 0x70, 0x64, 0x61,
-	0x74, 0x65, 0x46, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x34, 0x5a, 0x32, 0x67, 0x69,
-	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6b, 0x6b, 0x72, 0x74, 0x2d, 0x6c, 0x61,
-	0x62, 0x73, 0x2f, 0x7a, 0x6b, 0x2d, 0x70, 0x69, 0x67, 0x2f, 0x73, 0x72, 0x63, 0x2f, 0x70, 0x72,
-	0x6f, 0x76, 0x65, 0x72, 0x2d, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x67, 0x48, 0x01, 0x52, 0x06, 0x70, 0x72, 0x61, 0x67, 0x75, 0x65, 0x88, 0x01, 0x01, 0x12, 0x2c,
+	0x0a, 0x05, 0x6f, 0x73, 0x61, 0x6b, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e,
+	0x69, 0x6e, 0x70, 0x75, 0x74, 0x2e, 0x42, 0x6c, 0x6f, 0x62, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x48, 0x02, 0x52, 0x05, 0x6f, 0x73, 0x61, 0x6b, 0x61, 0x88, 0x01, 0x01, 0x12, 0x2e, 0x0a, 0x06,
+	0x76, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x69,
+	0x6e, 0x70, 0x75, 0x74, 0x2e, 0x42, 0x6c, 0x6f, 0x62, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x48,
+	0x03, 0x52, 0x06, 0x76, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x42, 0x09, 0x0a, 0x07,
+	0x5f, 0x63, 0x61, 0x6e, 0x63, 0x75, 0x6e, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x70, 0x72, 0x61, 0x67,
+	0x75, 0x65, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x6f, 0x73, 0x61, 0x6b, 0x61, 0x42, 0x09, 0x0a, 0x07,
+	0x5f, 0x76, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x22, 0x5f, 0x0a, 0x0a, 0x42, 0x6c, 0x6f, 0x62, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x10, 0x0a,
+	0x03, 0x6d, 0x61, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x6d, 0x61, 0x78, 0x12,
+	0x27, 0x0a, 0x0f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x66, 0x72, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x46, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xd1, 0x03, 0x0a, 0x0d, 0x4f, 0x70, 0x53,
+	0x74, 0x61, 0x63, 0x6b, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x28, 0x0a, 0x0d, 0x72, 0x65,
+	0x67, 0x6f, 0x6c, 0x69, 0x74, 0x68, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x48, 0x00, 0x52, 0x0c, 0x72, 0x65, 0x67, 0x6f, 0x6c, 0x69, 0x74, 0x68, 0x54, 0x69, 0x6d,
+	0x65, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x0b, 0x63, 0x61, 0x6e, 0x79, 0x6f, 0x6e, 0x5f, 0x74,
+	0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x48, 0x01, 0x52, 0x0a, 0x63, 0x61, 0x6e,
+	0x79, 0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x26, 0x0a, 0x0c, 0x65, 0x63,
+	0x6f, 0x74, 0x6f, 0x6e, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04,
+	0x48, 0x02, 0x52, 0x0b, 0x65, 0x63, 0x6f, 0x74, 0x6f, 0x6e, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x88,
+	0x01, 0x01, 0x12, 0x22, 0x0a, 0x0a, 0x66, 0x6a, 0x6f, 0x72, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x48, 0x03, 0x52, 0x09, 0x66, 0x6a, 0x6f, 0x72, 0x64, 0x54,
+	0x69, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x26, 0x0a, 0x0c, 0x67, 0x72, 0x61, 0x6e, 0x69, 0x74,
+	0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x48, 0x04, 0x52, 0x0b,
+	0x67, 0x72, 0x61, 0x6e, 0x69, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x28,
+	0x0a, 0x0d, 0x68, 0x6f, 0x6c, 0x6f, 0x63, 0x65, 0x6e, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x04, 0x48, 0x05, 0x52, 0x0c, 0x68, 0x6f, 0x6c, 0x6f, 0x63, 0x65, 0x6e,
+	0x65, 0x54, 0x69, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x26, 0x0a, 0x0c, 0x69, 0x73, 0x74, 0x68,
+	0x6d, 0x75, 0x73, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x48, 0x06,
+	0x52, 0x0b, 0x69, 0x73, 0x74, 0x68, 0x6d, 0x75, 0x73, 0x54, 0x69, 0x6d, 0x65, 0x88, 0x01, 0x01,
+	0x12, 0x24, 0x0a, 0x0b, 0x6a, 0x6f, 0x76, 0x69, 0x61, 0x6e, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x04, 0x48, 0x07, 0x52, 0x0a, 0x6a, 0x6f, 0x76, 0x69, 0x61, 0x6e, 0x54,
+	0x69, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x72, 0x65, 0x67, 0x6f, 0x6c,
+	0x69, 0x74, 0x68, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x63, 0x61, 0x6e,
+	0x79, 0x6f, 0x6e, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x65, 0x63, 0x6f,
+	0x74, 0x6f, 0x6e, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x66, 0x6a,
+	0x6f, 0x72, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x67, 0x72, 0x61,
+	0x6e, 0x69, 0x74, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x68, 0x6f,
+	0x6c, 0x6f, 0x63, 0x65, 0x6e, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x42, 0x0f, 0x0a, 0x0d, 0x5f,
+	0x69, 0x73, 0x74, 0x68, 0x6d, 0x75, 0x73, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x42, 0x0e, 0x0a, 0x0c,
+	0x5f, 0x6a, 0x6f, 0x76, 0x69, 0x61, 0x6e, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x42, 0x34, 0x5a, 0x32,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6b, 0x6b, 0x72, 0x74, 0x2d,
+	0x6c, 0x61, 0x62, 0x73, 0x2f, 0x7a, 0x6b, 0x2d, 0x70, 0x69, 0x67, 0x2f, 0x73, 0x72, 0x63, 0x2f,
+	0x70, 0x72, 0x6f, 0x76, 0x65, 0x72, 0x2d, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -601,12 +756,13 @@ func file_src_prover_input_proto_chain_config_proto_rawDescGZIP() []byte {
 	return file_src_prover_input_proto_chain_config_proto_rawDescData
 }
 
-var file_src_prover_input_proto_chain_config_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_src_prover_input_proto_chain_config_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_src_prover_input_proto_chain_config_proto_goTypes = []any{
 	(*ChainConfig)(nil),        // 0: input.ChainConfig
 	(*CliqueConfig)(nil),       // 1: input.CliqueConfig
 	(*BlobScheduleConfig)(nil), // 2: input.BlobScheduleConfig
 	(*BlobConfig)(nil),         // 3: input.BlobConfig
+	(*OpStackConfig)(nil),      // 4: input.OpStackConfig
 }
 var file_src_prover_input_proto_chain_config_proto_depIdxs = []int32{
 	1, // 0: input.ChainConfig.clique:type_name -> input.CliqueConfig
@@ -629,13 +785,14 @@ func file_src_prover_input_proto_chain_config_proto_init() {
 	}
 	file_src_prover_input_proto_chain_config_proto_msgTypes[0].OneofWrappers = []any{}
 	file_src_prover_input_proto_chain_config_proto_msgTypes[2].OneofWrappers = []any{}
+	file_src_prover_input_proto_chain_config_proto_msgTypes[4].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_src_prover_input_proto_chain_config_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -42,9 +42,11 @@ func TestChainConfigToProto(t *testing.T) {
 				ShanghaiTime:            common.Ptr(uint64(17)),
 				CancunTime:              common.Ptr(uint64(18)),
 				PragueTime:              common.Ptr(uint64(19)),
+				OsakaTime:               common.Ptr(uint64(22)),
 				VerkleTime:              common.Ptr(uint64(20)),
 				TerminalTotalDifficulty: big.NewInt(21),
 				DepositContractAddress:  gethcommon.HexToAddress("0x123"),
+				EnableVerkleAtGenesis:   true,
 				Ethash:                  &params.EthashConfig{},
 				Clique: &params.CliqueConfig{
 					Period: 1000000000000000000,
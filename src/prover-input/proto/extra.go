@@ -1,6 +1,9 @@
 package proto
 
 import (
+	"bytes"
+	"sort"
+
 	gethcommon "github.com/ethereum/go-ethereum/common"
 	input "github.com/kkrt-labs/zk-pig/src/prover-input"
 )
@@ -14,6 +17,8 @@ func ExtraToProto(extra *input.Extra) *Extra {
 		AccessList: AccessListToProto(extra.AccessList),
 		StateDiffs: StateDiffsToProto(extra.StateDiffs),
 		Committed:  extra.Committed,
+		PreState:   PreStateToProto(extra.PreState),
+		Stats:      ExecutionStatsToProto(extra.Stats),
 	}
 }
 
@@ -26,6 +31,8 @@ func ExtraFromProto(extra *Extra) *input.Extra {
 		AccessList: AccessListFromProto(extra.AccessList),
 		StateDiffs: StateDiffsFromProto(extra.StateDiffs),
 		Committed:  extra.Committed,
+		PreState:   PreStateFromProto(extra.PreState),
+		Stats:      ExecutionStatsFromProto(extra.Stats),
 	}
 }
 
@@ -152,3 +159,168 @@ func AccountFromProto(account *Account) *input.Account {
 		StorageHash: gethcommon.BytesToHash(account.StorageHash),
 	}
 }
+
+// PreStateToProto converts the pre-state map to a list of entries sorted by address
+// so the protobuf encoding is deterministic.
+func PreStateToProto(preState map[gethcommon.Address]*input.AccountState) []*PreStateEntry {
+	if preState == nil {
+		return nil
+	}
+
+	entries := make([]*PreStateEntry, 0, len(preState))
+	for addr, accountState := range preState {
+		entries = append(entries, &PreStateEntry{
+			Address:      addrToBytes(&addr),
+			AccountState: AccountStateToProto(accountState),
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].Address, entries[j].Address) < 0
+	})
+	return entries
+}
+
+func PreStateFromProto(entries []*PreStateEntry) map[gethcommon.Address]*input.AccountState {
+	if entries == nil {
+		return nil
+	}
+
+	preState := make(map[gethcommon.Address]*input.AccountState, len(entries))
+	for _, entry := range entries {
+		preState[gethcommon.BytesToAddress(entry.Address)] = AccountStateFromProto(entry.AccountState)
+	}
+	return preState
+}
+
+func AccountStateToProto(accountState *input.AccountState) *AccountState {
+	if accountState == nil {
+		return nil
+	}
+
+	return &AccountState{
+		Balance:     bigIntToBytes(accountState.Balance),
+		CodeHash:    accountState.CodeHash.Bytes(),
+		Code:        accountState.Code,
+		Nonce:       accountState.Nonce,
+		StorageHash: accountState.StorageHash.Bytes(),
+		Storage:     StorageToProto(accountState.Storage),
+	}
+}
+
+func AccountStateFromProto(accountState *AccountState) *input.AccountState {
+	if accountState == nil {
+		return nil
+	}
+
+	return &input.AccountState{
+		Balance:     bytesToBigInt(accountState.Balance),
+		CodeHash:    gethcommon.BytesToHash(accountState.CodeHash),
+		Code:        accountState.Code,
+		Nonce:       accountState.Nonce,
+		StorageHash: gethcommon.BytesToHash(accountState.StorageHash),
+		Storage:     StorageFromProto(accountState.Storage),
+	}
+}
+
+// StorageToProto converts the storage map to a list of entries sorted by slot
+// so the protobuf encoding is deterministic.
+func StorageToProto(storage map[gethcommon.Hash]gethcommon.Hash) []*StorageEntry {
+	if storage == nil {
+		return nil
+	}
+
+	entries := make([]*StorageEntry, 0, len(storage))
+	for slot, value := range storage {
+		entries = append(entries, &StorageEntry{
+			Slot:  slot.Bytes(),
+			Value: value.Bytes(),
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].Slot, entries[j].Slot) < 0
+	})
+	return entries
+}
+
+func StorageFromProto(entries []*StorageEntry) map[gethcommon.Hash]gethcommon.Hash {
+	if entries == nil {
+		return nil
+	}
+
+	storage := make(map[gethcommon.Hash]gethcommon.Hash, len(entries))
+	for _, entry := range entries {
+		storage[gethcommon.BytesToHash(entry.Slot)] = gethcommon.BytesToHash(entry.Value)
+	}
+	return storage
+}
+
+func ExecutionStatsToProto(stats *input.ExecutionStats) *ExecutionStats {
+	if stats == nil {
+		return nil
+	}
+
+	txs := make([]*TxStats, len(stats.Transactions))
+	for i, tx := range stats.Transactions {
+		txs[i] = &TxStats{
+			TxHash:  tx.TxHash.Bytes(),
+			GasUsed: tx.GasUsed,
+			Steps:   tx.Steps,
+		}
+	}
+
+	return &ExecutionStats{
+		Opcodes:      CountsToProto(stats.Opcodes),
+		Precompiles:  CountsToProto(stats.Precompiles),
+		Transactions: txs,
+	}
+}
+
+func ExecutionStatsFromProto(stats *ExecutionStats) *input.ExecutionStats {
+	if stats == nil {
+		return nil
+	}
+
+	txs := make([]*input.TxStats, len(stats.Transactions))
+	for i, tx := range stats.Transactions {
+		txs[i] = &input.TxStats{
+			TxHash:  gethcommon.BytesToHash(tx.TxHash),
+			GasUsed: tx.GasUsed,
+			Steps:   tx.Steps,
+		}
+	}
+
+	return &input.ExecutionStats{
+		Opcodes:      CountsFromProto(stats.Opcodes),
+		Precompiles:  CountsFromProto(stats.Precompiles),
+		Transactions: txs,
+	}
+}
+
+// CountsToProto converts a count map to a list of entries sorted by name
+// so the protobuf encoding is deterministic.
+func CountsToProto(counts map[string]uint64) []*StatsCount {
+	if counts == nil {
+		return nil
+	}
+
+	entries := make([]*StatsCount, 0, len(counts))
+	for name, count := range counts {
+		entries = append(entries, &StatsCount{Name: name, Count: count})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+	return entries
+}
+
+func CountsFromProto(entries []*StatsCount) map[string]uint64 {
+	if entries == nil {
+		return nil
+	}
+
+	counts := make(map[string]uint64, len(entries))
+	for _, entry := range entries {
+		counts[entry.Name] = entry.Count
+	}
+	return counts
+}
@@ -37,6 +37,26 @@ func TestExtra(t *testing.T) {
 					},
 				},
 				Committed: [][]byte{gethcommon.HexToHash("0x456").Bytes()},
+				PreState: map[gethcommon.Address]*input.AccountState{
+					gethcommon.HexToAddress("0x123"): {
+						Balance:     big.NewInt(100),
+						CodeHash:    gethcommon.HexToHash("0x789"),
+						Code:        []byte{0x60, 0x80},
+						Nonce:       1,
+						StorageHash: gethcommon.HexToHash("0xabc"),
+						Storage: map[gethcommon.Hash]gethcommon.Hash{
+							gethcommon.HexToHash("0x1"): gethcommon.HexToHash("0x2"),
+						},
+					},
+					gethcommon.HexToAddress("0x456"): nil,
+				},
+				Stats: &input.ExecutionStats{
+					Opcodes:     map[string]uint64{"KECCAK256": 2, "PUSH1": 10},
+					Precompiles: map[string]uint64{"ecrecover": 1},
+					Transactions: []*input.TxStats{
+						{TxHash: gethcommon.HexToHash("0x1"), GasUsed: 21000, Steps: 12},
+					},
+				},
 			},
 		},
 		{
@@ -45,6 +65,7 @@ func TestExtra(t *testing.T) {
 				AccessList: []gethtypes.AccessTuple{},
 				StateDiffs: []*input.StateDiff{},
 				Committed:  [][]byte{},
+				PreState:   map[gethcommon.Address]*input.AccountState{},
 			},
 		},
 	}
@@ -154,3 +175,70 @@ func TestAccount(t *testing.T) {
 		})
 	}
 }
+
+func TestAccountState(t *testing.T) {
+	var testCases = []struct {
+		desc  string
+		input *input.AccountState
+	}{
+		{
+			desc:  "nil account state",
+			input: nil,
+		},
+		{
+			desc: "account state with all fields set",
+			input: &input.AccountState{
+				Balance:     big.NewInt(100),
+				CodeHash:    gethcommon.HexToHash("0x123"),
+				Code:        []byte{0x60, 0x80, 0x60, 0x40},
+				Nonce:       1,
+				StorageHash: gethcommon.HexToHash("0x456"),
+				Storage: map[gethcommon.Hash]gethcommon.Hash{
+					gethcommon.HexToHash("0x1"): gethcommon.HexToHash("0x2"),
+					gethcommon.HexToHash("0x3"): gethcommon.HexToHash("0x4"),
+				},
+			},
+		},
+		{
+			desc: "account state with nil fields",
+			input: &input.AccountState{
+				Balance: nil,
+				Code:    nil,
+				Storage: nil,
+			},
+		},
+		{
+			desc: "account state with empty fields",
+			input: &input.AccountState{
+				Balance:     big.NewInt(0),
+				CodeHash:    gethcommon.Hash{},
+				Code:        []byte{},
+				Nonce:       0,
+				StorageHash: gethcommon.Hash{},
+				Storage:     map[gethcommon.Hash]gethcommon.Hash{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			protoAccountState := AccountStateToProto(tc.input)
+			accountStateFromProto := AccountStateFromProto(protoAccountState)
+			assert.Equal(t, tc.input, accountStateFromProto)
+		})
+	}
+}
+
+func TestPreStateToProtoIsSorted(t *testing.T) {
+	preState := map[gethcommon.Address]*input.AccountState{
+		gethcommon.HexToAddress("0x3"): nil,
+		gethcommon.HexToAddress("0x1"): nil,
+		gethcommon.HexToAddress("0x2"): nil,
+	}
+
+	entries := PreStateToProto(preState)
+	assert.Len(t, entries, 3)
+	for i, addr := range []string{"0x1", "0x2", "0x3"} {
+		assert.Equal(t, gethcommon.HexToAddress(addr).Bytes(), entries[i].Address)
+	}
+}
@@ -15,6 +15,7 @@ func ToProto(pi *input.ProverInput) *ProverInput {
 		Blocks:      BlocksToProto(pi.Blocks),
 		Witness:     WitnessToProto(pi.Witness),
 		ChainConfig: ChainConfigToProto(pi.ChainConfig),
+		OpStack:     OpStackConfigToProto(pi.OpStack),
 		Extra:       ExtraToProto(pi.Extra),
 	}
 }
@@ -29,6 +30,7 @@ func FromProto(pi *ProverInput) *input.ProverInput {
 		Blocks:      BlocksFromProto(pi.Blocks),
 		Witness:     WitnessFromProto(pi.Witness),
 		ChainConfig: ChainConfigFromProto(pi.ChainConfig),
+		OpStack:     OpStackConfigFromProto(pi.OpStack),
 		Extra:       ExtraFromProto(pi.Extra),
 	}
 }
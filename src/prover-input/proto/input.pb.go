@@ -22,12 +22,15 @@ const (
 
 // ProverInput contains the minimal data needed for block execution and proof validation
 type ProverInput struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Version       string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
-	Blocks        []*Block               `protobuf:"bytes,2,rep,name=blocks,proto3" json:"blocks,omitempty"`
-	Witness       *Witness               `protobuf:"bytes,3,opt,name=witness,proto3" json:"witness,omitempty"`
-	ChainConfig   *ChainConfig           `protobuf:"bytes,4,opt,name=chain_config,json=chainConfig,proto3" json:"chain_config,omitempty"`
-	Extra         *Extra                 `protobuf:"bytes,5,opt,name=extra,proto3" json:"extra,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Version     string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	Blocks      []*Block               `protobuf:"bytes,2,rep,name=blocks,proto3" json:"blocks,omitempty"`
+	Witness     *Witness               `protobuf:"bytes,3,opt,name=witness,proto3" json:"witness,omitempty"`
+	ChainConfig *ChainConfig           `protobuf:"bytes,4,opt,name=chain_config,json=chainConfig,proto3" json:"chain_config,omitempty"`
+	Extra       *Extra                 `protobuf:"bytes,5,opt,name=extra,proto3" json:"extra,omitempty"`
+	OpStack     *OpStackConfig         `protobuf:"bytes,6,opt,name=op_stack,json=opStack,proto3" json:"op_stack,omitempty"`
+	// Number of chunks following the head chunk of a chunked protobuf prover input (application/protobuf-chunked)
+	Chunks        uint64 `protobuf:"varint,7,opt,name=chunks,proto3" json:"chunks,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -97,6 +100,20 @@ func (x *ProverInput) GetExtra() *Extra {
 	return nil
 }
 
+func (x *ProverInput) GetOpStack() *OpStackConfig {
+	if x != nil {
+		return x.OpStack
+	}
+	return nil
+}
+
+func (x *ProverInput) GetChunks() uint64 {
+	if x != nil {
+		return x.Chunks
+	}
+	return 0
+}
+
 type Witness struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	State         [][]byte               `protobuf:"bytes,1,rep,name=state,proto3" json:"state,omitempty"`
@@ -169,8 +186,8 @@ var file_src_prover_input_proto_input_proto_rawDesc = []byte{
 	0x74, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x5f, 0x63, 0x6f,
 	0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x22, 0x73, 0x72, 0x63, 0x2f,
 	0x70, 0x72, 0x6f, 0x76, 0x65, 0x72, 0x2d, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x2f, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x2f, 0x65, 0x78, 0x74, 0x72, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xd2,
-	0x01, 0x0a, 0x0b, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x72, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x12, 0x18,
+	0x74, 0x6f, 0x2f, 0x65, 0x78, 0x74, 0x72, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x9b,
+	0x02, 0x0a, 0x0b, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x72, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x12, 0x18,
 	0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
 	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x24, 0x0a, 0x06, 0x62, 0x6c, 0x6f, 0x63,
 	0x6b, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x69, 0x6e, 0x70, 0x75, 0x74,
@@ -183,17 +200,21 @@ var file_src_prover_input_proto_input_proto_rawDesc = []byte{
 	0x69, 0x67, 0x52, 0x0b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12,
 	0x22, 0x0a, 0x05, 0x65, 0x78, 0x74, 0x72, 0x61, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c,
 	0x2e, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x2e, 0x45, 0x78, 0x74, 0x72, 0x61, 0x52, 0x05, 0x65, 0x78,
-	0x74, 0x72, 0x61, 0x22, 0x62, 0x0a, 0x07, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x12, 0x14,
-	0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x05, 0x73,
-	0x74, 0x61, 0x74, 0x65, 0x12, 0x2b, 0x0a, 0x09, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x74, 0x6f, 0x72,
-	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x2e,
-	0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x52, 0x09, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x74, 0x6f, 0x72,
-	0x73, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0c,
-	0x52, 0x05, 0x63, 0x6f, 0x64, 0x65, 0x73, 0x42, 0x34, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x68, 0x75,
-	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6b, 0x6b, 0x72, 0x74, 0x2d, 0x6c, 0x61, 0x62, 0x73, 0x2f,
-	0x7a, 0x6b, 0x2d, 0x70, 0x69, 0x67, 0x2f, 0x73, 0x72, 0x63, 0x2f, 0x70, 0x72, 0x6f, 0x76, 0x65,
-	0x72, 0x2d, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x74, 0x72, 0x61, 0x12, 0x2f, 0x0a, 0x08, 0x6f, 0x70, 0x5f, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x2e, 0x4f, 0x70,
+	0x53, 0x74, 0x61, 0x63, 0x6b, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x07, 0x6f, 0x70, 0x53,
+	0x74, 0x61, 0x63, 0x6b, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x22, 0x62, 0x0a, 0x07,
+	0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x2b, 0x0a,
+	0x09, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0d, 0x2e, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x52,
+	0x09, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f,
+	0x64, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x05, 0x63, 0x6f, 0x64, 0x65, 0x73,
+	0x42, 0x34, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6b,
+	0x6b, 0x72, 0x74, 0x2d, 0x6c, 0x61, 0x62, 0x73, 0x2f, 0x7a, 0x6b, 0x2d, 0x70, 0x69, 0x67, 0x2f,
+	0x73, 0x72, 0x63, 0x2f, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x72, 0x2d, 0x69, 0x6e, 0x70, 0x75, 0x74,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -210,24 +231,26 @@ func file_src_prover_input_proto_input_proto_rawDescGZIP() []byte {
 
 var file_src_prover_input_proto_input_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_src_prover_input_proto_input_proto_goTypes = []any{
-	(*ProverInput)(nil), // 0: input.ProverInput
-	(*Witness)(nil),     // 1: input.Witness
-	(*Block)(nil),       // 2: input.Block
-	(*ChainConfig)(nil), // 3: input.ChainConfig
-	(*Extra)(nil),       // 4: input.Extra
-	(*Header)(nil),      // 5: input.Header
+	(*ProverInput)(nil),   // 0: input.ProverInput
+	(*Witness)(nil),       // 1: input.Witness
+	(*Block)(nil),         // 2: input.Block
+	(*ChainConfig)(nil),   // 3: input.ChainConfig
+	(*Extra)(nil),         // 4: input.Extra
+	(*OpStackConfig)(nil), // 5: input.OpStackConfig
+	(*Header)(nil),        // 6: input.Header
 }
 var file_src_prover_input_proto_input_proto_depIdxs = []int32{
 	2, // 0: input.ProverInput.blocks:type_name -> input.Block
 	1, // 1: input.ProverInput.witness:type_name -> input.Witness
 	3, // 2: input.ProverInput.chain_config:type_name -> input.ChainConfig
 	4, // 3: input.ProverInput.extra:type_name -> input.Extra
-	5, // 4: input.Witness.ancestors:type_name -> input.Header
-	5, // [5:5] is the sub-list for method output_type
-	5, // [5:5] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	5, // 4: input.ProverInput.op_stack:type_name -> input.OpStackConfig
+	6, // 5: input.Witness.ancestors:type_name -> input.Header
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_src_prover_input_proto_input_proto_init() }
@@ -1,12 +1,18 @@
 package proto
 
 import (
+	"encoding/json"
+	"math/big"
 	"testing"
 
+	gethcommon "github.com/ethereum/go-ethereum/common"
 	gethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/kkrt-labs/go-utils/common"
 	input "github.com/kkrt-labs/zk-pig/src/prover-input"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestInput(t *testing.T) {
@@ -81,3 +87,102 @@ func TestWitness(t *testing.T) {
 		})
 	}
 }
+
+func TestJSONAndProtobufEncodingsAreEquivalent(t *testing.T) {
+	in := &input.ProverInput{
+		Version: "1",
+		Blocks: []*input.Block{
+			{
+				Header: &gethtypes.Header{
+					ParentHash:      gethcommon.Hash{0x1},
+					Root:            gethcommon.Hash{0x2},
+					Difficulty:      big.NewInt(1),
+					Number:          big.NewInt(1000),
+					GasLimit:        30000000,
+					GasUsed:         21000,
+					Time:            1000,
+					Extra:           []byte{0x3},
+					BaseFee:         big.NewInt(7),
+					WithdrawalsHash: common.Ptr(gethcommon.Hash{0x4}),
+				},
+			},
+		},
+		Witness: &input.Witness{
+			Ancestors: []*gethtypes.Header{
+				{
+					Difficulty: big.NewInt(1),
+					Number:     big.NewInt(999),
+				},
+			},
+			State: [][]byte{{0x01, 0x02, 0x03}},
+			Codes: [][]byte{{0x60, 0x80}},
+		},
+		ChainConfig: &params.ChainConfig{
+			ChainID:      big.NewInt(1),
+			LondonBlock:  big.NewInt(0),
+			ShanghaiTime: common.Ptr(uint64(0)),
+		},
+		Extra: &input.Extra{
+			AccessList: gethtypes.AccessList{
+				{
+					Address:     gethcommon.HexToAddress("0x123"),
+					StorageKeys: []gethcommon.Hash{gethcommon.HexToHash("0x456")},
+				},
+			},
+			Committed: [][]byte{{0xab}},
+			StateDiffs: []*input.StateDiff{
+				{
+					Address:     gethcommon.HexToAddress("0x123"),
+					PreAccount:  &input.Account{Balance: big.NewInt(100)},
+					PostAccount: &input.Account{Balance: big.NewInt(200), Nonce: 1},
+					Storage: []*input.StorageDiff{
+						{
+							Slot:      gethcommon.HexToHash("0x456"),
+							PreValue:  gethcommon.HexToHash("0x1"),
+							PostValue: gethcommon.HexToHash("0x2"),
+						},
+					},
+				},
+			},
+			PreState: map[gethcommon.Address]*input.AccountState{
+				gethcommon.HexToAddress("0x123"): {
+					Balance:     big.NewInt(100),
+					CodeHash:    gethcommon.HexToHash("0x789"),
+					Code:        []byte{0x60, 0x80},
+					Nonce:       1,
+					StorageHash: gethcommon.HexToHash("0xabc"),
+					Storage: map[gethcommon.Hash]gethcommon.Hash{
+						gethcommon.HexToHash("0x456"): gethcommon.HexToHash("0x1"),
+					},
+				},
+				gethcommon.HexToAddress("0x789"): nil,
+			},
+		},
+	}
+
+	// JSON round-trip
+	jsonBytes, err := json.Marshal(in)
+	require.NoError(t, err)
+	fromJSON := new(input.ProverInput)
+	require.NoError(t, json.Unmarshal(jsonBytes, fromJSON))
+
+	// Protobuf round-trip
+	protoBytes, err := proto.Marshal(ToProto(in))
+	require.NoError(t, err)
+	protoMsg := new(ProverInput)
+	require.NoError(t, proto.Unmarshal(protoBytes, protoMsg))
+	fromProto := FromProto(protoMsg)
+
+	// Both encodings are lossless
+	assert.Equal(t, in.Extra, fromJSON.Extra)
+	assert.Equal(t, in.Extra, fromProto.Extra)
+	assert.Equal(t, in.Witness, fromProto.Witness)
+
+	// Both encodings are equivalent
+	fromJSONBytes, err := json.Marshal(fromJSON)
+	require.NoError(t, err)
+	fromProtoBytes, err := json.Marshal(fromProto)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(jsonBytes), string(fromJSONBytes))
+	assert.JSONEq(t, string(jsonBytes), string(fromProtoBytes))
+}
@@ -0,0 +1,109 @@
+package proto
+
+import (
+	"math/big"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/kkrt-labs/zk-pig/src/ethereum/op"
+)
+
+func DepositsToProto(deposits []*op.DepositTx) []*DepositTransaction {
+	if deposits == nil {
+		return nil
+	}
+
+	result := make([]*DepositTransaction, len(deposits))
+	for i, d := range deposits {
+		result[i] = DepositToProto(d)
+	}
+	return result
+}
+
+func DepositsFromProto(deposits []*DepositTransaction) []*op.DepositTx {
+	if deposits == nil {
+		return nil
+	}
+
+	result := make([]*op.DepositTx, len(deposits))
+	for i, d := range deposits {
+		result[i] = DepositFromProto(d)
+	}
+	return result
+}
+
+func DepositToProto(d *op.DepositTx) *DepositTransaction {
+	if d == nil {
+		return nil
+	}
+
+	return &DepositTransaction{
+		SourceHash:          d.SourceHash.Bytes(),
+		From:                d.From.Bytes(),
+		To:                  addrToBytes(d.To),
+		Mint:                bigIntToBytes(d.Mint),
+		Value:               bigIntToBytes(d.Value),
+		Gas:                 d.Gas,
+		IsSystemTransaction: d.IsSystemTransaction,
+		Data:                d.Data,
+	}
+}
+
+func DepositFromProto(d *DepositTransaction) *op.DepositTx {
+	if d == nil {
+		return nil
+	}
+
+	deposit := &op.DepositTx{
+		SourceHash:          gethcommon.BytesToHash(d.GetSourceHash()),
+		From:                gethcommon.BytesToAddress(d.GetFrom()),
+		Mint:                bytesToBigInt(d.GetMint()),
+		Value:               new(big.Int).SetBytes(d.GetValue()),
+		Gas:                 d.GetGas(),
+		IsSystemTransaction: d.GetIsSystemTransaction(),
+		Data:                d.GetData(),
+	}
+
+	if d.GetTo() != nil {
+		deposit.To = (*gethcommon.Address)(d.GetTo())
+	}
+
+	if deposit.Data == nil {
+		deposit.Data = []byte{}
+	}
+
+	return deposit
+}
+
+func OpStackConfigToProto(c *op.Config) *OpStackConfig {
+	if c == nil {
+		return nil
+	}
+
+	return &OpStackConfig{
+		RegolithTime: c.RegolithTime,
+		CanyonTime:   c.CanyonTime,
+		EcotoneTime:  c.EcotoneTime,
+		FjordTime:    c.FjordTime,
+		GraniteTime:  c.GraniteTime,
+		HoloceneTime: c.HoloceneTime,
+		IsthmusTime:  c.IsthmusTime,
+		JovianTime:   c.JovianTime,
+	}
+}
+
+func OpStackConfigFromProto(c *OpStackConfig) *op.Config {
+	if c == nil {
+		return nil
+	}
+
+	return &op.Config{
+		RegolithTime: c.RegolithTime,
+		CanyonTime:   c.CanyonTime,
+		EcotoneTime:  c.EcotoneTime,
+		FjordTime:    c.FjordTime,
+		GraniteTime:  c.GraniteTime,
+		HoloceneTime: c.HoloceneTime,
+		IsthmusTime:  c.IsthmusTime,
+		JovianTime:   c.JovianTime,
+	}
+}
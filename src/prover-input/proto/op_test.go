@@ -0,0 +1,100 @@
+package proto
+
+import (
+	"math/big"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/kkrt-labs/go-utils/common"
+	"github.com/kkrt-labs/zk-pig/src/ethereum/op"
+	"github.com/stretchr/testify/assert"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+func TestDeposit(t *testing.T) {
+	type testCase struct {
+		desc    string
+		deposit *op.DepositTx
+	}
+
+	testCases := []testCase{
+		{
+			desc:    "nil deposit",
+			deposit: nil,
+		},
+		{
+			desc: "contract creation without mint",
+			deposit: &op.DepositTx{
+				SourceHash: gethcommon.HexToHash("0x1"),
+				From:       gethcommon.HexToAddress("0x2"),
+				Value:      big.NewInt(0),
+				Gas:        100000,
+				Data:       []byte{},
+			},
+		},
+		{
+			desc: "system call with mint",
+			deposit: &op.DepositTx{
+				SourceHash:          gethcommon.HexToHash("0x1"),
+				From:                gethcommon.HexToAddress("0x2"),
+				To:                  common.Ptr(gethcommon.HexToAddress("0x3")),
+				Mint:                big.NewInt(1000),
+				Value:               big.NewInt(10),
+				Gas:                 100000,
+				IsSystemTransaction: true,
+				Data:                []byte{0x1, 0x2},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			protoDeposit := DepositToProto(tc.deposit)
+			if tc.deposit != nil {
+				b, err := protobuf.Marshal(protoDeposit)
+				assert.NoError(t, err)
+				protoDeposit = new(DepositTransaction)
+				assert.NoError(t, protobuf.Unmarshal(b, protoDeposit))
+				assert.Equal(t, tc.deposit.Hash(), DepositFromProto(protoDeposit).Hash())
+			}
+			assert.Equal(t, tc.deposit, DepositFromProto(DepositToProto(tc.deposit)))
+		})
+	}
+}
+
+func TestOpStackConfig(t *testing.T) {
+	type testCase struct {
+		desc string
+		cfg  *op.Config
+	}
+
+	testCases := []testCase{
+		{
+			desc: "nil config",
+			cfg:  nil,
+		},
+		{
+			desc: "empty config",
+			cfg:  &op.Config{},
+		},
+		{
+			desc: "full config",
+			cfg: &op.Config{
+				RegolithTime: common.Ptr(uint64(0)),
+				CanyonTime:   common.Ptr(uint64(1)),
+				EcotoneTime:  common.Ptr(uint64(2)),
+				FjordTime:    common.Ptr(uint64(3)),
+				GraniteTime:  common.Ptr(uint64(4)),
+				HoloceneTime: common.Ptr(uint64(5)),
+				IsthmusTime:  common.Ptr(uint64(6)),
+				JovianTime:   common.Ptr(uint64(7)),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.Equal(t, tc.cfg, OpStackConfigFromProto(OpStackConfigToProto(tc.cfg)))
+		})
+	}
+}
@@ -40,6 +40,12 @@ func TransactionToProto(tx *gethtypes.Transaction) *Transaction {
 				DynamicFeeTransaction: DynamicFeeTransactionToProto(tx),
 			},
 		}
+	case gethtypes.SetCodeTxType:
+		return &Transaction{
+			TransactionType: &Transaction_SetCodeTransaction{
+				SetCodeTransaction: SetCodeTransactionToProto(tx),
+			},
+		}
 	default:
 		return nil
 	}
@@ -55,6 +61,8 @@ func TransactionFromProto(t *Transaction) *gethtypes.Transaction {
 		return BlobTransactionFromProto(tx.BlobTransaction)
 	case *Transaction_DynamicFeeTransaction:
 		return DynamicFeeTransactionFromProto(tx.DynamicFeeTransaction)
+	case *Transaction_SetCodeTransaction:
+		return SetCodeTransactionFromProto(tx.SetCodeTransaction)
 	default:
 		return nil
 	}
@@ -282,6 +290,97 @@ func DynamicFeeTransactionFromProto(t *DynamicFeeTransaction) *gethtypes.Transac
 	return tx
 }
 
+func SetCodeTransactionToProto(tx *gethtypes.Transaction) *SetCodeTransaction {
+	v, r, s := tx.RawSignatureValues()
+	return &SetCodeTransaction{
+		ChainId:           tx.ChainId().Bytes(),
+		Nonce:             tx.Nonce(),
+		GasTipCap:         tx.GasTipCap().Bytes(),
+		GasFeeCap:         tx.GasFeeCap().Bytes(),
+		Gas:               tx.Gas(),
+		To:                tx.To().Bytes(),
+		Value:             tx.Value().Bytes(),
+		Data:              tx.Data(),
+		AccessList:        AccessListToProto(tx.AccessList()),
+		AuthorizationList: AuthorizationListToProto(tx.SetCodeAuthorizations()),
+		V:                 v.Bytes(),
+		R:                 r.Bytes(),
+		S:                 s.Bytes(),
+	}
+}
+
+func SetCodeTransactionFromProto(t *SetCodeTransaction) *gethtypes.Transaction {
+	innerTx := &gethtypes.SetCodeTx{
+		ChainID:    new(uint256.Int).SetBytes(t.GetChainId()),
+		Nonce:      t.GetNonce(),
+		GasTipCap:  new(uint256.Int).SetBytes(t.GetGasTipCap()),
+		GasFeeCap:  new(uint256.Int).SetBytes(t.GetGasFeeCap()),
+		Gas:        t.GetGas(),
+		Value:      new(uint256.Int).SetBytes(t.GetValue()),
+		Data:       t.GetData(),
+		AccessList: AccessListFromProto(t.GetAccessList()),
+		AuthList:   AuthorizationListFromProto(t.GetAuthorizationList()),
+		V:          new(uint256.Int).SetBytes(t.GetV()),
+		R:          new(uint256.Int).SetBytes(t.GetR()),
+		S:          new(uint256.Int).SetBytes(t.GetS()),
+	}
+
+	if t.GetTo() != nil {
+		innerTx.To = gethcommon.BytesToAddress(t.GetTo())
+	}
+
+	tx := gethtypes.NewTx(innerTx)
+	tx.SetTime(time.Unix(0, 0))
+	return tx
+}
+
+func AuthorizationListToProto(auths []gethtypes.SetCodeAuthorization) []*SetCodeAuthorization {
+	if auths == nil {
+		return nil
+	}
+
+	result := make([]*SetCodeAuthorization, len(auths))
+	for i, auth := range auths {
+		result[i] = AuthorizationToProto(&auth)
+	}
+	return result
+}
+
+func AuthorizationListFromProto(p []*SetCodeAuthorization) []gethtypes.SetCodeAuthorization {
+	if p == nil {
+		return nil
+	}
+
+	result := make([]gethtypes.SetCodeAuthorization, len(p))
+	for i, auth := range p {
+		result[i] = *(AuthorizationFromProto(auth))
+	}
+	return result
+}
+
+func AuthorizationToProto(auth *gethtypes.SetCodeAuthorization) *SetCodeAuthorization {
+	return &SetCodeAuthorization{
+		ChainId: auth.ChainID.Bytes(),
+		Address: auth.Address.Bytes(),
+		Nonce:   auth.Nonce,
+		V:       uint32(auth.V),
+		R:       auth.R.Bytes(),
+		S:       auth.S.Bytes(),
+	}
+}
+
+func AuthorizationFromProto(p *SetCodeAuthorization) *gethtypes.SetCodeAuthorization {
+	auth := &gethtypes.SetCodeAuthorization{
+		Address: gethcommon.BytesToAddress(p.GetAddress()),
+		Nonce:   p.GetNonce(),
+		V:       uint8(p.GetV()), //nolint:gosec // V is a single byte y-parity
+	}
+	auth.ChainID.SetBytes(p.GetChainId())
+	auth.R.SetBytes(p.GetR())
+	auth.S.SetBytes(p.GetS())
+	return auth
+}
+
 func AccessListToProto(al gethtypes.AccessList) []*AccessTuple {
 	if al == nil {
 		return nil
@@ -143,6 +143,50 @@ func TestTransaction(t *testing.T) {
 			desc: "BlobTransaction#Empty",
 			tx:   gethtypes.NewTx(&gethtypes.BlobTx{}),
 		},
+		{
+			desc: "SetCodeTransaction#NonEmpty",
+			tx: gethtypes.NewTx(&gethtypes.SetCodeTx{
+				ChainID:   uint256.NewInt(1),
+				Nonce:     1,
+				GasTipCap: uint256.NewInt(1000000000000000000),
+				GasFeeCap: uint256.NewInt(2000000000000000000),
+				Gas:       100000,
+				To:        gethcommon.Address{1},
+				Value:     uint256.NewInt(3000000000000000000),
+				Data:      []byte("test"),
+				AccessList: gethtypes.AccessList{
+					{
+						Address:     gethcommon.Address{1},
+						StorageKeys: []gethcommon.Hash{gethcommon.HexToHash("0x123")},
+					},
+				},
+				AuthList: []gethtypes.SetCodeAuthorization{
+					{
+						ChainID: *uint256.NewInt(1),
+						Address: gethcommon.Address{2},
+						Nonce:   2,
+						V:       1,
+						R:       *uint256.NewInt(5),
+						S:       *uint256.NewInt(4),
+					},
+					{
+						ChainID: *uint256.NewInt(0),
+						Address: gethcommon.Address{3},
+						Nonce:   3,
+						V:       0,
+						R:       *uint256.NewInt(7),
+						S:       *uint256.NewInt(6),
+					},
+				},
+				V: uint256.NewInt(3),
+				R: uint256.NewInt(2),
+				S: uint256.NewInt(1),
+			}),
+		},
+		{
+			desc: "SetCodeTransaction#Empty",
+			tx:   gethtypes.NewTx(&gethtypes.SetCodeTx{}),
+		},
 	}
 
 	for _, testCase := range testCases {
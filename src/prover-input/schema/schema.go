@@ -0,0 +1,49 @@
+package schema
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// JSONSchema is the JSON Schema of the JSON encoding of prover inputs (input.ProverInput).
+//
+// It MUST be kept in sync with the JSON marshalling of input.ProverInput (which is enforced by tests).
+//
+//go:embed prover-input.schema.json
+var JSONSchema []byte
+
+const schemaURL = "prover-input.schema.json"
+
+var compile = sync.OnceValues(func() (*jsonschema.Schema, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(JSONSchema))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource(schemaURL, doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+
+	return c.Compile(schemaURL)
+})
+
+// Validate validates a JSON encoded prover input against JSONSchema
+func Validate(r io.Reader) error {
+	sch, err := compile()
+	if err != nil {
+		return err
+	}
+
+	doc, err := jsonschema.UnmarshalJSON(r)
+	if err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	return sch.Validate(doc)
+}
@@ -0,0 +1,174 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+	"github.com/kkrt-labs/go-utils/common"
+	"github.com/kkrt-labs/zk-pig/src/ethereum/op"
+	input "github.com/kkrt-labs/zk-pig/src/prover-input"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testProverInput() *input.ProverInput {
+	header := &gethtypes.Header{
+		ParentHash:       gethcommon.Hash{0x1},
+		UncleHash:        gethtypes.EmptyUncleHash,
+		Difficulty:       big.NewInt(0),
+		Number:           big.NewInt(100),
+		GasLimit:         30000000,
+		Time:             1000,
+		Extra:            []byte{},
+		BaseFee:          big.NewInt(7),
+		WithdrawalsHash:  &gethcommon.Hash{0x8},
+		BlobGasUsed:      common.Ptr(uint64(0)),
+		ExcessBlobGas:    common.Ptr(uint64(0)),
+		ParentBeaconRoot: &gethcommon.Hash{0x9},
+		RequestsHash:     &gethtypes.EmptyRequestsHash,
+	}
+
+	to := gethcommon.Address{0xa}
+	return &input.ProverInput{
+		Version: input.CurrentVersion,
+		ChainConfig: &params.ChainConfig{
+			ChainID:                 big.NewInt(1),
+			LondonBlock:             big.NewInt(12965000),
+			ShanghaiTime:            common.Ptr(uint64(1681338455)),
+			TerminalTotalDifficulty: new(big.Int).SetBytes(gethcommon.FromHex("0xc70d808a128d7380000")),
+			Ethash:                  &params.EthashConfig{},
+			BlobScheduleConfig: &params.BlobScheduleConfig{
+				Cancun: &params.BlobConfig{Target: 3, Max: 6, UpdateFraction: 3338477},
+			},
+		},
+		Blocks: []*input.Block{
+			{
+				Header: header,
+				Transactions: []*gethtypes.Transaction{
+					gethtypes.NewTx(&gethtypes.LegacyTx{GasPrice: big.NewInt(1), To: &to, V: big.NewInt(27), R: big.NewInt(1), S: big.NewInt(1)}),
+					gethtypes.NewTx(&gethtypes.AccessListTx{ChainID: big.NewInt(1), GasPrice: big.NewInt(1), V: big.NewInt(1), R: big.NewInt(1), S: big.NewInt(1)}),
+					gethtypes.NewTx(&gethtypes.DynamicFeeTx{ChainID: big.NewInt(1), GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(1), V: big.NewInt(1), R: big.NewInt(1), S: big.NewInt(1)}),
+					gethtypes.NewTx(&gethtypes.BlobTx{
+						ChainID:    uint256.NewInt(1),
+						BlobHashes: []gethcommon.Hash{{0x1}},
+						Sidecar:    &gethtypes.BlobTxSidecar{Blobs: []kzg4844.Blob{{}}, Commitments: []kzg4844.Commitment{{}}, Proofs: []kzg4844.Proof{{}}},
+					}),
+					gethtypes.NewTx(&gethtypes.SetCodeTx{
+						ChainID:  uint256.NewInt(1),
+						AuthList: []gethtypes.SetCodeAuthorization{{Address: gethcommon.Address{0xb}}},
+					}),
+				},
+				Withdrawals: []*gethtypes.Withdrawal{{Index: 1, Validator: 2, Address: gethcommon.Address{0xc}, Amount: 3}},
+			},
+		},
+		Witness: &input.Witness{
+			State:     [][]byte{{0x1, 0x2}},
+			Ancestors: []*gethtypes.Header{header},
+			Codes:     [][]byte{{0x60, 0x00}},
+		},
+		Extra: &input.Extra{
+			AccessList: gethtypes.AccessList{{Address: gethcommon.Address{0xa}, StorageKeys: []gethcommon.Hash{{0x1}}}},
+			Committed:  [][]byte{{0x4}},
+			StateDiffs: []*input.StateDiff{
+				{
+					Address:     gethcommon.Address{0xa},
+					PreAccount:  &input.Account{Balance: big.NewInt(10)},
+					PostAccount: &input.Account{Balance: big.NewInt(14)},
+					Storage:     []*input.StorageDiff{{Slot: gethcommon.Hash{0x1}, PostValue: gethcommon.Hash{0x3}}},
+				},
+			},
+			PreState: map[gethcommon.Address]*input.AccountState{
+				{0xa}: {Balance: big.NewInt(10), Code: []byte{0x60, 0x00}, Storage: map[gethcommon.Hash]gethcommon.Hash{{0x1}: {0x2}}},
+				{0xb}: nil,
+			},
+			Stats: &input.ExecutionStats{
+				Opcodes:      map[string]uint64{"KECCAK256": 2},
+				Precompiles:  map[string]uint64{"ecrecover": 1},
+				Transactions: []*input.TxStats{{TxHash: gethcommon.Hash{0x1}, GasUsed: 21000, Steps: 12}},
+			},
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	b, err := json.Marshal(testProverInput())
+	require.NoError(t, err)
+	assert.NoError(t, Validate(bytes.NewReader(b)))
+}
+
+func TestValidateOpStack(t *testing.T) {
+	in := testProverInput()
+	in.OpStack = &op.Config{RegolithTime: common.Ptr(uint64(0)), CanyonTime: common.Ptr(uint64(0))}
+	in.Blocks[0].Deposits = []*op.DepositTx{
+		{SourceHash: gethcommon.Hash{0x1}, From: gethcommon.Address{0x2}, Value: big.NewInt(0), Gas: 100000, Data: []byte{}},
+		{SourceHash: gethcommon.Hash{0x1}, From: gethcommon.Address{0x2}, To: &gethcommon.Address{0x3}, Mint: big.NewInt(1), Value: big.NewInt(1), Gas: 100000, IsSystemTransaction: true, Data: []byte{0x1}},
+	}
+
+	b, err := json.Marshal(in)
+	require.NoError(t, err)
+	assert.NoError(t, Validate(bytes.NewReader(b)))
+}
+
+func TestValidateInvalid(t *testing.T) {
+	var testCases = []struct {
+		desc   string
+		modify func(m map[string]any)
+	}{
+		{
+			desc:   "missing witness",
+			modify: func(m map[string]any) { delete(m, "witness") },
+		},
+		{
+			desc:   "unknown version",
+			modify: func(m map[string]any) { m["version"] = "999" },
+		},
+		{
+			desc: "renamed transactions key",
+			modify: func(m map[string]any) {
+				block := m["blocks"].([]any)[0].(map[string]any)
+				block["transactions"] = block["transaction"]
+				delete(block, "transaction")
+			},
+		},
+		{
+			desc: "invalid state node",
+			modify: func(m map[string]any) {
+				m["witness"].(map[string]any)["state"] = []any{"0x123"}
+			},
+		},
+		{
+			desc: "invalid pre-state address",
+			modify: func(m map[string]any) {
+				m["extra"].(map[string]any)["preState"] = map[string]any{"0x01": nil}
+			},
+		},
+		{
+			desc: "negative opcode count",
+			modify: func(m map[string]any) {
+				m["extra"].(map[string]any)["stats"].(map[string]any)["opcodes"] = map[string]any{"PUSH1": -1}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			b, err := json.Marshal(testProverInput())
+			require.NoError(t, err)
+
+			var m map[string]any
+			require.NoError(t, json.Unmarshal(b, &m))
+			tc.modify(m)
+
+			b, err = json.Marshal(m)
+			require.NoError(t, err)
+			assert.Error(t, Validate(bytes.NewReader(b)))
+		})
+	}
+}
@@ -0,0 +1,593 @@
+package ssz
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// This file implements a minimal reflection based SSZ codec (https://github.com/ethereum/consensus-specs/blob/dev/ssz/simple-serialize.md)
+// covering the subset of SSZ used by the prover input schema:
+// - basic types: bool, uint8, uint16, uint32, uint64
+// - vectors: Go arrays of fixed-size elements (e.g. [32]byte)
+// - lists: Go slices, whose maximum length is given by the `ssz-max` struct tag
+// - containers: Go structs (or pointers to structs)
+//
+// For nested lists (e.g. [][]byte), `ssz-max` contains a comma separated list of limits, one per nesting level.
+
+const (
+	bytesPerChunk      = 32
+	bytesPerLengthOffs = 4
+)
+
+// Marshal encodes v into SSZ
+func Marshal(v any) ([]byte, error) {
+	return marshal(nil, reflect.ValueOf(v), nil)
+}
+
+// Unmarshal decodes SSZ data into v, which must be a non-nil pointer
+func Unmarshal(b []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("ssz: unmarshal expects a non-nil pointer, got %T", v)
+	}
+	return unmarshal(b, rv.Elem(), nil)
+}
+
+// HashTreeRoot computes the SSZ hash tree root of v
+func HashTreeRoot(v any) ([32]byte, error) {
+	return hashTreeRoot(reflect.ValueOf(v), nil)
+}
+
+func isFixed(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	case reflect.Array:
+		return isFixed(t.Elem())
+	case reflect.Ptr:
+		return isFixed(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if !isFixed(t.Field(i).Type) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// fixedSize returns the size of the fixed part of type t
+// (for variable size types it is the size of an offset)
+func fixedSize(t reflect.Type) int {
+	if !isFixed(t) {
+		return bytesPerLengthOffs
+	}
+
+	switch t.Kind() {
+	case reflect.Bool, reflect.Uint8:
+		return 1
+	case reflect.Uint16:
+		return 2
+	case reflect.Uint32:
+		return 4
+	case reflect.Uint64:
+		return 8
+	case reflect.Array:
+		return t.Len() * fixedSize(t.Elem())
+	case reflect.Ptr:
+		return fixedSize(t.Elem())
+	case reflect.Struct:
+		size := 0
+		for i := 0; i < t.NumField(); i++ {
+			size += fixedSize(t.Field(i).Type)
+		}
+		return size
+	default:
+		return 0
+	}
+}
+
+func fieldLimits(f *reflect.StructField) ([]uint64, error) {
+	tag, ok := f.Tag.Lookup("ssz-max")
+	if !ok {
+		return nil, nil
+	}
+
+	parts := strings.Split(tag, ",")
+	limits := make([]uint64, len(parts))
+	for i, part := range parts {
+		limit, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("ssz: invalid ssz-max tag on field %s: %w", f.Name, err)
+		}
+		limits[i] = limit
+	}
+	return limits, nil
+}
+
+func listLimit(t reflect.Type, limits []uint64) (limit uint64, rest []uint64, err error) {
+	if len(limits) == 0 {
+		return 0, nil, fmt.Errorf("ssz: missing ssz-max limit for list of type %s", t)
+	}
+	return limits[0], limits[1:], nil
+}
+
+func marshal(buf []byte, v reflect.Value, limits []uint64) ([]byte, error) {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return append(buf, 1), nil
+		}
+		return append(buf, 0), nil
+	case reflect.Uint8:
+		return append(buf, uint8(v.Uint())), nil
+	case reflect.Uint16:
+		return binary.LittleEndian.AppendUint16(buf, uint16(v.Uint())), nil
+	case reflect.Uint32:
+		return binary.LittleEndian.AppendUint32(buf, uint32(v.Uint())), nil
+	case reflect.Uint64:
+		return binary.LittleEndian.AppendUint64(buf, v.Uint()), nil
+	case reflect.Ptr:
+		if v.IsNil() {
+			return marshal(buf, reflect.New(v.Type().Elem()).Elem(), limits)
+		}
+		return marshal(buf, v.Elem(), limits)
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			for i := 0; i < v.Len(); i++ {
+				buf = append(buf, uint8(v.Index(i).Uint()))
+			}
+			return buf, nil
+		}
+		if !isFixed(v.Type().Elem()) {
+			return nil, fmt.Errorf("ssz: vectors of variable size elements are not supported (%s)", v.Type())
+		}
+		return marshalSequence(buf, v, limits)
+	case reflect.Slice:
+		limit, rest, err := listLimit(v.Type(), limits)
+		if err != nil {
+			return nil, err
+		}
+		if uint64(v.Len()) > limit {
+			return nil, fmt.Errorf("ssz: list of type %s has %d elements, exceeding limit %d", v.Type(), v.Len(), limit)
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return append(buf, v.Bytes()...), nil
+		}
+		return marshalSequence(buf, v, rest)
+	case reflect.Struct:
+		return marshalContainer(buf, v)
+	default:
+		return nil, fmt.Errorf("ssz: unsupported type %s", v.Type())
+	}
+}
+
+// marshalSequence encodes the elements of a list or vector
+func marshalSequence(buf []byte, v reflect.Value, elemLimits []uint64) ([]byte, error) {
+	var err error
+	if isFixed(v.Type().Elem()) {
+		for i := 0; i < v.Len(); i++ {
+			if buf, err = marshal(buf, v.Index(i), elemLimits); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	}
+
+	// Variable size elements are encoded as a list of offsets followed by the elements
+	start := len(buf)
+	buf = append(buf, make([]byte, v.Len()*bytesPerLengthOffs)...)
+	for i := 0; i < v.Len(); i++ {
+		binary.LittleEndian.PutUint32(buf[start+i*bytesPerLengthOffs:], uint32(len(buf)-start))
+		if buf, err = marshal(buf, v.Index(i), elemLimits); err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
+
+func marshalContainer(buf []byte, v reflect.Value) ([]byte, error) {
+	t := v.Type()
+	start := len(buf)
+
+	// Encode fixed parts, reserving offsets for variable size fields
+	offsets := make([]int, 0)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !isFixed(f.Type) {
+			offsets = append(offsets, len(buf))
+			buf = append(buf, make([]byte, bytesPerLengthOffs)...)
+			continue
+		}
+		limits, err := fieldLimits(&f)
+		if err != nil {
+			return nil, err
+		}
+		if buf, err = marshal(buf, v.Field(i), limits); err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+	}
+
+	// Encode variable parts
+	j := 0
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if isFixed(f.Type) {
+			continue
+		}
+		binary.LittleEndian.PutUint32(buf[offsets[j]:], uint32(len(buf)-start))
+		j++
+
+		limits, err := fieldLimits(&f)
+		if err != nil {
+			return nil, err
+		}
+		if buf, err = marshal(buf, v.Field(i), limits); err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+	}
+
+	return buf, nil
+}
+
+func unmarshal(b []byte, v reflect.Value, limits []uint64) error {
+	t := v.Type()
+	if isFixed(t) && len(b) != fixedSize(t) {
+		return fmt.Errorf("ssz: invalid size for type %s: expected %d bytes, got %d", t, fixedSize(t), len(b))
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		switch b[0] {
+		case 0:
+			v.SetBool(false)
+		case 1:
+			v.SetBool(true)
+		default:
+			return fmt.Errorf("ssz: invalid boolean value %d", b[0])
+		}
+	case reflect.Uint8:
+		v.SetUint(uint64(b[0]))
+	case reflect.Uint16:
+		v.SetUint(uint64(binary.LittleEndian.Uint16(b)))
+	case reflect.Uint32:
+		v.SetUint(uint64(binary.LittleEndian.Uint32(b)))
+	case reflect.Uint64:
+		v.SetUint(binary.LittleEndian.Uint64(b))
+	case reflect.Ptr:
+		elem := reflect.New(t.Elem())
+		if err := unmarshal(b, elem.Elem(), limits); err != nil {
+			return err
+		}
+		v.Set(elem)
+	case reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			reflect.Copy(v, reflect.ValueOf(b))
+			return nil
+		}
+		elemSize := fixedSize(t.Elem())
+		for i := 0; i < v.Len(); i++ {
+			if err := unmarshal(b[i*elemSize:(i+1)*elemSize], v.Index(i), limits); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		return unmarshalList(b, v, limits)
+	case reflect.Struct:
+		return unmarshalContainer(b, v)
+	default:
+		return fmt.Errorf("ssz: unsupported type %s", t)
+	}
+
+	return nil
+}
+
+func unmarshalList(b []byte, v reflect.Value, limits []uint64) error {
+	t := v.Type()
+	limit, rest, err := listLimit(t, limits)
+	if err != nil {
+		return err
+	}
+
+	if t.Elem().Kind() == reflect.Uint8 {
+		if uint64(len(b)) > limit {
+			return fmt.Errorf("ssz: byte list has %d bytes, exceeding limit %d", len(b), limit)
+		}
+		v.SetBytes(append([]byte(nil), b...))
+		return nil
+	}
+
+	if len(b) == 0 {
+		v.Set(reflect.Zero(t))
+		return nil
+	}
+
+	var parts [][]byte
+	if isFixed(t.Elem()) {
+		elemSize := fixedSize(t.Elem())
+		if len(b)%elemSize != 0 {
+			return fmt.Errorf("ssz: invalid list size %d for elements of %d bytes", len(b), elemSize)
+		}
+		for i := 0; i < len(b); i += elemSize {
+			parts = append(parts, b[i:i+elemSize])
+		}
+	} else {
+		if parts, err = splitOffsets(b); err != nil {
+			return err
+		}
+	}
+
+	if uint64(len(parts)) > limit {
+		return fmt.Errorf("ssz: list of type %s has %d elements, exceeding limit %d", t, len(parts), limit)
+	}
+
+	list := reflect.MakeSlice(t, len(parts), len(parts))
+	for i, part := range parts {
+		if err := unmarshal(part, list.Index(i), rest); err != nil {
+			return err
+		}
+	}
+	v.Set(list)
+
+	return nil
+}
+
+// splitOffsets splits an encoded list of variable size elements into its elements
+func splitOffsets(b []byte) ([][]byte, error) {
+	if len(b) < bytesPerLengthOffs {
+		return nil, fmt.Errorf("ssz: list too short to contain an offset")
+	}
+
+	first := binary.LittleEndian.Uint32(b)
+	if first%bytesPerLengthOffs != 0 || first == 0 || int(first) > len(b) {
+		return nil, fmt.Errorf("ssz: invalid first offset %d", first)
+	}
+
+	count := int(first / bytesPerLengthOffs)
+	offsets := make([]int, count+1)
+	for i := 0; i < count; i++ {
+		offsets[i] = int(binary.LittleEndian.Uint32(b[i*bytesPerLengthOffs:]))
+	}
+	offsets[count] = len(b)
+
+	parts := make([][]byte, count)
+	for i := 0; i < count; i++ {
+		if offsets[i] > offsets[i+1] {
+			return nil, fmt.Errorf("ssz: offsets are not increasing (%d > %d)", offsets[i], offsets[i+1])
+		}
+		parts[i] = b[offsets[i]:offsets[i+1]]
+	}
+
+	return parts, nil
+}
+
+func unmarshalContainer(b []byte, v reflect.Value) error {
+	t := v.Type()
+
+	size := 0
+	for i := 0; i < t.NumField(); i++ {
+		size += fixedSize(t.Field(i).Type)
+	}
+	if len(b) < size {
+		return fmt.Errorf("ssz: container %s too short: expected at least %d bytes, got %d", t, size, len(b))
+	}
+
+	// Decode fixed fields and collect offsets of variable size fields
+	type variableField struct {
+		index  int
+		offset int
+	}
+	variableFields := make([]variableField, 0)
+
+	pos := 0
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fSize := fixedSize(f.Type)
+		if !isFixed(f.Type) {
+			offset := int(binary.LittleEndian.Uint32(b[pos:]))
+			if len(variableFields) == 0 && offset != size {
+				return fmt.Errorf("ssz: container %s: invalid first offset %d (expected %d)", t, offset, size)
+			}
+			if len(variableFields) > 0 && offset < variableFields[len(variableFields)-1].offset {
+				return fmt.Errorf("ssz: container %s: offsets are not increasing", t)
+			}
+			if offset > len(b) {
+				return fmt.Errorf("ssz: container %s: offset %d out of bounds", t, offset)
+			}
+			variableFields = append(variableFields, variableField{index: i, offset: offset})
+		} else {
+			limits, err := fieldLimits(&f)
+			if err != nil {
+				return err
+			}
+			if err := unmarshal(b[pos:pos+fSize], v.Field(i), limits); err != nil {
+				return fmt.Errorf("%s: %w", f.Name, err)
+			}
+		}
+		pos += fSize
+	}
+
+	if len(variableFields) == 0 && len(b) != size {
+		return fmt.Errorf("ssz: container %s: expected %d bytes, got %d", t, size, len(b))
+	}
+
+	// Decode variable size fields
+	for j, vf := range variableFields {
+		end := len(b)
+		if j+1 < len(variableFields) {
+			end = variableFields[j+1].offset
+		}
+		f := t.Field(vf.index)
+		limits, err := fieldLimits(&f)
+		if err != nil {
+			return err
+		}
+		if err := unmarshal(b[vf.offset:end], v.Field(vf.index), limits); err != nil {
+			return fmt.Errorf("%s: %w", f.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func hashTreeRoot(v reflect.Value, limits []uint64) ([32]byte, error) {
+	switch v.Kind() {
+	case reflect.Bool, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		b, err := marshal(nil, v, nil)
+		if err != nil {
+			return [32]byte{}, err
+		}
+		var chunk [32]byte
+		copy(chunk[:], b)
+		return chunk, nil
+	case reflect.Ptr:
+		if v.IsNil() {
+			return hashTreeRoot(reflect.New(v.Type().Elem()).Elem(), limits)
+		}
+		return hashTreeRoot(v.Elem(), limits)
+	case reflect.Array:
+		if isBasic(v.Type().Elem()) {
+			b, err := marshal(nil, v, nil)
+			if err != nil {
+				return [32]byte{}, err
+			}
+			chunks := pack(b)
+			return merkleize(chunks, uint64(len(chunks)))
+		}
+		chunks, err := elementRoots(v, limits)
+		if err != nil {
+			return [32]byte{}, err
+		}
+		return merkleize(chunks, uint64(v.Len()))
+	case reflect.Slice:
+		limit, rest, err := listLimit(v.Type(), limits)
+		if err != nil {
+			return [32]byte{}, err
+		}
+
+		var root [32]byte
+		if elem := v.Type().Elem(); isBasic(elem) {
+			b, err := marshal(nil, v, limits)
+			if err != nil {
+				return [32]byte{}, err
+			}
+			chunkLimit := (limit*uint64(fixedSize(elem)) + bytesPerChunk - 1) / bytesPerChunk
+			if root, err = merkleize(pack(b), chunkLimit); err != nil {
+				return [32]byte{}, err
+			}
+		} else {
+			chunks, err := elementRoots(v, rest)
+			if err != nil {
+				return [32]byte{}, err
+			}
+			if root, err = merkleize(chunks, limit); err != nil {
+				return [32]byte{}, err
+			}
+		}
+		return mixInLength(root, uint64(v.Len())), nil
+	case reflect.Struct:
+		t := v.Type()
+		chunks := make([][32]byte, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			limits, err := fieldLimits(&f)
+			if err != nil {
+				return [32]byte{}, err
+			}
+			if chunks[i], err = hashTreeRoot(v.Field(i), limits); err != nil {
+				return [32]byte{}, fmt.Errorf("%s: %w", f.Name, err)
+			}
+		}
+		return merkleize(chunks, uint64(len(chunks)))
+	default:
+		return [32]byte{}, fmt.Errorf("ssz: unsupported type %s", v.Type())
+	}
+}
+
+func isBasic(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+func elementRoots(v reflect.Value, elemLimits []uint64) ([][32]byte, error) {
+	chunks := make([][32]byte, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		root, err := hashTreeRoot(v.Index(i), elemLimits)
+		if err != nil {
+			return nil, err
+		}
+		chunks[i] = root
+	}
+	return chunks, nil
+}
+
+// pack splits serialized basic values into 32-bytes chunks, right-padding the last chunk with zeros
+func pack(b []byte) [][32]byte {
+	chunks := make([][32]byte, (len(b)+bytesPerChunk-1)/bytesPerChunk)
+	for i := range chunks {
+		copy(chunks[i][:], b[i*bytesPerChunk:])
+	}
+	return chunks
+}
+
+// zeroHashes[i] is the root of a tree of depth i with all leaves set to zero
+var zeroHashes = func() [][32]byte {
+	hashes := make([][32]byte, 64)
+	for i := 1; i < len(hashes); i++ {
+		hashes[i] = hashPair(hashes[i-1], hashes[i-1])
+	}
+	return hashes
+}()
+
+func hashPair(a, b [32]byte) [32]byte {
+	var buf [64]byte
+	copy(buf[:32], a[:])
+	copy(buf[32:], b[:])
+	return sha256.Sum256(buf[:])
+}
+
+// merkleize computes the root of the merkle tree of chunks, padded with zero chunks up to limit (rounded to the next power of two)
+func merkleize(chunks [][32]byte, limit uint64) ([32]byte, error) {
+	if uint64(len(chunks)) > limit {
+		return [32]byte{}, fmt.Errorf("ssz: %d chunks exceed limit %d", len(chunks), limit)
+	}
+
+	depth := 0
+	for (uint64(1) << depth) < limit {
+		depth++
+	}
+
+	if len(chunks) == 0 {
+		return zeroHashes[depth], nil
+	}
+
+	layer := chunks
+	for d := 0; d < depth; d++ {
+		if len(layer)%2 == 1 {
+			layer = append(layer, zeroHashes[d])
+		}
+		next := make([][32]byte, len(layer)/2)
+		for i := range next {
+			next[i] = hashPair(layer[2*i], layer[2*i+1])
+		}
+		layer = next
+	}
+
+	return layer[0], nil
+}
+
+func mixInLength(root [32]byte, length uint64) [32]byte {
+	var chunk [32]byte
+	binary.LittleEndian.PutUint64(chunk[:], length)
+	return hashPair(root, chunk)
+}
@@ -0,0 +1,141 @@
+package ssz
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixedContainer struct {
+	A uint16
+	B bool
+	C [4]byte
+}
+
+type variableContainer struct {
+	A uint16
+	B []byte   `ssz-max:"10"`
+	C []uint64 `ssz-max:"4"`
+	D [][]byte `ssz-max:"2,4"`
+}
+
+func TestMarshal(t *testing.T) {
+	var testCases = []struct {
+		desc     string
+		value    any
+		expected []byte
+	}{
+		{
+			desc:     "uint64",
+			value:    uint64(0x0102030405060708),
+			expected: []byte{0x08, 0x07, 0x06, 0x05, 0x04, 0x03, 0x02, 0x01},
+		},
+		{
+			desc:     "fixed container",
+			value:    &fixedContainer{A: 0x0102, B: true, C: [4]byte{1, 2, 3, 4}},
+			expected: []byte{0x02, 0x01, 0x01, 0x01, 0x02, 0x03, 0x04},
+		},
+		{
+			desc: "variable container",
+			value: &variableContainer{
+				A: 0x0102,
+				B: []byte{0x03, 0x04},
+				C: []uint64{5},
+				D: [][]byte{{0x06}, {}},
+			},
+			expected: []byte{
+				0x02, 0x01, // A
+				0x0e, 0x00, 0x00, 0x00, // offset of B
+				0x10, 0x00, 0x00, 0x00, // offset of C
+				0x18, 0x00, 0x00, 0x00, // offset of D
+				0x03, 0x04, // B
+				0x05, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // C
+				0x08, 0x00, 0x00, 0x00, 0x09, 0x00, 0x00, 0x00, 0x06, // D
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			b, err := Marshal(tc.value)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, b)
+		})
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	v := &variableContainer{
+		A: 0x0102,
+		B: []byte{0x03, 0x04},
+		C: []uint64{5, 6},
+		D: [][]byte{{0x06}, {0x07, 0x08}},
+	}
+
+	b, err := Marshal(v)
+	require.NoError(t, err)
+
+	decoded := new(variableContainer)
+	require.NoError(t, Unmarshal(b, decoded))
+	assert.Equal(t, v, decoded)
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	var testCases = []struct {
+		desc string
+		data []byte
+	}{
+		{
+			desc: "too short",
+			data: []byte{0x02, 0x01},
+		},
+		{
+			desc: "invalid first offset",
+			data: []byte{0x02, 0x01, 0x0f, 0x00, 0x00, 0x00, 0x0f, 0x00, 0x00, 0x00, 0x0f, 0x00, 0x00, 0x00},
+		},
+		{
+			desc: "byte list exceeding limit",
+			data: append([]byte{0x02, 0x01, 0x0e, 0x00, 0x00, 0x00, 0x19, 0x00, 0x00, 0x00, 0x19, 0x00, 0x00, 0x00}, make([]byte, 11)...),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := Unmarshal(tc.data, new(variableContainer))
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := Marshal(&variableContainer{B: make([]byte, 11)})
+	assert.Error(t, err)
+}
+
+func TestHashTreeRoot(t *testing.T) {
+	chunk := func(b ...byte) [32]byte {
+		var c [32]byte
+		copy(c[:], b)
+		return c
+	}
+	hash := func(a, b [32]byte) [32]byte {
+		return sha256.Sum256(append(a[:], b[:]...))
+	}
+
+	root, err := HashTreeRoot(uint64(5))
+	require.NoError(t, err)
+	assert.Equal(t, chunk(5), root)
+
+	// Container with 3 fields is merkleized over 4 leaves
+	root, err = HashTreeRoot(&fixedContainer{A: 1, B: true, C: [4]byte{1, 2, 3, 4}})
+	require.NoError(t, err)
+	assert.Equal(t, hash(hash(chunk(1), chunk(1)), hash(chunk(1, 2, 3, 4), chunk())), root)
+
+	// List of 2 uint64 with limit 4 packs into a single chunk, then mixes in the length
+	type list struct {
+		L []uint64 `ssz-max:"4"`
+	}
+	root, err = HashTreeRoot(&list{L: []uint64{1, 2}})
+	require.NoError(t, err)
+	assert.Equal(t, hash(chunk(1, 0, 0, 0, 0, 0, 0, 0, 2), chunk(2)), root)
+}
@@ -0,0 +1,61 @@
+package ssz
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+)
+
+// bigToUint256 converts a *big.Int to a little-endian Uint256 (nil is converted to 0)
+func bigToUint256(b *big.Int) (Uint256, error) {
+	var u Uint256
+	if b == nil {
+		return u, nil
+	}
+	if b.Sign() < 0 || b.BitLen() > 256 {
+		return u, fmt.Errorf("value %v does not fit in uint256", b)
+	}
+	b.FillBytes(u[:])
+	slices.Reverse(u[:])
+	return u, nil
+}
+
+func uint256ToBig(u Uint256) *big.Int {
+	if u == (Uint256{}) {
+		return new(big.Int)
+	}
+	slices.Reverse(u[:])
+	return new(big.Int).SetBytes(u[:])
+}
+
+func optionalUint256(b *big.Int) ([]Uint256, error) {
+	if b == nil {
+		return nil, nil
+	}
+	u, err := bigToUint256(b)
+	if err != nil {
+		return nil, err
+	}
+	return []Uint256{u}, nil
+}
+
+func optionalBig(u []Uint256) *big.Int {
+	if len(u) == 0 {
+		return nil
+	}
+	return uint256ToBig(u[0])
+}
+
+func optionalUint64(v *uint64) []uint64 {
+	if v == nil {
+		return nil
+	}
+	return []uint64{*v}
+}
+
+func optionalUint64Ptr(v []uint64) *uint64 {
+	if len(v) == 0 {
+		return nil
+	}
+	return &v[0]
+}
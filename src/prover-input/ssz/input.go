@@ -0,0 +1,647 @@
+package ssz
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	input "github.com/kkrt-labs/zk-pig/src/prover-input"
+)
+
+// ErrOpStackUnsupported is returned when converting an OP Stack prover input to SSZ
+//
+// The SSZ schema has no container for the OP Stack config nor for deposit transactions,
+// so OP Stack prover inputs must be stored in JSON or protobuf.
+var ErrOpStackUnsupported = errors.New("ssz: OP Stack prover inputs are not supported")
+
+// Encode encodes a prover input into SSZ
+//
+// It returns ErrOpStackUnsupported for OP Stack prover inputs.
+func Encode(pi *input.ProverInput) ([]byte, error) {
+	s, err := ToSSZ(pi)
+	if err != nil {
+		return nil, err
+	}
+	return Marshal(s)
+}
+
+// Decode decodes an SSZ encoded prover input
+func Decode(b []byte) (*input.ProverInput, error) {
+	if len(b) < 8 {
+		return nil, fmt.Errorf("ssz: data too short to contain schema version")
+	}
+	if v := binary.LittleEndian.Uint64(b); v != SchemaVersion {
+		return nil, fmt.Errorf("ssz: unsupported schema version %d (expected %d)", v, SchemaVersion)
+	}
+
+	s := new(ProverInput)
+	if err := Unmarshal(b, s); err != nil {
+		return nil, err
+	}
+	return FromSSZ(s)
+}
+
+// Root returns the SSZ hash tree root of a prover input
+func Root(pi *input.ProverInput) (gethcommon.Hash, error) {
+	s, err := ToSSZ(pi)
+	if err != nil {
+		return gethcommon.Hash{}, err
+	}
+	root, err := HashTreeRoot(s)
+	return gethcommon.Hash(root), err
+}
+
+// ToSSZ converts input.ProverInput to its SSZ container
+//
+// It returns ErrOpStackUnsupported for OP Stack prover inputs.
+func ToSSZ(pi *input.ProverInput) (*ProverInput, error) {
+	if pi == nil {
+		return nil, fmt.Errorf("ssz: nil prover input")
+	}
+
+	if pi.OpStack != nil {
+		return nil, ErrOpStackUnsupported
+	}
+
+	chainConfig, err := ChainConfigToSSZ(pi.ChainConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chain config: %w", err)
+	}
+
+	blocks := make([]*Block, len(pi.Blocks))
+	for i, b := range pi.Blocks {
+		if blocks[i], err = BlockToSSZ(b); err != nil {
+			return nil, fmt.Errorf("invalid block %d: %w", i, err)
+		}
+	}
+
+	witness, err := WitnessToSSZ(pi.Witness)
+	if err != nil {
+		return nil, fmt.Errorf("invalid witness: %w", err)
+	}
+
+	s := &ProverInput{
+		SchemaVersion: SchemaVersion,
+		Version:       []byte(pi.Version),
+		ChainConfig:   chainConfig,
+		Blocks:        blocks,
+		Witness:       witness,
+	}
+
+	if pi.Extra != nil {
+		extra, err := ExtraToSSZ(pi.Extra)
+		if err != nil {
+			return nil, fmt.Errorf("invalid extra: %w", err)
+		}
+		s.Extra = []*Extra{extra}
+	}
+
+	return s, nil
+}
+
+// FromSSZ converts an SSZ container to input.ProverInput
+func FromSSZ(s *ProverInput) (*input.ProverInput, error) {
+	var err error
+	blocks := make([]*input.Block, len(s.Blocks))
+	for i, b := range s.Blocks {
+		if blocks[i], err = BlockFromSSZ(b); err != nil {
+			return nil, fmt.Errorf("invalid block %d: %w", i, err)
+		}
+	}
+
+	witness, err := WitnessFromSSZ(s.Witness)
+	if err != nil {
+		return nil, fmt.Errorf("invalid witness: %w", err)
+	}
+
+	pi := &input.ProverInput{
+		Version:     string(s.Version),
+		ChainConfig: ChainConfigFromSSZ(s.ChainConfig),
+		Blocks:      blocks,
+		Witness:     witness,
+	}
+
+	if len(s.Extra) > 0 {
+		pi.Extra = ExtraFromSSZ(s.Extra[0])
+	}
+
+	return pi, nil
+}
+
+// BlockToSSZ converts input.Block to its SSZ container
+//
+// It returns ErrOpStackUnsupported for blocks with deposit transactions.
+func BlockToSSZ(b *input.Block) (*Block, error) {
+	if len(b.Deposits) > 0 {
+		return nil, ErrOpStackUnsupported
+	}
+
+	header, err := rlp.EncodeToBytes(b.Header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode header: %w", err)
+	}
+
+	txs := make([][]byte, len(b.Transactions))
+	for i, tx := range b.Transactions {
+		if txs[i], err = tx.MarshalBinary(); err != nil {
+			return nil, fmt.Errorf("failed to encode transaction %d: %w", i, err)
+		}
+	}
+
+	uncles, err := headersToSSZ(b.Uncles)
+	if err != nil {
+		return nil, err
+	}
+
+	withdrawals := make([]*Withdrawal, len(b.Withdrawals))
+	for i, w := range b.Withdrawals {
+		withdrawals[i] = &Withdrawal{
+			Index:     w.Index,
+			Validator: w.Validator,
+			Address:   w.Address,
+			Amount:    w.Amount,
+		}
+	}
+
+	return &Block{
+		Header:       header,
+		Transactions: txs,
+		Uncles:       uncles,
+		Withdrawals:  withdrawals,
+	}, nil
+}
+
+func BlockFromSSZ(b *Block) (*input.Block, error) {
+	header := new(gethtypes.Header)
+	if err := rlp.DecodeBytes(b.Header, header); err != nil {
+		return nil, fmt.Errorf("failed to decode header: %w", err)
+	}
+
+	var txs []*gethtypes.Transaction
+	for i, data := range b.Transactions {
+		tx := new(gethtypes.Transaction)
+		if err := tx.UnmarshalBinary(data); err != nil {
+			return nil, fmt.Errorf("failed to decode transaction %d: %w", i, err)
+		}
+		txs = append(txs, tx)
+	}
+
+	uncles, err := headersFromSSZ(b.Uncles)
+	if err != nil {
+		return nil, err
+	}
+
+	var withdrawals []*gethtypes.Withdrawal
+	if header.WithdrawalsHash != nil {
+		// post-Shanghai blocks must have a non-nil withdrawals list
+		withdrawals = make([]*gethtypes.Withdrawal, 0, len(b.Withdrawals))
+	}
+	for _, w := range b.Withdrawals {
+		withdrawals = append(withdrawals, &gethtypes.Withdrawal{
+			Index:     w.Index,
+			Validator: w.Validator,
+			Address:   w.Address,
+			Amount:    w.Amount,
+		})
+	}
+
+	return &input.Block{
+		Header:       header,
+		Transactions: txs,
+		Uncles:       uncles,
+		Withdrawals:  withdrawals,
+	}, nil
+}
+
+func WitnessToSSZ(w *input.Witness) (*Witness, error) {
+	if w == nil {
+		return &Witness{}, nil
+	}
+
+	ancestors, err := headersToSSZ(w.Ancestors)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Witness{
+		State:     w.State,
+		Ancestors: ancestors,
+		Codes:     w.Codes,
+	}, nil
+}
+
+func WitnessFromSSZ(w *Witness) (*input.Witness, error) {
+	ancestors, err := headersFromSSZ(w.Ancestors)
+	if err != nil {
+		return nil, err
+	}
+
+	return &input.Witness{
+		State:     w.State,
+		Ancestors: ancestors,
+		Codes:     w.Codes,
+	}, nil
+}
+
+func headersToSSZ(headers []*gethtypes.Header) ([][]byte, error) {
+	result := make([][]byte, len(headers))
+	for i, h := range headers {
+		b, err := rlp.EncodeToBytes(h)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode header %d: %w", i, err)
+		}
+		result[i] = b
+	}
+	return result, nil
+}
+
+func headersFromSSZ(headers [][]byte) ([]*gethtypes.Header, error) {
+	var result []*gethtypes.Header
+	for i, b := range headers {
+		h := new(gethtypes.Header)
+		if err := rlp.DecodeBytes(b, h); err != nil {
+			return nil, fmt.Errorf("failed to decode header %d: %w", i, err)
+		}
+		result = append(result, h)
+	}
+	return result, nil
+}
+
+func ChainConfigToSSZ(c *params.ChainConfig) (*ChainConfig, error) {
+	if c == nil {
+		return nil, fmt.Errorf("missing chain config")
+	}
+
+	chainID, err := bigToUint256(c.ChainID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chain id: %w", err)
+	}
+
+	s := &ChainConfig{
+		ChainID:                chainID,
+		DAOForkSupport:         c.DAOForkSupport,
+		ShanghaiTime:           optionalUint64(c.ShanghaiTime),
+		CancunTime:             optionalUint64(c.CancunTime),
+		PragueTime:             optionalUint64(c.PragueTime),
+		OsakaTime:              optionalUint64(c.OsakaTime),
+		VerkleTime:             optionalUint64(c.VerkleTime),
+		DepositContractAddress: c.DepositContractAddress,
+		EnableVerkleAtGenesis:  c.EnableVerkleAtGenesis,
+		Ethash:                 c.Ethash != nil,
+	}
+
+	for _, f := range []struct {
+		dst *[]Uint256
+		src *big.Int
+	}{
+		{&s.HomesteadBlock, c.HomesteadBlock},
+		{&s.DAOForkBlock, c.DAOForkBlock},
+		{&s.EIP150Block, c.EIP150Block},
+		{&s.EIP155Block, c.EIP155Block},
+		{&s.EIP158Block, c.EIP158Block},
+		{&s.ByzantiumBlock, c.ByzantiumBlock},
+		{&s.ConstantinopleBlock, c.ConstantinopleBlock},
+		{&s.PetersburgBlock, c.PetersburgBlock},
+		{&s.IstanbulBlock, c.IstanbulBlock},
+		{&s.MuirGlacierBlock, c.MuirGlacierBlock},
+		{&s.BerlinBlock, c.BerlinBlock},
+		{&s.LondonBlock, c.LondonBlock},
+		{&s.ArrowGlacierBlock, c.ArrowGlacierBlock},
+		{&s.GrayGlacierBlock, c.GrayGlacierBlock},
+		{&s.MergeNetsplitBlock, c.MergeNetsplitBlock},
+		{&s.TerminalTotalDifficulty, c.TerminalTotalDifficulty},
+	} {
+		if *f.dst, err = optionalUint256(f.src); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.Clique != nil {
+		s.Clique = []*CliqueConfig{{Period: c.Clique.Period, Epoch: c.Clique.Epoch}}
+	}
+
+	if c.BlobScheduleConfig != nil {
+		s.BlobSchedule = []*BlobScheduleConfig{{
+			Cancun: blobConfigToSSZ(c.BlobScheduleConfig.Cancun),
+			Prague: blobConfigToSSZ(c.BlobScheduleConfig.Prague),
+			Osaka:  blobConfigToSSZ(c.BlobScheduleConfig.Osaka),
+			Verkle: blobConfigToSSZ(c.BlobScheduleConfig.Verkle),
+		}}
+	}
+
+	return s, nil
+}
+
+func ChainConfigFromSSZ(s *ChainConfig) *params.ChainConfig {
+	c := &params.ChainConfig{
+		ChainID:                 uint256ToBig(s.ChainID),
+		HomesteadBlock:          optionalBig(s.HomesteadBlock),
+		DAOForkBlock:            optionalBig(s.DAOForkBlock),
+		DAOForkSupport:          s.DAOForkSupport,
+		EIP150Block:             optionalBig(s.EIP150Block),
+		EIP155Block:             optionalBig(s.EIP155Block),
+		EIP158Block:             optionalBig(s.EIP158Block),
+		ByzantiumBlock:          optionalBig(s.ByzantiumBlock),
+		ConstantinopleBlock:     optionalBig(s.ConstantinopleBlock),
+		PetersburgBlock:         optionalBig(s.PetersburgBlock),
+		IstanbulBlock:           optionalBig(s.IstanbulBlock),
+		MuirGlacierBlock:        optionalBig(s.MuirGlacierBlock),
+		BerlinBlock:             optionalBig(s.BerlinBlock),
+		LondonBlock:             optionalBig(s.LondonBlock),
+		ArrowGlacierBlock:       optionalBig(s.ArrowGlacierBlock),
+		GrayGlacierBlock:        optionalBig(s.GrayGlacierBlock),
+		MergeNetsplitBlock:      optionalBig(s.MergeNetsplitBlock),
+		ShanghaiTime:            optionalUint64Ptr(s.ShanghaiTime),
+		CancunTime:              optionalUint64Ptr(s.CancunTime),
+		PragueTime:              optionalUint64Ptr(s.PragueTime),
+		OsakaTime:               optionalUint64Ptr(s.OsakaTime),
+		VerkleTime:              optionalUint64Ptr(s.VerkleTime),
+		TerminalTotalDifficulty: optionalBig(s.TerminalTotalDifficulty),
+		DepositContractAddress:  s.DepositContractAddress,
+		EnableVerkleAtGenesis:   s.EnableVerkleAtGenesis,
+	}
+
+	if s.Ethash {
+		c.Ethash = &params.EthashConfig{}
+	}
+
+	if len(s.Clique) > 0 {
+		c.Clique = &params.CliqueConfig{Period: s.Clique[0].Period, Epoch: s.Clique[0].Epoch}
+	}
+
+	if len(s.BlobSchedule) > 0 {
+		c.BlobScheduleConfig = &params.BlobScheduleConfig{
+			Cancun: blobConfigFromSSZ(s.BlobSchedule[0].Cancun),
+			Prague: blobConfigFromSSZ(s.BlobSchedule[0].Prague),
+			Osaka:  blobConfigFromSSZ(s.BlobSchedule[0].Osaka),
+			Verkle: blobConfigFromSSZ(s.BlobSchedule[0].Verkle),
+		}
+	}
+
+	return c
+}
+
+func blobConfigToSSZ(c *params.BlobConfig) []*BlobConfig {
+	if c == nil {
+		return nil
+	}
+	return []*BlobConfig{{Target: uint64(c.Target), Max: uint64(c.Max), UpdateFraction: c.UpdateFraction}} //nolint:gosec // blob counts are small positive values
+}
+
+func blobConfigFromSSZ(s []*BlobConfig) *params.BlobConfig {
+	if len(s) == 0 {
+		return nil
+	}
+	return &params.BlobConfig{Target: int(s[0].Target), Max: int(s[0].Max), UpdateFraction: s[0].UpdateFraction} //nolint:gosec // blob counts are small positive values
+}
+
+func ExtraToSSZ(e *input.Extra) (*Extra, error) {
+	accessList := make([]*AccessTuple, len(e.AccessList))
+	for i, tuple := range e.AccessList {
+		keys := make([][32]byte, len(tuple.StorageKeys))
+		for j, key := range tuple.StorageKeys {
+			keys[j] = key
+		}
+		accessList[i] = &AccessTuple{Address: tuple.Address, StorageKeys: keys}
+	}
+
+	stateDiffs := make([]*StateDiff, len(e.StateDiffs))
+	for i, diff := range e.StateDiffs {
+		preAccount, err := accountToSSZ(diff.PreAccount)
+		if err != nil {
+			return nil, err
+		}
+		postAccount, err := accountToSSZ(diff.PostAccount)
+		if err != nil {
+			return nil, err
+		}
+		storage := make([]*StorageDiff, len(diff.Storage))
+		for j, s := range diff.Storage {
+			storage[j] = &StorageDiff{Slot: s.Slot, PreValue: s.PreValue, PostValue: s.PostValue}
+		}
+		stateDiffs[i] = &StateDiff{
+			Address:     diff.Address,
+			PreAccount:  preAccount,
+			PostAccount: postAccount,
+			Storage:     storage,
+		}
+	}
+
+	preState, err := PreStateToSSZ(e.PreState)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Extra{
+		AccessList: accessList,
+		Committed:  e.Committed,
+		StateDiffs: stateDiffs,
+		PreState:   preState,
+		Stats:      ExecutionStatsToSSZ(e.Stats),
+	}, nil
+}
+
+func ExtraFromSSZ(s *Extra) *input.Extra {
+	var accessList gethtypes.AccessList
+	for _, tuple := range s.AccessList {
+		keys := make([]gethcommon.Hash, len(tuple.StorageKeys))
+		for j, key := range tuple.StorageKeys {
+			keys[j] = key
+		}
+		accessList = append(accessList, gethtypes.AccessTuple{Address: tuple.Address, StorageKeys: keys})
+	}
+
+	var stateDiffs []*input.StateDiff
+	for _, diff := range s.StateDiffs {
+		var storage []*input.StorageDiff
+		for _, s := range diff.Storage {
+			storage = append(storage, &input.StorageDiff{Slot: s.Slot, PreValue: s.PreValue, PostValue: s.PostValue})
+		}
+		stateDiffs = append(stateDiffs, &input.StateDiff{
+			Address:     diff.Address,
+			PreAccount:  accountFromSSZ(diff.PreAccount),
+			PostAccount: accountFromSSZ(diff.PostAccount),
+			Storage:     storage,
+		})
+	}
+
+	return &input.Extra{
+		AccessList: accessList,
+		Committed:  s.Committed,
+		StateDiffs: stateDiffs,
+		PreState:   PreStateFromSSZ(s.PreState),
+		Stats:      ExecutionStatsFromSSZ(s.Stats),
+	}
+}
+
+func accountToSSZ(a *input.Account) ([]*Account, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	balance, err := bigToUint256(a.Balance)
+	if err != nil {
+		return nil, fmt.Errorf("invalid balance: %w", err)
+	}
+
+	return []*Account{{
+		Balance:     balance,
+		CodeHash:    a.CodeHash,
+		Nonce:       a.Nonce,
+		StorageHash: a.StorageHash,
+	}}, nil
+}
+
+func accountFromSSZ(s []*Account) *input.Account {
+	if len(s) == 0 {
+		return nil
+	}
+
+	return &input.Account{
+		Balance:     uint256ToBig(s[0].Balance),
+		CodeHash:    s[0].CodeHash,
+		Nonce:       s[0].Nonce,
+		StorageHash: s[0].StorageHash,
+	}
+}
+
+// PreStateToSSZ converts the pre-state map to a list of entries sorted by address
+// (and storage sorted by slot) so the SSZ encoding is canonical.
+func PreStateToSSZ(preState map[gethcommon.Address]*input.AccountState) ([]*PreStateEntry, error) {
+	entries := make([]*PreStateEntry, 0, len(preState))
+	for addr, accountState := range preState {
+		state, err := accountStateToSSZ(accountState)
+		if err != nil {
+			return nil, fmt.Errorf("invalid balance for %s: %w", addr.Hex(), err)
+		}
+		entries = append(entries, &PreStateEntry{Address: addr, AccountState: state})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].Address[:], entries[j].Address[:]) < 0
+	})
+
+	return entries, nil
+}
+
+func PreStateFromSSZ(entries []*PreStateEntry) map[gethcommon.Address]*input.AccountState {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	preState := make(map[gethcommon.Address]*input.AccountState, len(entries))
+	for _, entry := range entries {
+		preState[entry.Address] = accountStateFromSSZ(entry.AccountState)
+	}
+	return preState
+}
+
+func accountStateToSSZ(accountState *input.AccountState) ([]*AccountState, error) {
+	if accountState == nil {
+		return nil, nil
+	}
+
+	balance, err := bigToUint256(accountState.Balance)
+	if err != nil {
+		return nil, err
+	}
+
+	storage := make([]*StorageEntry, 0, len(accountState.Storage))
+	for slot, value := range accountState.Storage {
+		storage = append(storage, &StorageEntry{Slot: slot, Value: value})
+	}
+	sort.Slice(storage, func(i, j int) bool {
+		return bytes.Compare(storage[i].Slot[:], storage[j].Slot[:]) < 0
+	})
+
+	return []*AccountState{{
+		Balance:     balance,
+		CodeHash:    accountState.CodeHash,
+		Code:        accountState.Code,
+		Nonce:       accountState.Nonce,
+		StorageHash: accountState.StorageHash,
+		Storage:     storage,
+	}}, nil
+}
+
+func accountStateFromSSZ(s []*AccountState) *input.AccountState {
+	if len(s) == 0 {
+		return nil
+	}
+
+	var storage map[gethcommon.Hash]gethcommon.Hash
+	if len(s[0].Storage) > 0 {
+		storage = make(map[gethcommon.Hash]gethcommon.Hash, len(s[0].Storage))
+	}
+	for _, entry := range s[0].Storage {
+		storage[entry.Slot] = entry.Value
+	}
+
+	return &input.AccountState{
+		Balance:     uint256ToBig(s[0].Balance),
+		CodeHash:    s[0].CodeHash,
+		Code:        s[0].Code,
+		Nonce:       s[0].Nonce,
+		StorageHash: s[0].StorageHash,
+		Storage:     storage,
+	}
+}
+
+func ExecutionStatsToSSZ(stats *input.ExecutionStats) []*ExecutionStats {
+	if stats == nil {
+		return nil
+	}
+
+	txs := make([]*TxStats, len(stats.Transactions))
+	for i, tx := range stats.Transactions {
+		txs[i] = &TxStats{TxHash: tx.TxHash, GasUsed: tx.GasUsed, Steps: tx.Steps}
+	}
+
+	return []*ExecutionStats{{
+		Opcodes:      countsToSSZ(stats.Opcodes),
+		Precompiles:  countsToSSZ(stats.Precompiles),
+		Transactions: txs,
+	}}
+}
+
+func ExecutionStatsFromSSZ(s []*ExecutionStats) *input.ExecutionStats {
+	if len(s) == 0 {
+		return nil
+	}
+
+	txs := make([]*input.TxStats, len(s[0].Transactions))
+	for i, tx := range s[0].Transactions {
+		txs[i] = &input.TxStats{TxHash: tx.TxHash, GasUsed: tx.GasUsed, Steps: tx.Steps}
+	}
+
+	return &input.ExecutionStats{
+		Opcodes:      countsFromSSZ(s[0].Opcodes),
+		Precompiles:  countsFromSSZ(s[0].Precompiles),
+		Transactions: txs,
+	}
+}
+
+func countsToSSZ(counts map[string]uint64) []*StatsCount {
+	entries := make([]*StatsCount, 0, len(counts))
+	for name, count := range counts {
+		entries = append(entries, &StatsCount{Name: []byte(name), Count: count})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].Name, entries[j].Name) < 0
+	})
+	return entries
+}
+
+func countsFromSSZ(entries []*StatsCount) map[string]uint64 {
+	counts := make(map[string]uint64, len(entries))
+	for _, entry := range entries {
+		counts[string(entry.Name)] = entry.Count
+	}
+	return counts
+}
@@ -0,0 +1,224 @@
+package ssz
+
+import (
+	"encoding/binary"
+	"math/big"
+	"testing"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+	"github.com/kkrt-labs/go-utils/common"
+	"github.com/kkrt-labs/zk-pig/src/ethereum/op"
+	input "github.com/kkrt-labs/zk-pig/src/prover-input"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testProverInput() *input.ProverInput {
+	header := &gethtypes.Header{
+		ParentHash:       gethcommon.Hash{0x1},
+		UncleHash:        gethtypes.EmptyUncleHash,
+		Coinbase:         gethcommon.Address{0x2},
+		Root:             gethcommon.Hash{0x3},
+		TxHash:           gethcommon.Hash{0x4},
+		ReceiptHash:      gethcommon.Hash{0x5},
+		Difficulty:       big.NewInt(0),
+		Number:           big.NewInt(100),
+		GasLimit:         30000000,
+		GasUsed:          21000,
+		Time:             1000,
+		Extra:            []byte{0x6},
+		BaseFee:          big.NewInt(7),
+		WithdrawalsHash:  &gethcommon.Hash{0x8},
+		BlobGasUsed:      common.Ptr(uint64(0)),
+		ExcessBlobGas:    common.Ptr(uint64(0)),
+		ParentBeaconRoot: &gethcommon.Hash{0x9},
+	}
+
+	return &input.ProverInput{
+		Version: "v1.0.0",
+		ChainConfig: &params.ChainConfig{
+			ChainID:                 big.NewInt(1),
+			HomesteadBlock:          big.NewInt(1150000),
+			DAOForkBlock:            big.NewInt(1920000),
+			DAOForkSupport:          true,
+			LondonBlock:             big.NewInt(12965000),
+			ShanghaiTime:            common.Ptr(uint64(1681338455)),
+			CancunTime:              common.Ptr(uint64(1710338135)),
+			TerminalTotalDifficulty: new(big.Int).SetBytes(gethcommon.FromHex("0xc70d808a128d7380000")),
+			DepositContractAddress:  gethcommon.HexToAddress("0x00000000219ab540356cbb839cbe05303d7705fa"),
+			Ethash:                  &params.EthashConfig{},
+			BlobScheduleConfig: &params.BlobScheduleConfig{
+				Cancun: &params.BlobConfig{Target: 3, Max: 6, UpdateFraction: 3338477},
+			},
+		},
+		Blocks: []*input.Block{
+			{
+				Header: header,
+				Transactions: []*gethtypes.Transaction{
+					gethtypes.NewTx(&gethtypes.DynamicFeeTx{
+						ChainID:   big.NewInt(1),
+						Nonce:     1,
+						GasTipCap: big.NewInt(2),
+						GasFeeCap: big.NewInt(3),
+						Gas:       21000,
+						To:        &gethcommon.Address{0xa},
+						Value:     big.NewInt(4),
+						Data:      []byte{},
+						V:         big.NewInt(1),
+						R:         big.NewInt(2),
+						S:         big.NewInt(3),
+					}),
+					gethtypes.NewTx(&gethtypes.SetCodeTx{
+						ChainID:   uint256.NewInt(1),
+						GasTipCap: uint256.NewInt(2),
+						GasFeeCap: uint256.NewInt(3),
+						Value:     uint256.NewInt(0),
+						Data:      []byte{},
+						AuthList: []gethtypes.SetCodeAuthorization{
+							{ChainID: *uint256.NewInt(1), Address: gethcommon.Address{0xb}, Nonce: 1},
+						},
+						V: uint256.NewInt(1),
+						R: uint256.NewInt(2),
+						S: uint256.NewInt(3),
+					}),
+				},
+				Withdrawals: []*gethtypes.Withdrawal{
+					{Index: 1, Validator: 2, Address: gethcommon.Address{0xc}, Amount: 3},
+				},
+			},
+		},
+		Witness: &input.Witness{
+			State:     [][]byte{{0x1, 0x2}, {0x3}},
+			Ancestors: []*gethtypes.Header{header},
+			Codes:     [][]byte{{0x60, 0x00}},
+		},
+		Extra: &input.Extra{
+			AccessList: gethtypes.AccessList{
+				{Address: gethcommon.Address{0xa}, StorageKeys: []gethcommon.Hash{{0x1}}},
+			},
+			Committed: [][]byte{{0x4}},
+			StateDiffs: []*input.StateDiff{
+				{
+					Address:     gethcommon.Address{0xa},
+					PreAccount:  &input.Account{Balance: big.NewInt(10), Nonce: 1},
+					PostAccount: &input.Account{Balance: big.NewInt(14), Nonce: 1},
+					Storage: []*input.StorageDiff{
+						{Slot: gethcommon.Hash{0x1}, PreValue: gethcommon.Hash{0x2}, PostValue: gethcommon.Hash{0x3}},
+					},
+				},
+				{
+					Address:     gethcommon.Address{0xd},
+					PostAccount: &input.Account{Balance: big.NewInt(1)},
+				},
+			},
+			PreState: map[gethcommon.Address]*input.AccountState{
+				{0xa}: {
+					Balance: big.NewInt(10),
+					Code:    []byte{0x60, 0x00},
+					Nonce:   1,
+					Storage: map[gethcommon.Hash]gethcommon.Hash{{0x1}: {0x2}},
+				},
+				{0xb}: {Balance: big.NewInt(0)},
+			},
+			Stats: &input.ExecutionStats{
+				Opcodes:     map[string]uint64{"KECCAK256": 2, "PUSH1": 10},
+				Precompiles: map[string]uint64{"ecrecover": 1},
+				Transactions: []*input.TxStats{
+					{TxHash: gethcommon.Hash{0x1}, GasUsed: 21000, Steps: 12},
+				},
+			},
+		},
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	in := testProverInput()
+
+	b, err := Encode(in)
+	require.NoError(t, err)
+	assert.Equal(t, SchemaVersion, binary.LittleEndian.Uint64(b[:8]), "schema version should be the first field")
+
+	decoded, err := Decode(b)
+	require.NoError(t, err)
+
+	// Transaction time is not part of the encoding and size is cached on decoding
+	for _, block := range append(in.Blocks, decoded.Blocks...) {
+		for _, tx := range block.Transactions {
+			tx.SetTime(time.Unix(0, 0))
+			tx.Size()
+		}
+	}
+	assert.Equal(t, in, decoded)
+
+	// Re-encoding should produce the exact same bytes
+	reencoded, err := Encode(decoded)
+	require.NoError(t, err)
+	assert.Equal(t, b, reencoded)
+}
+
+func TestDecodeInvalidSchemaVersion(t *testing.T) {
+	b, err := Encode(testProverInput())
+	require.NoError(t, err)
+
+	binary.LittleEndian.PutUint64(b, SchemaVersion+1)
+	_, err = Decode(b)
+	assert.Error(t, err)
+}
+
+func TestEncodeOpStackUnsupported(t *testing.T) {
+	in := testProverInput()
+	in.OpStack = &op.Config{RegolithTime: common.Ptr(uint64(0))}
+	_, err := Encode(in)
+	assert.ErrorIs(t, err, ErrOpStackUnsupported)
+
+	// Deposits are rejected even without OP Stack config
+	in = testProverInput()
+	in.Blocks[0].Deposits = []*op.DepositTx{{SourceHash: gethcommon.Hash{0x1}, Value: new(big.Int)}}
+	_, err = Encode(in)
+	assert.ErrorIs(t, err, ErrOpStackUnsupported)
+	_, err = Root(in)
+	assert.ErrorIs(t, err, ErrOpStackUnsupported)
+}
+
+func TestRoot(t *testing.T) {
+	in := testProverInput()
+	root, err := Root(in)
+	require.NoError(t, err)
+	assert.NotEqual(t, gethcommon.Hash{}, root)
+
+	// Root does not depend on pre-state map iteration order
+	for i := 0; i < 10; i++ {
+		r, err := Root(testProverInput())
+		require.NoError(t, err)
+		assert.Equal(t, root, r)
+	}
+
+	in.Blocks[0].Header.GasUsed++
+	r, err := Root(in)
+	require.NoError(t, err)
+	assert.NotEqual(t, root, r)
+}
+
+func TestEncodeDecodeNilPreStateEntry(t *testing.T) {
+	// Accounts that do not exist before the block have a nil pre-state
+	in := testProverInput()
+	in.Blocks = nil
+	in.Extra.PreState[gethcommon.Address{0xc}] = nil
+
+	b, err := Encode(in)
+	require.NoError(t, err)
+
+	decoded, err := Decode(b)
+	require.NoError(t, err)
+	require.Contains(t, decoded.Extra.PreState, gethcommon.Address{0xc})
+	assert.Nil(t, decoded.Extra.PreState[gethcommon.Address{0xc}])
+	assert.Equal(t, in.Extra.PreState, decoded.Extra.PreState)
+
+	reencoded, err := Encode(decoded)
+	require.NoError(t, err)
+	assert.Equal(t, b, reencoded)
+}
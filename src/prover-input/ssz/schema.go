@@ -0,0 +1,179 @@
+package ssz
+
+// SchemaVersion is the version of the SSZ schema of prover inputs.
+//
+// It is encoded as the first 8 bytes (uint64 little-endian) of every SSZ encoded prover input,
+// so consumers can check it before decoding the rest of the data.
+// It MUST be incremented on any change to the types below.
+const SchemaVersion uint64 = 3
+
+// Uint256 is a 256-bits unsigned integer encoded in little-endian
+type Uint256 = [32]byte
+
+// All optional values are encoded as lists with at most 1 element (empty list means the value is not set)
+
+// ProverInput is the SSZ container of input.ProverInput
+type ProverInput struct {
+	SchemaVersion uint64
+	Version       []byte `ssz-max:"64"`
+	ChainConfig   *ChainConfig
+	Blocks        []*Block `ssz-max:"256"`
+	Witness       *Witness
+	Extra         []*Extra `ssz-max:"1"`
+}
+
+// Block is the SSZ container of input.Block
+type Block struct {
+	Header       []byte        `ssz-max:"65536"`              // RLP encoded header
+	Transactions [][]byte      `ssz-max:"1048576,1073741824"` // EIP-2718 encoded transactions
+	Uncles       [][]byte      `ssz-max:"16,65536"`           // RLP encoded uncle headers
+	Withdrawals  []*Withdrawal `ssz-max:"65536"`
+}
+
+// Withdrawal is the SSZ container of a block withdrawal
+type Withdrawal struct {
+	Index     uint64
+	Validator uint64
+	Address   [20]byte
+	Amount    uint64
+}
+
+// Witness is the SSZ container of input.Witness
+type Witness struct {
+	State     [][]byte `ssz-max:"16777216,65536"` // MPT nodes
+	Ancestors [][]byte `ssz-max:"1024,65536"`     // RLP encoded ancestor headers
+	Codes     [][]byte `ssz-max:"1048576,16777216"`
+}
+
+// ChainConfig is the SSZ container of params.ChainConfig
+type ChainConfig struct {
+	ChainID                 Uint256
+	HomesteadBlock          []Uint256 `ssz-max:"1"`
+	DAOForkBlock            []Uint256 `ssz-max:"1"`
+	DAOForkSupport          bool
+	EIP150Block             []Uint256 `ssz-max:"1"`
+	EIP155Block             []Uint256 `ssz-max:"1"`
+	EIP158Block             []Uint256 `ssz-max:"1"`
+	ByzantiumBlock          []Uint256 `ssz-max:"1"`
+	ConstantinopleBlock     []Uint256 `ssz-max:"1"`
+	PetersburgBlock         []Uint256 `ssz-max:"1"`
+	IstanbulBlock           []Uint256 `ssz-max:"1"`
+	MuirGlacierBlock        []Uint256 `ssz-max:"1"`
+	BerlinBlock             []Uint256 `ssz-max:"1"`
+	LondonBlock             []Uint256 `ssz-max:"1"`
+	ArrowGlacierBlock       []Uint256 `ssz-max:"1"`
+	GrayGlacierBlock        []Uint256 `ssz-max:"1"`
+	MergeNetsplitBlock      []Uint256 `ssz-max:"1"`
+	ShanghaiTime            []uint64  `ssz-max:"1"`
+	CancunTime              []uint64  `ssz-max:"1"`
+	PragueTime              []uint64  `ssz-max:"1"`
+	OsakaTime               []uint64  `ssz-max:"1"`
+	VerkleTime              []uint64  `ssz-max:"1"`
+	TerminalTotalDifficulty []Uint256 `ssz-max:"1"`
+	DepositContractAddress  [20]byte
+	EnableVerkleAtGenesis   bool
+	Ethash                  bool
+	Clique                  []*CliqueConfig       `ssz-max:"1"`
+	BlobSchedule            []*BlobScheduleConfig `ssz-max:"1"`
+}
+
+// CliqueConfig is the SSZ container of params.CliqueConfig
+type CliqueConfig struct {
+	Period uint64
+	Epoch  uint64
+}
+
+// BlobScheduleConfig is the SSZ container of params.BlobScheduleConfig
+type BlobScheduleConfig struct {
+	Cancun []*BlobConfig `ssz-max:"1"`
+	Prague []*BlobConfig `ssz-max:"1"`
+	Osaka  []*BlobConfig `ssz-max:"1"`
+	Verkle []*BlobConfig `ssz-max:"1"`
+}
+
+// BlobConfig is the SSZ container of params.BlobConfig
+type BlobConfig struct {
+	Target         uint64
+	Max            uint64
+	UpdateFraction uint64
+}
+
+// Extra is the SSZ container of input.Extra
+type Extra struct {
+	AccessList []*AccessTuple    `ssz-max:"1048576"`
+	Committed  [][]byte          `ssz-max:"16777216,65536"`
+	StateDiffs []*StateDiff      `ssz-max:"1048576"`
+	PreState   []*PreStateEntry  `ssz-max:"1048576"` // Sorted by address
+	Stats      []*ExecutionStats `ssz-max:"1"`
+}
+
+// AccessTuple is the SSZ container of an access list entry
+type AccessTuple struct {
+	Address     [20]byte
+	StorageKeys [][32]byte `ssz-max:"1048576"`
+}
+
+// StateDiff is the SSZ container of input.StateDiff
+type StateDiff struct {
+	Address     [20]byte
+	PreAccount  []*Account     `ssz-max:"1"`
+	PostAccount []*Account     `ssz-max:"1"`
+	Storage     []*StorageDiff `ssz-max:"1048576"`
+}
+
+// Account is the SSZ container of input.Account
+type Account struct {
+	Balance     Uint256
+	CodeHash    [32]byte
+	Nonce       uint64
+	StorageHash [32]byte
+}
+
+// StorageDiff is the SSZ container of input.StorageDiff
+type StorageDiff struct {
+	Slot      [32]byte
+	PreValue  [32]byte
+	PostValue [32]byte
+}
+
+// PreStateEntry is the SSZ container of an entry of input.Extra.PreState
+type PreStateEntry struct {
+	Address      [20]byte
+	AccountState []*AccountState `ssz-max:"1"` // Empty if the account does not exist
+}
+
+// AccountState is the SSZ container of input.AccountState
+type AccountState struct {
+	Balance     Uint256
+	CodeHash    [32]byte
+	Code        []byte `ssz-max:"16777216"`
+	Nonce       uint64
+	StorageHash [32]byte
+	Storage     []*StorageEntry `ssz-max:"1048576"` // Sorted by slot
+}
+
+// StorageEntry is the SSZ container of a storage slot
+type StorageEntry struct {
+	Slot  [32]byte
+	Value [32]byte
+}
+
+// ExecutionStats is the SSZ container of input.ExecutionStats
+type ExecutionStats struct {
+	Opcodes      []*StatsCount `ssz-max:"256"`  // Sorted by name
+	Precompiles  []*StatsCount `ssz-max:"1024"` // Sorted by name
+	Transactions []*TxStats    `ssz-max:"1048576"`
+}
+
+// StatsCount is the SSZ container of a named counter
+type StatsCount struct {
+	Name  []byte `ssz-max:"64"`
+	Count uint64
+}
+
+// TxStats is the SSZ container of input.TxStats
+type TxStats struct {
+	TxHash  [32]byte
+	GasUsed uint64
+	Steps   uint64
+}
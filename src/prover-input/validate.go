@@ -0,0 +1,101 @@
+package input
+
+import (
+	"errors"
+	"fmt"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Validate checks structural invariants of a prover input that can not be expressed by its encoding schema:
+// - every block has its parent header in the witness ancestors (or is the child of the previous block)
+// - witness state nodes, codes and ancestors are unique
+// - pre-state account codes match their code hash and are present in the witness codes
+//
+// It returns all the violations found, joined in a single error.
+func (pi *ProverInput) Validate() error {
+	var errs []error
+
+	if len(pi.Blocks) == 0 {
+		errs = append(errs, fmt.Errorf("no block"))
+	}
+
+	if pi.ChainConfig == nil || pi.ChainConfig.ChainID == nil {
+		errs = append(errs, fmt.Errorf("missing chain id"))
+	}
+
+	if pi.Witness == nil {
+		return errors.Join(append(errs, fmt.Errorf("missing witness"))...)
+	}
+
+	// Ancestors must be unique and contain the parent of every block
+	ancestors := make(map[gethcommon.Hash]*gethtypes.Header)
+	for _, h := range pi.Witness.Ancestors {
+		hash := h.Hash()
+		if _, ok := ancestors[hash]; ok {
+			errs = append(errs, fmt.Errorf("duplicate ancestor header %v (number %v)", hash.Hex(), h.Number))
+		}
+		ancestors[hash] = h
+	}
+
+	for i, b := range pi.Blocks {
+		if b.Header == nil {
+			errs = append(errs, fmt.Errorf("block %d: missing header", i))
+			continue
+		}
+
+		if i > 0 && pi.Blocks[i-1].Header != nil && pi.Blocks[i-1].Header.Hash() == b.Header.ParentHash {
+			continue
+		}
+
+		parent, ok := ancestors[b.Header.ParentHash]
+		if !ok {
+			errs = append(errs, fmt.Errorf("block %v: missing parent header %v in witness ancestors", b.Header.Number, b.Header.ParentHash.Hex()))
+			continue
+		}
+		if parent.Number == nil || b.Header.Number == nil || parent.Number.Uint64()+1 != b.Header.Number.Uint64() {
+			errs = append(errs, fmt.Errorf("block %v: parent header has invalid number %v", b.Header.Number, parent.Number))
+		}
+	}
+
+	// State nodes must be unique
+	nodes := make(map[gethcommon.Hash]struct{})
+	for _, node := range pi.Witness.State {
+		hash := crypto.Keccak256Hash(node)
+		if _, ok := nodes[hash]; ok {
+			errs = append(errs, fmt.Errorf("duplicate state node %v", hash.Hex()))
+		}
+		nodes[hash] = struct{}{}
+	}
+
+	// Codes must be unique
+	codes := make(map[gethcommon.Hash]struct{})
+	for _, code := range pi.Witness.Codes {
+		hash := crypto.Keccak256Hash(code)
+		if _, ok := codes[hash]; ok {
+			errs = append(errs, fmt.Errorf("duplicate code %v", hash.Hex()))
+		}
+		codes[hash] = struct{}{}
+	}
+
+	// Pre-state codes must be consistent with code hashes
+	if pi.Extra != nil {
+		for addr, account := range pi.Extra.PreState {
+			if account == nil || len(account.Code) == 0 {
+				continue
+			}
+
+			hash := crypto.Keccak256Hash(account.Code)
+			if hash != account.CodeHash {
+				errs = append(errs, fmt.Errorf("pre-state account %v: code hash mismatch (expected %v, got %v)", addr.Hex(), account.CodeHash.Hex(), hash.Hex()))
+			}
+			if _, ok := codes[hash]; !ok {
+				errs = append(errs, fmt.Errorf("pre-state account %v: code %v missing in witness codes", addr.Hex(), hash.Hex()))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
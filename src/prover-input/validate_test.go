@@ -0,0 +1,107 @@
+package input
+
+import (
+	"math/big"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+)
+
+func testValidProverInput() *ProverInput {
+	parent := &gethtypes.Header{Number: big.NewInt(9), Difficulty: big.NewInt(0)}
+	code := []byte{0x60, 0x00}
+	return &ProverInput{
+		ChainConfig: &params.ChainConfig{ChainID: big.NewInt(1)},
+		Blocks: []*Block{
+			{Header: &gethtypes.Header{Number: big.NewInt(10), Difficulty: big.NewInt(0), ParentHash: parent.Hash()}},
+		},
+		Witness: &Witness{
+			State:     [][]byte{{0x1}, {0x2}},
+			Ancestors: []*gethtypes.Header{parent},
+			Codes:     [][]byte{code},
+		},
+		Extra: &Extra{
+			PreState: map[gethcommon.Address]*AccountState{
+				{0xa}: {Code: code, CodeHash: crypto.Keccak256Hash(code)},
+				{0xb}: nil,
+			},
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	var testCases = []struct {
+		desc    string
+		modify  func(pi *ProverInput)
+		wantErr string
+	}{
+		{
+			desc:   "valid",
+			modify: func(_ *ProverInput) {},
+		},
+		{
+			desc: "valid with chained blocks",
+			modify: func(pi *ProverInput) {
+				prev := pi.Blocks[0].Header
+				pi.Blocks = append(pi.Blocks, &Block{Header: &gethtypes.Header{Number: big.NewInt(11), Difficulty: big.NewInt(0), ParentHash: prev.Hash()}})
+			},
+		},
+		{
+			desc:    "no block",
+			modify:  func(pi *ProverInput) { pi.Blocks = nil },
+			wantErr: "no block",
+		},
+		{
+			desc:    "missing witness",
+			modify:  func(pi *ProverInput) { pi.Witness = nil },
+			wantErr: "missing witness",
+		},
+		{
+			desc:    "missing parent",
+			modify:  func(pi *ProverInput) { pi.Witness.Ancestors = nil },
+			wantErr: "missing parent header",
+		},
+		{
+			desc:    "invalid parent number",
+			modify:  func(pi *ProverInput) { pi.Blocks[0].Header.Number = big.NewInt(12) },
+			wantErr: "parent header has invalid number",
+		},
+		{
+			desc:    "duplicate state node",
+			modify:  func(pi *ProverInput) { pi.Witness.State = append(pi.Witness.State, []byte{0x1}) },
+			wantErr: "duplicate state node",
+		},
+		{
+			desc:    "duplicate code",
+			modify:  func(pi *ProverInput) { pi.Witness.Codes = append(pi.Witness.Codes, pi.Witness.Codes[0]) },
+			wantErr: "duplicate code",
+		},
+		{
+			desc:    "code hash mismatch",
+			modify:  func(pi *ProverInput) { pi.Extra.PreState[gethcommon.Address{0xa}].CodeHash = gethcommon.Hash{0x1} },
+			wantErr: "code hash mismatch",
+		},
+		{
+			desc:    "code missing in witness",
+			modify:  func(pi *ProverInput) { pi.Witness.Codes = nil },
+			wantErr: "missing in witness codes",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			pi := testValidProverInput()
+			tc.modify(pi)
+			err := pi.Validate()
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.wantErr)
+			}
+		})
+	}
+}
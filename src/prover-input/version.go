@@ -0,0 +1,84 @@
+package input
+
+import (
+	"fmt"
+)
+
+// Prover input format versions.
+//
+// The version MUST be bumped on every change of the ProverInput layout (in any of its encodings: JSON, protobuf, SSZ),
+// and a migration from the previous version MUST be registered in migrations.
+const (
+	// VersionUnversioned is the version of prover inputs generated before versioning was introduced (empty "version" field).
+	// Its layout is the same as Version1 minus Extra.PreState (which was not recorded) and EIP-7702 transactions.
+	VersionUnversioned = ""
+
+	// Version1 is the first versioned layout of prover inputs.
+	Version1 = "1"
+
+	// Version2 adds the OP Stack config and deposit transactions (OpStack, Block.Deposits) and the execution statistics extension (Extra.Stats).
+	Version2 = "2"
+
+	// Version3 adds the Isthmus and Jovian hardfork timestamps to the OP Stack config (OpStack.IsthmusTime, OpStack.JovianTime).
+	Version3 = "3"
+
+	// CurrentVersion is the version stamped on every generated prover input.
+	CurrentVersion = Version3
+)
+
+// Migration upgrades a prover input from a version to the next one.
+type Migration struct {
+	From    string
+	To      string
+	Migrate func(*ProverInput) error
+}
+
+// migrations is indexed by the version the migration upgrades from.
+var migrations = map[string]*Migration{
+	VersionUnversioned: {
+		From: VersionUnversioned,
+		To:   Version1,
+		// Unversioned inputs have no pre-state, which is left unset (nil) rather than empty so it is not mistaken
+		// for the pre-state of a block accessing no account.
+		Migrate: func(in *ProverInput) error {
+			if in.Extra != nil {
+				in.Extra.PreState = nil
+			}
+			return nil
+		},
+	},
+	Version1: {
+		From: Version1,
+		To:   Version2,
+		// Version2 only adds optional fields, so there is nothing to convert.
+		Migrate: func(*ProverInput) error { return nil },
+	},
+	Version2: {
+		From: Version2,
+		To:   Version3,
+		// Version3 only adds optional fields, so there is nothing to convert.
+		Migrate: func(*ProverInput) error { return nil },
+	},
+}
+
+// ErrUnsupportedVersion is returned when a prover input version is unknown (e.g. generated by a more recent zk-pig).
+var ErrUnsupportedVersion = fmt.Errorf("unsupported prover input version")
+
+// Migrate upgrades a prover input to CurrentVersion in place by applying migrations in sequence.
+// It returns the version the input had before migrating (CurrentVersion if it was not migrated).
+func Migrate(in *ProverInput) (string, error) {
+	from := in.Version
+	for in.Version != CurrentVersion {
+		m, ok := migrations[in.Version]
+		if !ok {
+			return from, fmt.Errorf("%w: %q (current version is %q)", ErrUnsupportedVersion, in.Version, CurrentVersion)
+		}
+
+		if err := m.Migrate(in); err != nil {
+			return from, fmt.Errorf("failed to migrate prover input from version %q to %q: %w", m.From, m.To, err)
+		}
+		in.Version = m.To
+	}
+
+	return from, nil
+}
@@ -0,0 +1,65 @@
+package input
+
+import (
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	t.Run("Unversioned", func(t *testing.T) {
+		in := &ProverInput{}
+		from, err := Migrate(in)
+		require.NoError(t, err)
+		assert.Equal(t, VersionUnversioned, from)
+		assert.Equal(t, CurrentVersion, in.Version)
+	})
+
+	t.Run("UnversionedPreState", func(t *testing.T) {
+		in := &ProverInput{Extra: &Extra{PreState: map[gethcommon.Address]*AccountState{}}}
+		_, err := Migrate(in)
+		require.NoError(t, err)
+		assert.Nil(t, in.Extra.PreState, "pre-state of unversioned inputs is unknown")
+	})
+
+	t.Run("Version1", func(t *testing.T) {
+		in := &ProverInput{Version: Version1}
+		from, err := Migrate(in)
+		require.NoError(t, err)
+		assert.Equal(t, Version1, from)
+		assert.Equal(t, CurrentVersion, in.Version)
+	})
+
+	t.Run("Current", func(t *testing.T) {
+		in := &ProverInput{Version: CurrentVersion}
+		from, err := Migrate(in)
+		require.NoError(t, err)
+		assert.Equal(t, CurrentVersion, from)
+		assert.Equal(t, CurrentVersion, in.Version)
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		in := &ProverInput{Version: "999"}
+		_, err := Migrate(in)
+		assert.ErrorIs(t, err, ErrUnsupportedVersion)
+		assert.Equal(t, "999", in.Version)
+	})
+}
+
+func TestMigrationsReachCurrentVersion(t *testing.T) {
+	for from, m := range migrations {
+		assert.Equal(t, from, m.From)
+
+		// Every migration path must end at the current version without cycles
+		version, steps := m.To, 0
+		for version != CurrentVersion {
+			next, ok := migrations[version]
+			require.True(t, ok, "no migration from version %q", version)
+			version = next.To
+			steps++
+			require.Less(t, steps, len(migrations), "migration cycle from version %q", from)
+		}
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/kkrt-labs/go-utils/tag"
 	"github.com/kkrt-labs/zk-pig/src/ethereum"
 	"github.com/kkrt-labs/zk-pig/src/ethereum/evm"
+	"github.com/kkrt-labs/zk-pig/src/ethereum/op"
 	input "github.com/kkrt-labs/zk-pig/src/prover-input"
 	"go.uber.org/zap"
 )
@@ -34,6 +35,22 @@ type executor struct {
 	evm evm.Executor
 }
 
+// ExecutionError is returned when the execution of a block fails
+// It carries the result of the execution when the block could be processed (e.g. when the block validation failed),
+// so the receipts can be compared with the canonical ones to find the diverging transaction.
+type ExecutionError struct {
+	Result *core.ProcessResult
+	Err    error
+}
+
+func (e *ExecutionError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExecutionError) Unwrap() error {
+	return e.Err
+}
+
 // NewExecutor creates a new instance of the Executor.
 func NewExecutor() Executor {
 	return NewExecutorFromEvm(evm.NewExecutor())
@@ -86,9 +103,13 @@ func (e *executor) execute(ctx context.Context, in *input.ProverInput) (*core.Pr
 		State:    preState,
 	}
 
+	if in.OpStack != nil {
+		execParams.Processor = op.NewProcessor(in.OpStack, in.Blocks[0].Deposits)
+	}
+
 	res, err := e.evm.Execute(ctx, execParams)
 	if err != nil {
-		return res, fmt.Errorf("execute: %v", err)
+		return res, &ExecutionError{Result: res, Err: fmt.Errorf("execute: %v", err)}
 	}
 
 	return res, nil
@@ -13,6 +13,7 @@ const (
 	expPreState   = 1
 	expStateDiffs = 2
 	expCommitted  = 3
+	expStats      = 4
 )
 
 const (
@@ -21,7 +22,8 @@ const (
 	IncludePreState   Include = 1 << expPreState
 	IncludeStateDiffs Include = 1 << expStateDiffs
 	IncludeCommitted  Include = 1 << expCommitted
-	IncludeAll        Include = IncludeAccessList | IncludePreState | IncludeStateDiffs | IncludeCommitted
+	IncludeStats      Include = 1 << expStats
+	IncludeAll        Include = IncludeAccessList | IncludePreState | IncludeStateDiffs | IncludeCommitted | IncludeStats
 )
 
 var ValidIncludes = []Include{
@@ -30,6 +32,7 @@ var ValidIncludes = []Include{
 	IncludePreState,
 	IncludeStateDiffs,
 	IncludeCommitted,
+	IncludeStats,
 	IncludeAll,
 }
 
@@ -42,6 +45,7 @@ var (
 		"preState",
 		"stateDiffs",
 		"committed",
+		"stats",
 		includeAllStr,
 		includeNoneStr,
 	}
@@ -52,6 +56,7 @@ var includesStrReverse = map[string]Include{
 	includesStr[expPreState]:   IncludePreState,
 	includesStr[expStateDiffs]: IncludeStateDiffs,
 	includesStr[expCommitted]:  IncludeCommitted,
+	includesStr[expStats]:      IncludeStats,
 	includeAllStr:              IncludeAll,
 	includeNoneStr:             IncludeNone,
 }
@@ -19,11 +19,13 @@ func TestIncludesString(t *testing.T) {
 		{IncludePreState, "preState"},
 		{IncludeStateDiffs, "stateDiffs"},
 		{IncludeCommitted, "committed"},
+		{IncludeStats, "stats"},
 		{IncludeAccessList | IncludePreState, "accessList,preState"},
 		{IncludeAccessList | IncludePreState | IncludeStateDiffs, "accessList,preState,stateDiffs"},
-		{IncludeAccessList | IncludePreState | IncludeStateDiffs | IncludeCommitted, "all"},
-		{1 << 4, "none"},
-		{1<<4 | 1<<3, "committed"},
+		{IncludeAccessList | IncludePreState | IncludeStateDiffs | IncludeCommitted, "accessList,preState,stateDiffs,committed"},
+		{IncludeAccessList | IncludePreState | IncludeStateDiffs | IncludeCommitted | IncludeStats, "all"},
+		{1 << 5, "none"},
+		{1<<5 | 1<<3, "committed"},
 	}
 	for _, test := range tests {
 		if got := test.incl.String(); got != test.want {
@@ -45,6 +47,7 @@ func TestParseIncludes(t *testing.T) {
 		{[]string{"preState"}, IncludePreState, false},
 		{[]string{"stateDiffs"}, IncludeStateDiffs, false},
 		{[]string{"committed"}, IncludeCommitted, false},
+		{[]string{"stats"}, IncludeStats, false},
 		{[]string{"accessList", "preState"}, IncludeAccessList | IncludePreState, false},
 		{[]string{"accessList", "preState", "stateDiffs"}, IncludeAccessList | IncludePreState | IncludeStateDiffs, false},
 		{[]string{"all", "none"}, IncludeAll, false},
@@ -62,5 +65,5 @@ func TestParseIncludes(t *testing.T) {
 }
 
 func TestValidIncludes(t *testing.T) {
-	assert.Equal(t, "[\"none\" \"accessList\" \"preState\" \"stateDiffs\" \"committed\" \"all\"]", fmt.Sprintf("%q", ValidIncludes))
+	assert.Equal(t, "[\"none\" \"accessList\" \"preState\" \"stateDiffs\" \"committed\" \"stats\" \"all\"]", fmt.Sprintf("%q", ValidIncludes))
 }
@@ -21,6 +21,7 @@ import (
 	"github.com/kkrt-labs/zk-pig/src/ethereum"
 	"github.com/kkrt-labs/zk-pig/src/ethereum/ethdb/rpcdb"
 	"github.com/kkrt-labs/zk-pig/src/ethereum/evm"
+	"github.com/kkrt-labs/zk-pig/src/ethereum/op"
 	"github.com/kkrt-labs/zk-pig/src/ethereum/state"
 	"github.com/kkrt-labs/zk-pig/src/ethereum/trie"
 	"go.uber.org/zap"
@@ -30,12 +31,14 @@ import (
 // It contains the partial state & chain data necessary for processing the block and validating the final state.
 // The format is convenient but sub-optimal as it contains duplicated data, it is an intermediate object necessary to generate the final ProverInput.
 type PreflightData struct {
-	Block           *ethrpc.Block        `json:"block"`           // Block to execute
-	Ancestors       []*gethtypes.Header  `json:"ancestors"`       // Ancestors of the block that are accessed during the block execution
-	ChainConfig     *params.ChainConfig  `json:"chainConfig"`     // Chain configuration
-	Codes           []hexutil.Bytes      `json:"codes"`           // Contract bytecodes used during the block execution
-	PreStateProofs  []*trie.AccountProof `json:"preStateProofs"`  // Proofs of every accessed account and storage slot accessed during the block processing
-	PostStateProofs []*trie.AccountProof `json:"postStateProofs"` // Proofs of every account and storage slot deleted during the block processing
+	Block           *ethrpc.Block        `json:"block"`              // Block to execute
+	Ancestors       []*gethtypes.Header  `json:"ancestors"`          // Ancestors of the block that are accessed during the block execution
+	ChainConfig     *params.ChainConfig  `json:"chainConfig"`        // Chain configuration
+	Codes           []hexutil.Bytes      `json:"codes"`              // Contract bytecodes used during the block execution
+	PreStateProofs  []*trie.AccountProof `json:"preStateProofs"`     // Proofs of every accessed account and storage slot accessed during the block processing
+	PostStateProofs []*trie.AccountProof `json:"postStateProofs"`    // Proofs of every account and storage slot deleted during the block processing
+	OpStack         *op.Config           `json:"opStack,omitempty"`  // OP Stack configuration (set for OP Stack chains only)
+	Deposits        []*op.DepositTx      `json:"deposits,omitempty"` // OP Stack deposit transactions of the block (not part of Block transactions)
 }
 
 //go:generate mockgen -destination=./mock/preflight.go -package=mocksteps github.com/kkrt-labs/zk-pig/src/steps Preflight
@@ -55,22 +58,40 @@ type preflight struct {
 	chainCfg *params.ChainConfig
 
 	evm evm.Executor
+
+	deposits op.DepositReader
+}
+
+type PreflightOption func(*preflight)
+
+// WithDepositReader sets the reader of the deposit transactions of the blocks of OP Stack chains
+//
+// It is required to run preflight on OP Stack chains, as deposits are not part of the blocks passed to Preflight.
+func WithDepositReader(deposits op.DepositReader) PreflightOption {
+	return func(pf *preflight) {
+		pf.deposits = deposits
+	}
 }
 
 // NewPreflight creates a new RPC Preflight instance using the provided RPC client.
-func NewPreflight(remote ethrpc.Client) Preflight {
+func NewPreflight(remote ethrpc.Client, opts ...PreflightOption) Preflight {
 	return NewPreflightFromEvm(
 		evm.NewExecutor(),
 		remote,
+		opts...,
 	)
 }
 
 // NewPreflightFromEvm creates a new RPC Preflight instance using the provided EVM.
-func NewPreflightFromEvm(e evm.Executor, remote ethrpc.Client) Preflight {
-	return &preflight{
+func NewPreflightFromEvm(e evm.Executor, remote ethrpc.Client, opts ...PreflightOption) Preflight {
+	pf := &preflight{
 		remote: remote,
 		evm:    e,
 	}
+	for _, opt := range opts {
+		opt(pf)
+	}
+	return pf
 }
 
 func (pf *preflight) configureDBAndChain(ctx context.Context) (*state.RPCDatabase, *core.HeaderChain, error) {
@@ -144,6 +165,21 @@ func (pf *preflight) preflight(ctx context.Context, block *gethtypes.Block) (*Pr
 		Chain: hc,
 		State: st,
 	}
+
+	// OP Stack blocks are processed with their deposits, which are fetched separately
+	opCfg := op.GetConfig(pf.chainCfg.ChainID)
+	var deposits []*op.DepositTx
+	if opCfg != nil {
+		if pf.deposits == nil {
+			return nil, fmt.Errorf("preflight: no deposit reader configured for OP Stack chain %v", pf.chainCfg.ChainID)
+		}
+		deposits, err = pf.deposits.DepositsByHash(ctx, block.Hash())
+		if err != nil {
+			return nil, fmt.Errorf("preflight: failed to fetch deposits: %v", err)
+		}
+		execParams.Processor = op.NewProcessor(opCfg, deposits)
+	}
+
 	_, err = pf.evm.Execute(ctx, execParams)
 	if err != nil {
 		return nil, fmt.Errorf("preflight: failed to execute block: %v", err)
@@ -156,6 +192,8 @@ func (pf *preflight) preflight(ctx context.Context, block *gethtypes.Block) (*Pr
 		ChainConfig: pf.chainCfg,
 		Block:       new(ethrpc.Block).FromBlock(block, pf.chainCfg),
 		Ancestors:   witness.Headers,
+		OpStack:     opCfg,
+		Deposits:    deposits,
 	}
 	for code := range witness.Codes {
 		data.Codes = append(data.Codes, []byte(code))
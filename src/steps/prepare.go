@@ -18,6 +18,7 @@ import (
 	"github.com/kkrt-labs/go-utils/tag"
 	"github.com/kkrt-labs/zk-pig/src/ethereum"
 	"github.com/kkrt-labs/zk-pig/src/ethereum/evm"
+	"github.com/kkrt-labs/zk-pig/src/ethereum/op"
 	"github.com/kkrt-labs/zk-pig/src/ethereum/state"
 	"github.com/kkrt-labs/zk-pig/src/ethereum/trie"
 	input "github.com/kkrt-labs/zk-pig/src/prover-input"
@@ -37,11 +38,27 @@ type Preparer interface {
 type preparer struct {
 	evm evm.Executor
 
-	includeOpt Include
+	includeOpt     Include
+	reportAccesses func(ctx context.Context, counts *AccessCounts)
 }
 
 type PrepareOption func(*preparer) error
 
+// AccessCounts is the number of accounts and storage slots accessed during a block execution
+type AccessCounts struct {
+	Accounts int
+	Slots    int
+}
+
+// WithAccessReporter sets a function called with the number of accounts and storage slots
+// accessed during the execution of every prepared block (as tracked by the access tracker).
+func WithAccessReporter(report func(ctx context.Context, counts *AccessCounts)) PrepareOption {
+	return func(p *preparer) error {
+		p.reportAccesses = report
+		return nil
+	}
+}
+
 // NewPreparer creates a new Preparer.
 func NewPreparer(opts ...PrepareOption) (Preparer, error) {
 	return NewPreparerFromEvm(
@@ -109,9 +126,28 @@ func (p *preparer) prepare(ctx context.Context, data *PreflightData) (*input.Pro
 		State:    preState,
 	}
 
-	_, err = p.evm.Execute(ctx, execParams)
+	if data.OpStack != nil {
+		execParams.Processor = op.NewProcessor(data.OpStack, data.Deposits)
+	}
+
+	var stats *statsTracer
+	if p.include(IncludeStats) {
+		stats = newStatsTracer(hc.Config())
+		execParams.VMConfig.Tracer = stats.Hooks()
+	}
+
+	res, err := p.evm.Execute(ctx, execParams)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute block: %v", err)
+		return nil, &ExecutionError{Result: res, Err: fmt.Errorf("failed to execute block: %v", err)}
+	}
+
+	if p.reportAccesses != nil {
+		counts := new(AccessCounts)
+		for _, accountAccessTracker := range trackers.GetAccessTracker(parentHeader.Root).Accounts {
+			counts.Accounts++
+			counts.Slots += len(accountAccessTracker.Storage)
+		}
+		p.reportAccesses(ctx, counts)
 	}
 
 	extra := new(input.Extra)
@@ -188,7 +224,12 @@ func (p *preparer) prepare(ctx context.Context, data *PreflightData) (*input.Pro
 		}
 	}
 
+	if p.include(IncludeStats) {
+		extra.Stats = stats.Stats()
+	}
+
 	return &input.ProverInput{
+		Version:     input.CurrentVersion,
 		ChainConfig: execParams.Chain.Config(),
 		Blocks: []*input.Block{
 			{
@@ -196,6 +237,7 @@ func (p *preparer) prepare(ctx context.Context, data *PreflightData) (*input.Pro
 				Transactions: execParams.Block.Transactions(),
 				Uncles:       execParams.Block.Uncles(),
 				Withdrawals:  execParams.Block.Withdrawals(),
+				Deposits:     data.Deposits,
 			},
 		},
 		Witness: &input.Witness{
@@ -203,7 +245,8 @@ func (p *preparer) prepare(ctx context.Context, data *PreflightData) (*input.Pro
 			Codes:     witnessToBytes(execParams.State.Witness().Codes),
 			State:     witnessToBytes(execParams.State.Witness().State),
 		},
-		Extra: extra,
+		OpStack: data.OpStack,
+		Extra:   extra,
 	}, nil
 }
 
@@ -291,14 +334,12 @@ func (p *preparer) prepareStateDBAndChain(_ context.Context, in *PreflightData)
 		return nil, nil, fmt.Errorf("missing parent header for block %q", in.Block.Header.Number.String())
 	}
 
-	genesisHeader := hc.GetHeaderByNumber(0)
-
 	nodeSet, err := trie.NodeSetFromStateTransitionProofs(parentHeader.Root, in.Block.Root, in.PreStateProofs, in.PostStateProofs)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create state nodes: %v", err)
 	}
 
-	err = stateDB.TrieDB().Update(parentHeader.Root, genesisHeader.Root, 0, nodeSet, triedb.NewStateSet())
+	err = stateDB.TrieDB().Update(parentHeader.Root, gethtypes.EmptyRootHash, 0, nodeSet, triedb.NewStateSet())
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to update trie db with state nodes: %v", err)
 	}
@@ -2,9 +2,20 @@ package steps
 
 import (
 	"context"
+	"math/big"
 	"testing"
 
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/kkrt-labs/go-utils/common"
+	ethrpc "github.com/kkrt-labs/go-utils/ethereum/rpc"
+	"github.com/kkrt-labs/zk-pig/src/ethereum/evm"
+	"github.com/kkrt-labs/zk-pig/src/ethereum/op"
 	input "github.com/kkrt-labs/zk-pig/src/prover-input"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -16,8 +27,10 @@ func TestPreparer(t *testing.T) {
 	for _, name := range testcases {
 		t.Run(name, func(t *testing.T) {
 			testDataInputs := loadTestDataInputs(t, testDataInputsPath(name))
+			var counts *AccessCounts
 			p, err := NewPreparer(
 				WithDataInclude(IncludeAll),
+				WithAccessReporter(func(_ context.Context, c *AccessCounts) { counts = c }),
 			)
 			require.NoError(t, err)
 			result, err := p.Prepare(context.Background(), &testDataInputs.PreflightData)
@@ -25,6 +38,15 @@ func TestPreparer(t *testing.T) {
 			require.NotNil(t, result)
 			equal := input.CompareProverInput(&testDataInputs.ProverInput, result)
 			require.True(t, equal)
+
+			// Access counts match the access list
+			require.NotNil(t, counts)
+			slots := 0
+			for _, tuple := range result.Extra.AccessList {
+				slots += len(tuple.StorageKeys)
+			}
+			assert.Equal(t, len(result.Extra.AccessList), counts.Accounts)
+			assert.Equal(t, slots, counts.Slots)
 		})
 	}
 }
@@ -32,3 +54,55 @@ func TestPreparer(t *testing.T) {
 func testDataInputsPath(filename string) string {
 	return "testdata/" + filename
 }
+
+func TestPreparerOpStack(t *testing.T) {
+	chainCfg := params.AllDevChainProtocolChanges
+	parent := &gethtypes.Header{
+		Number:      big.NewInt(9),
+		Difficulty:  new(big.Int),
+		Root:        gethtypes.EmptyRootHash,
+		UncleHash:   gethtypes.EmptyUncleHash,
+		TxHash:      gethtypes.EmptyTxsHash,
+		ReceiptHash: gethtypes.EmptyReceiptsHash,
+	}
+	block := gethtypes.NewBlockWithHeader(&gethtypes.Header{
+		ParentHash:  parent.Hash(),
+		Number:      big.NewInt(10),
+		Difficulty:  new(big.Int),
+		Root:        gethtypes.EmptyRootHash,
+		UncleHash:   gethtypes.EmptyUncleHash,
+		TxHash:      gethcommon.Hash{0x1},
+		ReceiptHash: gethcommon.Hash{0x2},
+		BaseFee:     big.NewInt(1),
+	})
+
+	data := &PreflightData{
+		Block:       new(ethrpc.Block).FromBlock(block, chainCfg),
+		Ancestors:   []*gethtypes.Header{parent},
+		ChainConfig: chainCfg,
+		OpStack:     &op.Config{RegolithTime: common.Ptr(uint64(0))},
+		Deposits:    []*op.DepositTx{{SourceHash: gethcommon.Hash{0x3}, Value: new(big.Int)}},
+	}
+
+	// The block is executed with the OP Stack processor
+	var processor evm.Processor
+	e := evm.ExecutorFunc(func(_ context.Context, execParams *evm.ExecParams) (*core.ProcessResult, error) {
+		witness, err := stateless.NewWitness(execParams.Block.Header(), execParams.Chain)
+		if err != nil {
+			return nil, err
+		}
+		execParams.State.StartPrefetcher("chain", witness)
+		processor = execParams.Processor
+		return &core.ProcessResult{}, nil
+	})
+
+	p, err := NewPreparerFromEvm(e)
+	require.NoError(t, err)
+	in, err := p.Prepare(context.Background(), data)
+	require.NoError(t, err)
+
+	assert.NotNil(t, processor)
+	assert.Equal(t, data.OpStack, in.OpStack)
+	require.Len(t, in.Blocks, 1)
+	assert.Equal(t, data.Deposits, in.Blocks[0].Deposits)
+}
@@ -0,0 +1,118 @@
+package steps
+
+import (
+	"math/big"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+	input "github.com/kkrt-labs/zk-pig/src/prover-input"
+)
+
+// precompileNames are the names of the precompiles used in execution stats
+var precompileNames = map[gethcommon.Address]string{
+	gethcommon.BytesToAddress([]byte{0x01}): "ecrecover",
+	gethcommon.BytesToAddress([]byte{0x02}): "sha256",
+	gethcommon.BytesToAddress([]byte{0x03}): "ripemd160",
+	gethcommon.BytesToAddress([]byte{0x04}): "identity",
+	gethcommon.BytesToAddress([]byte{0x05}): "modexp",
+	gethcommon.BytesToAddress([]byte{0x06}): "bn256Add",
+	gethcommon.BytesToAddress([]byte{0x07}): "bn256ScalarMul",
+	gethcommon.BytesToAddress([]byte{0x08}): "bn256Pairing",
+	gethcommon.BytesToAddress([]byte{0x09}): "blake2f",
+	gethcommon.BytesToAddress([]byte{0x0a}): "kzgPointEvaluation",
+	gethcommon.BytesToAddress([]byte{0x0b}): "bls12381G1Add",
+	gethcommon.BytesToAddress([]byte{0x0c}): "bls12381G1MultiExp",
+	gethcommon.BytesToAddress([]byte{0x0d}): "bls12381G2Add",
+	gethcommon.BytesToAddress([]byte{0x0e}): "bls12381G2MultiExp",
+	gethcommon.BytesToAddress([]byte{0x0f}): "bls12381Pairing",
+	gethcommon.BytesToAddress([]byte{0x10}): "bls12381MapG1",
+	gethcommon.BytesToAddress([]byte{0x11}): "bls12381MapG2",
+}
+
+// precompileName returns the name of a precompile (defaults to its address)
+func precompileName(addr gethcommon.Address) string {
+	if name, ok := precompileNames[addr]; ok {
+		return name
+	}
+	return addr.Hex()
+}
+
+// statsTracer is an EVM tracer that collects opcode and precompile usage statistics of a block execution
+// Opcodes and precompile calls are counted for the whole block (including system calls)
+// while steps and gas used are counted per transaction
+type statsTracer struct {
+	chainCfg *params.ChainConfig
+
+	precompiles map[gethcommon.Address]struct{} // Precompiles active for the block
+	current     *input.TxStats
+	stats       *input.ExecutionStats
+}
+
+func newStatsTracer(chainCfg *params.ChainConfig) *statsTracer {
+	return &statsTracer{
+		chainCfg: chainCfg,
+		stats: &input.ExecutionStats{
+			Opcodes:      make(map[string]uint64),
+			Precompiles:  make(map[string]uint64),
+			Transactions: make([]*input.TxStats, 0),
+		},
+	}
+}
+
+func (t *statsTracer) OnBlockStart(event tracing.BlockEvent) {
+	header := event.Block.Header()
+	rules := t.chainCfg.Rules(header.Number, header.Difficulty == nil || header.Difficulty.Sign() == 0, header.Time)
+
+	t.precompiles = make(map[gethcommon.Address]struct{})
+	for _, addr := range vm.ActivePrecompiles(rules) {
+		t.precompiles[addr] = struct{}{}
+	}
+}
+
+func (t *statsTracer) OnTxStart(_ *tracing.VMContext, tx *gethtypes.Transaction, _ gethcommon.Address) {
+	t.current = &input.TxStats{TxHash: tx.Hash()}
+	t.stats.Transactions = append(t.stats.Transactions, t.current)
+}
+
+func (t *statsTracer) OnTxEnd(receipt *gethtypes.Receipt, _ error) {
+	// Transactions that are not go-ethereum transactions (e.g. OP Stack deposits) are identified by their receipt
+	if t.current != nil && receipt != nil {
+		t.current.TxHash = receipt.TxHash
+		t.current.GasUsed = receipt.GasUsed
+	}
+	t.current = nil
+}
+
+func (t *statsTracer) OnEnter(_ int, typ byte, _, to gethcommon.Address, _ []byte, _ uint64, _ *big.Int) {
+	switch vm.OpCode(typ) {
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		if _, ok := t.precompiles[to]; ok {
+			t.stats.Precompiles[precompileName(to)]++
+		}
+	}
+}
+
+func (t *statsTracer) OnOpcode(_ uint64, op byte, _, _ uint64, _ tracing.OpContext, _ []byte, _ int, _ error) {
+	t.stats.Opcodes[vm.OpCode(op).String()]++
+	if t.current != nil {
+		t.current.Steps++
+	}
+}
+
+// Stats returns the statistics collected so far
+func (t *statsTracer) Stats() *input.ExecutionStats {
+	return t.stats
+}
+
+func (t *statsTracer) Hooks() *tracing.Hooks {
+	return &tracing.Hooks{
+		OnBlockStart: t.OnBlockStart,
+		OnTxStart:    t.OnTxStart,
+		OnTxEnd:      t.OnTxEnd,
+		OnEnter:      t.OnEnter,
+		OnOpcode:     t.OnOpcode,
+	}
+}
@@ -0,0 +1,118 @@
+package steps
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	gethstate "github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/holiman/uint256"
+	"github.com/kkrt-labs/go-utils/common"
+	"github.com/kkrt-labs/zk-pig/src/ethereum"
+	"github.com/kkrt-labs/zk-pig/src/ethereum/evm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsTracer(t *testing.T) {
+	chainCfg := &params.ChainConfig{
+		ChainID:                 big.NewInt(1),
+		HomesteadBlock:          big.NewInt(0),
+		EIP150Block:             big.NewInt(0),
+		EIP155Block:             big.NewInt(0),
+		EIP158Block:             big.NewInt(0),
+		ByzantiumBlock:          big.NewInt(0),
+		ConstantinopleBlock:     big.NewInt(0),
+		PetersburgBlock:         big.NewInt(0),
+		IstanbulBlock:           big.NewInt(0),
+		MuirGlacierBlock:        big.NewInt(0),
+		BerlinBlock:             big.NewInt(0),
+		LondonBlock:             big.NewInt(0),
+		ArrowGlacierBlock:       big.NewInt(0),
+		GrayGlacierBlock:        big.NewInt(0),
+		MergeNetsplitBlock:      big.NewInt(0),
+		ShanghaiTime:            common.Ptr(uint64(0)),
+		TerminalTotalDifficulty: big.NewInt(0),
+		Ethash:                  new(params.EthashConfig),
+	}
+
+	stateDB := gethstate.NewDatabase(triedb.NewDatabase(rawdb.NewMemoryDatabase(), nil), nil)
+	preState, err := gethstate.New(gethtypes.EmptyRootHash, stateDB)
+	require.NoError(t, err)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	preState.AddBalance(crypto.PubkeyToAddress(key.PublicKey), uint256.NewInt(params.Ether), tracing.BalanceChangeUnspecified)
+
+	// Contract calling ecrecover then hashing empty memory
+	// PUSH1 0 PUSH1 0 PUSH1 0 PUSH1 0 PUSH1 1 GAS STATICCALL POP PUSH1 0 PUSH1 0 KECCAK256 POP STOP
+	contract := gethcommon.Address{0xc}
+	preState.SetCode(contract, []byte{
+		0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x01, 0x5a, 0xfa, 0x50,
+		0x60, 0x00, 0x60, 0x00, 0x20, 0x50, 0x00,
+	})
+
+	hc, err := ethereum.NewChain(chainCfg, stateDB)
+	require.NoError(t, err)
+
+	var txs gethtypes.Transactions
+	for nonce, to := range []gethcommon.Address{contract, {0xe}} {
+		tx, txErr := gethtypes.SignNewTx(key, gethtypes.LatestSignerForChainID(chainCfg.ChainID), &gethtypes.DynamicFeeTx{
+			ChainID:   chainCfg.ChainID,
+			Nonce:     uint64(nonce),
+			GasTipCap: big.NewInt(params.GWei),
+			GasFeeCap: big.NewInt(2 * params.GWei),
+			Gas:       100000,
+			To:        &to,
+		})
+		require.NoError(t, txErr)
+		txs = append(txs, tx)
+	}
+
+	header := &gethtypes.Header{
+		ParentHash: hc.GetHeaderByNumber(0).Hash(),
+		Number:     big.NewInt(1),
+		GasLimit:   30_000_000,
+		Time:       10,
+		BaseFee:    big.NewInt(params.GWei),
+		Difficulty: new(big.Int),
+	}
+	block := gethtypes.NewBlock(header, &gethtypes.Body{Transactions: txs}, nil, trie.NewStackTrie(nil))
+
+	tracer := newStatsTracer(chainCfg)
+	res, err := evm.NewExecutor().Execute(context.TODO(), &evm.ExecParams{
+		VMConfig: &vm.Config{Tracer: tracer.Hooks()},
+		Block:    block,
+		State:    preState,
+		Chain:    hc,
+	})
+	require.NoError(t, err)
+
+	stats := tracer.Stats()
+	assert.Equal(t, map[string]uint64{"ecrecover": 1}, stats.Precompiles)
+	assert.Equal(t, map[string]uint64{
+		"PUSH1":      7,
+		"GAS":        1,
+		"STATICCALL": 1,
+		"POP":        2,
+		"KECCAK256":  1,
+		"STOP":       1,
+	}, stats.Opcodes)
+
+	require.Len(t, stats.Transactions, 2)
+	assert.Equal(t, txs[0].Hash(), stats.Transactions[0].TxHash)
+	assert.Equal(t, uint64(13), stats.Transactions[0].Steps)
+	assert.Equal(t, res.Receipts[0].GasUsed, stats.Transactions[0].GasUsed)
+	assert.Equal(t, txs[1].Hash(), stats.Transactions[1].TxHash)
+	assert.Equal(t, uint64(0), stats.Transactions[1].Steps)
+	assert.Equal(t, uint64(21000), stats.Transactions[1].GasUsed)
+}
@@ -0,0 +1,221 @@
+package steps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/kkrt-labs/go-utils/app/svc"
+	"github.com/kkrt-labs/go-utils/jsonrpc"
+	"github.com/kkrt-labs/go-utils/log"
+	"github.com/kkrt-labs/go-utils/tag"
+	input "github.com/kkrt-labs/zk-pig/src/prover-input"
+	"go.uber.org/zap"
+)
+
+//go:generate mockgen -destination=./mock/verifier.go -package=mocksteps github.com/kkrt-labs/zk-pig/src/steps Verifier
+
+// Verifier is the interface for verifying the result of a block execution on provable inputs.
+// It runs after a successful execution and compares the execution result with the canonical chain data.
+// It enables to localize the transaction at which a stateless execution diverges from the chain.
+type Verifier interface {
+	// Verify compares the result of the execution of the prover input block with the canonical chain data.
+	Verify(ctx context.Context, in *input.ProverInput, res *core.ProcessResult) error
+}
+
+type receiptsVerifier struct {
+	remote jsonrpc.Client
+}
+
+// NewReceiptsVerifier creates a Verifier that compares the receipts of the execution
+// with the canonical receipts fetched with eth_getBlockReceipts.
+func NewReceiptsVerifier(remote jsonrpc.Client) Verifier {
+	return &receiptsVerifier{
+		remote: remote,
+	}
+}
+
+// Verify compares the receipts of the execution with the canonical receipts of the block.
+// On mismatch, it returns a *ReceiptsMismatchError containing a per-transaction diff.
+func (v *receiptsVerifier) Verify(ctx context.Context, in *input.ProverInput, res *core.ProcessResult) error {
+	log.LoggerFromContext(ctx).Info("Verify execution receipts against canonical receipts...")
+	err := v.verify(ctx, in, res)
+	if err != nil {
+		log.LoggerFromContext(ctx).Error("Receipts verification failed", zap.Error(err))
+		return err
+	}
+	log.LoggerFromContext(ctx).Info("Receipts verification succeeded")
+
+	return nil
+}
+
+func (v *receiptsVerifier) verify(ctx context.Context, in *input.ProverInput, res *core.ProcessResult) error {
+	if res == nil {
+		return fmt.Errorf("missing execution result")
+	}
+
+	header := in.Blocks[0].Header
+	var remote gethtypes.Receipts
+	err := v.remote.Call(
+		ctx,
+		&jsonrpc.Request{
+			Method: "eth_getBlockReceipts",
+			Params: []any{header.Hash()},
+		},
+		&remote,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to fetch block receipts: %v", err)
+	}
+
+	if diffs := DiffReceipts(remote, res.Receipts); len(diffs) > 0 {
+		return &ReceiptsMismatchError{
+			BlockNumber: header.Number.Uint64(),
+			BlockHash:   header.Hash(),
+			Diffs:       diffs,
+		}
+	}
+
+	return nil
+}
+
+// FieldDiff is a mismatch on a single receipt field
+type FieldDiff struct {
+	Field  string
+	Remote string
+	Local  string
+}
+
+// ReceiptDiff contains the mismatching fields of the receipt of a transaction
+type ReceiptDiff struct {
+	Index  int
+	TxHash gethcommon.Hash
+	Fields []*FieldDiff
+}
+
+// DiffReceipts compares canonical (remote) receipts with receipts obtained from local execution.
+// It compares status, gas used, cumulative gas used and logs, and returns a diff for every mismatching transaction.
+func DiffReceipts(remote, local gethtypes.Receipts) []*ReceiptDiff {
+	var diffs []*ReceiptDiff
+	for i := 0; i < max(len(remote), len(local)); i++ {
+		var r, l *gethtypes.Receipt
+		if i < len(remote) {
+			r = remote[i]
+		}
+		if i < len(local) {
+			l = local[i]
+		}
+
+		if diff := diffReceipt(i, r, l); diff != nil {
+			diffs = append(diffs, diff)
+		}
+	}
+	return diffs
+}
+
+func diffReceipt(i int, remote, local *gethtypes.Receipt) *ReceiptDiff {
+	diff := &ReceiptDiff{Index: i}
+	switch {
+	case remote == nil:
+		diff.TxHash = local.TxHash
+		diff.Fields = append(diff.Fields, &FieldDiff{Field: "receipt", Remote: "<missing>", Local: "<present>"})
+		return diff
+	case local == nil:
+		diff.TxHash = remote.TxHash
+		diff.Fields = append(diff.Fields, &FieldDiff{Field: "receipt", Remote: "<present>", Local: "<missing>"})
+		return diff
+	}
+
+	diff.TxHash = remote.TxHash
+	addDiff := func(field string, r, l any) {
+		diff.Fields = append(diff.Fields, &FieldDiff{Field: field, Remote: fmt.Sprint(r), Local: fmt.Sprint(l)})
+	}
+
+	if remote.Status != local.Status {
+		addDiff("status", remote.Status, local.Status)
+	}
+	if remote.GasUsed != local.GasUsed {
+		addDiff("gasUsed", remote.GasUsed, local.GasUsed)
+	}
+	if remote.CumulativeGasUsed != local.CumulativeGasUsed {
+		addDiff("cumulativeGasUsed", remote.CumulativeGasUsed, local.CumulativeGasUsed)
+	}
+	if len(remote.Logs) != len(local.Logs) {
+		addDiff("logs.length", len(remote.Logs), len(local.Logs))
+	}
+	for j := 0; j < min(len(remote.Logs), len(local.Logs)); j++ {
+		r, l := remote.Logs[j], local.Logs[j]
+		if r.Address != l.Address {
+			addDiff(fmt.Sprintf("logs[%d].address", j), r.Address.Hex(), l.Address.Hex())
+		}
+		if !equalTopics(r.Topics, l.Topics) {
+			addDiff(fmt.Sprintf("logs[%d].topics", j), r.Topics, l.Topics)
+		}
+		if !bytes.Equal(r.Data, l.Data) {
+			addDiff(fmt.Sprintf("logs[%d].data", j), gethcommon.Bytes2Hex(r.Data), gethcommon.Bytes2Hex(l.Data))
+		}
+	}
+
+	if len(diff.Fields) == 0 {
+		return nil
+	}
+	return diff
+}
+
+func equalTopics(a, b []gethcommon.Hash) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ReceiptsMismatchError is returned when the receipts of an execution differ from the canonical receipts
+type ReceiptsMismatchError struct {
+	BlockNumber uint64
+	BlockHash   gethcommon.Hash
+	Diffs       []*ReceiptDiff
+}
+
+func (e *ReceiptsMismatchError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "receipts mismatch for block %d (%v) on %d transaction(s)", e.BlockNumber, e.BlockHash.Hex(), len(e.Diffs))
+	for _, d := range e.Diffs {
+		fmt.Fprintf(&b, "\n  tx %d (%v):", d.Index, d.TxHash.Hex())
+		for _, f := range d.Fields {
+			fmt.Fprintf(&b, "\n    %s: remote=%s local=%s", f.Field, f.Remote, f.Local)
+		}
+	}
+	return b.String()
+}
+
+type taggedVerifier struct {
+	Verifier
+	*svc.Tagged
+}
+
+func VerifierWithTags(v Verifier, tags ...*tag.Tag) Verifier {
+	return &taggedVerifier{
+		Verifier: v,
+		Tagged:   svc.NewTagged(tags...),
+	}
+}
+
+func (tv *taggedVerifier) Verify(ctx context.Context, in *input.ProverInput, res *core.ProcessResult) error {
+	ctx = tv.Context(
+		ctx,
+		tag.Key("chain.id").String(in.ChainConfig.ChainID.String()),
+		tag.Key("block.number").Int64(in.Blocks[0].Header.Number.Int64()),
+		tag.Key("block.hash").String(in.Blocks[0].Header.Hash().Hex()),
+	)
+
+	return tv.Verifier.Verify(ctx, in, res)
+}
@@ -0,0 +1,167 @@
+package steps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/kkrt-labs/go-utils/jsonrpc"
+	input "github.com/kkrt-labs/zk-pig/src/prover-input"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testReceipts() gethtypes.Receipts {
+	return gethtypes.Receipts{
+		{
+			Status:            gethtypes.ReceiptStatusSuccessful,
+			CumulativeGasUsed: 21000,
+			GasUsed:           21000,
+			TxHash:            gethcommon.Hash{0x1},
+			Logs:              []*gethtypes.Log{},
+		},
+		{
+			Status:            gethtypes.ReceiptStatusSuccessful,
+			CumulativeGasUsed: 71000,
+			GasUsed:           50000,
+			TxHash:            gethcommon.Hash{0x2},
+			Logs: []*gethtypes.Log{
+				{
+					Address: gethcommon.Address{0xa},
+					Topics:  []gethcommon.Hash{{0xb}},
+					Data:    []byte{0xc},
+					TxHash:  gethcommon.Hash{0x2},
+				},
+			},
+		},
+	}
+}
+
+func TestDiffReceipts(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		modify   func(local gethtypes.Receipts) gethtypes.Receipts
+		expected []*ReceiptDiff
+	}{
+		{
+			desc:   "identical",
+			modify: func(local gethtypes.Receipts) gethtypes.Receipts { return local },
+		},
+		{
+			desc: "status and gas",
+			modify: func(local gethtypes.Receipts) gethtypes.Receipts {
+				local[0].Status = gethtypes.ReceiptStatusFailed
+				local[0].GasUsed = 22000
+				local[0].CumulativeGasUsed = 22000
+				local[1].CumulativeGasUsed = 72000
+				return local
+			},
+			expected: []*ReceiptDiff{
+				{
+					Index:  0,
+					TxHash: gethcommon.Hash{0x1},
+					Fields: []*FieldDiff{
+						{Field: "status", Remote: "1", Local: "0"},
+						{Field: "gasUsed", Remote: "21000", Local: "22000"},
+						{Field: "cumulativeGasUsed", Remote: "21000", Local: "22000"},
+					},
+				},
+				{
+					Index:  1,
+					TxHash: gethcommon.Hash{0x2},
+					Fields: []*FieldDiff{
+						{Field: "cumulativeGasUsed", Remote: "71000", Local: "72000"},
+					},
+				},
+			},
+		},
+		{
+			desc: "logs",
+			modify: func(local gethtypes.Receipts) gethtypes.Receipts {
+				local[0].Logs = []*gethtypes.Log{{}}
+				local[1].Logs[0].Data = []byte{0xd}
+				return local
+			},
+			expected: []*ReceiptDiff{
+				{
+					Index:  0,
+					TxHash: gethcommon.Hash{0x1},
+					Fields: []*FieldDiff{{Field: "logs.length", Remote: "0", Local: "1"}},
+				},
+				{
+					Index:  1,
+					TxHash: gethcommon.Hash{0x2},
+					Fields: []*FieldDiff{{Field: "logs[0].data", Remote: "0c", Local: "0d"}},
+				},
+			},
+		},
+		{
+			desc: "missing receipt",
+			modify: func(local gethtypes.Receipts) gethtypes.Receipts {
+				return local[:1]
+			},
+			expected: []*ReceiptDiff{
+				{
+					Index:  1,
+					TxHash: gethcommon.Hash{0x2},
+					Fields: []*FieldDiff{{Field: "receipt", Remote: "<present>", Local: "<missing>"}},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			diffs := DiffReceipts(testReceipts(), tc.modify(testReceipts()))
+			assert.Equal(t, tc.expected, diffs)
+		})
+	}
+}
+
+func TestReceiptsVerifier(t *testing.T) {
+	header := &gethtypes.Header{Number: big.NewInt(10)}
+	in := &input.ProverInput{
+		Blocks: []*input.Block{{Header: header}},
+	}
+
+	remote := jsonrpc.ClientFunc(func(_ context.Context, req *jsonrpc.Request, res any) error {
+		if req.Method != "eth_getBlockReceipts" {
+			return fmt.Errorf("unexpected method %q", req.Method)
+		}
+		if params := req.Params.([]any); len(params) != 1 || params[0] != header.Hash() {
+			return fmt.Errorf("unexpected params %v", req.Params)
+		}
+
+		b, err := json.Marshal(testReceipts())
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(b, res)
+	})
+	v := NewReceiptsVerifier(remote)
+
+	t.Run("match", func(t *testing.T) {
+		err := v.Verify(context.TODO(), in, &core.ProcessResult{Receipts: testReceipts()})
+		require.NoError(t, err)
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		local := testReceipts()
+		local[1].GasUsed = 50001
+
+		err := v.Verify(context.TODO(), in, &core.ProcessResult{Receipts: local})
+		require.Error(t, err)
+
+		var mismatchErr *ReceiptsMismatchError
+		require.ErrorAs(t, err, &mismatchErr)
+		assert.Equal(t, uint64(10), mismatchErr.BlockNumber)
+		require.Len(t, mismatchErr.Diffs, 1)
+		assert.Equal(t, 1, mismatchErr.Diffs[0].Index)
+		assert.Contains(t, err.Error(), "gasUsed: remote=50000 local=50001")
+	})
+}
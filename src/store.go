@@ -3,6 +3,7 @@ package src
 import (
 	"context"
 	"fmt"
+	"os"
 
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
@@ -10,20 +11,26 @@ import (
 	"github.com/kkrt-labs/go-utils/app"
 	"github.com/kkrt-labs/go-utils/common"
 	store "github.com/kkrt-labs/go-utils/store"
-	compressstore "github.com/kkrt-labs/go-utils/store/compress"
 	filestore "github.com/kkrt-labs/go-utils/store/file"
-	multistore "github.com/kkrt-labs/go-utils/store/multi"
-	s3store "github.com/kkrt-labs/go-utils/store/s3"
 	inputstore "github.com/kkrt-labs/zk-pig/src/store"
+	"github.com/kkrt-labs/zk-pig/src/telemetry"
 )
 
 var (
 	storeComponentName              = "store"
 	fileStoreComponentName          = fmt.Sprintf("%s.file", storeComponentName)
 	s3StoreComponentName            = fmt.Sprintf("%s.s3", storeComponentName)
+	kvStoreComponentName            = fmt.Sprintf("%s.kv", storeComponentName)
 	blockStoreComponentName         = fmt.Sprintf("%s.block", storeComponentName)
 	proverInputStoreComponentName   = "prover-input-store"
 	preflightDataStoreComponentName = "preflight-data-store"
+	badBlockStoreComponentName      = "bad-block-store"
+	traceStoreComponentName         = "trace-store"
+	proverInputCatalogComponentName = "prover-input-catalog"
+	storeListerComponentName        = fmt.Sprintf("%s.lister", storeComponentName)
+	storeRoutesComponentName        = fmt.Sprintf("%s.routes", storeComponentName)
+	blobCacheComponentName          = fmt.Sprintf("%s.blob-cache", storeComponentName)
+	garbageCollectorComponentName   = "garbage-collector"
 )
 
 func (a *App) BlockStore() inputstore.BlockStore {
@@ -45,7 +52,12 @@ func (a *App) blockStoreBase() inputstore.BlockStore {
 		a,
 		fmt.Sprintf("%s.base", blockStoreComponentName),
 		func() (inputstore.BlockStore, error) {
-			return inputstore.NewBlockStore(a.Store()), nil
+			opts, err := a.storeOptions()
+			if err != nil {
+				return nil, err
+			}
+
+			return inputstore.NewBlockStore(a.blocksStore(), opts...), nil
 		},
 	)
 }
@@ -55,6 +67,7 @@ func (a *App) ProverInputStore() inputstore.ProverInputStore {
 		proverInputStoreComponentName,
 		func() (inputstore.ProverInputStore, error) {
 			s := a.proverInputStoreBase()
+			s = inputstore.ProverInputStoreWithCatalog(s, a.ProverInputCatalog())
 			s = inputstore.ProverInputStoreWithLog(s)
 			s = inputstore.ProverInputStoreWithTags(s)
 
@@ -71,16 +84,217 @@ func (a *App) proverInputStoreBase() inputstore.ProverInputStore {
 		func() (inputstore.ProverInputStore, error) {
 			cfg := a.Config().ProverInputs
 
-			return inputstore.NewProverInputStore(a.Store(), common.Val(cfg.ContentType)), nil
+			opts, err := a.storeOptions()
+			if err != nil {
+				return nil, err
+			}
+
+			if cfg.Manifest != nil && common.Val(cfg.Manifest.Enabled) {
+				manifestOpts, err := manifestOptions(cfg.Manifest)
+				if err != nil {
+					return nil, err
+				}
+				opts = append(opts, manifestOpts...)
+			}
+
+			if common.Val(cfg.Dedup) {
+				opts = append(opts, inputstore.WithDedup(), inputstore.WithBlobCache(a.blobCache()))
+			}
+
+			opts = append(opts, inputstore.WithSizeReporter(a.reportProverInputSize))
+
+			return inputstore.NewProverInputStore(a.proverInputsStore(), common.Val(cfg.ContentType), opts...), nil
 		})
 }
 
+// ProverInputCatalog returns the catalog of the prover inputs available in the store
+func (a *App) ProverInputCatalog() inputstore.ProverInputCatalog {
+	return provide(
+		a,
+		proverInputCatalogComponentName,
+		func() (inputstore.ProverInputCatalog, error) {
+			opts, err := a.storeOptions()
+			if err != nil {
+				return nil, err
+			}
+
+			// Metadata is stored next to the prover inputs
+			return inputstore.NewProverInputCatalog(a.proverInputsStore(), a.StoreLister(), Version, opts...), nil
+		},
+	)
+}
+
+// StoreLister returns the lister of the objects of the enabled stores
+func (a *App) StoreLister() inputstore.Lister {
+	return provide(
+		a,
+		storeListerComponentName,
+		func() (inputstore.Lister, error) {
+			var listers []inputstore.Lister
+			if a.Config().Store.File != nil && common.Val(a.Config().Store.File.Enabled) {
+				listers = append(listers, inputstore.NewFileLister(common.Val(a.Config().Store.File.Dir)))
+			}
+			if a.Config().Store.S3 != nil && common.Val(a.Config().Store.S3.Enabled) {
+				cfg := a.Config().Store.S3
+				listers = append(listers, inputstore.NewS3Lister(a.s3Client(), common.Val(cfg.Bucket), common.Val(cfg.Prefix)))
+			}
+			if a.Config().Store.KV != nil && common.Val(a.Config().Store.KV.Enabled) {
+				listers = append(listers, a.kvStoreBase())
+			}
+			return inputstore.NewMultiLister(listers...), nil
+		},
+	)
+}
+
+// GarbageCollector returns the garbage collector of stored artifacts applying the configured retention policy
+func (a *App) GarbageCollector() inputstore.GarbageCollector {
+	return provide(
+		a,
+		garbageCollectorComponentName,
+		func() (inputstore.GarbageCollector, error) {
+			cfg := a.Config().GC
+			if cfg == nil {
+				cfg = new(GCConfig)
+			}
+
+			// Listed keys contain the encoding extension so objects are deleted from the backends directly
+			s := telemetry.StoreWithTracing(inputstore.NewMultiStore(a.FileStore(), a.S3Store(), a.KVStore()))
+
+			opts, err := a.storeOptions()
+			if err != nil {
+				return nil, err
+			}
+			// Witness references are loaded from the store of prover inputs, which decompresses them
+			opts = append(opts, inputstore.WithBlobSweep(a.proverInputsStore()), inputstore.WithBlobCache(a.blobCache()))
+
+			return inputstore.NewGarbageCollector(s, a.StoreLister(), &inputstore.RetentionPolicy{
+				KeepLast:            common.Val(cfg.KeepLast),
+				KeepNewerThan:       common.Val(cfg.KeepNewerThan),
+				KeepModulo:          common.Val(cfg.KeepModulo),
+				DeletePreflightData: common.Val(cfg.DeletePreflightData),
+				KeepBlobsNewerThan:  common.Val(cfg.KeepBlobsNewerThan),
+			}, opts...), nil
+		},
+	)
+}
+
+// blobCache returns the cache of the blobs of deduplicated prover inputs stored by the process
+// It is shared by the prover input store and the garbage collector, which removes the blobs it deletes
+func (a *App) blobCache() *inputstore.BlobCache {
+	return provide(
+		a,
+		blobCacheComponentName,
+		func() (*inputstore.BlobCache, error) {
+			return inputstore.NewBlobCache(), nil
+		},
+	)
+}
+
 func (a *App) PreflightDataStore() inputstore.PreflightDataStore {
 	return provide(
 		a,
 		preflightDataStoreComponentName,
 		func() (inputstore.PreflightDataStore, error) {
-			return inputstore.NewPreflightDataStore(a.Store())
+			opts, err := a.storeOptions()
+			if err != nil {
+				return nil, err
+			}
+
+			return inputstore.NewPreflightDataStore(a.preflightDataStore(), opts...)
+		},
+	)
+}
+
+// manifestOptions returns the options of the manifests of the stored prover inputs
+func manifestOptions(cfg *ManifestConfig) ([]inputstore.Option, error) {
+	opts := []inputstore.Option{inputstore.WithManifest(Version)}
+
+	if key := common.Val(cfg.SigningKey); key != "" {
+		signingKey, err := inputstore.ParseEd25519PrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, inputstore.WithManifestSigningKey(signingKey))
+	}
+
+	if key := common.Val(cfg.VerifyingKey); key != "" {
+		verifyingKey, err := inputstore.ParseEd25519PublicKey(key)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, inputstore.WithManifestVerifyingKey(verifyingKey))
+	}
+
+	return opts, nil
+}
+
+// storeOptions returns the options of the stores of block artifacts
+func (a *App) storeOptions() ([]inputstore.Option, error) {
+	layout, err := inputstore.ParseLayout(common.Val(a.Config().Store.Layout))
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []inputstore.Option{inputstore.WithLayout(layout)}
+
+	keys := a.Config().Store.Keys
+	if keys == nil {
+		return opts, nil
+	}
+
+	if template := common.Val(keys.ProverInput); template != "" {
+		t, err := inputstore.ParseKeyTemplate(template, inputstore.KeyExt)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, inputstore.WithProverInputKey(t))
+	}
+
+	if template := common.Val(keys.PreflightData); template != "" {
+		t, err := inputstore.ParseKeyTemplate(template)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, inputstore.WithPreflightDataKey(t))
+	}
+
+	if template := common.Val(keys.Block); template != "" {
+		t, err := inputstore.ParseKeyTemplate(template)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, inputstore.WithBlockKey(t))
+	}
+
+	return opts, nil
+}
+
+func (a *App) BadBlockStore() inputstore.BadBlockStore {
+	return provide(
+		a,
+		badBlockStoreComponentName,
+		func() (inputstore.BadBlockStore, error) {
+			opts, err := a.storeOptions()
+			if err != nil {
+				return nil, err
+			}
+
+			return inputstore.NewBadBlockStore(a.Store(), opts...), nil
+		},
+	)
+}
+
+func (a *App) TraceStore() inputstore.TraceStore {
+	return provide(
+		a,
+		traceStoreComponentName,
+		func() (inputstore.TraceStore, error) {
+			opts, err := a.storeOptions()
+			if err != nil {
+				return nil, err
+			}
+
+			return inputstore.NewTraceStore(a.Store(), opts...), nil
 		},
 	)
 }
@@ -90,21 +304,149 @@ func (a *App) Store() store.Store {
 		a,
 		storeComponentName,
 		func() (store.Store, error) {
-			multiStore := multistore.New(
+			multiStore := inputstore.NewMultiStore(
 				a.FileStore(),
 				a.S3Store(),
+				a.KVStore(),
 			)
 
-			compressedStore, err := compressstore.New(multiStore, compressstore.WithContentEncoding(common.Val(a.Config().Store.ContentEncoding)))
+			compressedStore, err := a.compressStore(multiStore, common.Val(a.Config().Store.ContentEncoding))
 			if err != nil {
-				return nil, fmt.Errorf("failed to create compressed store: %w", err)
+				return nil, err
 			}
 
-			return compressedStore, nil
+			a.TracerProvider()
+
+			return telemetry.StoreWithTracing(compressedStore), nil
 		},
 	)
 }
 
+// proverInputsStore returns the store of prover inputs according to their route
+func (a *App) proverInputsStore() store.Store {
+	return a.routedStore("prover-inputs", a.storeRoutes().ProverInputs)
+}
+
+// preflightDataStore returns the store of preflight data according to its route
+func (a *App) preflightDataStore() store.Store {
+	return a.routedStore("preflight-data", a.storeRoutes().PreflightData)
+}
+
+// blocksStore returns the store of blocks according to their route
+func (a *App) blocksStore() store.Store {
+	return a.routedStore("blocks", a.storeRoutes().Blocks)
+}
+
+func (a *App) storeRoutes() *StoreRoutesConfig {
+	if routes := a.Config().Store.Routes; routes != nil {
+		return routes
+	}
+	return new(StoreRoutesConfig)
+}
+
+// routedStore returns the store of a type of artifact writing to the backends of its route with its content encoding
+// Artifacts without route are written to all enabled backends with the store content encoding
+func (a *App) routedStore(artifact string, route *StoreRouteConfig) store.Store {
+	return provide(
+		a,
+		fmt.Sprintf("%s.%s", storeRoutesComponentName, artifact),
+		func() (store.Store, error) {
+			if route == nil || (route.Backends == nil && route.ContentEncoding == nil) {
+				return a.Store(), nil
+			}
+
+			backends := []store.Store{a.FileStore(), a.S3Store(), a.KVStore()}
+			if route.Backends != nil {
+				var err error
+				if backends, err = a.routeBackends(common.ValSlice(*route.Backends...)); err != nil {
+					return nil, fmt.Errorf("invalid %s store route: %w", artifact, err)
+				}
+			}
+
+			encoding := common.Val(a.Config().Store.ContentEncoding)
+			if route.ContentEncoding != nil {
+				encoding = *route.ContentEncoding
+			}
+
+			compressedStore, err := a.compressStore(inputstore.NewMultiStore(backends...), encoding)
+			if err != nil {
+				return nil, err
+			}
+
+			a.TracerProvider()
+
+			return telemetry.StoreWithTracing(compressedStore), nil
+		},
+	)
+}
+
+// compressStore returns a store compressing objects with the given content encoding
+// zstd dictionaries are loaded from the paths of the zstd config
+func (a *App) compressStore(s store.Store, encoding inputstore.ContentEncoding) (store.Store, error) {
+	var opts []inputstore.CompressOption
+	if cfg := a.Config().Store.Zstd; encoding == inputstore.ContentEncodingZstd && cfg != nil {
+		if cfg.Level != nil {
+			opts = append(opts, inputstore.WithZstdLevel(*cfg.Level))
+		}
+
+		if cfg.Dictionary != nil && *cfg.Dictionary != "" {
+			d, err := os.ReadFile(*cfg.Dictionary)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read zstd dictionary: %w", err)
+			}
+			opts = append(opts, inputstore.WithZstdDictionary(d))
+		}
+
+		if cfg.Dictionaries != nil {
+			for _, path := range common.ValSlice(*cfg.Dictionaries...) {
+				d, err := os.ReadFile(path)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read zstd dictionary: %w", err)
+				}
+				opts = append(opts, inputstore.WithZstdDecoderDictionaries(d))
+			}
+		}
+	}
+
+	compressedStore, err := inputstore.NewCompressStore(s, encoding, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create compressed store: %w", err)
+	}
+
+	return compressedStore, nil
+}
+
+// routeBackends returns the backends of a route (e.g. ["file", "s3"]), "none" meaning artifacts are not stored
+func (a *App) routeBackends(names []string) ([]store.Store, error) {
+	cfg := a.Config().Store
+
+	var backends []store.Store
+	for _, name := range names {
+		switch name {
+		case "none":
+		case "file":
+			if cfg.File == nil || !common.Val(cfg.File.Enabled) {
+				return nil, fmt.Errorf("file store is not enabled")
+			}
+			backends = append(backends, a.FileStore())
+		case "s3":
+			if cfg.S3 == nil || !common.Val(cfg.S3.Enabled) {
+				return nil, fmt.Errorf("s3 store is not enabled")
+			}
+			backends = append(backends, a.S3Store())
+		case "kv":
+			if cfg.KV == nil || !common.Val(cfg.KV.Enabled) {
+				return nil, fmt.Errorf("kv store is not enabled")
+			}
+			backends = append(backends, a.KVStore())
+		default:
+			return nil, fmt.Errorf("unknown store %q (expected one of \"file\" \"s3\" \"kv\" or \"none\")", name)
+		}
+	}
+
+	return backends, nil
+}
+
 func (a *App) FileStore() store.Store {
 	return provide(
 		a,
@@ -131,6 +473,19 @@ func (a *App) S3Store() store.Store {
 	)
 }
 
+func (a *App) KVStore() store.Store {
+	return provide(
+		a,
+		kvStoreComponentName,
+		func() (store.Store, error) {
+			if a.Config().Store.KV != nil && common.Val(a.Config().Store.KV.Enabled) {
+				return a.kvStoreWithTags(), nil
+			}
+			return store.NewNoOpStore(), nil
+		},
+	)
+}
+
 func (a *App) fileStoreBase() store.Store {
 	return provide(
 		a,
@@ -216,11 +571,7 @@ func (a *App) s3StoreBase() store.Store {
 		func() (store.Store, error) {
 			cfg := a.Config().Store.S3
 
-			return s3store.New(
-				a.s3Client(),
-				common.Val(cfg.Bucket),
-				s3store.WithKeyPrefix(common.Val(cfg.Prefix)),
-			)
+			return inputstore.NewS3Store(a.s3Client(), common.Val(cfg.Bucket), common.Val(cfg.Prefix))
 		},
 	)
 }
@@ -259,3 +610,48 @@ func (a *App) s3StoreWithTags() store.Store {
 		app.WithComponentName(s3StoreComponentName),
 	)
 }
+
+// kvStoreBase returns the embedded key-value store, which database is opened on Start and closed on Stop
+func (a *App) kvStoreBase() *inputstore.KVStore {
+	return provide(
+		a,
+		fmt.Sprintf("%s.base", kvStoreComponentName),
+		func() (*inputstore.KVStore, error) {
+			return inputstore.NewKVStore(common.Val(a.Config().Store.KV.Path)), nil
+		},
+	)
+}
+
+func (a *App) kvStoreWithMetrics() store.Store {
+	return provide(
+		a,
+		fmt.Sprintf("%s.metrics", kvStoreComponentName),
+		func() (store.Store, error) {
+			s := a.kvStoreBase()
+			return store.WithMetrics(s), nil
+		},
+	)
+}
+
+func (a *App) kvStoreWithLog() store.Store {
+	return provide(
+		a,
+		fmt.Sprintf("%s.logging", kvStoreComponentName),
+		func() (store.Store, error) {
+			s := a.kvStoreWithMetrics()
+			return store.WithLog(s), nil
+		},
+	)
+}
+
+func (a *App) kvStoreWithTags() store.Store {
+	return provide(
+		a,
+		fmt.Sprintf("%s.tags", kvStoreComponentName),
+		func() (store.Store, error) {
+			s := a.kvStoreWithLog()
+			return store.WithTags(s), nil
+		},
+		app.WithComponentName(kvStoreComponentName),
+	)
+}
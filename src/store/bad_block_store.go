@@ -0,0 +1,80 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	store "github.com/kkrt-labs/go-utils/store"
+	"github.com/kkrt-labs/zk-pig/src/ethereum/evm"
+)
+
+// BadBlockStore is a store for reports of blocks which processing or validation failed.
+type BadBlockStore interface {
+	// StoreBadBlockReport stores the bad block report of a block.
+	StoreBadBlockReport(ctx context.Context, report *evm.BadBlockReport) error
+
+	// LoadBadBlockReport loads the bad block report of a block.
+	// If reports of competing blocks have been stored at the same height, the last stored one is loaded.
+	LoadBadBlockReport(ctx context.Context, chainID, blockNumber uint64) (*evm.BadBlockReport, error)
+}
+
+// NewBadBlockStore creates a new BadBlockStore instance
+// Reports are stored next to the prover input of the block (see WithProverInputKey)
+func NewBadBlockStore(s store.Store, opts ...Option) BadBlockStore {
+	return &badBlockStore{
+		store:   s,
+		options: newOptions(opts...),
+	}
+}
+
+type badBlockStore struct {
+	store store.Store
+	*options
+}
+
+func (s *badBlockStore) StoreBadBlockReport(ctx context.Context, report *evm.BadBlockReport) error {
+	chainID := report.ChainID.Uint64()
+	blockNumber := report.Header.Number.Uint64()
+	params := blockKeyParams(chainID, blockNumber, report.Header.Hash(), report.Header.Time)
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(report); err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	headers := store.Headers{
+		ContentType:     store.ContentTypeJSON,
+		ContentEncoding: store.ContentEncodingPlain,
+		KeyValue: map[string]string{
+			"chain.id":     fmt.Sprintf("%d", chainID),
+			"block.number": fmt.Sprintf("%d", blockNumber),
+		},
+	}
+	key := s.badBlockKey()
+	if err := s.store.Store(ctx, key.execute(params), bytes.NewReader(buf.Bytes()), &headers); err != nil {
+		return err
+	}
+
+	return storeIndex(ctx, s.store, key, latestBadBlock, params, report.Header.Time)
+}
+
+func (s *badBlockStore) LoadBadBlockReport(ctx context.Context, chainID, blockNumber uint64) (*evm.BadBlockReport, error) {
+	key := s.badBlockKey()
+	params, err := resolve(ctx, s.store, key, latestBadBlock, chainID, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, _, err := s.store.Load(ctx, key.execute(params))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	report := new(evm.BadBlockReport)
+	if err := json.NewDecoder(reader).Decode(report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -0,0 +1,49 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	memorystore "github.com/kkrt-labs/go-utils/store/memory"
+	"github.com/kkrt-labs/zk-pig/src/ethereum/evm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBadBlockStore(t *testing.T) {
+	ctx := context.TODO()
+	s := memorystore.New()
+	badBlockStore := NewBadBlockStore(s)
+
+	block := gethtypes.NewBlockWithHeader(&gethtypes.Header{Number: big.NewInt(10), Difficulty: big.NewInt(0)})
+	report := evm.NewBadBlockReport(&params.ChainConfig{ChainID: big.NewInt(1)}, block, nil, errors.New("invalid merkle root"))
+	report.Version = "v1.2.3"
+
+	// Reports are stored next to the prover input, keyed by block hash
+	err := badBlockStore.StoreBadBlockReport(ctx, report)
+	require.NoError(t, err)
+	_, _, err = s.Load(ctx, "/1/10/badblock."+block.Hash().Hex()+".json")
+	require.NoError(t, err)
+
+	loaded, err := badBlockStore.LoadBadBlockReport(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, report.ChainID, loaded.ChainID)
+	assert.Equal(t, block.Hash(), loaded.Header.Hash())
+	assert.Equal(t, "invalid merkle root", loaded.Error)
+	assert.Equal(t, "v1.2.3", loaded.Version)
+	assert.Equal(t, evm.GethVersion, loaded.GethVersion)
+
+	// Reports of date-partitioned stores follow the prover input key template
+	badBlockStore = NewBadBlockStore(s, WithProverInputKey(MustParseKeyTemplate("/chain={chainID}/date={date}/{number}/zkpi.{ext}", KeyExt)))
+	require.NoError(t, badBlockStore.StoreBadBlockReport(ctx, report))
+	_, _, err = s.Load(ctx, "/chain=1/date=1970-01-01/10/badblock."+block.Hash().Hex()+".json")
+	require.NoError(t, err)
+
+	loaded, err = badBlockStore.LoadBadBlockReport(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, block.Hash(), loaded.Header.Hash())
+}
@@ -21,16 +21,19 @@ type BlockStore interface {
 	LoadBlock(ctx context.Context, chainID, blockNumber uint64) (*ethrpc.Block, error)
 }
 
-func NewBlockStore(store store.Store) BlockStore {
-	return &blockStore{store: store}
+func NewBlockStore(store store.Store, opts ...Option) BlockStore {
+	return &blockStore{store: store, options: newOptions(opts...)}
 }
 
 type blockStore struct {
 	store store.Store
+	*options
 }
 
 func (s *blockStore) StoreBlock(ctx context.Context, chainID uint64, block *ethrpc.Block) error {
-	path := s.path(chainID, block.Number.ToInt().Uint64())
+	timestamp := uint64(block.Time)
+	params := blockKeyParams(chainID, block.Number.ToInt().Uint64(), block.Hash, timestamp)
+	path := s.blockKey.execute(params)
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(block); err != nil {
 		return fmt.Errorf("failed to encode JSON: %w", err)
@@ -44,13 +47,21 @@ func (s *blockStore) StoreBlock(ctx context.Context, chainID uint64, block *ethr
 			"block.number": block.Number.ToInt().String(),
 		},
 	}
-	return s.store.Store(ctx, path, reader, &headers)
+	if err := s.store.Store(ctx, path, reader, &headers); err != nil {
+		return err
+	}
+
+	return storeIndex(ctx, s.store, s.blockKey, latestBlock, params, timestamp)
 }
 
 func (s *blockStore) LoadBlock(ctx context.Context, chainID, blockNumber uint64) (*ethrpc.Block, error) {
-	path := s.path(chainID, blockNumber)
+	params, err := resolve(ctx, s.store, s.blockKey, latestBlock, chainID, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
 	block := &ethrpc.Block{}
-	reader, _, err := s.store.Load(ctx, path)
+	reader, _, err := s.store.Load(ctx, s.blockKey.execute(params))
 	if err != nil {
 		return nil, err
 	}
@@ -62,10 +73,6 @@ func (s *blockStore) LoadBlock(ctx context.Context, chainID, blockNumber uint64)
 	return block, nil
 }
 
-func (s *blockStore) path(chainID, blockNumber uint64) string {
-	return fmt.Sprintf("/%d/blocks/%d.json", chainID, blockNumber)
-}
-
 type noOpBlockStore struct{}
 
 func NewNoOpBlockStore() BlockStore {
@@ -0,0 +1,305 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	store "github.com/kkrt-labs/go-utils/store"
+	input "github.com/kkrt-labs/zk-pig/src/prover-input"
+	"github.com/kkrt-labs/zk-pig/src/steps"
+)
+
+//go:generate mockgen -destination=./mock/catalog.go -package=mockstore github.com/kkrt-labs/zk-pig/src/store ProverInputCatalog
+
+// ProverInputEntry describes a prover input available in the store
+type ProverInputEntry struct {
+	ChainID         uint64           `json:"chainId"`
+	BlockNumber     uint64           `json:"blockNumber"`
+	BlockHash       *gethcommon.Hash `json:"blockHash,omitempty"` // Unknown for prover inputs stored without catalog metadata
+	ContentType     string           `json:"contentType"`
+	ContentEncoding string           `json:"contentEncoding"`
+	Size            int64            `json:"size"`                   // Size of the stored object (in bytes, after compression)
+	Version         string           `json:"version,omitempty"`      // Version of zk-pig that generated the prover input
+	InputVersion    string           `json:"inputVersion,omitempty"` // Version of the prover input layout
+	Include         string           `json:"include,omitempty"`      // Extensions included in the prover input (see steps.Include)
+}
+
+// ProverInputCatalog lists the prover inputs available in the store
+type ProverInputCatalog interface {
+	// AddProverInput records the metadata of a stored prover input
+	AddProverInput(ctx context.Context, in *input.ProverInput) error
+
+	// ListProverInputs lists the prover inputs of a chain with a block number in [fromBlock, toBlock]
+	// Entries are sorted by block number then content type
+	ListProverInputs(ctx context.Context, chainID, fromBlock, toBlock uint64) ([]*ProverInputEntry, error)
+}
+
+// proverInputMetadata is the metadata of a prover input stored next to it
+type proverInputMetadata struct {
+	BlockHash    gethcommon.Hash `json:"blockHash"`
+	Version      string          `json:"version"`
+	InputVersion string          `json:"inputVersion"`
+	Include      string          `json:"include"`
+}
+
+type proverInputCatalog struct {
+	store   store.Store
+	lister  Lister
+	version string
+	*options
+}
+
+// NewProverInputCatalog creates a catalog of the prover inputs of a store
+// Objects are listed with lister, which must list the objects of the backends of s
+// version is the zk-pig version recorded with the added prover inputs
+// The key template options must match the ones of the prover input store
+func NewProverInputCatalog(s store.Store, lister Lister, version string, opts ...Option) ProverInputCatalog {
+	return &proverInputCatalog{store: s, lister: lister, version: version, options: newOptions(opts...)}
+}
+
+func (c *proverInputCatalog) AddProverInput(ctx context.Context, in *input.ProverInput) error {
+	header := in.Blocks[0].Header
+	params := blockKeyParams(in.ChainConfig.ChainID.Uint64(), header.Number.Uint64(), header.Hash(), header.Time)
+
+	b, err := json.Marshal(&proverInputMetadata{
+		BlockHash:    header.Hash(),
+		Version:      c.version,
+		InputVersion: in.Version,
+		Include:      includeOf(in).String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode prover input metadata: %w", err)
+	}
+
+	headers := &store.Headers{
+		ContentType:     store.ContentTypeJSON,
+		ContentEncoding: store.ContentEncodingPlain,
+		KeyValue: map[string]string{
+			"chain.id":     fmt.Sprintf("%d", params.chainID),
+			"block.number": fmt.Sprintf("%d", params.blockNumber),
+		},
+	}
+
+	return c.store.Store(ctx, c.metadataKey(params), bytes.NewReader(b), headers)
+}
+
+// includeOf returns the extensions included in a prover input
+func includeOf(in *input.ProverInput) steps.Include {
+	include := steps.IncludeNone
+	if in.Extra == nil {
+		return include
+	}
+	if in.Extra.AccessList != nil {
+		include |= steps.IncludeAccessList
+	}
+	if in.Extra.PreState != nil {
+		include |= steps.IncludePreState
+	}
+	if in.Extra.StateDiffs != nil {
+		include |= steps.IncludeStateDiffs
+	}
+	if in.Extra.Committed != nil {
+		include |= steps.IncludeCommitted
+	}
+	if in.Extra.Stats != nil {
+		include |= steps.IncludeStats
+	}
+	return include
+}
+
+func (c *proverInputCatalog) ListProverInputs(ctx context.Context, chainID, fromBlock, toBlock uint64) ([]*ProverInputEntry, error) {
+	// Only list the keys of the blocks in the range (e.g. "/1/12" for blocks 120 to 129)
+	objects, err := listPrefixes(ctx, c.lister, c.proverInputKey.rangePrefixes(chainID, fromBlock, toBlock))
+	if err != nil {
+		return nil, err
+	}
+
+	// Only load the metadata of the prover inputs which have been stored with it
+	hasMetadata := make(map[string]bool)
+	for _, o := range objects {
+		if key, ok := parseMetadataKey(c.proverInputKey, o.Key); ok {
+			hasMetadata[key] = true
+		}
+	}
+
+	entries := make([]*ProverInputEntry, 0)
+	metadata := make(map[string]*proverInputMetadata)
+	for _, o := range objects {
+		entry, params, ok := parseProverInputKey(c.proverInputKey, o.Key)
+		if !ok || entry.ChainID != chainID || entry.BlockNumber < fromBlock || entry.BlockNumber > toBlock {
+			continue
+		}
+		entry.Size = o.Size
+
+		metaKey := c.metadataKey(params)
+		meta, ok := metadata[metaKey]
+		if !ok && hasMetadata[metaKey] {
+			meta, err = c.loadMetadata(ctx, metaKey)
+			if err != nil {
+				return nil, err
+			}
+			metadata[metaKey] = meta
+		}
+		if meta != nil {
+			entry.BlockHash = &meta.BlockHash
+			entry.Version = meta.Version
+			entry.InputVersion = meta.InputVersion
+			entry.Include = meta.Include
+		}
+
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].BlockNumber != entries[j].BlockNumber {
+			return entries[i].BlockNumber < entries[j].BlockNumber
+		}
+		return entries[i].ContentType < entries[j].ContentType
+	})
+
+	return entries, nil
+}
+
+func (c *proverInputCatalog) loadMetadata(ctx context.Context, key string) (*proverInputMetadata, error) {
+	reader, _, err := c.store.Load(ctx, key)
+	if err == nil && reader == nil {
+		err = store.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load prover input metadata: %w", err)
+	}
+	defer reader.Close()
+
+	meta := new(proverInputMetadata)
+	if err := json.NewDecoder(reader).Decode(meta); err != nil {
+		return nil, fmt.Errorf("failed to decode prover input metadata: %w", err)
+	}
+	return meta, nil
+}
+
+// metadataKey returns the key of the metadata of a prover input (e.g. "/1/1234/zkpi.meta.json")
+func (c *proverInputCatalog) metadataKey(params *keyParams) string {
+	p := *params
+	p.ext = metadataExt
+	return c.proverInputKey.execute(&p)
+}
+
+const metadataExt = "meta.json"
+
+var (
+	// proverInputContentTypes maps prover input file extensions to content types
+	proverInputContentTypes = map[string]ContentType{
+		ContentTypeJSON.FileExtension():            ContentTypeJSON,
+		ContentTypeProtobuf.FileExtension():        ContentTypeProtobuf,
+		ContentTypeProtobufChunked.FileExtension(): ContentTypeProtobufChunked,
+		ContentTypeSSZ.FileExtension():             ContentTypeSSZ,
+	}
+
+	// contentEncodings maps file extensions to content encodings
+	contentEncodings = map[string]ContentEncoding{
+		ContentEncodingGzip.FileExtension():  ContentEncodingGzip,
+		ContentEncodingZlib.FileExtension():  ContentEncodingZlib,
+		ContentEncodingFlate.FileExtension(): ContentEncodingFlate,
+		ContentEncodingZstd.FileExtension():  ContentEncodingZstd,
+	}
+)
+
+func isHashHex(s string) bool {
+	b, err := hexutil.Decode(s)
+	return err == nil && len(b) == gethcommon.HashLength
+}
+
+// trimContentEncoding removes the content encoding extension added by the compress store from a key (e.g. "/1/1234/zkpi.json.gz")
+func trimContentEncoding(key string) (string, ContentEncoding) {
+	if ce, ok := contentEncodings[strings.TrimPrefix(path.Ext(key), ".")]; ok {
+		return strings.TrimSuffix(key, path.Ext(key)), ce
+	}
+	return key, ContentEncodingPlain
+}
+
+// parseMetadataKey parses a prover input metadata key (e.g. "/1/1234/zkpi.meta.json.gz") and returns it without content encoding extension
+func parseMetadataKey(t *KeyTemplate, key string) (string, bool) {
+	key, _ = trimContentEncoding(key)
+	params, ok := t.match(key)
+	if !ok || params.ext != metadataExt {
+		return "", false
+	}
+	return key, true
+}
+
+// parseProverInputKey parses a prover input key (e.g. "/1/1234/zkpi.json.gz")
+func parseProverInputKey(t *KeyTemplate, key string) (*ProverInputEntry, *keyParams, bool) {
+	key, encoding := trimContentEncoding(key)
+	params, ok := t.match(key)
+	if !ok {
+		return nil, nil, false
+	}
+
+	contentType, ok := proverInputContentTypes[params.ext]
+	if !ok {
+		return nil, nil, false
+	}
+
+	return &ProverInputEntry{
+		ChainID:         params.chainID,
+		BlockNumber:     params.blockNumber,
+		BlockHash:       params.blockHash,
+		ContentType:     contentType.String(),
+		ContentEncoding: encoding.String(),
+	}, params, true
+}
+
+type proverInputStoreWithCatalog struct {
+	s       ProverInputStore
+	catalog ProverInputCatalog
+}
+
+// ProverInputStoreWithCatalog records the metadata of every stored prover input in the catalog
+func ProverInputStoreWithCatalog(s ProverInputStore, catalog ProverInputCatalog) ProverInputStore {
+	return &proverInputStoreWithCatalog{s: s, catalog: catalog}
+}
+
+func (s *proverInputStoreWithCatalog) StoreProverInput(ctx context.Context, in *input.ProverInput) error {
+	if err := s.s.StoreProverInput(ctx, in); err != nil {
+		return err
+	}
+
+	if err := s.catalog.AddProverInput(ctx, in); err != nil {
+		return fmt.Errorf("failed to add prover input to catalog: %w", err)
+	}
+
+	return nil
+}
+
+func (s *proverInputStoreWithCatalog) LoadProverInput(ctx context.Context, chainID, blockNumber uint64) (*input.ProverInput, error) {
+	return s.s.LoadProverInput(ctx, chainID, blockNumber)
+}
+
+func (s *proverInputStoreWithCatalog) LoadProverInputByHash(ctx context.Context, chainID, blockNumber uint64, blockHash gethcommon.Hash) (*input.ProverInput, error) {
+	return s.s.LoadProverInputByHash(ctx, chainID, blockNumber, blockHash)
+}
+
+func (s *proverInputStoreWithCatalog) MigrateProverInput(ctx context.Context, chainID, blockNumber uint64) (string, error) {
+	from, err := s.s.MigrateProverInput(ctx, chainID, blockNumber)
+	if err != nil || from == input.CurrentVersion {
+		return from, err
+	}
+
+	// The catalog records the prover input version, so it is updated with the migrated prover input
+	in, err := s.s.LoadProverInput(ctx, chainID, blockNumber)
+	if err != nil {
+		return from, err
+	}
+	if err := s.catalog.AddProverInput(ctx, in); err != nil {
+		return from, fmt.Errorf("failed to add prover input to catalog: %w", err)
+	}
+
+	return from, nil
+}
@@ -0,0 +1,160 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	filestore "github.com/kkrt-labs/go-utils/store/file"
+	input "github.com/kkrt-labs/zk-pig/src/prover-input"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testCatalogInput(blockNumber int64, extra *input.Extra) *input.ProverInput {
+	return &input.ProverInput{
+		Version:     input.CurrentVersion,
+		ChainConfig: &params.ChainConfig{ChainID: big.NewInt(1)},
+		Blocks:      []*input.Block{{Header: &gethtypes.Header{Number: big.NewInt(blockNumber)}}},
+		Witness:     &input.Witness{},
+		Extra:       extra,
+	}
+}
+
+func TestProverInputCatalog(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewCompressStore(filestore.New(dir), ContentEncodingGzip)
+	require.NoError(t, err)
+
+	catalog := NewProverInputCatalog(s, NewFileLister(dir), "v0.0.1")
+	inputStore := ProverInputStoreWithCatalog(NewProverInputStore(s, ContentTypeJSON), catalog)
+
+	in1 := testCatalogInput(1, &input.Extra{AccessList: gethtypes.AccessList{}})
+	in2 := testCatalogInput(2, nil)
+	in3 := testCatalogInput(3, nil)
+	require.NoError(t, inputStore.StoreProverInput(context.TODO(), in1))
+	require.NoError(t, inputStore.StoreProverInput(context.TODO(), in2))
+	require.NoError(t, inputStore.StoreProverInput(context.TODO(), in3))
+
+	// Prover input stored without catalog metadata
+	require.NoError(t, NewProverInputStore(s, ContentTypeSSZ).StoreProverInput(context.TODO(), testCatalogInput(4, nil)))
+
+	entries, err := catalog.ListProverInputs(context.TODO(), 1, 2, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	assert.Equal(t, uint64(2), entries[0].BlockNumber)
+	assert.Equal(t, in2.Blocks[0].Header.Hash(), *entries[0].BlockHash)
+	assert.Equal(t, "application/json", entries[0].ContentType)
+	assert.Equal(t, "gzip", entries[0].ContentEncoding)
+	assert.Positive(t, entries[0].Size)
+	assert.Equal(t, "v0.0.1", entries[0].Version)
+	assert.Equal(t, input.CurrentVersion, entries[0].InputVersion)
+	assert.Equal(t, "none", entries[0].Include)
+
+	assert.Equal(t, uint64(3), entries[1].BlockNumber)
+
+	assert.Equal(t, uint64(4), entries[2].BlockNumber)
+	assert.Equal(t, "application/ssz", entries[2].ContentType)
+	assert.Nil(t, entries[2].BlockHash)
+	assert.Empty(t, entries[2].Version)
+
+	entries, err = catalog.ListProverInputs(context.TODO(), 1, 1, 1)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "accessList", entries[0].Include)
+
+	entries, err = catalog.ListProverInputs(context.TODO(), 2, 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+// recordingLister records the listed prefixes
+type recordingLister struct {
+	Lister
+	prefixes []string
+}
+
+func (l *recordingLister) List(ctx context.Context, prefix string) ([]*Object, error) {
+	l.prefixes = append(l.prefixes, prefix)
+	return l.Lister.List(ctx, prefix)
+}
+
+func TestProverInputCatalogListsRange(t *testing.T) {
+	dir := t.TempDir()
+	s := filestore.New(dir)
+
+	lister := &recordingLister{Lister: NewFileLister(dir)}
+	catalog := NewProverInputCatalog(s, lister, "v0.0.1")
+	inputStore := ProverInputStoreWithCatalog(NewProverInputStore(s, ContentTypeJSON), catalog)
+	for _, n := range []int64{9, 10, 11, 19, 20, 100} {
+		require.NoError(t, inputStore.StoreProverInput(context.TODO(), testCatalogInput(n, nil)))
+	}
+
+	entries, err := catalog.ListProverInputs(context.TODO(), 1, 10, 19)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/1/1"}, lister.prefixes)
+	require.Len(t, entries, 3)
+	assert.Equal(t, uint64(10), entries[0].BlockNumber)
+	assert.Equal(t, uint64(11), entries[1].BlockNumber)
+	assert.Equal(t, uint64(19), entries[2].BlockNumber)
+	assert.Equal(t, "v0.0.1", entries[2].Version)
+}
+
+func TestParseProverInputKey(t *testing.T) {
+	entry, _, ok := parseProverInputKey(DefaultProverInputKey, "/1/1234/zkpi.protobuf.zlib")
+	require.True(t, ok)
+	assert.Equal(t, &ProverInputEntry{ChainID: 1, BlockNumber: 1234, ContentType: "application/protobuf", ContentEncoding: "zlib"}, entry)
+
+	entry, _, ok = parseProverInputKey(DefaultProverInputKey, "/1/1234/zkpi.chunked.protobuf.zst")
+	require.True(t, ok)
+	assert.Equal(t, &ProverInputEntry{ChainID: 1, BlockNumber: 1234, ContentType: "application/protobuf-chunked", ContentEncoding: "zstd"}, entry)
+
+	hash := gethcommon.HexToHash("0x1234")
+	entry, params, ok := parseProverInputKey(hashProverInputKey, fmt.Sprintf("/1/1234/%s/zkpi.json.gz", hash.Hex()))
+	require.True(t, ok)
+	assert.Equal(t, &ProverInputEntry{ChainID: 1, BlockNumber: 1234, BlockHash: &hash, ContentType: "application/json", ContentEncoding: "gzip"}, entry)
+	assert.Equal(t, &hash, params.blockHash)
+
+	for _, key := range []string{"/1/1234/zkpi.meta.json", "/1/1234/trace.json", "/1/1234/zkpi", "/1/latest/zkpi.json", "/1/zkpi.json", "/1/1234/0x12/zkpi.json", "/1/1234/latest", "/1/1234/latest.zkpi", "/1/1234/zkpi.json.manifest.json", "/1/1234/zkpi.json.refs.json"} {
+		_, _, ok := parseProverInputKey(DefaultProverInputKey, key)
+		assert.False(t, ok, key)
+		_, _, ok = parseProverInputKey(hashProverInputKey, key)
+		assert.False(t, ok, key)
+	}
+
+	key, ok := parseMetadataKey(DefaultProverInputKey, "/1/1234/zkpi.meta.json.gz")
+	require.True(t, ok)
+	assert.Equal(t, "/1/1234/zkpi.meta.json", key)
+	_, ok = parseMetadataKey(DefaultProverInputKey, "/1/1234/zkpi.json")
+	assert.False(t, ok)
+}
+
+func TestProverInputCatalogHashLayout(t *testing.T) {
+	dir := t.TempDir()
+	s := filestore.New(dir)
+
+	catalog := NewProverInputCatalog(s, NewFileLister(dir), "v0.0.1", WithLayout(LayoutHash))
+	inputStore := ProverInputStoreWithCatalog(NewProverInputStore(s, ContentTypeJSON, WithLayout(LayoutHash)), catalog)
+
+	// Two competing blocks at the same height
+	in1 := testCatalogInput(1, nil)
+	in2 := testCatalogInput(1, nil)
+	in2.Blocks[0].Header.Extra = []byte("reorg")
+	require.NoError(t, inputStore.StoreProverInput(context.TODO(), in1))
+	require.NoError(t, inputStore.StoreProverInput(context.TODO(), in2))
+
+	entries, err := catalog.ListProverInputs(context.TODO(), 1, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	hashes := []gethcommon.Hash{*entries[0].BlockHash, *entries[1].BlockHash}
+	assert.ElementsMatch(t, []gethcommon.Hash{in1.Blocks[0].Header.Hash(), in2.Blocks[0].Header.Hash()}, hashes)
+	for _, e := range entries {
+		assert.Equal(t, "v0.0.1", e.Version)
+	}
+}
@@ -0,0 +1,344 @@
+package store
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
+
+	store "github.com/kkrt-labs/go-utils/store"
+	"github.com/klauspost/compress/dict"
+	"github.com/klauspost/compress/zstd"
+	"go.uber.org/multierr"
+)
+
+// ContentEncoding is the content encoding of stored artifacts
+//
+// go-utils store only knows about gzip, zlib and flate content encodings, so content encodings are carried
+// in their own type supporting zstd on top of them, and handled by NewCompressStore.
+type ContentEncoding int
+
+const (
+	ContentEncodingPlain ContentEncoding = iota
+	ContentEncodingGzip
+	ContentEncodingZlib
+	ContentEncodingFlate
+	// ContentEncodingZstd is the content encoding for Zstandard compressed objects.
+	ContentEncodingZstd
+)
+
+var contentEncodingStrings = [...]string{
+	"plain",
+	"gzip",
+	"zlib",
+	"flate",
+	"zstd",
+}
+
+var contentEncodingFileExtensions = [...]string{
+	"",
+	"gz",
+	"zlib",
+	"flate",
+	"zst",
+}
+
+// ParseContentEncoding parses a content encoding (e.g. "gzip")
+func ParseContentEncoding(contentEncoding string) (ContentEncoding, error) {
+	for ce, s := range contentEncodingStrings {
+		if s == contentEncoding {
+			return ContentEncoding(ce), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid content encoding: %q", contentEncoding)
+}
+
+// String returns the name of the content encoding (e.g. "gzip")
+func (ce ContentEncoding) String() string {
+	if ce < 0 || int(ce) >= len(contentEncodingStrings) {
+		return "unknown"
+	}
+	return contentEncodingStrings[ce]
+}
+
+// FileExtension returns the file extension of the content encoding (e.g. "gz"), empty for plain
+func (ce ContentEncoding) FileExtension() string {
+	if ce < 0 || int(ce) >= len(contentEncodingFileExtensions) {
+		return ""
+	}
+	return contentEncodingFileExtensions[ce]
+}
+
+// FilePath returns the file path for a key with the file extension of the content encoding
+func (ce ContentEncoding) FilePath(key string) string {
+	if ext := ce.FileExtension(); ext != "" {
+		return fmt.Sprintf("%s.%s", key, ext)
+	}
+	return key
+}
+
+// storeContentEncoding returns the go-utils content encoding of gzip, zlib and flate
+func (ce ContentEncoding) storeContentEncoding() store.ContentEncoding {
+	switch ce {
+	case ContentEncodingGzip:
+		return store.ContentEncodingGzip
+	case ContentEncodingZlib:
+		return store.ContentEncodingZlib
+	case ContentEncodingFlate:
+		return store.ContentEncodingFlate
+	default:
+		return store.ContentEncodingPlain
+	}
+}
+
+// contentEncodingHeader is the key-value header carrying the zstd content encoding, which go-utils headers can not carry
+const contentEncodingHeader = "content.encoding"
+
+type compressOptions struct {
+	zstdLevel        int
+	zstdDictionary   []byte
+	zstdDictionaries [][]byte
+}
+
+// CompressOption is an option of NewCompressStore
+type CompressOption func(*compressOptions)
+
+// WithZstdLevel sets the zstd compression level from 1 (fastest) to 22 (best compression)
+func WithZstdLevel(level int) CompressOption {
+	return func(o *compressOptions) {
+		o.zstdLevel = level
+	}
+}
+
+// WithZstdDictionary compresses objects with a zstd dictionary (see TrainZstdDictionary)
+func WithZstdDictionary(d []byte) CompressOption {
+	return func(o *compressOptions) {
+		o.zstdDictionary = d
+	}
+}
+
+// WithZstdDecoderDictionaries decompresses objects compressed with previous zstd dictionaries
+func WithZstdDecoderDictionaries(dicts ...[]byte) CompressOption {
+	return func(o *compressOptions) {
+		o.zstdDictionaries = append(o.zstdDictionaries, dicts...)
+	}
+}
+
+// NewCompressStore returns a store compressing objects with the given content encoding
+// Objects are compressed while the underlying store reads them, so payloads are never buffered in full.
+func NewCompressStore(s store.Store, contentEncoding ContentEncoding, opts ...CompressOption) (store.Store, error) {
+	cs := &compressStore{store: s, contentEncoding: contentEncoding}
+	switch contentEncoding {
+	case ContentEncodingPlain:
+		return s, nil
+	case ContentEncodingGzip:
+		cs.newWriter = func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil }
+		cs.newReader = func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) }
+		return cs, nil
+	case ContentEncodingZlib:
+		cs.newWriter = func(w io.Writer) (io.WriteCloser, error) { return zlib.NewWriter(w), nil }
+		cs.newReader = zlib.NewReader
+		return cs, nil
+	case ContentEncodingFlate:
+		cs.newWriter = func(w io.Writer) (io.WriteCloser, error) { return flate.NewWriter(w, flate.BestCompression) }
+		cs.newReader = func(r io.Reader) (io.ReadCloser, error) { return flate.NewReader(r), nil }
+		return cs, nil
+	case ContentEncodingZstd:
+	default:
+		return nil, fmt.Errorf("unsupported content encoding: %s", contentEncoding)
+	}
+
+	o := &compressOptions{zstdLevel: 3}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	encoderOpts := []zstd.EOption{
+		zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(o.zstdLevel)),
+		zstd.WithEncoderConcurrency(1),
+	}
+	decoderOpts := []zstd.DOption{zstd.WithDecoderConcurrency(1)}
+
+	dicts := o.zstdDictionaries
+	if o.zstdDictionary != nil {
+		encoderOpts = append(encoderOpts, zstd.WithEncoderDict(o.zstdDictionary))
+		dicts = append(dicts, o.zstdDictionary)
+	}
+
+	for _, d := range dicts {
+		if _, err := ZstdDictionaryID(d); err != nil {
+			return nil, err
+		}
+	}
+	if len(dicts) > 0 {
+		decoderOpts = append(decoderOpts, zstd.WithDecoderDicts(dicts...))
+	}
+
+	cs.newWriter = func(w io.Writer) (io.WriteCloser, error) { return zstd.NewWriter(w, encoderOpts...) }
+	cs.newReader = func(r io.Reader) (io.ReadCloser, error) {
+		dec, err := zstd.NewReader(r, decoderOpts...)
+		if err != nil {
+			return nil, err
+		}
+		return dec.IOReadCloser(), nil
+	}
+
+	return cs, nil
+}
+
+// ZstdDictionaryID returns the ID of a zstd dictionary, which identifies the version of the dictionary in the frames of the objects compressed with it
+func ZstdDictionaryID(d []byte) (uint32, error) {
+	info, err := zstd.InspectDictionary(d)
+	if err != nil {
+		return 0, fmt.Errorf("invalid zstd dictionary: %w", err)
+	}
+	return info.ID(), nil
+}
+
+// zstdDictionarySampleSize is the size of the chunks of prior payloads used as samples to train a zstd dictionary
+const zstdDictionarySampleSize = 128 * 1024
+
+// TrainZstdDictionary trains a zstd dictionary of up to maxSize bytes on prior payloads (e.g. encoded prover inputs)
+// Payloads are split in chunks so repeated trie nodes and contract bytecodes are found across blocks.
+// id is the ID of the dictionary (a random ID is generated if 0).
+func TrainZstdDictionary(payloads [][]byte, id uint32, maxSize int) (d []byte, err error) {
+	// The dictionary builder panics on samples without repeated content
+	defer func() {
+		if r := recover(); r != nil {
+			d, err = nil, fmt.Errorf("failed to train zstd dictionary: not enough repeated content in samples (%v)", r)
+		}
+	}()
+
+	var samples [][]byte
+	for _, payload := range payloads {
+		for len(payload) > zstdDictionarySampleSize {
+			samples = append(samples, payload[:zstdDictionarySampleSize])
+			payload = payload[zstdDictionarySampleSize:]
+		}
+		if len(payload) > 0 {
+			samples = append(samples, payload)
+		}
+	}
+
+	d, err = dict.BuildZstdDict(samples, dict.Options{
+		MaxDictSize: maxSize,
+		HashBytes:   6,
+		ZstdDictID:  id,
+		ZstdLevel:   zstd.SpeedBestCompression,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to train zstd dictionary: %w", err)
+	}
+
+	return d, nil
+}
+
+type compressStore struct {
+	store           store.Store
+	contentEncoding ContentEncoding
+	newWriter       func(w io.Writer) (io.WriteCloser, error)
+	newReader       func(r io.Reader) (io.ReadCloser, error)
+}
+
+// Store compresses the object while the underlying store reads it
+// The compressed stream is closed once the object is read, so it always ends with the trailer of the content encoding.
+func (s *compressStore) Store(ctx context.Context, key string, reader io.Reader, headers *store.Headers) error {
+	h := &store.Headers{KeyValue: make(map[string]string)}
+	if headers != nil {
+		h.ContentType = headers.ContentType
+		for k, v := range headers.KeyValue {
+			h.KeyValue[k] = v
+		}
+	}
+	h.ContentEncoding = s.contentEncoding.storeContentEncoding()
+	if s.contentEncoding == ContentEncodingZstd {
+		h.KeyValue[contentEncodingHeader] = ContentEncodingZstd.String()
+	}
+
+	pr, pw := io.Pipe()
+	compressErr := make(chan error, 1)
+	go func() {
+		err := s.compress(pw, reader)
+		pw.CloseWithError(err)
+		compressErr <- err
+	}()
+
+	if err := s.store.Store(ctx, s.key(key), pr, h); err != nil {
+		pr.CloseWithError(err)
+		<-compressErr
+		return err
+	}
+
+	// Drain the object left unread by the store (e.g. no-op store)
+	_, _ = io.Copy(io.Discard, pr)
+	return <-compressErr
+}
+
+func (s *compressStore) compress(w io.Writer, reader io.Reader) error {
+	enc, err := s.newWriter(w)
+	if err != nil {
+		return fmt.Errorf("failed to create %s writer: %w", s.contentEncoding, err)
+	}
+
+	if _, err := io.Copy(enc, reader); err != nil {
+		enc.Close()
+		return fmt.Errorf("failed to compress with %s: %w", s.contentEncoding, err)
+	}
+
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to compress with %s: %w", s.contentEncoding, err)
+	}
+
+	return nil
+}
+
+// Load decompresses the object as it is read
+// Truncated objects fail to read with io.ErrUnexpectedEOF.
+// Objects compressed with a zstd dictionary are decompressed with the dictionary of the ID in their frames,
+// not with the headers which file and key-value stores do not persist. Frames of an unknown dictionary fail to read with zstd.ErrUnknownDictionary.
+func (s *compressStore) Load(ctx context.Context, key string) (io.ReadCloser, *store.Headers, error) {
+	reader, headers, err := s.store.Load(ctx, s.key(key))
+	if err != nil {
+		return nil, nil, err
+	}
+	if reader == nil {
+		return nil, nil, store.ErrNotFound
+	}
+
+	if headers == nil {
+		headers = &store.Headers{}
+	}
+	headers.ContentEncoding = s.contentEncoding.storeContentEncoding()
+
+	dec, err := s.newReader(reader)
+	if err != nil {
+		reader.Close()
+		return nil, nil, fmt.Errorf("failed to decompress with %s: %w", s.contentEncoding, err)
+	}
+
+	return &decompressReadCloser{ReadCloser: dec, reader: reader}, headers, nil
+}
+
+func (s *compressStore) Delete(ctx context.Context, key string) error {
+	return s.store.Delete(ctx, s.key(key))
+}
+
+func (s *compressStore) Copy(ctx context.Context, srcKey, dstKey string) error {
+	return s.store.Copy(ctx, s.key(srcKey), s.key(dstKey))
+}
+
+func (s *compressStore) key(key string) string {
+	return s.contentEncoding.FilePath(key)
+}
+
+// decompressReadCloser closes both the decompressor and the underlying object
+type decompressReadCloser struct {
+	io.ReadCloser
+	reader io.Closer
+}
+
+func (r *decompressReadCloser) Close() error {
+	return multierr.Combine(r.ReadCloser.Close(), r.reader.Close())
+}
@@ -0,0 +1,195 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/kkrt-labs/go-utils/store"
+	filestore "github.com/kkrt-labs/go-utils/store/file"
+	memorystore "github.com/kkrt-labs/go-utils/store/memory"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseContentEncoding(t *testing.T) {
+	for _, ce := range []ContentEncoding{ContentEncodingPlain, ContentEncodingGzip, ContentEncodingZlib, ContentEncodingFlate, ContentEncodingZstd} {
+		parsed, err := ParseContentEncoding(ce.String())
+		require.NoError(t, err)
+		assert.Equal(t, ce, parsed)
+	}
+
+	_, err := ParseContentEncoding("unknown")
+	assert.Error(t, err)
+}
+
+func TestCompressStore(t *testing.T) {
+	for _, ce := range []ContentEncoding{ContentEncodingGzip, ContentEncodingZlib, ContentEncodingFlate, ContentEncodingZstd} {
+		t.Run(ce.String(), func(t *testing.T) {
+			s := memorystore.New()
+			cs, err := NewCompressStore(s, ce)
+			require.NoError(t, err)
+
+			data := bytes.Repeat([]byte("zkpig"), 1000)
+			require.NoError(t, cs.Store(context.TODO(), "/1/10/zkpi.json", bytes.NewReader(data), &store.Headers{ContentType: store.ContentTypeJSON}))
+
+			reader, headers, err := cs.Load(context.TODO(), "/1/10/zkpi.json")
+			require.NoError(t, err)
+			loaded, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			require.NoError(t, reader.Close())
+			assert.Equal(t, data, loaded)
+			assert.Equal(t, ce.storeContentEncoding(), headers.ContentEncoding)
+
+			// Objects end with the trailer of the content encoding, so truncated objects are detected
+			reader, _, err = s.Load(context.TODO(), ce.FilePath("/1/10/zkpi.json"))
+			require.NoError(t, err)
+			compressed, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			require.NoError(t, s.Store(context.TODO(), ce.FilePath("/1/11/zkpi.json"), bytes.NewReader(compressed[:len(compressed)-4]), nil))
+
+			reader, _, err = cs.Load(context.TODO(), "/1/11/zkpi.json")
+			require.NoError(t, err)
+			defer reader.Close()
+			_, err = io.ReadAll(reader)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestZstdStore(t *testing.T) {
+	s := memorystore.New()
+	zs, err := NewCompressStore(s, ContentEncodingZstd, WithZstdLevel(19))
+	require.NoError(t, err)
+
+	data := bytes.Repeat([]byte("zkpig"), 1000)
+	require.NoError(t, zs.Store(context.TODO(), "/1/10/zkpi.json", bytes.NewReader(data), &store.Headers{ContentType: store.ContentTypeJSON}))
+
+	// Objects are stored compressed with the zstd file extension
+	reader, _, err := s.Load(context.TODO(), "/1/10/zkpi.json.zst")
+	require.NoError(t, err)
+	compressed, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Less(t, len(compressed), len(data))
+
+	reader, _, err = zs.Load(context.TODO(), "/1/10/zkpi.json")
+	require.NoError(t, err)
+	defer reader.Close()
+	loaded, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, data, loaded)
+
+	require.NoError(t, zs.Copy(context.TODO(), "/1/10/zkpi.json", "/1/11/zkpi.json"))
+	require.NoError(t, zs.Delete(context.TODO(), "/1/10/zkpi.json"))
+	_, _, err = zs.Load(context.TODO(), "/1/10/zkpi.json")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+	_, _, err = s.Load(context.TODO(), "/1/11/zkpi.json.zst")
+	assert.NoError(t, err)
+}
+
+// testDictionaryPayloads returns payloads sharing bytecode and trie nodes as prover inputs of successive blocks do
+func testDictionaryPayloads() [][]byte {
+	code := bytes.Repeat([]byte{0x60, 0x80, 0x60, 0x40, 0x52, 0x34, 0x80, 0x15}, 64)
+	var payloads [][]byte
+	for i := 0; i < 64; i++ {
+		payloads = append(payloads, []byte(fmt.Sprintf(`{"blockNumber":%d,"codes":["%x"],"state":["%x","%x"]}`, i, code, bytes.Repeat([]byte{byte(i)}, 32), code[:128+i])))
+	}
+	return payloads
+}
+
+func TestZstdStoreDictionary(t *testing.T) {
+	payloads := testDictionaryPayloads()
+	dict, err := TrainZstdDictionary(payloads, 1, 4096)
+	require.NoError(t, err)
+	id, err := ZstdDictionaryID(dict)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), id)
+
+	newDict, err := TrainZstdDictionary(payloads, 2, 4096)
+	require.NoError(t, err)
+
+	s := memorystore.New()
+	zs, err := NewCompressStore(s, ContentEncodingZstd, WithZstdDictionary(dict))
+	require.NoError(t, err)
+	require.NoError(t, zs.Store(context.TODO(), "/1/10/zkpi.json", bytes.NewReader(payloads[10]), nil))
+
+	// Objects compressed with a dictionary are smaller than without it
+	plain, err := NewCompressStore(memorystore.New(), ContentEncodingZstd)
+	require.NoError(t, err)
+	require.NoError(t, plain.Store(context.TODO(), "/1/10/zkpi.json", bytes.NewReader(payloads[10]), nil))
+	assert.Less(t, zstdObjectSize(t, s), zstdObjectSize(t, plain.(*compressStore).store))
+
+	// After a dictionary update, objects compressed with the previous dictionary are loaded with the decoder dictionaries
+	for desc, opts := range map[string][]CompressOption{
+		"current":  {WithZstdDictionary(dict)},
+		"previous": {WithZstdDictionary(newDict), WithZstdDecoderDictionaries(dict)},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			zs, err := NewCompressStore(s, ContentEncodingZstd, opts...)
+			require.NoError(t, err)
+			reader, _, err := zs.Load(context.TODO(), "/1/10/zkpi.json")
+			require.NoError(t, err)
+			defer reader.Close()
+			loaded, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, payloads[10], loaded)
+		})
+	}
+
+	// Objects compressed with an unknown dictionary fail to load
+	zs, err = NewCompressStore(s, ContentEncodingZstd, WithZstdDictionary(newDict))
+	require.NoError(t, err)
+	reader, _, err := zs.Load(context.TODO(), "/1/10/zkpi.json")
+	require.NoError(t, err)
+	defer reader.Close()
+	_, err = io.ReadAll(reader)
+	assert.Error(t, err)
+
+	_, err = NewCompressStore(s, ContentEncodingZstd, WithZstdDictionary([]byte("not a dictionary")))
+	assert.Error(t, err)
+}
+
+func TestZstdStoreDictionaryFrames(t *testing.T) {
+	dict, err := TrainZstdDictionary(testDictionaryPayloads(), 7, 4096)
+	require.NoError(t, err)
+
+	// File stores do not persist key-value headers, the dictionary is selected by the ID in the zstd frames
+	s := filestore.New(t.TempDir())
+	zs, err := NewCompressStore(s, ContentEncodingZstd, WithZstdDictionary(dict))
+	require.NoError(t, err)
+	payload := testDictionaryPayloads()[0]
+	require.NoError(t, zs.Store(context.TODO(), "/1/10/zkpi.json", bytes.NewReader(payload), &store.Headers{ContentType: store.ContentTypeJSON}))
+
+	decoder, err := NewCompressStore(s, ContentEncodingZstd, WithZstdDecoderDictionaries(dict))
+	require.NoError(t, err)
+	reader, _, err := decoder.Load(context.TODO(), "/1/10/zkpi.json")
+	require.NoError(t, err)
+	b, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	assert.Equal(t, payload, b)
+
+	plain, err := NewCompressStore(s, ContentEncodingZstd)
+	require.NoError(t, err)
+	reader, _, err = plain.Load(context.TODO(), "/1/10/zkpi.json")
+	require.NoError(t, err)
+	defer reader.Close()
+	_, err = io.ReadAll(reader)
+	assert.ErrorIs(t, err, zstd.ErrUnknownDictionary)
+}
+
+func TestTrainZstdDictionaryNotEnoughSamples(t *testing.T) {
+	_, err := TrainZstdDictionary([][]byte{[]byte("zkpig")}, 1, 1024)
+	assert.Error(t, err)
+}
+
+func zstdObjectSize(t *testing.T, s store.Store) int {
+	reader, _, err := s.Load(context.TODO(), "/1/10/zkpi.json.zst")
+	require.NoError(t, err)
+	b, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return len(b)
+}
@@ -0,0 +1,86 @@
+package store
+
+import (
+	"fmt"
+
+	store "github.com/kkrt-labs/go-utils/store"
+)
+
+// ContentType is the content type of stored prover inputs
+//
+// go-utils store only knows about text, JSON and protobuf content types, so prover input content types are carried
+// in their own type and mapped to go-utils content types in the headers of stored objects (see StoreContentType).
+type ContentType int
+
+const (
+	ContentTypeJSON ContentType = iota
+	ContentTypeProtobuf
+	// ContentTypeProtobufChunked is the content type for prover inputs encoded as a stream of length-delimited protobuf chunks.
+	//
+	// Unlike ContentTypeProtobuf, chunked prover inputs are encoded and decoded chunk by chunk (see EncodeProverInputTo).
+	ContentTypeProtobufChunked
+	// ContentTypeSSZ is the content type for SSZ encoded prover inputs.
+	ContentTypeSSZ
+)
+
+var contentTypeStrings = [...]string{
+	"application/json",
+	"application/protobuf",
+	"application/protobuf-chunked",
+	"application/ssz",
+}
+
+var contentTypeFileExtensions = [...]string{
+	"json",
+	"protobuf",
+	"chunked.protobuf",
+	"ssz",
+}
+
+// ParseContentType parses the MIME type of a prover input content type (e.g. "application/json")
+func ParseContentType(contentType string) (ContentType, error) {
+	for ct, s := range contentTypeStrings {
+		if s == contentType {
+			return ContentType(ct), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid content type: %q", contentType)
+}
+
+// String returns the MIME type of the content type (e.g. "application/json")
+func (ct ContentType) String() string {
+	if ct < 0 || int(ct) >= len(contentTypeStrings) {
+		return "unknown"
+	}
+	return contentTypeStrings[ct]
+}
+
+// FileExtension returns the file extension of the content type (e.g. "json")
+func (ct ContentType) FileExtension() string {
+	if ct < 0 || int(ct) >= len(contentTypeFileExtensions) {
+		return ""
+	}
+	return contentTypeFileExtensions[ct]
+}
+
+// FilePath returns the file path for a key with the file extension of the content type
+func (ct ContentType) FilePath(key string) string {
+	return fmt.Sprintf("%s.%s", key, ct.FileExtension())
+}
+
+// StoreContentType returns the go-utils content type set in the headers of stored objects
+// Content types unknown to go-utils are stored as text, for which no content type is set on S3 objects
+// (their MIME type is set in the "content.type" key-value header instead)
+func (ct ContentType) StoreContentType() store.ContentType {
+	switch ct {
+	case ContentTypeJSON:
+		return store.ContentTypeJSON
+	case ContentTypeProtobuf:
+		return store.ContentTypeProtobuf
+	default:
+		return store.ContentTypeText
+	}
+}
+
+// contentTypeHeader is the key-value header holding the MIME type of stored prover inputs
+const contentTypeHeader = "content.type"
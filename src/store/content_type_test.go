@@ -0,0 +1,29 @@
+package store
+
+import (
+	"testing"
+
+	store "github.com/kkrt-labs/go-utils/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseContentType(t *testing.T) {
+	for _, ct := range []ContentType{ContentTypeJSON, ContentTypeProtobuf, ContentTypeProtobufChunked, ContentTypeSSZ} {
+		parsed, err := ParseContentType(ct.String())
+		require.NoError(t, err)
+		assert.Equal(t, ct, parsed)
+	}
+
+	_, err := ParseContentType("unknown")
+	assert.Error(t, err)
+}
+
+func TestStoreContentType(t *testing.T) {
+	assert.Equal(t, store.ContentTypeJSON, ContentTypeJSON.StoreContentType())
+	assert.Equal(t, store.ContentTypeProtobuf, ContentTypeProtobuf.StoreContentType())
+
+	// Content types unknown to go-utils are stored as text so no content type is set on S3 objects
+	assert.Equal(t, store.ContentTypeText, ContentTypeProtobufChunked.StoreContentType())
+	assert.Equal(t, store.ContentTypeText, ContentTypeSSZ.StoreContentType())
+}
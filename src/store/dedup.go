@@ -0,0 +1,279 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/crypto"
+	store "github.com/kkrt-labs/go-utils/store"
+	input "github.com/kkrt-labs/zk-pig/src/prover-input"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// dedupCacheSize is the number of blob hashes remembered as already stored, so blobs shared by consecutive blocks are stored once per process
+	dedupCacheSize = 1 << 18
+
+	// dedupConcurrency is the maximum number of blobs stored or loaded concurrently
+	dedupConcurrency = 16
+)
+
+// BlobCacheTTL is the duration after which a blob stored by the process is stored again when a prover input references it
+// Storing the blob again refreshes its modification time, so blobs referenced by a prover input being stored are
+// protected from the garbage collectors of other processes by a blob grace period longer than the TTL (see RetentionPolicy.KeepBlobsNewerThan).
+const BlobCacheTTL = time.Hour
+
+// BlobCache remembers the hashes of the blobs stored by the process, so blobs shared by consecutive blocks are stored once per process
+//
+// The garbage collector forgets the blobs it deletes and holds the cache while sweeping blobs, so prover inputs
+// are never stored referencing a blob being deleted by the process. It must be shared by the stores of a process (see WithBlobCache).
+// Blobs stored more than BlobCacheTTL ago are stored again, as the garbage collector of another process may have deleted them.
+type BlobCache struct {
+	mu     sync.RWMutex // Read-locked while a prover input is deduplicated, locked while blobs are swept
+	stored *lru.Cache[gethcommon.Hash, time.Time]
+	now    func() time.Time
+}
+
+// NewBlobCache creates an empty blob cache
+func NewBlobCache() *BlobCache {
+	return &BlobCache{stored: lru.NewCache[gethcommon.Hash, time.Time](dedupCacheSize), now: time.Now}
+}
+
+// contains returns true if the blob has been stored by the process less than BlobCacheTTL ago
+func (c *BlobCache) contains(hash gethcommon.Hash) bool {
+	storedAt, ok := c.stored.Get(hash)
+	return ok && c.now().Sub(storedAt) < BlobCacheTTL
+}
+
+func (c *BlobCache) add(hash gethcommon.Hash) {
+	c.stored.Add(hash, c.now())
+}
+
+func (c *BlobCache) remove(hash gethcommon.Hash) {
+	c.stored.Remove(hash)
+}
+
+// WithBlobCache sets the cache of the blobs stored by the process (defaults to a cache per store)
+func WithBlobCache(cache *BlobCache) Option {
+	return func(o *options) {
+		o.blobs = cache
+	}
+}
+
+// WithDedup stores the witness state nodes and codes of prover inputs once, content-addressed by keccak hash (e.g. "/1/blobs/0x1234...")
+// The prover input is stored without them, next to a references object listing their hashes (e.g. "/1/1234/zkpi.json.refs.json")
+func WithDedup() Option {
+	return func(o *options) {
+		o.dedup = true
+	}
+}
+
+// witnessRefs references the witness state nodes and codes of a deduplicated prover input, in order
+type witnessRefs struct {
+	State []gethcommon.Hash `json:"state"`
+	Codes []gethcommon.Hash `json:"codes"`
+}
+
+// blobPath returns the path of a content-addressed blob (e.g. "/1/blobs/0x1234...")
+func blobPath(chainID uint64, hash gethcommon.Hash) string {
+	return fmt.Sprintf("/%d/blobs/%s", chainID, hash.Hex())
+}
+
+// refsPath returns the path of the references of a deduplicated prover input (e.g. "/1/1234/zkpi.json.refs.json")
+func refsPath(payloadPath string) string {
+	return payloadPath + refsSuffix
+}
+
+const refsSuffix = ".refs.json"
+
+// parseBlobKey parses the key of a blob of a chain (e.g. "/1/blobs/0x1234...")
+func parseBlobKey(chainID uint64, key string) (gethcommon.Hash, bool) {
+	hash, ok := strings.CutPrefix(key, fmt.Sprintf("/%d/blobs/", chainID))
+	if !ok || !isHashHex(hash) {
+		return gethcommon.Hash{}, false
+	}
+	return gethcommon.HexToHash(hash), true
+}
+
+// dedupWitness stores the witness state nodes and codes of a prover input as blobs followed by their references
+// and returns a copy of the prover input without them, with the encoded references
+func (s *proverInputStore) dedupWitness(ctx context.Context, data *input.ProverInput, chainID uint64, payloadPath string) (*input.ProverInput, []byte, error) {
+	// Blobs found in the cache are not swept until the references are stored
+	s.blobs.mu.RLock()
+	defer s.blobs.mu.RUnlock()
+
+	refs := &witnessRefs{
+		State: make([]gethcommon.Hash, len(data.Witness.State)),
+		Codes: make([]gethcommon.Hash, len(data.Witness.Codes)),
+	}
+	blobs := make(map[gethcommon.Hash][]byte)
+	for i, node := range data.Witness.State {
+		refs.State[i] = crypto.Keccak256Hash(node)
+		blobs[refs.State[i]] = node
+	}
+	for i, code := range data.Witness.Codes {
+		refs.Codes[i] = crypto.Keccak256Hash(code)
+		blobs[refs.Codes[i]] = code
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(dedupConcurrency)
+	for hash, blob := range blobs {
+		if s.blobs.contains(hash) {
+			continue
+		}
+		g.Go(func() error {
+			headers := &store.Headers{
+				ContentType:     store.ContentTypeText, // Raw bytes, no content type is set on the stored object
+				ContentEncoding: store.ContentEncodingPlain,
+				KeyValue: map[string]string{
+					"chain.id": fmt.Sprintf("%d", chainID),
+				},
+			}
+			if err := s.store.Store(gctx, blobPath(chainID, hash), bytes.NewReader(blob), headers); err != nil {
+				return fmt.Errorf("failed to store blob %s: %w", hash.Hex(), err)
+			}
+			s.blobs.add(hash)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	// References are stored before the prover input, so a stored prover input without witness always has its references
+	b, err := json.Marshal(refs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode witness references: %w", err)
+	}
+	headers := &store.Headers{
+		ContentType:     store.ContentTypeJSON,
+		ContentEncoding: store.ContentEncodingPlain,
+		KeyValue: map[string]string{
+			"chain.id":     fmt.Sprintf("%d", chainID),
+			"block.number": data.Blocks[0].Header.Number.String(),
+		},
+	}
+	if err := s.store.Store(ctx, refsPath(payloadPath), bytes.NewReader(b), headers); err != nil {
+		return nil, nil, fmt.Errorf("failed to store witness references: %w", err)
+	}
+
+	witness := *data.Witness
+	witness.State, witness.Codes = [][]byte{}, [][]byte{}
+	deduped := *data
+	deduped.Witness = &witness
+
+	return &deduped, b, nil
+}
+
+// rehydrateWitness loads the witness state nodes and codes of a prover input stored without them
+// Prover inputs with a witness, or without references (e.g. stored before deduplication was enabled), are left untouched
+// If the prover input has a manifest, the references must match the hash recorded in the manifest.
+func (s *proverInputStore) rehydrateWitness(ctx context.Context, data *input.ProverInput, chainID uint64, payloadPath string, manifest *Manifest) error {
+	if data.Witness == nil || len(data.Witness.State) > 0 || len(data.Witness.Codes) > 0 {
+		return nil
+	}
+
+	reader, _, err := s.store.Load(ctx, refsPath(payloadPath))
+	if err == nil && reader == nil {
+		err = store.ErrNotFound
+	}
+	if errors.Is(err, store.ErrNotFound) {
+		if manifest != nil && len(manifest.RefsSHA256) > 0 {
+			return fmt.Errorf("%w: witness references are missing", ErrIntegrity)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load witness references: %w", err)
+	}
+	defer reader.Close()
+
+	b, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("failed to read witness references: %w", err)
+	}
+
+	if manifest != nil {
+		if err := manifest.verifyRefs(b); err != nil {
+			return err
+		}
+	}
+
+	refs := new(witnessRefs)
+	if err := json.Unmarshal(b, refs); err != nil {
+		return fmt.Errorf("failed to decode witness references: %w", err)
+	}
+
+	// Blobs referenced several times are loaded once
+	var hashes []gethcommon.Hash
+	blobs := make(map[gethcommon.Hash][]byte)
+	for _, hash := range append(append([]gethcommon.Hash{}, refs.State...), refs.Codes...) {
+		if _, ok := blobs[hash]; !ok {
+			blobs[hash] = nil
+			hashes = append(hashes, hash)
+		}
+	}
+
+	var mu sync.Mutex
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(dedupConcurrency)
+	for _, hash := range hashes {
+		g.Go(func() error {
+			blob, err := s.loadBlob(gctx, chainID, hash)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			blobs[hash] = blob
+			mu.Unlock()
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	data.Witness.State = make([][]byte, len(refs.State))
+	for i, hash := range refs.State {
+		data.Witness.State[i] = blobs[hash]
+	}
+	data.Witness.Codes = make([][]byte, len(refs.Codes))
+	for i, hash := range refs.Codes {
+		data.Witness.Codes[i] = blobs[hash]
+	}
+
+	return nil
+}
+
+// loadBlob loads a blob and verifies it matches its hash
+func (s *proverInputStore) loadBlob(ctx context.Context, chainID uint64, hash gethcommon.Hash) ([]byte, error) {
+	reader, _, err := s.store.Load(ctx, blobPath(chainID, hash))
+	if err == nil && reader == nil {
+		err = store.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load blob %s: %w", hash.Hex(), err)
+	}
+	defer reader.Close()
+
+	blob, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blob %s: %w", hash.Hex(), err)
+	}
+
+	if crypto.Keccak256Hash(blob) != hash {
+		return nil, fmt.Errorf("%w: blob %s does not match its hash", ErrIntegrity, hash.Hex())
+	}
+
+	return blob, nil
+}
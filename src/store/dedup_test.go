@@ -0,0 +1,231 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	store "github.com/kkrt-labs/go-utils/store"
+	filestore "github.com/kkrt-labs/go-utils/store/file"
+	input "github.com/kkrt-labs/zk-pig/src/prover-input"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDedupInputs returns the prover inputs of 2 consecutive blocks sharing most of their state nodes and codes
+func testDedupInputs() []*input.ProverInput {
+	first, second := testEncodingInput(), testEncodingInput()
+	second.Blocks = []*input.Block{{Header: &gethtypes.Header{Number: big.NewInt(11), Difficulty: big.NewInt(0), BaseFee: big.NewInt(1)}}}
+	second.Witness.State = append(second.Witness.State[1:], []byte{0x6})
+	second.Witness.Codes = append(second.Witness.Codes, second.Witness.Codes[0])
+	return []*input.ProverInput{first, second}
+}
+
+func TestProverInputStoreDedup(t *testing.T) {
+	for _, contentType := range []ContentType{ContentTypeJSON, ContentTypeProtobufChunked} {
+		t.Run(contentType.String(), func(t *testing.T) {
+			dir := t.TempDir()
+			s := filestore.New(dir)
+			inputStore := NewProverInputStore(s, contentType, WithDedup(), WithManifest("v0.0.1"))
+
+			inputs := testDedupInputs()
+			for _, in := range inputs {
+				require.NoError(t, inputStore.StoreProverInput(context.TODO(), in))
+			}
+
+			// Shared state nodes and codes are stored once
+			blobs, err := os.ReadDir(filepath.Join(dir, "1", "blobs"))
+			require.NoError(t, err)
+			assert.Len(t, blobs, 6)
+
+			// Prover inputs are stored without their state nodes and codes
+			reader, _, err := s.Load(context.TODO(), "/1/10/zkpi."+contentType.FileExtension())
+			require.NoError(t, err)
+			stored, err := DecodeProverInputFrom(reader, contentType)
+			require.NoError(t, err)
+			reader.Close()
+			assert.Empty(t, stored.Witness.State)
+			assert.Empty(t, stored.Witness.Codes)
+			assert.Len(t, stored.Witness.Ancestors, 1)
+			assert.FileExists(t, filepath.Join(dir, "1", "10", "zkpi."+contentType.FileExtension()+".refs.json"))
+
+			// Prover inputs are rehydrated on load
+			for _, in := range inputs {
+				payload, err := EncodeProverInput(in, contentType)
+				require.NoError(t, err)
+				expected, err := DecodeProverInput(payload, contentType)
+				require.NoError(t, err)
+
+				loaded, err := inputStore.LoadProverInput(context.TODO(), 1, in.Blocks[0].Header.Number.Uint64())
+				require.NoError(t, err)
+				assertSameJSON(t, expected, loaded)
+			}
+		})
+	}
+}
+
+func TestProverInputStoreDedupNotDeduplicated(t *testing.T) {
+	s := filestore.New(t.TempDir())
+	in := testEncodingInput()
+	require.NoError(t, NewProverInputStore(s, ContentTypeJSON).StoreProverInput(context.TODO(), in))
+
+	// Prover inputs stored before deduplication was enabled are loaded as is
+	loaded, err := NewProverInputStore(s, ContentTypeJSON, WithDedup()).LoadProverInput(context.TODO(), 1, 10)
+	require.NoError(t, err)
+	assert.Len(t, loaded.Witness.State, len(in.Witness.State))
+}
+
+func TestProverInputStoreDedupCorruptedBlob(t *testing.T) {
+	dir := t.TempDir()
+	inputStore := NewProverInputStore(filestore.New(dir), ContentTypeJSON, WithDedup())
+
+	in := testEncodingInput()
+	require.NoError(t, inputStore.StoreProverInput(context.TODO(), in))
+
+	blob := filepath.Join(dir, "1", "blobs", crypto.Keccak256Hash(in.Witness.Codes[0]).Hex())
+	require.NoError(t, os.WriteFile(blob, []byte{0x60, 0x01}, 0o600))
+	_, err := inputStore.LoadProverInput(context.TODO(), 1, 10)
+	assert.ErrorIs(t, err, ErrIntegrity)
+
+	require.NoError(t, os.Remove(blob))
+	_, err = inputStore.LoadProverInput(context.TODO(), 1, 10)
+	assert.ErrorIs(t, err, store.ErrNotFound)
+}
+
+func TestProverInputStoreDedupBlobCacheTTL(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewBlobCache()
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	inputStore := NewProverInputStore(filestore.New(dir), ContentTypeJSON, WithDedup(), WithBlobCache(cache))
+
+	in := testEncodingInput()
+	require.NoError(t, inputStore.StoreProverInput(context.TODO(), in))
+
+	// The blob is deleted by the garbage collector of another process
+	blob := filepath.Join(dir, "1", "blobs", crypto.Keccak256Hash(in.Witness.Codes[0]).Hex())
+	require.NoError(t, os.Remove(blob))
+
+	// Blobs stored less than BlobCacheTTL ago are trusted
+	require.NoError(t, inputStore.StoreProverInput(context.TODO(), in))
+	assert.NoFileExists(t, blob)
+
+	// Blobs stored earlier are stored again
+	now = now.Add(BlobCacheTTL)
+	require.NoError(t, inputStore.StoreProverInput(context.TODO(), in))
+	assert.FileExists(t, blob)
+	_, err := inputStore.LoadProverInput(context.TODO(), 1, 10)
+	require.NoError(t, err)
+}
+
+func TestProverInputStoreDedupManifest(t *testing.T) {
+	loadManifest := func(dir string) *Manifest {
+		b, err := os.ReadFile(filepath.Join(dir, "1", "10", "zkpi.json.manifest.json"))
+		require.NoError(t, err)
+		m := new(Manifest)
+		require.NoError(t, json.Unmarshal(b, m))
+		return m
+	}
+
+	in := testEncodingInput()
+	plainDir, dedupDir := t.TempDir(), t.TempDir()
+	require.NoError(t, NewProverInputStore(filestore.New(plainDir), ContentTypeJSON, WithManifest("v0.0.1")).StoreProverInput(context.TODO(), in))
+	inputStore := NewProverInputStore(filestore.New(dedupDir), ContentTypeJSON, WithDedup(), WithManifest("v0.0.1"))
+	require.NoError(t, inputStore.StoreProverInput(context.TODO(), in))
+
+	// The input hash covers the prover input with its witness, the references are covered by their hash
+	plain, deduped := loadManifest(plainDir), loadManifest(dedupDir)
+	assert.Equal(t, plain.InputHash, deduped.InputHash)
+	assert.Empty(t, plain.RefsSHA256)
+	assert.NotEmpty(t, deduped.RefsSHA256)
+
+	// Deduplicated prover inputs can not be loaded without their witness
+	_, err := NewProverInputStore(filestore.New(dedupDir), ContentTypeJSON, WithManifest("v0.0.1")).LoadProverInput(context.TODO(), 1, 10)
+	assert.Error(t, err)
+
+	// References not matching the manifest are rejected
+	refs := filepath.Join(dedupDir, "1", "10", "zkpi.json.refs.json")
+	b, err := os.ReadFile(refs)
+	require.NoError(t, err)
+	tampered := new(witnessRefs)
+	require.NoError(t, json.Unmarshal(b, tampered))
+	tampered.State = tampered.State[1:]
+	tamperedBytes, err := json.Marshal(tampered)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(refs, tamperedBytes, 0o600))
+	_, err = inputStore.LoadProverInput(context.TODO(), 1, 10)
+	assert.ErrorIs(t, err, ErrIntegrity)
+
+	require.NoError(t, os.Remove(refs))
+	_, err = inputStore.LoadProverInput(context.TODO(), 1, 10)
+	assert.ErrorIs(t, err, ErrIntegrity)
+}
+
+// benchmarkDedupInput returns the prover input of the i-th of consecutive blocks, which witnesses overlap by 90%
+func benchmarkDedupInput(i int, nodes, codes [][]byte) *input.ProverInput {
+	in := testEncodingInput()
+	in.Blocks = []*input.Block{{Header: &gethtypes.Header{Number: big.NewInt(int64(10 + i)), Difficulty: big.NewInt(0), BaseFee: big.NewInt(1)}}}
+	in.Witness.State = nodes[i*200 : i*200+2000]
+	in.Witness.Codes = codes[i*2 : i*2+20]
+	return in
+}
+
+// BenchmarkProverInputStoreDedup measures the time to store and load, number of objects and bytes written per block
+// for blocks with 2000 state nodes of 500 bytes and 20 codes of 8KB, 90% of them shared with the previous block
+func BenchmarkProverInputStoreDedup(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	random := func(n, size int) [][]byte {
+		items := make([][]byte, n)
+		for i := range items {
+			items[i] = make([]byte, size)
+			_, _ = rng.Read(items[i])
+		}
+		return items
+	}
+
+	for _, test := range []struct {
+		desc string
+		opts []Option
+	}{
+		{desc: "plain"},
+		{desc: "dedup", opts: []Option{WithDedup()}},
+	} {
+		b.Run(test.desc, func(b *testing.B) {
+			nodes, codes := random(200*b.N+2000, 500), random(2*b.N+20, 8192)
+			dir := b.TempDir()
+			inputStore := NewProverInputStore(filestore.New(dir), ContentTypeProtobufChunked, test.opts...)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := inputStore.StoreProverInput(context.TODO(), benchmarkDedupInput(i, nodes, codes)); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+
+			start := time.Now()
+			for i := 0; i < b.N; i++ {
+				if _, err := inputStore.LoadProverInput(context.TODO(), 1, uint64(10+i)); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(time.Since(start).Nanoseconds())/float64(b.N), "load-ns/block")
+
+			objects, err := NewFileLister(dir).List(context.TODO(), "/")
+			require.NoError(b, err)
+			var size int64
+			for _, o := range objects {
+				size += o.Size
+			}
+			b.ReportMetric(float64(len(objects))/float64(b.N), "objects/block")
+			b.ReportMetric(float64(size)/float64(b.N), "bytes/block")
+		})
+	}
+}
@@ -0,0 +1,420 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	input "github.com/kkrt-labs/zk-pig/src/prover-input"
+	protoinput "github.com/kkrt-labs/zk-pig/src/prover-input/proto"
+	sszinput "github.com/kkrt-labs/zk-pig/src/prover-input/ssz"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/proto"
+)
+
+// protobufChunkSize is the size above which the witness items (state nodes or codes) of a prover input are split in another chunk
+const protobufChunkSize = 1 << 20
+
+// protobufMaxChunkSize is the maximum size of a protobuf chunk (128 MiB)
+// It bounds the memory allocated when decoding a corrupted length prefix, while leaving room for the first chunk (holding the extra)
+// and for the largest blocks (a block filled with calldata is ~40 MiB at a 150M gas limit).
+const protobufMaxChunkSize = 128 << 20
+
+// EncodeProverInputTo encodes a prover input in the given content type ("json", "protobuf", "protobuf-chunked" or "ssz") to a writer
+//
+// JSON and chunked protobuf are written item by item (blocks, state nodes, codes...) so memory is bounded by the largest item,
+// protobuf and SSZ are encoded in memory before being written.
+func EncodeProverInputTo(w io.Writer, data *input.ProverInput, contentType ContentType) error {
+	switch contentType {
+	case ContentTypeJSON:
+		if err := encodeJSON(w, data); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+	case ContentTypeProtobufChunked:
+		if err := encodeProtobufChunks(w, data); err != nil {
+			return fmt.Errorf("failed to marshal protobuf: %w", err)
+		}
+	case ContentTypeProtobuf:
+		b, err := proto.Marshal(protoinput.ToProto(data))
+		if err != nil {
+			return fmt.Errorf("failed to marshal protobuf: %w", err)
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	case ContentTypeSSZ:
+		b, err := sszinput.Encode(data)
+		if err != nil {
+			return fmt.Errorf("failed to encode SSZ: %w", err)
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported content type: %s", contentType.String())
+	}
+	return nil
+}
+
+// DecodeProverInputFrom decodes a prover input encoded in the given content type ("json", "protobuf", "protobuf-chunked" or "ssz") from a reader
+//
+// JSON and chunked protobuf are read item by item so memory is bounded by the decoded prover input and the largest item,
+// protobuf and SSZ are read in memory before being decoded.
+func DecodeProverInputFrom(r io.Reader, contentType ContentType) (*input.ProverInput, error) {
+	switch contentType {
+	case ContentTypeJSON:
+		data, err := decodeJSON(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode JSON: %w", err)
+		}
+		return data, nil
+	case ContentTypeProtobufChunked:
+		data, err := decodeProtobufChunks(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal protobuf: %w", err)
+		}
+		return data, nil
+	case ContentTypeProtobuf, ContentTypeSSZ:
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read data: %w", err)
+		}
+		return DecodeProverInput(b, contentType)
+	default:
+		return nil, fmt.Errorf("unsupported content type: %s", contentType.String())
+	}
+}
+
+// jsonWriter writes JSON values and keeps the first error
+type jsonWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (w *jsonWriter) raw(s string) {
+	if w.err == nil {
+		_, w.err = io.WriteString(w.w, s)
+	}
+}
+
+func (w *jsonWriter) value(v any) {
+	if w.err != nil {
+		return
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		w.err = err
+		return
+	}
+	_, w.err = w.w.Write(b)
+}
+
+// writeJSONArray writes the items of an array one by one
+func writeJSONArray[T any](w *jsonWriter, items []T) {
+	if items == nil {
+		w.raw("null")
+		return
+	}
+
+	w.raw("[")
+	for i, item := range items {
+		if i > 0 {
+			w.raw(",")
+		}
+		w.value(item)
+	}
+	w.raw("]")
+}
+
+// encodeJSON writes the same bytes as json.Encoder.Encode without holding the whole encoding in memory
+func encodeJSON(writer io.Writer, data *input.ProverInput) error {
+	w := &jsonWriter{w: writer}
+
+	w.raw(`{"version":`)
+	w.value(data.Version)
+	w.raw(`,"blocks":`)
+	writeJSONArray(w, data.Blocks)
+	w.raw(`,"witness":`)
+	if data.Witness == nil {
+		w.raw("null")
+	} else {
+		w.raw(`{"state":`)
+		writeJSONArray(w, toHexBytes(data.Witness.State))
+		w.raw(`,"ancestors":`)
+		writeJSONArray(w, data.Witness.Ancestors)
+		w.raw(`,"codes":`)
+		writeJSONArray(w, toHexBytes(data.Witness.Codes))
+		w.raw("}")
+	}
+	w.raw(`,"chainConfig":`)
+	w.value(data.ChainConfig)
+	if data.OpStack != nil {
+		w.raw(`,"opStack":`)
+		w.value(data.OpStack)
+	}
+	if data.Extra != nil {
+		w.raw(`,"extra":`)
+		w.value(data.Extra)
+	}
+	w.raw("}\n")
+
+	return w.err
+}
+
+// decodeJSON decodes a prover input decoding blocks and witness items one by one
+func decodeJSON(r io.Reader) (*input.ProverInput, error) {
+	dec := json.NewDecoder(r)
+	if err := expectJSONDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+
+	data := new(input.ProverInput)
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch key {
+		case "version":
+			err = dec.Decode(&data.Version)
+		case "blocks":
+			data.Blocks, err = decodeJSONArray[*input.Block](dec)
+		case "witness":
+			data.Witness, err = decodeWitnessJSON(dec)
+		case "chainConfig":
+			err = dec.Decode(&data.ChainConfig)
+		case "opStack":
+			err = dec.Decode(&data.OpStack)
+		case "extra":
+			err = dec.Decode(&data.Extra)
+		default:
+			err = dec.Decode(new(json.RawMessage))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := expectJSONDelim(dec, '}'); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func decodeWitnessJSON(dec *json.Decoder) (*input.Witness, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok == nil {
+		return nil, nil
+	}
+	if tok != json.Delim('{') {
+		return nil, fmt.Errorf("invalid witness: unexpected %v", tok)
+	}
+
+	// Same as input.Witness.UnmarshalJSON which always sets state and codes
+	w := &input.Witness{State: [][]byte{}, Codes: [][]byte{}}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		var items []hexutil.Bytes
+		switch key {
+		case "state":
+			items, err = decodeJSONArray[hexutil.Bytes](dec)
+			w.State = fromHexBytes(items)
+		case "ancestors":
+			w.Ancestors, err = decodeJSONArray[*gethtypes.Header](dec)
+		case "codes":
+			items, err = decodeJSONArray[hexutil.Bytes](dec)
+			w.Codes = fromHexBytes(items)
+		default:
+			err = dec.Decode(new(json.RawMessage))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return w, expectJSONDelim(dec, '}')
+}
+
+// decodeJSONArray decodes the items of an array one by one
+func decodeJSONArray[T any](dec *json.Decoder) ([]T, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok == nil {
+		return nil, nil
+	}
+	if tok != json.Delim('[') {
+		return nil, fmt.Errorf("expected array but got %v", tok)
+	}
+
+	items := []T{}
+	for dec.More() {
+		var item T
+		if err := dec.Decode(&item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, expectJSONDelim(dec, ']')
+}
+
+func expectJSONDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != delim {
+		return fmt.Errorf("expected %v but got %v", delim, tok)
+	}
+	return nil
+}
+
+func toHexBytes(items [][]byte) []hexutil.Bytes {
+	h := make([]hexutil.Bytes, len(items))
+	for i, item := range items {
+		h[i] = item
+	}
+	return h
+}
+
+func fromHexBytes(items []hexutil.Bytes) [][]byte {
+	b := make([][]byte, len(items))
+	for i, item := range items {
+		b[i] = item
+	}
+	return b
+}
+
+// encodeProtobufChunks writes a prover input as length-delimited protobuf chunks
+//
+// The first chunk contains everything but the blocks, state nodes and codes, followed by a chunk per block
+// and chunks of state nodes and codes of up to protobufChunkSize bytes.
+// The first chunk holds the number of chunks following it, so streams truncated at a chunk boundary are detected.
+func encodeProtobufChunks(w io.Writer, data *input.ProverInput) error {
+	var state, codes [][][]byte
+	if data.Witness != nil {
+		state, codes = splitProtobufItems(data.Witness.State), splitProtobufItems(data.Witness.Codes)
+	}
+
+	head := &protoinput.ProverInput{
+		Version:     data.Version,
+		ChainConfig: protoinput.ChainConfigToProto(data.ChainConfig),
+		OpStack:     protoinput.OpStackConfigToProto(data.OpStack),
+		Extra:       protoinput.ExtraToProto(data.Extra),
+		Chunks:      uint64(len(data.Blocks) + len(state) + len(codes)),
+	}
+	if data.Witness != nil {
+		head.Witness = &protoinput.Witness{Ancestors: protoinput.HeadersToProto(data.Witness.Ancestors)}
+	}
+	if err := marshalProtobufChunk(w, head); err != nil {
+		return err
+	}
+
+	for _, block := range data.Blocks {
+		chunk := &protoinput.ProverInput{Blocks: []*protoinput.Block{protoinput.BlockToProto(block)}}
+		if err := marshalProtobufChunk(w, chunk); err != nil {
+			return err
+		}
+	}
+
+	for _, items := range state {
+		if err := marshalProtobufChunk(w, &protoinput.ProverInput{Witness: &protoinput.Witness{State: items}}); err != nil {
+			return err
+		}
+	}
+
+	for _, items := range codes {
+		if err := marshalProtobufChunk(w, &protoinput.ProverInput{Witness: &protoinput.Witness{Codes: items}}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// marshalProtobufChunk writes a length-delimited protobuf chunk
+// Chunks larger than protobufMaxChunkSize are rejected, as they could not be decoded.
+func marshalProtobufChunk(w io.Writer, chunk *protoinput.ProverInput) error {
+	if size := proto.Size(chunk); size > protobufMaxChunkSize {
+		return fmt.Errorf("protobuf chunk of %d bytes exceeds maximum size of %d bytes", size, protobufMaxChunkSize)
+	}
+	_, err := protodelim.MarshalTo(w, chunk)
+	return err
+}
+
+// splitProtobufItems splits witness items (state nodes or codes) in chunks of up to protobufChunkSize bytes
+func splitProtobufItems(items [][]byte) [][][]byte {
+	var chunks [][][]byte
+	for start := 0; start < len(items); {
+		end, size := start, 0
+		for end < len(items) && (end == start || size+len(items[end]) <= protobufChunkSize) {
+			size += len(items[end])
+			end++
+		}
+		chunks = append(chunks, items[start:end])
+		start = end
+	}
+	return chunks
+}
+
+// decodeProtobufChunks reads length-delimited protobuf chunks one by one, merging them into the prover input decoded from the first chunk
+//
+// It fails with io.ErrUnexpectedEOF if the stream ends before the number of chunks announced by the first chunk.
+func decodeProtobufChunks(r io.Reader) (*input.ProverInput, error) {
+	br := bufio.NewReader(r)
+	opts := protodelim.UnmarshalOptions{MaxSize: protobufMaxChunkSize}
+
+	head := new(protoinput.ProverInput)
+	if err := opts.UnmarshalFrom(br, head); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	data := protoinput.FromProto(head)
+
+	for i := uint64(0); i < head.Chunks; i++ {
+		chunk := new(protoinput.ProverInput)
+		if err := opts.UnmarshalFrom(br, chunk); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("%w: got %d chunks out of %d", io.ErrUnexpectedEOF, i, head.Chunks)
+			}
+			return nil, err
+		}
+
+		data.Blocks = append(data.Blocks, protoinput.BlocksFromProto(chunk.Blocks)...)
+		if chunk.Witness != nil {
+			if data.Witness == nil {
+				data.Witness = new(input.Witness)
+			}
+			data.Witness.State = append(data.Witness.State, chunk.Witness.State...)
+			data.Witness.Ancestors = append(data.Witness.Ancestors, protoinput.HeadersFromProto(chunk.Witness.Ancestors)...)
+			data.Witness.Codes = append(data.Witness.Codes, chunk.Witness.Codes...)
+		}
+	}
+
+	if _, err := br.ReadByte(); !errors.Is(err, io.EOF) {
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("unexpected data after %d chunks", head.Chunks)
+	}
+
+	return data, nil
+}
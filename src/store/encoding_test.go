@@ -0,0 +1,180 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	memorystore "github.com/kkrt-labs/go-utils/store/memory"
+	input "github.com/kkrt-labs/zk-pig/src/prover-input"
+	protoinput "github.com/kkrt-labs/zk-pig/src/prover-input/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func testEncodingInput() *input.ProverInput {
+	parent := &gethtypes.Header{Number: big.NewInt(9), Difficulty: big.NewInt(0)}
+	tx := gethtypes.NewTx(&gethtypes.DynamicFeeTx{
+		ChainID:   big.NewInt(1),
+		Nonce:     1,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(2),
+		Gas:       21000,
+		To:        &gethcommon.Address{0x1},
+		Value:     big.NewInt(3),
+	})
+
+	return &input.ProverInput{
+		Version:     input.CurrentVersion,
+		ChainConfig: params.MainnetChainConfig,
+		Blocks: []*input.Block{
+			{
+				Header:       &gethtypes.Header{Number: big.NewInt(10), Difficulty: big.NewInt(0), ParentHash: parent.Hash(), BaseFee: big.NewInt(1)},
+				Transactions: []*gethtypes.Transaction{tx},
+				Withdrawals:  []*gethtypes.Withdrawal{{Index: 1, Amount: 2}},
+			},
+			{
+				Header: &gethtypes.Header{Number: big.NewInt(11), Difficulty: big.NewInt(0), BaseFee: big.NewInt(1)},
+			},
+		},
+		Witness: &input.Witness{
+			// Large enough state to be split in several chunks
+			State: [][]byte{
+				bytes.Repeat([]byte{0x1}, protobufChunkSize/2),
+				bytes.Repeat([]byte{0x2}, protobufChunkSize/2),
+				bytes.Repeat([]byte{0x3}, protobufChunkSize+1),
+				{0x4},
+			},
+			Ancestors: []*gethtypes.Header{parent},
+			Codes:     [][]byte{{0x60, 0x00}},
+		},
+		Extra: &input.Extra{
+			Committed: [][]byte{{0x5}},
+		},
+	}
+}
+
+func TestEncodeJSON(t *testing.T) {
+	for _, in := range []*input.ProverInput{
+		testEncodingInput(),
+		{Version: input.CurrentVersion, ChainConfig: params.MainnetChainConfig},
+		{Blocks: []*input.Block{}, Witness: &input.Witness{}},
+	} {
+		expected := new(bytes.Buffer)
+		require.NoError(t, json.NewEncoder(expected).Encode(in))
+
+		b, err := EncodeProverInput(in, ContentTypeJSON)
+		require.NoError(t, err)
+		assert.Equal(t, expected.String(), string(b))
+
+		decoded, err := DecodeProverInput(b, ContentTypeJSON)
+		require.NoError(t, err)
+		expectedDecoded := new(input.ProverInput)
+		require.NoError(t, json.Unmarshal(b, expectedDecoded))
+		assertSameJSON(t, expectedDecoded, decoded)
+	}
+}
+
+func TestDecodeJSONTruncated(t *testing.T) {
+	b, err := EncodeProverInput(testEncodingInput(), ContentTypeJSON)
+	require.NoError(t, err)
+
+	_, err = DecodeProverInput(b[:len(b)/2], ContentTypeJSON)
+	assert.Error(t, err)
+}
+
+func TestEncodeProtobufChunks(t *testing.T) {
+	in := testEncodingInput()
+
+	b, err := EncodeProverInput(in, ContentTypeProtobufChunked)
+	require.NoError(t, err)
+
+	// Head, 2 blocks, 3 chunks of state nodes and 1 chunk of codes
+	var ends []int
+	head := new(protoinput.ProverInput)
+	for r := bytes.NewReader(b); r.Len() > 0; ends = append(ends, len(b)-r.Len()) {
+		chunk := new(protoinput.ProverInput)
+		require.NoError(t, protodelim.UnmarshalOptions{MaxSize: -1}.UnmarshalFrom(r, chunk))
+		if len(ends) == 0 {
+			head = chunk
+		}
+	}
+	assert.Len(t, ends, 7)
+	assert.Equal(t, uint64(6), head.Chunks)
+
+	decoded, err := DecodeProverInput(b, ContentTypeProtobufChunked)
+	require.NoError(t, err)
+
+	protobuf, err := EncodeProverInput(in, ContentTypeProtobuf)
+	require.NoError(t, err)
+	expected, err := DecodeProverInput(protobuf, ContentTypeProtobuf)
+	require.NoError(t, err)
+	assertSameJSON(t, expected, decoded)
+
+	_, err = DecodeProverInput(b[:len(b)-10], ContentTypeProtobufChunked)
+	assert.Error(t, err)
+
+	// Streams truncated at a chunk boundary miss chunks announced by the head chunk
+	for _, end := range ends[:len(ends)-1] {
+		_, err = DecodeProverInput(b[:end], ContentTypeProtobufChunked)
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	}
+
+	_, err = DecodeProverInput(append(b, b[:ends[1]]...), ContentTypeProtobufChunked)
+	assert.ErrorContains(t, err, "unexpected data after 6 chunks")
+
+	_, err = DecodeProverInput(protowire.AppendVarint(nil, 10), ContentTypeProtobufChunked)
+	assert.Error(t, err)
+
+	// Corrupted length prefixes are rejected before allocating the chunk
+	var sizeErr *protodelim.SizeTooLargeError
+	_, err = DecodeProverInput(protowire.AppendVarint(nil, protobufMaxChunkSize+1), ContentTypeProtobufChunked)
+	assert.ErrorAs(t, err, &sizeErr)
+}
+
+func TestProverInputStoreStreaming(t *testing.T) {
+	for _, contentType := range []ContentType{ContentTypeJSON, ContentTypeProtobufChunked} {
+		for _, encoding := range []ContentEncoding{ContentEncodingPlain, ContentEncodingGzip} {
+			t.Run(contentType.String()+"/"+encoding.String(), func(t *testing.T) {
+				s, err := NewCompressStore(memorystore.New(), encoding)
+				require.NoError(t, err)
+				inputStore := NewProverInputStore(s, contentType, WithManifest("v0.0.1"))
+
+				in := testEncodingInput()
+				require.NoError(t, inputStore.StoreProverInput(context.TODO(), in))
+
+				payload, err := EncodeProverInput(in, contentType)
+				require.NoError(t, err)
+				expectedInput, err := DecodeProverInput(payload, contentType)
+				require.NoError(t, err)
+
+				loaded, err := inputStore.LoadProverInput(context.TODO(), 1, 10)
+				require.NoError(t, err)
+				assertSameJSON(t, expectedInput, loaded)
+
+				// The manifest streamed with the payload matches the one computed on the encoded payload
+				expected, err := NewManifest(in, contentType, payload, "v0.0.1")
+				require.NoError(t, err)
+				manifest, err := inputStore.(*proverInputStore).loadManifest(context.TODO(), "/1/10/zkpi."+contentType.FileExtension())
+				require.NoError(t, err)
+				assert.Equal(t, expected, manifest)
+			})
+		}
+	}
+}
+
+func assertSameJSON(t *testing.T, expected, actual *input.ProverInput) {
+	expectedJSON, err := json.Marshal(expected)
+	require.NoError(t, err)
+	actualJSON, err := json.Marshal(actual)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(expectedJSON), string(actualJSON))
+}
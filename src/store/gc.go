@@ -0,0 +1,536 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	store "github.com/kkrt-labs/go-utils/store"
+	"go.uber.org/multierr"
+)
+
+//go:generate mockgen -destination=./mock/gc.go -package=mockstore github.com/kkrt-labs/zk-pig/src/store GarbageCollector
+
+// RetentionPolicy describes which stored artifacts are retained by the garbage collector
+//
+// The artifacts of a block (prover input, preflight data, block, trace...) are retained as long as
+// one of the keep rules applies. If no keep rule is set, all blocks are retained.
+type RetentionPolicy struct {
+	KeepLast            uint64        // Keep the artifacts of the last N blocks of the store (0 to disable)
+	KeepNewerThan       time.Duration // Keep the artifacts of blocks modified more recently than the given age (0 to disable)
+	KeepModulo          uint64        // Always keep the artifacts of blocks which number is divisible by the given modulo (0 to disable)
+	DeletePreflightData bool          // Delete the preflight data of retained blocks once their prover input exists
+
+	// KeepBlobsNewerThan keeps the blobs of deduplicated prover inputs modified more recently than the given age (0 to disable)
+	// Processes storing prover inputs only store again the blobs they stored more than BlobCacheTTL ago, so the grace period must be
+	// longer than BlobCacheTTL plus the time to store a prover input to protect blobs referenced by prover inputs being stored.
+	KeepBlobsNewerThan time.Duration
+}
+
+// retainBlob returns true if an unreferenced blob must be retained
+func (p *RetentionPolicy) retainBlob(o *Object, now time.Time) bool {
+	// Blobs with an unknown modification time are considered new
+	return p.KeepBlobsNewerThan > 0 && (o.LastModified.IsZero() || now.Sub(o.LastModified) < p.KeepBlobsNewerThan)
+}
+
+// hasKeepRules returns true if at least one keep rule is set
+func (p *RetentionPolicy) hasKeepRules() bool {
+	return p.KeepLast > 0 || p.KeepNewerThan > 0 || p.KeepModulo > 0
+}
+
+// retainForever returns true if the artifacts of a block are retained whatever the latest block and the time
+func (p *RetentionPolicy) retainForever(blk *storedBlock) bool {
+	return !p.hasKeepRules() || (p.KeepModulo > 0 && blk.number%p.KeepModulo == 0)
+}
+
+// retain returns true if the artifacts of a block must be retained
+func (p *RetentionPolicy) retain(blk *storedBlock, latest uint64, now time.Time) bool {
+	if !p.hasKeepRules() {
+		return true
+	}
+
+	if p.KeepLast > 0 && blk.number+p.KeepLast > latest {
+		return true
+	}
+
+	if p.KeepModulo > 0 && blk.number%p.KeepModulo == 0 {
+		return true
+	}
+
+	if p.KeepNewerThan > 0 {
+		for _, o := range blk.objects {
+			// Objects with an unknown modification time are considered new
+			if o.LastModified.IsZero() || now.Sub(o.LastModified) < p.KeepNewerThan {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// GCReport reports the objects deleted by a garbage collection
+type GCReport struct {
+	Objects []*Object `json:"objects"` // Objects deleted (or that would be deleted on a dry run)
+	Bytes   int64     `json:"bytes"`   // Bytes reclaimed (or that would be reclaimed on a dry run)
+	Blocks  int       `json:"blocks"`  // Number of blocks which artifacts have all been deleted
+	DryRun  bool      `json:"dryRun"`  // True if no object has actually been deleted
+
+	// Blobs is the number of blobs of deduplicated prover inputs deleted as no retained prover input references them (see WithBlobSweep)
+	Blobs int `json:"blobs"`
+
+	// NextBlock is the lowest block number of the collected range which artifacts may be deleted by a later collection
+	// Blocks below it have been deleted or are retained forever, so later collections can start from it.
+	NextBlock uint64 `json:"nextBlock"`
+}
+
+// GarbageCollector deletes the stored artifacts which are not retained by a retention policy
+type GarbageCollector interface {
+	// Collect deletes the artifacts of a chain which are not retained
+	// On a dry run, no object is deleted and the report lists the objects that would be deleted
+	Collect(ctx context.Context, chainID uint64, dryRun bool) (*GCReport, error)
+
+	// CollectRange deletes the artifacts of the blocks of a chain with a number in [fromBlock, toBlock] which are not retained
+	// Only the keys of the blocks in the range are listed, and KeepLast applies to the latest block of the range.
+	CollectRange(ctx context.Context, chainID, fromBlock, toBlock uint64, dryRun bool) (*GCReport, error)
+}
+
+// WithBlobSweep makes the garbage collector delete the blobs of deduplicated prover inputs (see WithDedup) that no retained prover input references
+// refs loads the witness references of prover inputs by key without encoding extension (i.e. the store of prover inputs).
+// Deleted blobs are removed from the blob cache (see WithBlobCache).
+func WithBlobSweep(refs store.Store) Option {
+	return func(o *options) {
+		o.blobRefs = refs
+	}
+}
+
+type garbageCollector struct {
+	store  store.Store
+	lister Lister
+	policy *RetentionPolicy
+	now    func() time.Time
+	*options
+
+	siblingKeys []*KeyTemplate // Templates of the artifacts stored next to the prover input (traces and bad block reports)
+	latestKeys  []*KeyTemplate // Templates of the latest pointers of the artifacts
+}
+
+// NewGarbageCollector creates a garbage collector applying the given retention policy
+//
+// Objects are listed with lister and deleted with s, which must access the objects by their listed key
+// (i.e. s must not be a compress store as listed keys already contain the encoding extension)
+// The key template options must match the ones of the stores of the artifacts
+func NewGarbageCollector(s store.Store, lister Lister, policy *RetentionPolicy, opts ...Option) GarbageCollector {
+	o := newOptions(opts...)
+	traceKey, badBlockKey := o.traceKey(TraceStepPrepare), o.badBlockKey()
+	return &garbageCollector{
+		store:       s,
+		lister:      lister,
+		policy:      policy,
+		now:         time.Now,
+		options:     o,
+		siblingKeys: []*KeyTemplate{traceKey, o.traceKey(TraceStepExecute), badBlockKey},
+		latestKeys: []*KeyTemplate{
+			o.proverInputKey.latestKey(latestProverInput),
+			o.preflightDataKey.latestKey(latestPreflightData),
+			o.blockKey.latestKey(latestBlock),
+			traceKey.latestKey(latestTrace),
+			badBlockKey.latestKey(latestBadBlock),
+		},
+	}
+}
+
+// storedBlock holds the objects stored for a block
+type storedBlock struct {
+	number  uint64
+	objects []*blockObject
+}
+
+// artifact is the kind of a block object
+type artifact int
+
+const (
+	artifactOther artifact = iota
+	artifactProverInput
+	artifactPreflightData
+)
+
+// blockObject is an object attached to a block
+type blockObject struct {
+	*Object
+	params   *keyParams
+	artifact artifact
+}
+
+// blockDirKey is the template of the directory of a block, holding the artifacts stored next to the prover input
+var blockDirKey = MustParseKeyTemplate("/{chainID}/{number}/")
+
+func (gc *garbageCollector) Collect(ctx context.Context, chainID uint64, dryRun bool) (*GCReport, error) {
+	objects, err := gc.list(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := gc.collect(ctx, gc.groupByBlock(chainID, 0, math.MaxUint64, objects), 0, dryRun)
+	if gc.blobRefs == nil || !hasBlobs(chainID, objects) {
+		return report, err
+	}
+
+	return report, multierr.Append(err, gc.sweepBlobs(ctx, chainID, report, dryRun))
+}
+
+func (gc *garbageCollector) CollectRange(ctx context.Context, chainID, fromBlock, toBlock uint64, dryRun bool) (*GCReport, error) {
+	objects, err := gc.listRange(ctx, chainID, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := gc.collect(ctx, gc.groupByBlock(chainID, fromBlock, toBlock, objects), fromBlock, dryRun)
+	if gc.blobRefs == nil || !hasRefs(report.Objects) {
+		return report, err
+	}
+
+	// Blobs may be referenced by prover inputs of any block, so only collections deleting references sweep blobs
+	return report, multierr.Append(err, gc.sweepBlobs(ctx, chainID, report, dryRun))
+}
+
+// collect deletes the objects of the blocks which are not retained
+func (gc *garbageCollector) collect(ctx context.Context, blocks []*storedBlock, fromBlock uint64, dryRun bool) (*GCReport, error) {
+	var latest uint64
+	for _, blk := range blocks {
+		if blk.number > latest {
+			latest = blk.number
+		}
+	}
+
+	now := gc.now()
+	report := &GCReport{Objects: make([]*Object, 0), DryRun: dryRun, NextBlock: fromBlock}
+	if len(blocks) > 0 {
+		report.NextBlock = latest + 1
+	}
+	var (
+		errs         []error
+		nextBlockSet bool
+	)
+	for _, blk := range blocks {
+		var toDelete []*blockObject
+		settled := true
+		if !gc.policy.retain(blk, latest, now) {
+			toDelete = blk.objects
+			report.Blocks++
+		} else {
+			if gc.policy.DeletePreflightData {
+				toDelete = preflightDataObjects(blk)
+			}
+			settled = gc.policy.retainForever(blk) && (!gc.policy.DeletePreflightData || len(toDelete) == countPreflightData(blk))
+		}
+
+		for _, o := range toDelete {
+			if !dryRun {
+				if err := ignoreNotFound(gc.store.Delete(ctx, o.Key)); err != nil {
+					errs = append(errs, fmt.Errorf("failed to delete %q: %w", o.Key, err))
+					settled = false
+					continue
+				}
+			}
+			report.Objects = append(report.Objects, o.Object)
+			report.Bytes += o.Size
+		}
+
+		if !settled && !nextBlockSet {
+			report.NextBlock, nextBlockSet = blk.number, true
+		}
+	}
+
+	return report, multierr.Combine(errs...)
+}
+
+// sweepBlobs deletes the blobs of a chain which are not referenced by the witness references of the prover inputs left by a collection
+//
+// The blob cache is locked while blobs are swept, so the prover inputs deduplicated by the process are stored
+// either before the references are listed or after the deleted blobs have been removed from the cache.
+// Prover inputs stored by other processes are protected by the blob grace period (see RetentionPolicy.KeepBlobsNewerThan).
+func (gc *garbageCollector) sweepBlobs(ctx context.Context, chainID uint64, report *GCReport, dryRun bool) error {
+	gc.blobs.mu.Lock()
+	defer gc.blobs.mu.Unlock()
+
+	objects, err := gc.list(ctx, chainID)
+	if err != nil {
+		return err
+	}
+
+	// References deleted by the collection (or that would be deleted on a dry run) do not mark blobs
+	collected := make(map[string]bool, len(report.Objects))
+	for _, o := range report.Objects {
+		collected[o.Key] = true
+	}
+
+	referenced := make(map[gethcommon.Hash]bool)
+	var blobs []*Object
+	for _, o := range objects {
+		key, _ := trimContentEncoding(o.Key)
+		if _, ok := parseBlobKey(chainID, key); ok {
+			blobs = append(blobs, o)
+			continue
+		}
+		if !strings.HasSuffix(key, refsSuffix) || collected[o.Key] {
+			continue
+		}
+
+		refs, err := gc.loadRefs(ctx, key)
+		if err != nil {
+			// Blobs can not be swept without knowing all the referenced blobs
+			return err
+		}
+		for _, hash := range append(refs.State, refs.Codes...) {
+			referenced[hash] = true
+		}
+	}
+
+	var errs []error
+	for _, o := range blobs {
+		key, _ := trimContentEncoding(o.Key)
+		hash, _ := parseBlobKey(chainID, key)
+		if referenced[hash] || gc.policy.retainBlob(o, gc.now()) {
+			continue
+		}
+
+		if !dryRun {
+			gc.blobs.remove(hash)
+			if err := ignoreNotFound(gc.store.Delete(ctx, o.Key)); err != nil {
+				errs = append(errs, fmt.Errorf("failed to delete %q: %w", o.Key, err))
+				continue
+			}
+		}
+		report.Objects = append(report.Objects, o)
+		report.Bytes += o.Size
+		report.Blobs++
+	}
+
+	return multierr.Combine(errs...)
+}
+
+// loadRefs loads witness references by key without encoding extension
+func (gc *garbageCollector) loadRefs(ctx context.Context, key string) (*witnessRefs, error) {
+	reader, _, err := gc.blobRefs.Load(ctx, key)
+	if err == nil && reader == nil {
+		err = store.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load witness references %q: %w", key, err)
+	}
+	defer reader.Close()
+
+	refs := new(witnessRefs)
+	if err := json.NewDecoder(reader).Decode(refs); err != nil {
+		return nil, fmt.Errorf("failed to decode witness references %q: %w", key, err)
+	}
+	return refs, nil
+}
+
+// hasBlobs returns true if objects contain a blob of a chain
+func hasBlobs(chainID uint64, objects []*Object) bool {
+	for _, o := range objects {
+		key, _ := trimContentEncoding(o.Key)
+		if _, ok := parseBlobKey(chainID, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// hasRefs returns true if objects contain witness references
+func hasRefs(objects []*Object) bool {
+	for _, o := range objects {
+		if key, _ := trimContentEncoding(o.Key); strings.HasSuffix(key, refsSuffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// templates returns the given key templates followed by the ones of the artifacts stored next to the prover input and of the latest pointers
+func (gc *garbageCollector) templates(templates ...*KeyTemplate) []*KeyTemplate {
+	return append(append(templates, gc.siblingKeys...), gc.latestKeys...)
+}
+
+// list lists the objects of a chain under the prefixes of all key templates
+func (gc *garbageCollector) list(ctx context.Context, chainID uint64) ([]*Object, error) {
+	prefixes := []string{fmt.Sprintf("/%d/", chainID)}
+	for _, t := range gc.templates(gc.proverInputKey, gc.preflightDataKey, gc.blockKey) {
+		prefixes = append(prefixes, t.prefix(chainID))
+	}
+
+	// Skip prefixes contained in another prefix so objects are listed once
+	sort.Strings(prefixes)
+	var objects []*Object
+	for i, prefix := range prefixes {
+		if i > 0 && strings.HasPrefix(prefix, prefixes[i-1]) {
+			prefixes[i] = prefixes[i-1]
+			continue
+		}
+		objs, err := gc.lister.List(ctx, prefix)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, objs...)
+	}
+
+	return objects, nil
+}
+
+// listRange lists the objects of the blocks of a chain in [fromBlock, toBlock] under the range prefixes of all key templates
+func (gc *garbageCollector) listRange(ctx context.Context, chainID, fromBlock, toBlock uint64) ([]*Object, error) {
+	var prefixes []string
+	for _, t := range gc.templates(blockDirKey, gc.proverInputKey, gc.preflightDataKey, gc.blockKey) {
+		prefixes = append(prefixes, t.rangePrefixes(chainID, fromBlock, toBlock)...)
+	}
+	return listPrefixes(ctx, gc.lister, prefixes)
+}
+
+// groupByBlock groups the objects of the blocks of a chain in [fromBlock, toBlock], in increasing order of block numbers
+// Objects which are not attached to a block of the chain in the range are ignored
+func (gc *garbageCollector) groupByBlock(chainID, fromBlock, toBlock uint64, objects []*Object) []*storedBlock {
+	byNumber := make(map[uint64]*storedBlock)
+	for _, o := range objects {
+		obj, ok := gc.parseBlockObjectKey(o)
+		if !ok || obj.params.chainID != chainID || obj.params.blockNumber < fromBlock || obj.params.blockNumber > toBlock {
+			continue
+		}
+
+		blk, ok := byNumber[obj.params.blockNumber]
+		if !ok {
+			blk = &storedBlock{number: obj.params.blockNumber}
+			byNumber[obj.params.blockNumber] = blk
+		}
+		blk.objects = append(blk.objects, obj)
+	}
+
+	blocks := make([]*storedBlock, 0, len(byNumber))
+	for _, blk := range byNumber {
+		blocks = append(blocks, blk)
+	}
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i].number < blocks[j].number })
+
+	return blocks
+}
+
+// parseBlockObjectKey parses the key of an object attached to a block
+// i.e. an artifact matching a key template (e.g. "/1/1234/zkpi.json.gz", "/1/blocks/1234.json.gz")
+// or stored next to the prover input (e.g. "/1/1234/trace.execute.0x....json" or "/1/1234/0x.../badblock.json")
+func (gc *garbageCollector) parseBlockObjectKey(o *Object) (*blockObject, bool) {
+	key, _ := trimContentEncoding(o.Key)
+
+	if params, ok := gc.proverInputKey.match(key); ok {
+		if _, ok := proverInputContentTypes[params.ext]; ok {
+			return &blockObject{Object: o, params: params, artifact: artifactProverInput}, true
+		}
+		return &blockObject{Object: o, params: params}, true
+	}
+
+	if params, ok := gc.preflightDataKey.match(key); ok {
+		return &blockObject{Object: o, params: params, artifact: artifactPreflightData}, true
+	}
+
+	if params, ok := gc.blockKey.match(key); ok {
+		return &blockObject{Object: o, params: params}, true
+	}
+
+	for _, t := range append(gc.siblingKeys, gc.latestKeys...) {
+		if params, ok := t.match(key); ok {
+			return &blockObject{Object: o, params: params}, true
+		}
+	}
+
+	if params, ok := parseBlockDirKey(key); ok {
+		return &blockObject{Object: o, params: params}, true
+	}
+
+	return nil, false
+}
+
+// parseBlockDirKey parses the key of an object stored in the directory of a block (e.g. "/1/1234/trace.execute.json" or "/1/1234/0x.../trace.execute.json")
+func parseBlockDirKey(key string) (*keyParams, bool) {
+	parts := strings.Split(strings.TrimPrefix(key, "/"), "/")
+	if len(parts) != 3 && len(parts) != 4 {
+		return nil, false
+	}
+
+	params := new(keyParams)
+	var err error
+	if params.chainID, err = strconv.ParseUint(parts[0], 10, 64); err != nil {
+		return nil, false
+	}
+	if params.blockNumber, err = strconv.ParseUint(parts[1], 10, 64); err != nil {
+		return nil, false
+	}
+	if len(parts) == 4 {
+		if !isHashHex(parts[2]) {
+			return nil, false
+		}
+		blockHash := gethcommon.HexToHash(parts[2])
+		params.blockHash = &blockHash
+	}
+
+	return params, true
+}
+
+// preflightDataObjects returns the preflight data objects of a block which prover input exists
+// When both keys contain the block hash, the prover input must be for the same block
+// (so the preflight data of a reorged block is kept until its own prover input exists)
+func preflightDataObjects(blk *storedBlock) []*blockObject {
+	var hasProverInput, hasUnknownProverInput bool
+	proverInputs := make(map[gethcommon.Hash]bool)
+	for _, o := range blk.objects {
+		if o.artifact != artifactProverInput {
+			continue
+		}
+		hasProverInput = true
+		if o.params.blockHash == nil {
+			hasUnknownProverInput = true
+		} else {
+			proverInputs[*o.params.blockHash] = true
+		}
+	}
+
+	objects := make([]*blockObject, 0)
+	for _, o := range blk.objects {
+		if o.artifact != artifactPreflightData || !hasProverInput {
+			continue
+		}
+		if o.params.blockHash == nil || hasUnknownProverInput || proverInputs[*o.params.blockHash] {
+			objects = append(objects, o)
+		}
+	}
+	return objects
+}
+
+// countPreflightData returns the number of preflight data objects of a block
+func countPreflightData(blk *storedBlock) int {
+	var count int
+	for _, o := range blk.objects {
+		if o.artifact == artifactPreflightData {
+			count++
+		}
+	}
+	return count
+}
+
+// ignoreNotFound ignores not found errors, as returned by the backends of a multi store
+// which do not hold the deleted object
+func ignoreNotFound(err error) error {
+	var errs []error
+	for _, e := range multierr.Errors(err) {
+		if !errors.Is(e, store.ErrNotFound) {
+			errs = append(errs, e)
+		}
+	}
+	return multierr.Combine(errs...)
+}
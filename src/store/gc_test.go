@@ -0,0 +1,359 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	filestore "github.com/kkrt-labs/go-utils/store/file"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var gcNow = time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+
+// newTestGCStore creates a file store holding the given objects, modified at the given times
+func newTestGCStore(t *testing.T, objects map[string]time.Time) string {
+	dir := t.TempDir()
+	s := filestore.New(dir)
+	for key, modTime := range objects {
+		require.NoError(t, s.Store(context.TODO(), key, bytes.NewReader([]byte("data")), nil))
+		require.NoError(t, os.Chtimes(filepath.Join(dir, key), modTime, modTime))
+	}
+	return dir
+}
+
+func collect(t *testing.T, dir string, policy *RetentionPolicy, dryRun bool, opts ...Option) (report *GCReport, remaining []string) {
+	gc := NewGarbageCollector(filestore.New(dir), NewFileLister(dir), policy, opts...).(*garbageCollector)
+	gc.now = func() time.Time { return gcNow }
+
+	report, err := gc.Collect(context.TODO(), 1, dryRun)
+	require.NoError(t, err)
+
+	objects, err := NewFileLister(dir).List(context.TODO(), "/")
+	require.NoError(t, err)
+	for _, o := range objects {
+		remaining = append(remaining, o.Key)
+	}
+
+	return report, remaining
+}
+
+func keys(objects []*Object) []string {
+	res := make([]string, 0, len(objects))
+	for _, o := range objects {
+		res = append(res, o.Key)
+	}
+	return res
+}
+
+func TestGarbageCollector(t *testing.T) {
+	old := gcNow.Add(-72 * time.Hour)
+	recent := gcNow.Add(-1 * time.Hour)
+	objects := map[string]time.Time{
+		"/1/10/zkpi.json.gz":        old,
+		"/1/10/preflight.json":      old,
+		"/1/blocks/10.json":         old,
+		"/1/11/preflight.json":      old,
+		"/1/12/zkpi.json":           old,
+		"/1/12/zkpi.meta.json":      old,
+		"/1/12/zkpi.json.refs.json": old,
+		"/1/blobs/0x8e4d5d3bfa3a0ea9c1bc6b2a4e0c9fa8e7bb1d6d5e2f0a4f3f4c1d1a2b3c4d5e": old,
+		"/1/13/zkpi.json":        recent,
+		"/1/13/preflight.json":   recent,
+		"/1/14/zkpi.protobuf":    old,
+		"/1/14/preflight.json":   old,
+		"/1/genesis.json":        old,
+		"/2/10/zkpi.json":        old,
+		"/2/10/preflight.json":   old,
+		"/1/unknown/preflight":   old,
+		"/1/blocks/unknown.json": old,
+	}
+
+	tests := []struct {
+		desc    string
+		policy  *RetentionPolicy
+		deleted []string
+		blocks  int
+	}{
+		{
+			desc:   "no rules",
+			policy: &RetentionPolicy{},
+		},
+		{
+			desc:    "keep last",
+			policy:  &RetentionPolicy{KeepLast: 2},
+			deleted: []string{"/1/10/preflight.json", "/1/10/zkpi.json.gz", "/1/blocks/10.json", "/1/11/preflight.json", "/1/12/zkpi.json", "/1/12/zkpi.json.refs.json", "/1/12/zkpi.meta.json"},
+			blocks:  3,
+		},
+		{
+			desc:    "keep newer than",
+			policy:  &RetentionPolicy{KeepNewerThan: 24 * time.Hour},
+			deleted: []string{"/1/10/preflight.json", "/1/10/zkpi.json.gz", "/1/blocks/10.json", "/1/11/preflight.json", "/1/12/zkpi.json", "/1/12/zkpi.json.refs.json", "/1/12/zkpi.meta.json", "/1/14/preflight.json", "/1/14/zkpi.protobuf"},
+			blocks:  4,
+		},
+		{
+			desc:    "keep modulo",
+			policy:  &RetentionPolicy{KeepModulo: 5},
+			deleted: []string{"/1/11/preflight.json", "/1/12/zkpi.json", "/1/12/zkpi.json.refs.json", "/1/12/zkpi.meta.json", "/1/13/preflight.json", "/1/13/zkpi.json", "/1/14/preflight.json", "/1/14/zkpi.protobuf"},
+			blocks:  4,
+		},
+		{
+			desc:    "combined rules",
+			policy:  &RetentionPolicy{KeepLast: 1, KeepNewerThan: 24 * time.Hour, KeepModulo: 5},
+			deleted: []string{"/1/11/preflight.json", "/1/12/zkpi.json", "/1/12/zkpi.json.refs.json", "/1/12/zkpi.meta.json"},
+			blocks:  2,
+		},
+		{
+			desc:    "delete preflight data",
+			policy:  &RetentionPolicy{DeletePreflightData: true},
+			deleted: []string{"/1/10/preflight.json", "/1/13/preflight.json", "/1/14/preflight.json"},
+		},
+		{
+			desc:    "keep last and delete preflight data",
+			policy:  &RetentionPolicy{KeepLast: 2, DeletePreflightData: true},
+			deleted: []string{"/1/10/preflight.json", "/1/10/zkpi.json.gz", "/1/blocks/10.json", "/1/11/preflight.json", "/1/12/zkpi.json", "/1/12/zkpi.json.refs.json", "/1/12/zkpi.meta.json", "/1/13/preflight.json", "/1/14/preflight.json"},
+			blocks:  3,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			if test.deleted == nil {
+				test.deleted = []string{}
+			}
+
+			// Dry run does not delete anything
+			dir := newTestGCStore(t, objects)
+			report, remaining := collect(t, dir, test.policy, true)
+			assert.True(t, report.DryRun)
+			assert.Equal(t, test.deleted, keys(report.Objects))
+			assert.Equal(t, int64(4*len(test.deleted)), report.Bytes)
+			assert.Equal(t, test.blocks, report.Blocks)
+			assert.Len(t, remaining, len(objects))
+
+			report, remaining = collect(t, dir, test.policy, false)
+			assert.False(t, report.DryRun)
+			assert.Equal(t, test.deleted, keys(report.Objects))
+			assert.Equal(t, int64(4*len(test.deleted)), report.Bytes)
+			assert.Len(t, remaining, len(objects)-len(test.deleted))
+			for _, key := range test.deleted {
+				assert.NotContains(t, remaining, key)
+			}
+		})
+	}
+}
+
+func TestGarbageCollectorMultiStore(t *testing.T) {
+	old := gcNow.Add(-72 * time.Hour)
+	dir1 := newTestGCStore(t, map[string]time.Time{"/1/1/zkpi.json": old, "/1/2/zkpi.json": old})
+	dir2 := newTestGCStore(t, map[string]time.Time{"/1/0/zkpi.json": old})
+
+	gc := NewGarbageCollector(
+		NewMultiStore(filestore.New(dir1), filestore.New(dir2)),
+		NewMultiLister(NewFileLister(dir1), NewFileLister(dir2)),
+		&RetentionPolicy{KeepLast: 1},
+	)
+
+	// Objects missing from one of the stores are deleted from the others
+	report, err := gc.Collect(context.TODO(), 1, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/1/0/zkpi.json", "/1/1/zkpi.json"}, keys(report.Objects))
+
+	objects, err := NewMultiLister(NewFileLister(dir1), NewFileLister(dir2)).List(context.TODO(), "/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/1/2/zkpi.json"}, keys(objects))
+}
+
+func TestGarbageCollectorHashLayout(t *testing.T) {
+	old := gcNow.Add(-72 * time.Hour)
+	hash1 := "/0x1111111111111111111111111111111111111111111111111111111111111111"
+	hash2 := "/0x2222222222222222222222222222222222222222222222222222222222222222"
+	dir := newTestGCStore(t, map[string]time.Time{
+		"/1/10" + hash1 + "/zkpi.json":      old,
+		"/1/10" + hash1 + "/preflight.json": old,
+		"/1/10/latest.zkpi":                 old,
+		"/1/11" + hash1 + "/zkpi.json":      old,
+		"/1/11" + hash1 + "/preflight.json": old,
+		"/1/11" + hash2 + "/preflight.json": old,
+		"/1/11/latest.zkpi":                 old,
+	})
+
+	// Preflight data of the reorged block is kept until its own prover input exists
+	report, _ := collect(t, dir, &RetentionPolicy{KeepLast: 1, DeletePreflightData: true}, false, WithLayout(LayoutHash))
+	assert.Equal(t, []string{
+		"/1/10" + hash1 + "/preflight.json",
+		"/1/10" + hash1 + "/zkpi.json",
+		"/1/10/latest.zkpi",
+		"/1/11" + hash1 + "/preflight.json",
+	}, keys(report.Objects))
+	assert.Equal(t, 1, report.Blocks)
+}
+
+func TestGarbageCollectorCollectRange(t *testing.T) {
+	old := gcNow.Add(-72 * time.Hour)
+	recent := gcNow.Add(-1 * time.Hour)
+	dir := newTestGCStore(t, map[string]time.Time{
+		"/1/5/zkpi.json":        old,
+		"/1/10/zkpi.json":       old,
+		"/1/10/preflight.json":  old,
+		"/1/blocks/10.json":     old,
+		"/1/11/zkpi.json":       old,
+		"/1/12/zkpi.json":       recent,
+		"/1/13/zkpi.json":       old,
+		"/1/100/zkpi.json":      old,
+		"/1/blocks/100.json":    old,
+		"/1/13/trace.exec.json": old,
+	})
+
+	lister := &recordingLister{Lister: NewFileLister(dir)}
+	gc := NewGarbageCollector(filestore.New(dir), lister, &RetentionPolicy{KeepNewerThan: 24 * time.Hour, KeepModulo: 10}).(*garbageCollector)
+	gc.now = func() time.Time { return gcNow }
+
+	// Blocks outside the range are not listed nor deleted
+	report, err := gc.CollectRange(context.TODO(), 1, 10, 13, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/1/10/", "/1/11/", "/1/12/", "/1/13/", "/1/blocks/10.json", "/1/blocks/11.json", "/1/blocks/12.json", "/1/blocks/13.json"}, lister.prefixes)
+	assert.Equal(t, []string{"/1/11/zkpi.json", "/1/13/trace.exec.json", "/1/13/zkpi.json"}, keys(report.Objects))
+	assert.Equal(t, 2, report.Blocks)
+
+	// Block 10 is retained forever by KeepModulo, block 12 may be deleted once older
+	assert.Equal(t, uint64(12), report.NextBlock)
+
+	objects, err := NewFileLister(dir).List(context.TODO(), "/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/1/10/preflight.json", "/1/10/zkpi.json", "/1/100/zkpi.json", "/1/12/zkpi.json", "/1/5/zkpi.json", "/1/blocks/10.json", "/1/blocks/100.json"}, keys(objects))
+
+	// Ranges without block report the start of the range
+	report, err = gc.CollectRange(context.TODO(), 1, 20, 29, false)
+	require.NoError(t, err)
+	assert.Empty(t, report.Objects)
+	assert.Equal(t, uint64(20), report.NextBlock)
+}
+
+func TestGarbageCollectorSweepBlobs(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewCompressStore(filestore.New(dir), ContentEncodingGzip)
+	require.NoError(t, err)
+	cache := NewBlobCache()
+	inputStore := NewProverInputStore(s, ContentTypeJSON, WithDedup(), WithBlobCache(cache), WithManifest("v0.0.1"))
+
+	inputs := testDedupInputs()
+	for _, in := range inputs {
+		require.NoError(t, inputStore.StoreProverInput(context.TODO(), in))
+	}
+	// The first state node is only referenced by the prover input of block 10
+	unreferenced := crypto.Keccak256Hash(inputs[0].Witness.State[0])
+	blobFile := filepath.Join(dir, "1", "blobs", unreferenced.Hex()+".gz")
+
+	gc := NewGarbageCollector(filestore.New(dir), NewFileLister(dir), &RetentionPolicy{KeepLast: 1}, WithBlobSweep(s), WithBlobCache(cache))
+
+	report, err := gc.Collect(context.TODO(), 1, true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Blobs)
+	assert.Contains(t, keys(report.Objects), "/1/blobs/"+unreferenced.Hex()+".gz")
+	assert.FileExists(t, blobFile)
+
+	report, err = gc.Collect(context.TODO(), 1, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Blocks)
+	assert.Equal(t, 1, report.Blobs)
+	assert.NoFileExists(t, blobFile)
+	blobs, err := os.ReadDir(filepath.Join(dir, "1", "blobs"))
+	require.NoError(t, err)
+	assert.Len(t, blobs, 5)
+
+	// Blobs still referenced are kept
+	_, err = inputStore.LoadProverInput(context.TODO(), 1, 11)
+	require.NoError(t, err)
+
+	// Deleted blobs are removed from the cache, so they are stored again
+	require.NoError(t, inputStore.StoreProverInput(context.TODO(), inputs[0]))
+	assert.FileExists(t, blobFile)
+	_, err = inputStore.LoadProverInput(context.TODO(), 1, 10)
+	require.NoError(t, err)
+}
+
+func TestGarbageCollectorSweepBlobsGracePeriod(t *testing.T) {
+	dir := t.TempDir()
+	s := filestore.New(dir)
+	inputStore := NewProverInputStore(s, ContentTypeJSON, WithDedup())
+	inputs := testDedupInputs()
+	for _, in := range inputs {
+		require.NoError(t, inputStore.StoreProverInput(context.TODO(), in))
+	}
+	blobFile := filepath.Join(dir, "1", "blobs", crypto.Keccak256Hash(inputs[0].Witness.State[0]).Hex())
+
+	// The garbage collector runs in another process, so it does not share the blob cache of the store
+	gc := NewGarbageCollector(s, NewFileLister(dir), &RetentionPolicy{KeepLast: 1, KeepBlobsNewerThan: 2 * time.Hour}, WithBlobSweep(s)).(*garbageCollector)
+
+	// Unreferenced blobs modified within the grace period are kept
+	report, err := gc.Collect(context.TODO(), 1, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Blocks)
+	assert.Equal(t, 0, report.Blobs)
+	assert.FileExists(t, blobFile)
+
+	// They are swept by a later collection once older than the grace period
+	gc.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	report, err = gc.Collect(context.TODO(), 1, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Blobs)
+	assert.NoFileExists(t, blobFile)
+}
+
+func TestGarbageCollectorCollectRangeSweepBlobs(t *testing.T) {
+	dir := t.TempDir()
+	s := filestore.New(dir)
+	cache := NewBlobCache()
+	inputStore := NewProverInputStore(s, ContentTypeJSON, WithDedup(), WithBlobCache(cache))
+	for _, in := range testDedupInputs() {
+		require.NoError(t, inputStore.StoreProverInput(context.TODO(), in))
+	}
+
+	gc := NewGarbageCollector(s, NewFileLister(dir), &RetentionPolicy{KeepLast: 1}, WithBlobSweep(s), WithBlobCache(cache))
+
+	// Collections not deleting witness references do not sweep blobs
+	report, err := gc.CollectRange(context.TODO(), 1, 11, 11, false)
+	require.NoError(t, err)
+	assert.Empty(t, report.Objects)
+
+	report, err = gc.CollectRange(context.TODO(), 1, 10, 11, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Blocks)
+	assert.Equal(t, 1, report.Blobs)
+}
+
+func TestGarbageCollectorKeyTemplate(t *testing.T) {
+	old := gcNow.Add(-72 * time.Hour)
+	hash := "0x1111111111111111111111111111111111111111111111111111111111111111"
+	dir := newTestGCStore(t, map[string]time.Time{
+		"/chain=1/date=2025-01-01/10/zkpi.json":                       old,
+		"/chain=1/date=2025-01-01/10/trace.prepare." + hash + ".json": old,
+		"/chain=1/date=2025-01-01/10/badblock." + hash + ".json":      old,
+		"/chain=1/date=2025-01-01/11/zkpi.json":                       old,
+		"/chain=1/date=2025-01-01/11/trace.execute." + hash + ".json": old,
+		"/chain=1/date=2025-01-01/11/trace.unknown." + hash + ".json": old,
+		"/1/10/latest.zkpi":  old,
+		"/1/10/latest.trace": old,
+	})
+
+	// Traces and bad block reports are stored next to the prover input and collected with it
+	report, remaining := collect(t, dir, &RetentionPolicy{KeepLast: 1}, false, WithProverInputKey(MustParseKeyTemplate("/chain={chainID}/date={date}/{number}/zkpi.{ext}", KeyExt)))
+	assert.Equal(t, []string{
+		"/1/10/latest.trace",
+		"/1/10/latest.zkpi",
+		"/chain=1/date=2025-01-01/10/badblock." + hash + ".json",
+		"/chain=1/date=2025-01-01/10/trace.prepare." + hash + ".json",
+		"/chain=1/date=2025-01-01/10/zkpi.json",
+	}, keys(report.Objects))
+	assert.Equal(t, []string{
+		"/chain=1/date=2025-01-01/11/trace.execute." + hash + ".json",
+		"/chain=1/date=2025-01-01/11/trace.unknown." + hash + ".json",
+		"/chain=1/date=2025-01-01/11/zkpi.json",
+	}, remaining)
+}
@@ -3,13 +3,15 @@ package store
 import (
 	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 
+	gethcommon "github.com/ethereum/go-ethereum/common"
 	store "github.com/kkrt-labs/go-utils/store"
 	input "github.com/kkrt-labs/zk-pig/src/prover-input"
 	protoinput "github.com/kkrt-labs/zk-pig/src/prover-input/proto"
+	sszinput "github.com/kkrt-labs/zk-pig/src/prover-input/ssz"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -21,83 +23,287 @@ type ProverInputStore interface {
 	StoreProverInput(ctx context.Context, inputs *input.ProverInput) error
 
 	// LoadProverInput loads the prover inputs for a block.
-	// format can be "protobuf" or "json"
+	// format can be "protobuf", "json" or "ssz"
+	// Inputs stored with an older version are migrated to input.CurrentVersion, unknown versions are rejected.
 	LoadProverInput(ctx context.Context, chainID, blockNumber uint64) (*input.ProverInput, error)
+
+	// LoadProverInputByHash loads the prover inputs for a block identified by its hash.
+	// It returns an error wrapping store.ErrNotFound if the stored prover input is for another block (e.g. a reorged block).
+	LoadProverInputByHash(ctx context.Context, chainID, blockNumber uint64, blockHash gethcommon.Hash) (*input.ProverInput, error)
+
+	// MigrateProverInput upgrades the stored prover input for a block to input.CurrentVersion in place.
+	// It returns the version of the stored prover input, which is left untouched if it is input.CurrentVersion.
+	MigrateProverInput(ctx context.Context, chainID, blockNumber uint64) (string, error)
 }
 
 type proverInputStore struct {
 	store       store.Store
-	contentType store.ContentType
+	contentType ContentType
+	*options
 }
 
-func NewProverInputStore(s store.Store, contentType store.ContentType) ProverInputStore {
-	return &proverInputStore{store: s, contentType: contentType}
+func NewProverInputStore(s store.Store, contentType ContentType, opts ...Option) ProverInputStore {
+	return &proverInputStore{store: s, contentType: contentType, options: newOptions(opts...)}
 }
 
 func (s *proverInputStore) StoreProverInput(ctx context.Context, data *input.ProverInput) error {
-	buf := new(bytes.Buffer)
-	switch s.contentType {
-	case store.ContentTypeProtobuf:
-		protoMsg := protoinput.ToProto(data)
-		protoBytes, err := proto.Marshal(protoMsg)
-		if err != nil {
-			return fmt.Errorf("failed to marshal protobuf: %w", err)
-		}
-		buf.Write(protoBytes)
-	case store.ContentTypeJSON:
-		if err := json.NewEncoder(buf).Encode(data); err != nil {
-			return fmt.Errorf("failed to encode JSON: %w", err)
-		}
-	default:
-		return fmt.Errorf("unsupported content type: %s", s.contentType)
+	header := data.Blocks[0].Header
+	params := blockKeyParams(data.ChainConfig.ChainID.Uint64(), header.Number.Uint64(), header.Hash(), header.Time)
+	if err := s.storeAt(ctx, data, s.contentType, s.path(params, s.contentType), params); err != nil {
+		return err
 	}
 
-	path := s.path(data.ChainConfig.ChainID.Uint64(), data.Blocks[0].Header.Number.Uint64())
+	return storeIndex(ctx, s.store, s.proverInputKey, latestProverInput, params, header.Time)
+}
+
+// storeAt stores a prover input encoded in the given content type at path
+func (s *proverInputStore) storeAt(ctx context.Context, data *input.ProverInput, contentType ContentType, path string, params *keyParams) error {
 	headers := &store.Headers{
-		ContentType:     s.contentType,
+		ContentType:     contentType.StoreContentType(),
 		ContentEncoding: store.ContentEncodingPlain,
 		KeyValue: map[string]string{
-			"chain.id":     fmt.Sprintf("%d", data.ChainConfig.ChainID.Uint64()),
-			"block.number": fmt.Sprintf("%d", data.Blocks[0].Header.Number.Uint64()),
+			contentTypeHeader: contentType.String(),
+			"chain.id":        fmt.Sprintf("%d", params.chainID),
+			"block.number":    fmt.Sprintf("%d", params.blockNumber),
 		},
 	}
-	return s.store.Store(ctx, path, bytes.NewReader(buf.Bytes()), headers)
+
+	original := data
+	var refs []byte
+	if s.dedup && data.Witness != nil {
+		var err error
+		if data, refs, err = s.dedupWitness(ctx, data, params.chainID, path); err != nil {
+			return err
+		}
+	}
+
+	// The prover input is encoded while the store reads it, so it is never held in memory as a whole
+	// The input hash of the manifest covers the prover input with its witness, so it is not the hash of a deduplicated payload
+	digest := newPayloadDigest(contentType == ContentTypeJSON && refs == nil)
+	reader, writer := io.Pipe()
+	encodeErr := make(chan error, 1)
+	go func() {
+		err := EncodeProverInputTo(io.MultiWriter(writer, digest), data, contentType)
+		writer.CloseWithError(err)
+		encodeErr <- err
+	}()
+
+	if err := s.store.Store(ctx, path, reader, headers); err != nil {
+		reader.CloseWithError(err)
+		<-encodeErr
+		return err
+	}
+
+	// Drain the payload left unread by the store (e.g. no-op store) so the manifest covers the whole payload
+	_, _ = io.Copy(io.Discard, reader)
+	if err := <-encodeErr; err != nil {
+		return err
+	}
+
+	if s.reportSize != nil {
+		s.reportSize(ctx, contentType, digest.size)
+	}
+
+	if s.manifest != nil {
+		if err := s.storeManifest(ctx, original, contentType, digest, refs, path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// EncodeProverInput encodes a prover input in the given content type ("json", "protobuf", "protobuf-chunked" or "ssz")
+func EncodeProverInput(data *input.ProverInput, contentType ContentType) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := EncodeProverInputTo(buf, data, contentType); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
 }
 
 func (s *proverInputStore) LoadProverInput(ctx context.Context, chainID, blockNumber uint64) (*input.ProverInput, error) {
-	path := s.path(chainID, blockNumber)
+	params, err := resolve(ctx, s.store, s.proverInputKey, latestProverInput, chainID, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.load(ctx, s.path(params, s.contentType), s.contentType, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := input.Migrate(data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (s *proverInputStore) LoadProverInputByHash(ctx context.Context, chainID, blockNumber uint64, blockHash gethcommon.Hash) (*input.ProverInput, error) {
+	params, err := resolveByHash(ctx, s.store, s.proverInputKey, latestProverInput, chainID, blockNumber, blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.load(ctx, s.path(params, s.contentType), s.contentType, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkBlockHash(data.Blocks[0].Header.Hash(), blockHash); err != nil {
+		return nil, err
+	}
+
+	if _, err := input.Migrate(data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// MigrateProverInput loads the stored prover input with the content type it was stored with (which may not be the
+// configured content type), and re-stores it at the same key and with the same content type if it was migrated.
+// Latest pointers and indexes are left untouched, so migrating a reorged block does not make it the latest one.
+func (s *proverInputStore) MigrateProverInput(ctx context.Context, chainID, blockNumber uint64) (string, error) {
+	params, err := resolve(ctx, s.store, s.proverInputKey, latestProverInput, chainID, blockNumber)
+	if err != nil {
+		return "", err
+	}
+
+	path, contentType, err := s.find(ctx, params)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := s.load(ctx, path, contentType, params)
+	if err != nil {
+		return "", err
+	}
+
+	from, err := input.Migrate(data)
+	if err != nil || from == input.CurrentVersion {
+		return from, err
+	}
+
+	return from, s.storeAt(ctx, data, contentType, path, params)
+}
+
+// find returns the path and content type of a stored prover input, trying the configured content type first
+func (s *proverInputStore) find(ctx context.Context, params *keyParams) (string, ContentType, error) {
+	contentTypes := []ContentType{s.contentType}
+	for ct := range contentTypeStrings {
+		if ContentType(ct) != s.contentType {
+			contentTypes = append(contentTypes, ContentType(ct))
+		}
+	}
+
+	for _, contentType := range contentTypes {
+		path := s.path(params, contentType)
+		reader, _, err := s.store.Load(ctx, path)
+		if errors.Is(err, store.ErrNotFound) || (err == nil && reader == nil) {
+			continue
+		}
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to load data from store: %w", err)
+		}
+		reader.Close()
+		return path, contentType, nil
+	}
+
+	return "", 0, fmt.Errorf("prover input for block %d: %w", params.blockNumber, store.ErrNotFound)
+}
+
+// load loads and decodes the prover input stored at path in the given content type, without migrating it
+func (s *proverInputStore) load(ctx context.Context, path string, contentType ContentType, params *keyParams) (*input.ProverInput, error) {
+	var manifest *Manifest
+	if s.manifest != nil {
+		var err error
+		if manifest, err = s.loadManifest(ctx, path); err != nil {
+			return nil, err
+		}
+	}
+
 	reader, _, err := s.store.Load(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load data from store: %w", err)
 	}
 	defer reader.Close()
 
-	data := &input.ProverInput{}
+	// The payload is hashed while it is decoded, so it is read once
+	// The decoded prover input is only returned if the payload matches the manifest
+	var digest *payloadDigest
+	payload := io.Reader(reader)
+	if manifest != nil {
+		digest = newPayloadDigest(contentType == ContentTypeJSON && len(manifest.RefsSHA256) == 0)
+		payload = io.TeeReader(reader, digest)
+	}
+
+	data, err := DecodeProverInputFrom(payload, contentType)
+	if manifest != nil {
+		// Drain the payload left unread by the decoder so the digest covers the whole payload
+		_, readErr := io.Copy(io.Discard, payload)
 
-	switch s.contentType {
-	case store.ContentTypeJSON:
-		if err := json.NewDecoder(reader).Decode(data); err != nil {
-			return nil, fmt.Errorf("failed to decode JSON: %w", err)
+		// A payload failing to read or decode (e.g. truncated compressed payload) is reported as tampered if it does not match the manifest
+		if err := manifest.verifyDigest(digest); err != nil {
+			return nil, err
 		}
-	case store.ContentTypeProtobuf:
-		protoBytes, err := io.ReadAll(reader)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read protobuf data: %w", err)
+		if err == nil && readErr != nil {
+			return nil, fmt.Errorf("failed to read data: %w", readErr)
 		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if manifest != nil {
+		if err := manifest.VerifyInput(data); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.dedup {
+		if err := s.rehydrateWitness(ctx, data, params.chainID, path, manifest); err != nil {
+			return nil, err
+		}
+	} else if manifest != nil && len(manifest.RefsSHA256) > 0 {
+		return nil, fmt.Errorf("prover input is deduplicated, its witness can not be loaded without dedup")
+	}
+
+	// The input hash covers the prover input with its witness, so it is verified once the witness is rehydrated
+	if manifest != nil {
+		if err := manifest.verifyInputHash(data, digest.input); err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+// DecodeProverInput decodes a prover input encoded in the given content type ("json", "protobuf", "protobuf-chunked" or "ssz")
+func DecodeProverInput(b []byte, contentType ContentType) (*input.ProverInput, error) {
+	switch contentType {
+	case ContentTypeProtobuf:
 		protoMsg := &protoinput.ProverInput{}
-		if err := proto.Unmarshal(protoBytes, protoMsg); err != nil {
+		if err := proto.Unmarshal(b, protoMsg); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal protobuf: %w", err)
 		}
-		data = protoinput.FromProto(protoMsg)
+		return protoinput.FromProto(protoMsg), nil
+	case ContentTypeSSZ:
+		data, err := sszinput.Decode(b)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode SSZ: %w", err)
+		}
+		return data, nil
 	default:
-		return nil, fmt.Errorf("unsupported content type: %s", s.contentType)
+		return DecodeProverInputFrom(bytes.NewReader(b), contentType)
 	}
-
-	return data, nil
 }
 
-func (s *proverInputStore) path(chainID, blockNumber uint64) string {
-	return s.contentType.FilePath(fmt.Sprintf("/%d/%d/zkpi", chainID, blockNumber))
+func (s *proverInputStore) path(params *keyParams, contentType ContentType) string {
+	p := *params
+	p.ext = contentType.FileExtension()
+	return s.proverInputKey.execute(&p)
 }
 
 type noOpProverInputStore struct{}
@@ -110,6 +316,14 @@ func (s *noOpProverInputStore) LoadProverInput(_ context.Context, _, _ uint64) (
 	return nil, nil
 }
 
+func (s *noOpProverInputStore) LoadProverInputByHash(_ context.Context, _, _ uint64, _ gethcommon.Hash) (*input.ProverInput, error) {
+	return nil, nil
+}
+
+func (s *noOpProverInputStore) MigrateProverInput(_ context.Context, _, _ uint64) (string, error) {
+	return input.CurrentVersion, nil
+}
+
 func NewNoOpProverInputStore() ProverInputStore {
 	return &noOpProverInputStore{}
 }
@@ -12,9 +12,11 @@ import (
 	gethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/kkrt-labs/go-utils/store"
+	memorystore "github.com/kkrt-labs/go-utils/store/memory"
 	mockstore "github.com/kkrt-labs/go-utils/store/mock"
 	input "github.com/kkrt-labs/zk-pig/src/prover-input"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
@@ -26,24 +28,44 @@ func TestProverInputStore(t *testing.T) {
 
 	testCases := []struct {
 		desc        string
-		contentType store.ContentType
+		contentType ContentType
 		chainID     int64
 		blockNumber int64
 		expectedKey string
+		// Content types unknown to go-utils are stored as text so no invalid content type is set on stored objects
+		expectedContentType store.ContentType
 	}{
 		{
-			desc:        "JSON Plain File",
-			contentType: store.ContentTypeJSON,
-			chainID:     2,
-			blockNumber: 15,
-			expectedKey: "/2/15/zkpi.json",
+			desc:                "JSON Plain File",
+			contentType:         ContentTypeJSON,
+			chainID:             2,
+			blockNumber:         15,
+			expectedKey:         "/2/15/zkpi.json",
+			expectedContentType: store.ContentTypeJSON,
 		},
 		{
-			desc:        "Protobuf Plain File",
-			contentType: store.ContentTypeProtobuf,
-			chainID:     2,
-			blockNumber: 15,
-			expectedKey: "/2/15/zkpi.protobuf",
+			desc:                "Protobuf Plain File",
+			contentType:         ContentTypeProtobuf,
+			chainID:             2,
+			blockNumber:         15,
+			expectedKey:         "/2/15/zkpi.protobuf",
+			expectedContentType: store.ContentTypeProtobuf,
+		},
+		{
+			desc:                "Chunked Protobuf Plain File",
+			contentType:         ContentTypeProtobufChunked,
+			chainID:             2,
+			blockNumber:         15,
+			expectedKey:         "/2/15/zkpi.chunked.protobuf",
+			expectedContentType: store.ContentTypeText,
+		},
+		{
+			desc:                "SSZ Plain File",
+			contentType:         ContentTypeSSZ,
+			chainID:             2,
+			blockNumber:         15,
+			expectedKey:         "/2/15/zkpi.ssz",
+			expectedContentType: store.ContentTypeText,
 		},
 	}
 	for _, tt := range testCases {
@@ -71,9 +93,10 @@ func TestProverInputStore(t *testing.T) {
 			var dataCache []byte
 			ctx := context.TODO()
 			mockStore.EXPECT().Store(ctx, tt.expectedKey, gomock.Any(), &store.Headers{
-				ContentType:     tt.contentType,
+				ContentType:     tt.expectedContentType,
 				ContentEncoding: store.ContentEncodingPlain,
 				KeyValue: map[string]string{
+					"content.type": tt.contentType.String(),
 					"chain.id":     fmt.Sprintf("%d", in.ChainConfig.ChainID.Uint64()),
 					"block.number": fmt.Sprintf("%d", in.Blocks[0].Header.Number.Uint64()),
 				},
@@ -94,6 +117,62 @@ func TestProverInputStore(t *testing.T) {
 	}
 }
 
+func TestProverInputStoreVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mockstore.NewMockStore(ctrl)
+	inputStore := NewProverInputStore(mockStore, ContentTypeJSON)
+
+	t.Run("Unversioned", func(t *testing.T) {
+		data := `{"version":"","blocks":[],"witness":{"state":[],"ancestors":[],"codes":[]},"chainConfig":{"chainId":1}}`
+		mockStore.EXPECT().Load(gomock.Any(), "/1/15/zkpi.json").Return(io.NopCloser(bytes.NewReader([]byte(data))), nil, nil)
+
+		loaded, err := inputStore.LoadProverInput(context.TODO(), 1, 15)
+		require.NoError(t, err)
+		assert.Equal(t, input.CurrentVersion, loaded.Version)
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		data := `{"version":"999","blocks":[],"witness":{"state":[],"ancestors":[],"codes":[]},"chainConfig":{"chainId":1}}`
+		mockStore.EXPECT().Load(gomock.Any(), "/1/15/zkpi.json").Return(io.NopCloser(bytes.NewReader([]byte(data))), nil, nil)
+
+		_, err := inputStore.LoadProverInput(context.TODO(), 1, 15)
+		assert.ErrorIs(t, err, input.ErrUnsupportedVersion)
+	})
+}
+
+func TestProverInputStoreMigrate(t *testing.T) {
+	ctx := context.TODO()
+	s := memorystore.New()
+	// The prover input was stored in JSON while the store is now configured with protobuf
+	inputStore := NewProverInputStore(s, ContentTypeProtobuf)
+
+	data := `{"version":"","blocks":[],"witness":{"state":[],"ancestors":[],"codes":[]},"chainConfig":{"chainId":1}}`
+	require.NoError(t, s.Store(ctx, "/1/15/zkpi.json", bytes.NewReader([]byte(data)), &store.Headers{ContentType: store.ContentTypeJSON}))
+
+	from, err := inputStore.MigrateProverInput(ctx, 1, 15)
+	require.NoError(t, err)
+	assert.Equal(t, input.VersionUnversioned, from)
+
+	// The prover input is re-stored in place, with its original content type
+	reader, _, err := s.Load(ctx, "/1/15/zkpi.json")
+	require.NoError(t, err)
+	b, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	migrated, err := DecodeProverInput(b, ContentTypeJSON)
+	require.NoError(t, err)
+	assert.Equal(t, input.CurrentVersion, migrated.Version)
+
+	_, _, err = s.Load(ctx, "/1/15/zkpi.protobuf")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+
+	// Prover inputs at the current version are left untouched
+	from, err = inputStore.MigrateProverInput(ctx, 1, 15)
+	require.NoError(t, err)
+	assert.Equal(t, input.CurrentVersion, from)
+}
+
 func TestNoOpProverInputStore(t *testing.T) {
 	noOpStore := NewNoOpProverInputStore()
 	// Should implement interface
@@ -104,3 +183,26 @@ func TestNoOpProverInputStore(t *testing.T) {
 	assert.Nil(t, loaded)
 	assert.NoError(t, err)
 }
+
+func TestProverInputStoreSizeReporter(t *testing.T) {
+	var (
+		reportedContentType ContentType
+		reportedSize        int
+	)
+	s := memorystore.New()
+	inputStore := NewProverInputStore(s, ContentTypeProtobuf, WithSizeReporter(func(_ context.Context, contentType ContentType, size int) {
+		reportedContentType, reportedSize = contentType, size
+	}))
+
+	in := &input.ProverInput{
+		ChainConfig: &params.ChainConfig{ChainID: big.NewInt(1)},
+		Blocks:      []*input.Block{{Header: &gethtypes.Header{Number: big.NewInt(10), Difficulty: big.NewInt(0)}}},
+	}
+	require.NoError(t, inputStore.StoreProverInput(context.TODO(), in))
+
+	// The reported size is the one of the stored payload
+	b, err := EncodeProverInput(in, ContentTypeProtobuf)
+	require.NoError(t, err)
+	assert.Equal(t, ContentTypeProtobuf, reportedContentType)
+	assert.Equal(t, len(b), reportedSize)
+}
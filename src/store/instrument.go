@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 
+	gethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/kkrt-labs/go-utils/app/svc"
 	ethrpc "github.com/kkrt-labs/go-utils/ethereum/rpc"
 	"github.com/kkrt-labs/go-utils/log"
@@ -37,8 +38,17 @@ func (s *taggedProverInputStore) LoadProverInput(ctx context.Context, chainID, b
 	return s.s.LoadProverInput(s.context(ctx, chainID, blockNumber), chainID, blockNumber)
 }
 
-func (s *taggedProverInputStore) context(ctx context.Context, chainID, blockNumber uint64) context.Context {
-	return s.tagged.Context(ctx, tag.Key("chain.id").Int64(int64(chainID)), tag.Key("block.number").Int64(int64(blockNumber)))
+func (s *taggedProverInputStore) LoadProverInputByHash(ctx context.Context, chainID, blockNumber uint64, blockHash gethcommon.Hash) (*input.ProverInput, error) {
+	return s.s.LoadProverInputByHash(s.context(ctx, chainID, blockNumber, tag.Key("block.hash").String(blockHash.Hex())), chainID, blockNumber, blockHash)
+}
+
+func (s *taggedProverInputStore) MigrateProverInput(ctx context.Context, chainID, blockNumber uint64) (string, error) {
+	return s.s.MigrateProverInput(s.context(ctx, chainID, blockNumber), chainID, blockNumber)
+}
+
+func (s *taggedProverInputStore) context(ctx context.Context, chainID, blockNumber uint64, tags ...*tag.Tag) context.Context {
+	tags = append([]*tag.Tag{tag.Key("chain.id").Int64(int64(chainID)), tag.Key("block.number").Int64(int64(blockNumber))}, tags...)
+	return s.tagged.Context(ctx, tags...)
 }
 
 type loggedProverInputStore struct {
@@ -71,6 +81,26 @@ func (s *loggedProverInputStore) LoadProverInput(ctx context.Context, chainID, b
 	return inputs, err
 }
 
+func (s *loggedProverInputStore) LoadProverInputByHash(ctx context.Context, chainID, blockNumber uint64, blockHash gethcommon.Hash) (*input.ProverInput, error) {
+	log.LoggerFromContext(ctx).Debug("Loading prover input by hash")
+	inputs, err := s.s.LoadProverInputByHash(ctx, chainID, blockNumber, blockHash)
+	if err != nil {
+		log.LoggerFromContext(ctx).Error("Failed to load prover input by hash", zap.Error(err))
+	}
+	log.LoggerFromContext(ctx).Debug("Prover input successfully loaded by hash")
+	return inputs, err
+}
+
+func (s *loggedProverInputStore) MigrateProverInput(ctx context.Context, chainID, blockNumber uint64) (string, error) {
+	log.LoggerFromContext(ctx).Debug("Migrating prover input")
+	from, err := s.s.MigrateProverInput(ctx, chainID, blockNumber)
+	if err != nil {
+		log.LoggerFromContext(ctx).Error("Failed to migrate prover input", zap.Error(err))
+	}
+	log.LoggerFromContext(ctx).Debug("Prover input successfully migrated", zap.String("from", from))
+	return from, err
+}
+
 type taggedPreflightDataStore struct {
 	s      PreflightDataStore
 	tagged *svc.Tagged
@@ -95,8 +125,13 @@ func (s *taggedPreflightDataStore) LoadPreflightData(ctx context.Context, chainI
 	return s.s.LoadPreflightData(s.context(ctx, chainID, blockNumber), chainID, blockNumber)
 }
 
-func (s *taggedPreflightDataStore) context(ctx context.Context, chainID, blockNumber uint64) context.Context {
-	return s.tagged.Context(ctx, tag.Key("chain.id").Int64(int64(chainID)), tag.Key("block.number").Int64(int64(blockNumber)))
+func (s *taggedPreflightDataStore) LoadPreflightDataByHash(ctx context.Context, chainID, blockNumber uint64, blockHash gethcommon.Hash) (*steps.PreflightData, error) {
+	return s.s.LoadPreflightDataByHash(s.context(ctx, chainID, blockNumber, tag.Key("block.hash").String(blockHash.Hex())), chainID, blockNumber, blockHash)
+}
+
+func (s *taggedPreflightDataStore) context(ctx context.Context, chainID, blockNumber uint64, tags ...*tag.Tag) context.Context {
+	tags = append([]*tag.Tag{tag.Key("chain.id").Int64(int64(chainID)), tag.Key("block.number").Int64(int64(blockNumber))}, tags...)
+	return s.tagged.Context(ctx, tags...)
 }
 
 type loggedPreflightDataStore struct {
@@ -129,6 +164,16 @@ func (s *loggedPreflightDataStore) LoadPreflightData(ctx context.Context, chainI
 	return data, err
 }
 
+func (s *loggedPreflightDataStore) LoadPreflightDataByHash(ctx context.Context, chainID, blockNumber uint64, blockHash gethcommon.Hash) (*steps.PreflightData, error) {
+	log.LoggerFromContext(ctx).Debug("Loading preflight data by hash")
+	data, err := s.s.LoadPreflightDataByHash(ctx, chainID, blockNumber, blockHash)
+	if err != nil {
+		log.LoggerFromContext(ctx).Error("Failed to load preflight data by hash", zap.Error(err))
+	}
+	log.LoggerFromContext(ctx).Debug("Preflight data successfully loaded by hash")
+	return data, err
+}
+
 type taggedBlockStore struct {
 	s      BlockStore
 	tagged *svc.Tagged
@@ -0,0 +1,323 @@
+package store
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// Placeholders of key templates
+const (
+	KeyChainID      = "{chainID}"      // Chain ID (e.g. 1)
+	KeyNumber       = "{number}"       // Block number (e.g. 1234)
+	KeyPaddedNumber = "{paddedNumber}" // Block number zero-padded to 12 digits (e.g. 000000001234)
+	KeyHash         = "{hash}"         // Block hash (e.g. 0x...)
+	KeyDate         = "{date}"         // UTC date of the block timestamp (e.g. 2026-10-17)
+	KeyExt          = "{ext}"          // File extension of the content type (e.g. json, protobuf, ssz)
+)
+
+// keyPlaceholderPatterns maps placeholders to the pattern of their values
+var keyPlaceholderPatterns = map[string]string{
+	KeyChainID:      `[0-9]+`,
+	KeyNumber:       `[0-9]+`,
+	KeyPaddedNumber: `[0-9]+`,
+	KeyHash:         `0x[0-9a-fA-F]{64}`,
+	KeyDate:         `[0-9]{4}-[0-9]{2}-[0-9]{2}`,
+	KeyExt:          `[a-z0-9.]+`,
+}
+
+var keyPlaceholderRegexp = regexp.MustCompile(`\{[a-zA-Z]+\}`)
+
+const dateLayout = "2006-01-02"
+
+// Default key templates
+var (
+	DefaultProverInputKey   = MustParseKeyTemplate("/{chainID}/{number}/zkpi.{ext}")
+	DefaultPreflightDataKey = MustParseKeyTemplate("/{chainID}/{number}/preflight.json")
+	DefaultBlockKey         = MustParseKeyTemplate("/{chainID}/blocks/{number}.json")
+
+	hashProverInputKey   = MustParseKeyTemplate("/{chainID}/{number}/{hash}/zkpi.{ext}")
+	hashPreflightDataKey = MustParseKeyTemplate("/{chainID}/{number}/{hash}/preflight.json")
+)
+
+// KeyTemplate is a template of the keys of a block artifact in the store (e.g. "/chain={chainID}/date={date}/{number}/zkpi.{ext}")
+type KeyTemplate struct {
+	template string
+	re       *regexp.Regexp
+}
+
+// ParseKeyTemplate parses a key template
+// A key template must contain {chainID}, {number} or {paddedNumber}, and the given required placeholders.
+// A leading "/" is added if missing.
+func ParseKeyTemplate(template string, required ...string) (*KeyTemplate, error) {
+	if !strings.HasPrefix(template, "/") {
+		template = "/" + template
+	}
+
+	pattern := new(strings.Builder)
+	pattern.WriteString("^")
+	last := 0
+	for _, loc := range keyPlaceholderRegexp.FindAllStringIndex(template, -1) {
+		placeholder := template[loc[0]:loc[1]]
+		p, ok := keyPlaceholderPatterns[placeholder]
+		if !ok {
+			return nil, fmt.Errorf("invalid key template %q: unknown placeholder %s", template, placeholder)
+		}
+		pattern.WriteString(regexp.QuoteMeta(template[last:loc[0]]))
+		fmt.Fprintf(pattern, "(?P<%s>%s)", strings.Trim(placeholder, "{}"), p)
+		last = loc[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(template[last:]))
+	pattern.WriteString("$")
+
+	t := &KeyTemplate{template: template, re: regexp.MustCompile(pattern.String())}
+
+	if !t.has(KeyChainID) {
+		return nil, fmt.Errorf("invalid key template %q: missing %s", template, KeyChainID)
+	}
+	if !t.has(KeyNumber) && !t.has(KeyPaddedNumber) {
+		return nil, fmt.Errorf("invalid key template %q: missing %s or %s", template, KeyNumber, KeyPaddedNumber)
+	}
+	for _, placeholder := range required {
+		if !t.has(placeholder) {
+			return nil, fmt.Errorf("invalid key template %q: missing %s", template, placeholder)
+		}
+	}
+
+	return t, nil
+}
+
+// MustParseKeyTemplate parses a key template and panics if it is invalid
+func MustParseKeyTemplate(template string, required ...string) *KeyTemplate {
+	t, err := ParseKeyTemplate(template, required...)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func (t *KeyTemplate) String() string {
+	return t.template
+}
+
+func (t *KeyTemplate) has(placeholder string) bool {
+	return strings.Contains(t.template, placeholder)
+}
+
+// needsIndex returns true if keys can not be computed from the block number only
+// in which case loads by number resolve the block through the latest pointer
+func (t *KeyTemplate) needsIndex() bool {
+	return t.has(KeyHash) || t.has(KeyDate)
+}
+
+// siblingKey returns the template of an artifact stored in the directory of the keys of t (e.g. "/{chainID}/{number}/trace.execute.{hash}.json" for "/{chainID}/{number}/zkpi.{ext}")
+// Placeholders identifying the block which the directory does not contain are appended to the name,
+// so artifacts of competing blocks at the same height do not overwrite each other.
+func (t *KeyTemplate) siblingKey(name, ext string) *KeyTemplate {
+	dir := t.template[:strings.LastIndex(t.template, "/")+1]
+	if !strings.Contains(dir, KeyChainID) {
+		name += "." + KeyChainID
+	}
+	if !strings.Contains(dir, KeyNumber) && !strings.Contains(dir, KeyPaddedNumber) {
+		name += "." + KeyNumber
+	}
+	if !strings.Contains(dir, KeyHash) {
+		name += "." + KeyHash
+	}
+	return MustParseKeyTemplate(dir + name + "." + ext)
+}
+
+// latestKey returns the template of the latest pointers of an artifact which keys follow t
+// As pointers resolve {hash} and {date}, they are stored in the directory of t truncated before its first segment containing one of them
+// (e.g. "/chain={chainID}/{number}/latest.zkpi" for "/chain={chainID}/{number}/date={date}/zkpi.{ext}").
+// Pointers of templates which truncated directory does not identify the block number are stored at "/{chainID}/{number}/latest.<artifact>".
+func (t *KeyTemplate) latestKey(artifact string) *KeyTemplate {
+	dir := t.template[:strings.LastIndex(t.template, "/")+1]
+	for _, placeholder := range []string{KeyHash, KeyDate} {
+		if i := strings.Index(dir, placeholder); i >= 0 {
+			dir = dir[:strings.LastIndex(dir[:i], "/")+1]
+		}
+	}
+
+	name := latestFileName + "." + artifact
+	if latest, err := ParseKeyTemplate(dir + name); err == nil {
+		return latest
+	}
+	return MustParseKeyTemplate("/" + KeyChainID + "/" + KeyNumber + "/" + name)
+}
+
+// keyParams are the values of the placeholders of a key
+type keyParams struct {
+	chainID     uint64
+	blockNumber uint64
+	blockHash   *gethcommon.Hash // Unset if the key does not contain the block hash
+	date        string           // Unset if the key does not contain the date
+	ext         string
+}
+
+func blockDate(timestamp uint64) string {
+	return time.Unix(int64(timestamp), 0).UTC().Format(dateLayout) //nolint:gosec // block timestamps fit in int64
+}
+
+// execute returns the key for the given placeholder values
+func (t *KeyTemplate) execute(p *keyParams) string {
+	var blockHash string
+	if p.blockHash != nil {
+		blockHash = p.blockHash.Hex()
+	}
+
+	return strings.NewReplacer(
+		KeyChainID, strconv.FormatUint(p.chainID, 10),
+		KeyNumber, strconv.FormatUint(p.blockNumber, 10),
+		KeyPaddedNumber, fmt.Sprintf("%012d", p.blockNumber),
+		KeyHash, blockHash,
+		KeyDate, p.date,
+		KeyExt, p.ext,
+	).Replace(t.template)
+}
+
+// match parses a key generated by the template
+func (t *KeyTemplate) match(key string) (*keyParams, bool) {
+	m := t.re.FindStringSubmatch(key)
+	if m == nil {
+		return nil, false
+	}
+
+	p := new(keyParams)
+	for i, name := range t.re.SubexpNames() {
+		var err error
+		switch "{" + name + "}" {
+		case KeyChainID:
+			p.chainID, err = strconv.ParseUint(m[i], 10, 64)
+		case KeyNumber, KeyPaddedNumber:
+			p.blockNumber, err = strconv.ParseUint(m[i], 10, 64)
+		case KeyHash:
+			if !isHashHex(m[i]) {
+				return nil, false
+			}
+			blockHash := gethcommon.HexToHash(m[i])
+			p.blockHash = &blockHash
+		case KeyDate:
+			p.date = m[i]
+		case KeyExt:
+			p.ext = m[i]
+		}
+		if err != nil {
+			return nil, false
+		}
+	}
+
+	return p, true
+}
+
+// prefix returns the prefix of all the keys of a chain (i.e. the template up to its first placeholder other than {chainID})
+func (t *KeyTemplate) prefix(chainID uint64) string {
+	template := strings.ReplaceAll(t.template, KeyChainID, strconv.FormatUint(chainID, 10))
+	if loc := keyPlaceholderRegexp.FindStringIndex(template); loc != nil {
+		return template[:loc[0]]
+	}
+	return template
+}
+
+// maxRangePrefixes is the maximum number of prefixes a block range is listed with before listing the chain prefix
+const maxRangePrefixes = 64
+
+// rangePrefixes returns prefixes covering the keys of the blocks of a chain with a number in [fromBlock, toBlock]
+// Prefixes are the common prefixes of the block numbers in the range (e.g. "/1/12" for blocks 120 to 129),
+// so keys of blocks outside the range (e.g. "/1/1234/zkpi.json") may also match and must be filtered out after listing.
+// It returns the prefix of the chain if the block number is not the first placeholder after {chainID}.
+func (t *KeyTemplate) rangePrefixes(chainID, fromBlock, toBlock uint64) []string {
+	chainPrefix := t.prefix(chainID)
+	template := strings.ReplaceAll(t.template, KeyChainID, strconv.FormatUint(chainID, 10))
+	loc := keyPlaceholderRegexp.FindStringIndex(template)
+	if loc == nil || fromBlock > toBlock {
+		return []string{chainPrefix}
+	}
+
+	var format func(uint64) string
+	switch template[loc[0]:loc[1]] {
+	case KeyNumber:
+		format = func(n uint64) string { return strconv.FormatUint(n, 10) }
+	case KeyPaddedNumber:
+		format = func(n uint64) string { return fmt.Sprintf("%012d", n) }
+	default:
+		return []string{chainPrefix}
+	}
+
+	// Prefixes of complete block numbers are followed by the literal after the placeholder (e.g. "/1/12/")
+	suffix := template[loc[1]:]
+	if next := keyPlaceholderRegexp.FindStringIndex(suffix); next != nil {
+		suffix = suffix[:next[0]]
+	}
+
+	// Split the range by number of digits as common prefixes only apply to numbers of the same length
+	var prefixes []string
+	for from := fromBlock; ; {
+		lo := format(from)
+		to := min(toBlock, maxNumberOfLength(len(lo)))
+		for _, p := range numberPrefixes(lo, format(to)) {
+			if p == "" {
+				return []string{chainPrefix}
+			}
+			if len(p) == len(lo) {
+				p += suffix
+			}
+			prefixes = append(prefixes, chainPrefix+p)
+		}
+		if to == toBlock {
+			break
+		}
+		from = to + 1
+	}
+
+	prefixes = coverPrefixes(prefixes)
+	if len(prefixes) > maxRangePrefixes {
+		return []string{chainPrefix}
+	}
+	return prefixes
+}
+
+// maxNumberOfLength returns the largest number with the given number of decimal digits
+func maxNumberOfLength(length int) uint64 {
+	n := uint64(9)
+	for i := 1; i < length; i++ {
+		if n > (math.MaxUint64-9)/10 {
+			return math.MaxUint64
+		}
+		n = n*10 + 9
+	}
+	return n
+}
+
+// numberPrefixes returns the common prefixes of the decimal numbers in [lo, hi], which have the same number of digits
+// An empty prefix means all the numbers of that length (e.g. "0" to "9").
+func numberPrefixes(lo, hi string) []string {
+	if lo == hi {
+		return []string{lo}
+	}
+	if strings.Trim(lo, "0") == "" && strings.Trim(hi, "9") == "" {
+		return []string{""}
+	}
+	if lo[0] == hi[0] {
+		return prependAll(lo[:1], numberPrefixes(lo[1:], hi[1:]))
+	}
+
+	prefixes := prependAll(lo[:1], numberPrefixes(lo[1:], strings.Repeat("9", len(lo)-1)))
+	for d := lo[0] + 1; d < hi[0]; d++ {
+		prefixes = append(prefixes, string(d))
+	}
+	return append(prefixes, prependAll(hi[:1], numberPrefixes(strings.Repeat("0", len(hi)-1), hi[1:]))...)
+}
+
+func prependAll(prefix string, s []string) []string {
+	res := make([]string, len(s))
+	for i := range s {
+		res[i] = prefix + s[i]
+	}
+	return res
+}
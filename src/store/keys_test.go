@@ -0,0 +1,163 @@
+package store
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	filestore "github.com/kkrt-labs/go-utils/store/file"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKeyTemplate(t *testing.T) {
+	tmpl, err := ParseKeyTemplate("chain={chainID}/date={date}/{paddedNumber}/zkpi.{ext}", KeyExt)
+	require.NoError(t, err)
+	assert.Equal(t, "/chain={chainID}/date={date}/{paddedNumber}/zkpi.{ext}", tmpl.String())
+	assert.True(t, tmpl.needsIndex())
+	assert.False(t, DefaultProverInputKey.needsIndex())
+
+	for _, invalid := range []string{
+		"/{number}/zkpi.{ext}",              // missing chain ID
+		"/{chainID}/zkpi.{ext}",             // missing number
+		"/{chainID}/{number}/zkpi.json",     // missing required ext
+		"/{chainID}/{number}/{block}.{ext}", // unknown placeholder
+	} {
+		_, err := ParseKeyTemplate(invalid, KeyExt)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestKeyTemplate(t *testing.T) {
+	tmpl := MustParseKeyTemplate("/chain={chainID}/date={date}/{paddedNumber}/{hash}/zkpi.{ext}")
+	hash := gethcommon.HexToHash("0x1234")
+	timestamp := uint64(time.Date(2026, 10, 17, 23, 59, 59, 0, time.UTC).Unix())
+
+	params := blockKeyParams(1, 1234, hash, timestamp)
+	params.ext = "json"
+	key := tmpl.execute(params)
+	assert.Equal(t, "/chain=1/date=2026-10-17/000000001234/"+hash.Hex()+"/zkpi.json", key)
+
+	parsed, ok := tmpl.match(key)
+	require.True(t, ok)
+	assert.Equal(t, params, parsed)
+
+	_, ok = tmpl.match("/chain=1/date=2026-10-17/000000001234/zkpi.json")
+	assert.False(t, ok)
+
+	assert.Equal(t, "/chain=1/date=", tmpl.prefix(1))
+	assert.Equal(t, "/1/", DefaultProverInputKey.prefix(1))
+	assert.Equal(t, "/1/blocks/", DefaultBlockKey.prefix(1))
+}
+
+func TestKeyTemplateLatestKey(t *testing.T) {
+	tests := []struct {
+		template string
+		artifact string
+		expected string
+	}{
+		{DefaultProverInputKey.String(), latestProverInput, "/{chainID}/{number}/latest.zkpi"},
+		{hashProverInputKey.String(), latestProverInput, "/{chainID}/{number}/latest.zkpi"},
+		{"/zkpi/{chainID}/{paddedNumber}/{hash}.{ext}", latestProverInput, "/zkpi/{chainID}/{paddedNumber}/latest.zkpi"},
+		{"/chain={chainID}/{number}/date={date}/preflight.json", latestPreflightData, "/chain={chainID}/{number}/latest.preflight"},
+		// Pointers of templates which directory does not identify the block number before {hash} or {date} fall back to the default directory
+		{"/chain={chainID}/date={date}/{number}/zkpi.{ext}", latestProverInput, "/{chainID}/{number}/latest.zkpi"},
+		{DefaultBlockKey.String(), latestBlock, "/{chainID}/{number}/latest.block"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.template, func(t *testing.T) {
+			assert.Equal(t, test.expected, MustParseKeyTemplate(test.template).latestKey(test.artifact).String())
+		})
+	}
+
+	// Stores and the garbage collector follow the pointer key
+	dir := t.TempDir()
+	s := filestore.New(dir)
+	opts := []Option{WithProverInputKey(MustParseKeyTemplate("/chain={chainID}/{number}/{hash}/zkpi.{ext}", KeyExt))}
+	inputStore := NewProverInputStore(s, ContentTypeJSON, opts...)
+	in, _ := testLayoutInputs(10)
+	require.NoError(t, inputStore.StoreProverInput(context.TODO(), in))
+
+	_, _, err := s.Load(context.TODO(), "/chain=1/10/latest.zkpi")
+	require.NoError(t, err)
+	_, err = inputStore.LoadProverInput(context.TODO(), 1, 10)
+	require.NoError(t, err)
+
+	gc := NewGarbageCollector(s, NewFileLister(dir), &RetentionPolicy{KeepLast: 1}, opts...).(*garbageCollector)
+	objects, err := gc.list(context.TODO(), 1)
+	require.NoError(t, err)
+	blocks := gc.groupByBlock(1, 0, math.MaxUint64, objects)
+	require.Len(t, blocks, 1)
+	assert.Len(t, blocks[0].objects, 2)
+}
+
+func TestDatePartitionedKeys(t *testing.T) {
+	dir := t.TempDir()
+	s := filestore.New(dir)
+	opts := []Option{
+		WithProverInputKey(MustParseKeyTemplate("/chain={chainID}/date={date}/{number}/zkpi.{ext}", KeyExt)),
+		WithPreflightDataKey(MustParseKeyTemplate("/chain={chainID}/date={date}/{number}/preflight.json")),
+	}
+
+	catalog := NewProverInputCatalog(s, NewFileLister(dir), "v0.0.1", opts...)
+	inputStore := ProverInputStoreWithCatalog(NewProverInputStore(s, ContentTypeJSON, opts...), catalog)
+
+	in, _ := testLayoutInputs(10)
+	in.Blocks[0].Header.Time = uint64(time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC).Unix())
+	require.NoError(t, inputStore.StoreProverInput(context.TODO(), in))
+
+	_, _, err := s.Load(context.TODO(), "/chain=1/date=2026-10-17/10/zkpi.json")
+	require.NoError(t, err)
+
+	// Loads by number resolve the date through the latest pointer
+	loaded, err := inputStore.LoadProverInput(context.TODO(), 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, in.Blocks[0].Header.Hash(), loaded.Blocks[0].Header.Hash())
+
+	loaded, err = inputStore.LoadProverInputByHash(context.TODO(), 1, 10, in.Blocks[0].Header.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, in.Blocks[0].Header.Hash(), loaded.Blocks[0].Header.Hash())
+
+	entries, err := catalog.ListProverInputs(context.TODO(), 1, 0, 100)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, uint64(10), entries[0].BlockNumber)
+	assert.Equal(t, "v0.0.1", entries[0].Version)
+
+	// Garbage collection groups the artifacts and the latest pointer of the block
+	gc := NewGarbageCollector(s, NewFileLister(dir), &RetentionPolicy{KeepLast: 1}, opts...).(*garbageCollector)
+	objects, err := gc.list(context.TODO(), 1)
+	require.NoError(t, err)
+	blocks := gc.groupByBlock(1, 0, math.MaxUint64, objects)
+	require.Len(t, blocks, 1)
+	assert.Equal(t, uint64(10), blocks[0].number)
+	assert.Len(t, blocks[0].objects, 3)
+}
+
+func TestKeyTemplateRangePrefixes(t *testing.T) {
+	tests := []struct {
+		desc     string
+		tmpl     *KeyTemplate
+		from, to uint64
+		expected []string
+	}{
+		{desc: "single block", tmpl: DefaultProverInputKey, from: 1234, to: 1234, expected: []string{"/1/1234/zkpi."}},
+		{desc: "aligned range", tmpl: DefaultProverInputKey, from: 1230, to: 1239, expected: []string{"/1/123"}},
+		{desc: "unaligned range", tmpl: DefaultProverInputKey, from: 1228, to: 1241, expected: []string{"/1/1228/zkpi.", "/1/1229/zkpi.", "/1/123", "/1/1240/zkpi.", "/1/1241/zkpi."}},
+		{desc: "range across number lengths", tmpl: DefaultProverInputKey, from: 98, to: 101, expected: []string{"/1/100/zkpi.", "/1/101/zkpi.", "/1/98/zkpi.", "/1/99/zkpi."}},
+		{desc: "hash layout", tmpl: hashProverInputKey, from: 120, to: 129, expected: []string{"/1/12"}},
+		{desc: "block key", tmpl: DefaultBlockKey, from: 10, to: 11, expected: []string{"/1/blocks/10.json", "/1/blocks/11.json"}},
+		{desc: "padded number", tmpl: MustParseKeyTemplate("/{chainID}/{paddedNumber}/zkpi.{ext}"), from: 1200, to: 1299, expected: []string{"/1/0000000012"}},
+		{desc: "full range", tmpl: DefaultProverInputKey, from: 0, to: math.MaxUint64, expected: []string{"/1/"}},
+		{desc: "number after date", tmpl: MustParseKeyTemplate("/{chainID}/{date}/{number}/zkpi.{ext}"), from: 10, to: 10, expected: []string{"/1/"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.tmpl.rangePrefixes(1, test.from, test.to))
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	store "github.com/kkrt-labs/go-utils/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// kvObjectsBucket holds the content of the objects by key
+	kvObjectsBucket = []byte("objects")
+
+	// kvModifiedBucket holds the last modification time of the objects by key (unix nanoseconds, big endian)
+	kvModifiedBucket = []byte("modified")
+)
+
+// KVStore is a store keeping all objects in a single embedded key-value database (bbolt) file
+//
+// Every write is atomic (an object is either fully stored or not at all) and objects can be listed by prefix
+// without walking a directory tree, which makes it suitable for long backfills producing millions of objects.
+// The database is opened on Start and closed on Stop. It can only be opened by one process at a time.
+type KVStore struct {
+	path string
+	db   *bolt.DB
+	now  func() time.Time
+}
+
+// NewKVStore creates a store over the database file at the given path (created on Start if it does not exist)
+func NewKVStore(path string) *KVStore {
+	return &KVStore{path: path, now: time.Now}
+}
+
+// Start opens the database
+func (s *KVStore) Start(_ context.Context) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	db, err := bolt.Open(s.path, 0o644, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return fmt.Errorf("failed to open kv store %q: %w", s.path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{kvObjectsBucket, kvModifiedBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return fmt.Errorf("failed to initialize kv store: %w", err)
+	}
+
+	s.db = db
+
+	return nil
+}
+
+// Stop closes the database
+func (s *KVStore) Stop(_ context.Context) error {
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
+
+func (s *KVStore) update(fn func(objects, modified *bolt.Bucket) error) error {
+	if s.db == nil {
+		return fmt.Errorf("kv store %q is not open", s.path)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return fn(tx.Bucket(kvObjectsBucket), tx.Bucket(kvModifiedBucket))
+	})
+}
+
+func (s *KVStore) view(fn func(objects, modified *bolt.Bucket) error) error {
+	if s.db == nil {
+		return fmt.Errorf("kv store %q is not open", s.path)
+	}
+	return s.db.View(func(tx *bolt.Tx) error {
+		return fn(tx.Bucket(kvObjectsBucket), tx.Bucket(kvModifiedBucket))
+	})
+}
+
+// Store stores an object in a single transaction
+func (s *KVStore) Store(_ context.Context, key string, reader io.Reader, _ *store.Headers) error {
+	b, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("failed to read object: %w", err)
+	}
+
+	return s.update(func(objects, modified *bolt.Bucket) error {
+		return s.put(objects, modified, key, b)
+	})
+}
+
+func (s *KVStore) put(objects, modified *bolt.Bucket, key string, b []byte) error {
+	if err := objects.Put([]byte(key), b); err != nil {
+		return fmt.Errorf("failed to store object: %w", err)
+	}
+
+	modTime := make([]byte, 8)
+	binary.BigEndian.PutUint64(modTime, uint64(s.now().UnixNano()))
+	if err := modified.Put([]byte(key), modTime); err != nil {
+		return fmt.Errorf("failed to store object modification time: %w", err)
+	}
+
+	return nil
+}
+
+// Load loads an object
+// It is the responsibility of the caller to close the returned reader
+func (s *KVStore) Load(_ context.Context, key string) (io.ReadCloser, *store.Headers, error) {
+	var b []byte
+	err := s.view(func(objects, _ *bolt.Bucket) error {
+		v := objects.Get([]byte(key))
+		if v == nil {
+			return store.ErrNotFound
+		}
+		// Values are only valid during the transaction
+		b = bytes.Clone(v)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return io.NopCloser(bytes.NewReader(b)), nil, nil
+}
+
+// Delete deletes an object
+func (s *KVStore) Delete(_ context.Context, key string) error {
+	return s.update(func(objects, modified *bolt.Bucket) error {
+		if objects.Get([]byte(key)) == nil {
+			return store.ErrNotFound
+		}
+		if err := objects.Delete([]byte(key)); err != nil {
+			return err
+		}
+		return modified.Delete([]byte(key))
+	})
+}
+
+// Copy copies an object in a single transaction
+func (s *KVStore) Copy(_ context.Context, srcKey, dstKey string) error {
+	return s.update(func(objects, modified *bolt.Bucket) error {
+		v := objects.Get([]byte(srcKey))
+		if v == nil {
+			return store.ErrNotFound
+		}
+		return s.put(objects, modified, dstKey, bytes.Clone(v))
+	})
+}
+
+// List returns the objects which key starts with the given prefix, in lexical order of keys
+func (s *KVStore) List(_ context.Context, prefix string) ([]*Object, error) {
+	objects := make([]*Object, 0)
+	err := s.view(func(objectsBucket, modified *bolt.Bucket) error {
+		c := objectsBucket.Cursor()
+		for k, v := c.Seek([]byte(prefix)); k != nil && strings.HasPrefix(string(k), prefix); k, v = c.Next() {
+			o := &Object{Key: string(k), Size: int64(len(v))}
+			if modTime := modified.Get(k); len(modTime) == 8 {
+				o.LastModified = time.Unix(0, int64(binary.BigEndian.Uint64(modTime)))
+			}
+			objects = append(objects, o)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list kv store objects: %w", err)
+	}
+
+	return objects, nil
+}
@@ -0,0 +1,117 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	store "github.com/kkrt-labs/go-utils/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKVStore(t *testing.T) *KVStore {
+	s := NewKVStore(filepath.Join(t.TempDir(), "data", "zkpig.db"))
+	require.NoError(t, s.Start(context.TODO()))
+	t.Cleanup(func() { require.NoError(t, s.Stop(context.TODO())) })
+	return s
+}
+
+func TestKVStore(t *testing.T) {
+	s := newTestKVStore(t)
+
+	_, _, err := s.Load(context.TODO(), "/1/10/zkpi.json")
+	require.ErrorIs(t, err, store.ErrNotFound)
+
+	require.NoError(t, s.Store(context.TODO(), "/1/10/zkpi.json", bytes.NewReader([]byte("data")), nil))
+	reader, _, err := s.Load(context.TODO(), "/1/10/zkpi.json")
+	require.NoError(t, err)
+	b, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("data"), b)
+
+	require.NoError(t, s.Copy(context.TODO(), "/1/10/zkpi.json", "/1/11/zkpi.json"))
+	reader, _, err = s.Load(context.TODO(), "/1/11/zkpi.json")
+	require.NoError(t, err)
+	b, err = io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("data"), b)
+
+	require.NoError(t, s.Delete(context.TODO(), "/1/10/zkpi.json"))
+	_, _, err = s.Load(context.TODO(), "/1/10/zkpi.json")
+	require.ErrorIs(t, err, store.ErrNotFound)
+	require.ErrorIs(t, s.Delete(context.TODO(), "/1/10/zkpi.json"), store.ErrNotFound)
+	require.ErrorIs(t, s.Copy(context.TODO(), "/1/10/zkpi.json", "/1/12/zkpi.json"), store.ErrNotFound)
+}
+
+func TestKVStoreList(t *testing.T) {
+	s := newTestKVStore(t)
+	modTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return modTime }
+
+	for _, key := range []string{"/1/10/zkpi.json", "/1/2/zkpi.json.gz", "/1/2/trace.json", "/10/2/zkpi.json"} {
+		require.NoError(t, s.Store(context.TODO(), key, bytes.NewReader([]byte("data")), nil))
+	}
+
+	objects, err := s.List(context.TODO(), "/1/")
+	require.NoError(t, err)
+	for _, o := range objects {
+		o.LastModified = o.LastModified.UTC()
+	}
+	assert.Equal(t, []*Object{
+		{Key: "/1/10/zkpi.json", Size: 4, LastModified: modTime},
+		{Key: "/1/2/trace.json", Size: 4, LastModified: modTime},
+		{Key: "/1/2/zkpi.json.gz", Size: 4, LastModified: modTime},
+	}, objects)
+
+	objects, err = s.List(context.TODO(), "/2/")
+	require.NoError(t, err)
+	assert.Empty(t, objects)
+}
+
+func TestKVStoreReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "zkpig.db")
+
+	s := NewKVStore(path)
+	require.NoError(t, s.Start(context.TODO()))
+	require.NoError(t, s.Store(context.TODO(), "/1/10/zkpi.json", bytes.NewReader([]byte("data")), nil))
+	require.NoError(t, s.Stop(context.TODO()))
+
+	s = NewKVStore(path)
+	require.NoError(t, s.Start(context.TODO()))
+	defer s.Stop(context.TODO()) //nolint:errcheck // closing in test cleanup
+	_, _, err := s.Load(context.TODO(), "/1/10/zkpi.json")
+	require.NoError(t, err)
+}
+
+func TestKVStoreNotOpen(t *testing.T) {
+	s := NewKVStore(filepath.Join(t.TempDir(), "zkpig.db"))
+	require.Error(t, s.Store(context.TODO(), "/1/10/zkpi.json", bytes.NewReader([]byte("data")), nil))
+	_, _, err := s.Load(context.TODO(), "/1/10/zkpi.json")
+	require.Error(t, err)
+}
+
+func TestProverInputCatalogKVStore(t *testing.T) {
+	kv := newTestKVStore(t)
+	s, err := NewCompressStore(kv, ContentEncodingGzip)
+	require.NoError(t, err)
+
+	catalog := NewProverInputCatalog(s, kv, "v0.0.1")
+	inputStore := ProverInputStoreWithCatalog(NewProverInputStore(s, ContentTypeJSON), catalog)
+
+	in, _ := testLayoutInputs(10)
+	require.NoError(t, inputStore.StoreProverInput(context.TODO(), in))
+
+	loaded, err := inputStore.LoadProverInput(context.TODO(), 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, in.Blocks[0].Header.Hash(), loaded.Blocks[0].Header.Hash())
+
+	entries, err := catalog.ListProverInputs(context.TODO(), 1, 0, 100)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "gzip", entries[0].ContentEncoding)
+	assert.Equal(t, "v0.0.1", entries[0].Version)
+}
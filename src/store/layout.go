@@ -0,0 +1,257 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	store "github.com/kkrt-labs/go-utils/store"
+)
+
+// Layout is a preset of the key templates of prover inputs and preflight data
+type Layout int
+
+const (
+	// LayoutNumber stores the artifacts of a block at /<chainID>/<blockNumber>/<name>
+	// Artifacts of a reorged block overwrite the ones of the block it replaces.
+	LayoutNumber Layout = iota
+
+	// LayoutHash stores the artifacts of a block at /<chainID>/<blockNumber>/<blockHash>/<name>
+	// and points /<chainID>/<blockNumber>/latest.<artifact> to the last stored block of each artifact (e.g. latest.zkpi).
+	// Loads by number resolve through the latest pointer, loads by hash go direct.
+	LayoutHash
+)
+
+var layoutStrings = map[Layout]string{
+	LayoutNumber: "number",
+	LayoutHash:   "hash",
+}
+
+// ParseLayout parses a layout ("number" or "hash")
+func ParseLayout(layout string) (Layout, error) {
+	switch layout {
+	case "", layoutStrings[LayoutNumber]:
+		return LayoutNumber, nil
+	case layoutStrings[LayoutHash]:
+		return LayoutHash, nil
+	default:
+		return 0, fmt.Errorf("invalid store layout: %q (expected %q or %q)", layout, layoutStrings[LayoutNumber], layoutStrings[LayoutHash])
+	}
+}
+
+func (l Layout) String() string {
+	return layoutStrings[l]
+}
+
+// Option configures a store of block artifacts
+type Option func(*options)
+
+type options struct {
+	proverInputKey   *KeyTemplate
+	preflightDataKey *KeyTemplate
+	blockKey         *KeyTemplate
+	manifest         *manifestOptions // Only applies to prover inputs
+	dedup            bool             // Only applies to prover inputs
+	blobs            *BlobCache       // Applies to deduplicated prover inputs and to the garbage collector
+	blobRefs         store.Store      // Only applies to the garbage collector
+
+	reportSize func(ctx context.Context, contentType ContentType, size int) // Only applies to prover inputs
+}
+
+// WithLayout sets the key templates of prover inputs and preflight data to the ones of the layout (defaults to LayoutNumber)
+func WithLayout(layout Layout) Option {
+	return func(o *options) {
+		switch layout {
+		case LayoutHash:
+			o.proverInputKey, o.preflightDataKey = hashProverInputKey, hashPreflightDataKey
+		default:
+			o.proverInputKey, o.preflightDataKey = DefaultProverInputKey, DefaultPreflightDataKey
+		}
+	}
+}
+
+// WithSizeReporter sets a function called with the size of every stored prover input
+// The size is the one of the encoded payload written to the store (before compression), in the content type of the store.
+func WithSizeReporter(report func(ctx context.Context, contentType ContentType, size int)) Option {
+	return func(o *options) {
+		o.reportSize = report
+	}
+}
+
+// WithProverInputKey sets the key template of prover inputs, which must contain {ext} (overrides the layout)
+func WithProverInputKey(t *KeyTemplate) Option {
+	return func(o *options) {
+		o.proverInputKey = t
+	}
+}
+
+// WithPreflightDataKey sets the key template of preflight data (overrides the layout)
+func WithPreflightDataKey(t *KeyTemplate) Option {
+	return func(o *options) {
+		o.preflightDataKey = t
+	}
+}
+
+// WithBlockKey sets the key template of blocks
+func WithBlockKey(t *KeyTemplate) Option {
+	return func(o *options) {
+		o.blockKey = t
+	}
+}
+
+func newOptions(opts ...Option) *options {
+	o := &options{
+		proverInputKey:   DefaultProverInputKey,
+		preflightDataKey: DefaultPreflightDataKey,
+		blockKey:         DefaultBlockKey,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.blobs == nil {
+		o.blobs = NewBlobCache()
+	}
+	return o
+}
+
+// traceKey returns the key template of the execution traces of a step, stored next to the prover input
+func (o *options) traceKey(step TraceStep) *KeyTemplate {
+	return o.proverInputKey.siblingKey("trace."+string(step), "json")
+}
+
+// badBlockKey returns the key template of bad block reports, stored next to the prover input
+func (o *options) badBlockKey() *KeyTemplate {
+	return o.proverInputKey.siblingKey("badblock", "json")
+}
+
+const latestFileName = "latest"
+
+// Artifacts of the latest pointers
+// Each artifact has its own pointer, so storing the preflight data of a block does not hide the prover input of the previous one.
+const (
+	latestProverInput   = "zkpi"
+	latestPreflightData = "preflight"
+	latestBlock         = "block"
+	latestTrace         = "trace"
+	latestBadBlock      = "badblock"
+)
+
+// latestPointer is the content of the latest pointer of a block number
+type latestPointer struct {
+	BlockHash gethcommon.Hash `json:"blockHash"`
+	Timestamp uint64          `json:"timestamp"`
+}
+
+// latestPath returns the path of the latest pointer of an artifact which keys follow t (see KeyTemplate.latestKey)
+func latestPath(t *KeyTemplate, artifact string, chainID, blockNumber uint64) string {
+	return t.latestKey(artifact).execute(&keyParams{chainID: chainID, blockNumber: blockNumber})
+}
+
+// legacyLatestPath returns the path of the latest pointer shared by all artifacts of stores written by older zk-pig versions
+func legacyLatestPath(chainID, blockNumber uint64) string {
+	return fmt.Sprintf("/%d/%d/%s", chainID, blockNumber, latestFileName)
+}
+
+// storeLatest points the latest pointer of an artifact at a block number to the given block
+func storeLatest(ctx context.Context, s store.Store, t *KeyTemplate, artifact string, chainID, blockNumber uint64, blockHash gethcommon.Hash, timestamp uint64) error {
+	b, err := json.Marshal(&latestPointer{BlockHash: blockHash, Timestamp: timestamp})
+	if err != nil {
+		return fmt.Errorf("failed to encode latest pointer: %w", err)
+	}
+
+	headers := &store.Headers{
+		ContentType:     store.ContentTypeJSON,
+		ContentEncoding: store.ContentEncodingPlain,
+		KeyValue: map[string]string{
+			"chain.id":     fmt.Sprintf("%d", chainID),
+			"block.number": fmt.Sprintf("%d", blockNumber),
+			"block.hash":   blockHash.Hex(),
+			"artifact":     artifact,
+		},
+	}
+
+	if err := s.Store(ctx, latestPath(t, artifact, chainID, blockNumber), bytes.NewReader(b), headers); err != nil {
+		return fmt.Errorf("failed to store latest pointer: %w", err)
+	}
+
+	return nil
+}
+
+// loadLatest returns the block the latest pointer of an artifact at a block number points to
+// It falls back to the legacy latest pointer shared by all artifacts.
+func loadLatest(ctx context.Context, s store.Store, t *KeyTemplate, artifact string, chainID, blockNumber uint64) (*latestPointer, error) {
+	reader, _, err := s.Load(ctx, latestPath(t, artifact, chainID, blockNumber))
+	if err == nil && reader == nil {
+		err = store.ErrNotFound
+	}
+	if errors.Is(err, store.ErrNotFound) {
+		reader, _, err = s.Load(ctx, legacyLatestPath(chainID, blockNumber))
+		if err == nil && reader == nil {
+			err = store.ErrNotFound
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load latest pointer: %w", err)
+	}
+	defer reader.Close()
+
+	pointer := new(latestPointer)
+	if err := json.NewDecoder(reader).Decode(pointer); err != nil {
+		return nil, fmt.Errorf("failed to decode latest pointer: %w", err)
+	}
+
+	return pointer, nil
+}
+
+// checkBlockHash checks that a loaded artifact is for the expected block
+func checkBlockHash(stored, expected gethcommon.Hash) error {
+	if stored != expected {
+		return fmt.Errorf("%w: stored block %s does not match block %s", store.ErrNotFound, stored.Hex(), expected.Hex())
+	}
+	return nil
+}
+
+// blockKeyParams returns the placeholder values of the keys of a block
+func blockKeyParams(chainID, blockNumber uint64, blockHash gethcommon.Hash, timestamp uint64) *keyParams {
+	return &keyParams{chainID: chainID, blockNumber: blockNumber, blockHash: &blockHash, date: blockDate(timestamp)}
+}
+
+// storeIndex points the latest pointer of an artifact at a block number to the given block if keys of t can not be computed from the block number only
+func storeIndex(ctx context.Context, s store.Store, t *KeyTemplate, artifact string, p *keyParams, timestamp uint64) error {
+	if !t.needsIndex() {
+		return nil
+	}
+	return storeLatest(ctx, s, t, artifact, p.chainID, p.blockNumber, *p.blockHash, timestamp)
+}
+
+// resolve returns the placeholder values of the keys of the block loaded by number
+// Blocks which keys can not be computed from the block number only are resolved through the latest pointer
+func resolve(ctx context.Context, s store.Store, t *KeyTemplate, artifact string, chainID, blockNumber uint64) (*keyParams, error) {
+	if !t.needsIndex() {
+		return &keyParams{chainID: chainID, blockNumber: blockNumber}, nil
+	}
+
+	pointer, err := loadLatest(ctx, s, t, artifact, chainID, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	return blockKeyParams(chainID, blockNumber, pointer.BlockHash, pointer.Timestamp), nil
+}
+
+// resolveByHash returns the placeholder values of the keys of the block loaded by hash
+// The date of keys containing {date} is resolved through the latest pointer (competing blocks at the same height are assumed to share their date)
+func resolveByHash(ctx context.Context, s store.Store, t *KeyTemplate, artifact string, chainID, blockNumber uint64, blockHash gethcommon.Hash) (*keyParams, error) {
+	params := &keyParams{chainID: chainID, blockNumber: blockNumber, blockHash: &blockHash}
+	if t.has(KeyDate) {
+		pointer, err := loadLatest(ctx, s, t, artifact, chainID, blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		params.date = blockDate(pointer.Timestamp)
+	}
+	return params, nil
+}
@@ -0,0 +1,155 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/kkrt-labs/go-utils/ethereum/rpc"
+	store "github.com/kkrt-labs/go-utils/store"
+	memorystore "github.com/kkrt-labs/go-utils/store/memory"
+	input "github.com/kkrt-labs/zk-pig/src/prover-input"
+	"github.com/kkrt-labs/zk-pig/src/steps"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLayout(t *testing.T) {
+	for s, expected := range map[string]Layout{"": LayoutNumber, "number": LayoutNumber, "hash": LayoutHash} {
+		layout, err := ParseLayout(s)
+		require.NoError(t, err)
+		assert.Equal(t, expected, layout)
+	}
+
+	_, err := ParseLayout("invalid")
+	assert.Error(t, err)
+}
+
+// testLayoutInputs returns the prover inputs of two competing blocks at the same height
+func testLayoutInputs(blockNumber int64) (canonical, reorged *input.ProverInput) {
+	canonical = testCatalogInput(blockNumber, nil)
+	canonical.Blocks[0].Header.Difficulty = big.NewInt(0)
+	reorged = testCatalogInput(blockNumber, nil)
+	reorged.Blocks[0].Header.Difficulty = big.NewInt(0)
+	reorged.Blocks[0].Header.Extra = []byte("reorg")
+	return canonical, reorged
+}
+
+func TestProverInputStoreHashLayout(t *testing.T) {
+	s := memorystore.New()
+	inputStore := NewProverInputStore(s, ContentTypeJSON, WithLayout(LayoutHash))
+
+	canonical, reorged := testLayoutInputs(10)
+	canonicalHash, reorgedHash := canonical.Blocks[0].Header.Hash(), reorged.Blocks[0].Header.Hash()
+
+	// Nothing stored yet
+	_, err := inputStore.LoadProverInput(context.TODO(), 1, 10)
+	require.ErrorIs(t, err, store.ErrNotFound)
+
+	require.NoError(t, inputStore.StoreProverInput(context.TODO(), reorged))
+	require.NoError(t, inputStore.StoreProverInput(context.TODO(), canonical))
+
+	// Both blocks are kept
+	_, _, err = s.Load(context.TODO(), fmt.Sprintf("/1/10/%s/zkpi.json", canonicalHash.Hex()))
+	require.NoError(t, err)
+	_, _, err = s.Load(context.TODO(), fmt.Sprintf("/1/10/%s/zkpi.json", reorgedHash.Hex()))
+	require.NoError(t, err)
+
+	// Loads by number resolve through the latest pointer
+	in, err := inputStore.LoadProverInput(context.TODO(), 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, canonicalHash, in.Blocks[0].Header.Hash())
+
+	// Loads by hash go direct
+	in, err = inputStore.LoadProverInputByHash(context.TODO(), 1, 10, reorgedHash)
+	require.NoError(t, err)
+	assert.Equal(t, reorgedHash, in.Blocks[0].Header.Hash())
+
+	_, err = inputStore.LoadProverInputByHash(context.TODO(), 1, 10, gethcommon.HexToHash("0x1234"))
+	require.ErrorIs(t, err, store.ErrNotFound)
+}
+
+func TestProverInputStoreNumberLayoutLoadByHash(t *testing.T) {
+	inputStore := NewProverInputStore(memorystore.New(), ContentTypeJSON)
+
+	canonical, reorged := testLayoutInputs(10)
+
+	require.NoError(t, inputStore.StoreProverInput(context.TODO(), reorged))
+	require.NoError(t, inputStore.StoreProverInput(context.TODO(), canonical))
+
+	in, err := inputStore.LoadProverInputByHash(context.TODO(), 1, 10, canonical.Blocks[0].Header.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, canonical.Blocks[0].Header.Hash(), in.Blocks[0].Header.Hash())
+
+	// The reorged block has been overwritten
+	_, err = inputStore.LoadProverInputByHash(context.TODO(), 1, 10, reorged.Blocks[0].Header.Hash())
+	require.ErrorIs(t, err, store.ErrNotFound)
+}
+
+func TestPreflightDataStoreHashLayout(t *testing.T) {
+	s := memorystore.New()
+	preflightDataStore, err := NewPreflightDataStore(s, WithLayout(LayoutHash))
+	require.NoError(t, err)
+
+	newData := func(hash gethcommon.Hash) *steps.PreflightData {
+		return &steps.PreflightData{
+			ChainConfig: &params.ChainConfig{ChainID: big.NewInt(1)},
+			Block: &rpc.Block{
+				Header: rpc.Header{
+					Number: (*hexutil.Big)(big.NewInt(10)),
+					Hash:   hash,
+				},
+			},
+		}
+	}
+	canonicalHash, reorgedHash := gethcommon.HexToHash("0xaa"), gethcommon.HexToHash("0xbb")
+
+	require.NoError(t, preflightDataStore.StorePreflightData(context.TODO(), newData(reorgedHash)))
+	require.NoError(t, preflightDataStore.StorePreflightData(context.TODO(), newData(canonicalHash)))
+
+	data, err := preflightDataStore.LoadPreflightData(context.TODO(), 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, canonicalHash, data.Block.Hash)
+
+	data, err = preflightDataStore.LoadPreflightDataByHash(context.TODO(), 1, 10, reorgedHash)
+	require.NoError(t, err)
+	assert.Equal(t, reorgedHash, data.Block.Hash)
+}
+
+func TestLatestPointerPerArtifact(t *testing.T) {
+	s := memorystore.New()
+	inputStore := NewProverInputStore(s, ContentTypeJSON, WithLayout(LayoutHash))
+	preflightDataStore, err := NewPreflightDataStore(s, WithLayout(LayoutHash))
+	require.NoError(t, err)
+
+	canonical, reorged := testLayoutInputs(10)
+	canonicalHash, reorgedHash := canonical.Blocks[0].Header.Hash(), reorged.Blocks[0].Header.Hash()
+
+	// Preflight data of a competing block does not move the prover input pointer
+	require.NoError(t, inputStore.StoreProverInput(context.TODO(), canonical))
+	require.NoError(t, preflightDataStore.StorePreflightData(context.TODO(), &steps.PreflightData{
+		ChainConfig: &params.ChainConfig{ChainID: big.NewInt(1)},
+		Block:       &rpc.Block{Header: rpc.Header{Number: (*hexutil.Big)(big.NewInt(10)), Hash: reorgedHash}},
+	}))
+
+	in, err := inputStore.LoadProverInput(context.TODO(), 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, canonicalHash, in.Blocks[0].Header.Hash())
+
+	data, err := preflightDataStore.LoadPreflightData(context.TODO(), 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, reorgedHash, data.Block.Hash)
+
+	// Stores written by older versions resolve through the legacy pointer shared by all artifacts
+	require.NoError(t, s.Delete(context.TODO(), "/1/10/latest.zkpi"))
+	require.NoError(t, s.Store(context.TODO(), "/1/10/latest", bytes.NewReader([]byte(fmt.Sprintf(`{"blockHash":%q}`, canonicalHash.Hex()))), nil))
+
+	in, err = inputStore.LoadProverInput(context.TODO(), 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, canonicalHash, in.Blocks[0].Header.Hash())
+}
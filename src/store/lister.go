@@ -0,0 +1,196 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/kkrt-labs/go-utils/common"
+)
+
+// Object describes an object of a store
+type Object struct {
+	Key          string    `json:"key"`          // Key of the object as passed to the store (e.g. "/1/1234/zkpi.json.gz")
+	Size         int64     `json:"size"`         // Size of the stored object (in bytes)
+	LastModified time.Time `json:"lastModified"` // Last modification time of the object
+}
+
+// Lister lists the objects of a store
+//
+// go-utils stores can only access objects by exact key, so listing is implemented per backend.
+type Lister interface {
+	// List returns the objects which key starts with the given prefix, in lexical order of keys
+	List(ctx context.Context, prefix string) ([]*Object, error)
+}
+
+type fileLister struct {
+	dataDir string
+}
+
+// NewFileLister creates a lister over the objects of a file store
+func NewFileLister(dataDir string) Lister {
+	return &fileLister{dataDir: dataDir}
+}
+
+func (l *fileLister) List(_ context.Context, prefix string) ([]*Object, error) {
+	// Only walk the directory containing the prefix
+	dir := prefix
+	if !strings.HasSuffix(dir, "/") {
+		dir = path.Dir(dir)
+	}
+	root := filepath.Join(l.dataDir, filepath.FromSlash(dir))
+
+	objects := make([]*Object, 0)
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			if p == root {
+				return nil
+			}
+			rel, err := filepath.Rel(l.dataDir, p)
+			if err != nil {
+				return err
+			}
+			// Skip directories which can not contain keys with the prefix
+			dirKey := "/" + filepath.ToSlash(rel) + "/"
+			if !strings.HasPrefix(dirKey, prefix) && !strings.HasPrefix(prefix, dirKey) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(l.dataDir, p)
+		if err != nil {
+			return err
+		}
+
+		key := "/" + filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, &Object{Key: key, Size: info.Size(), LastModified: info.ModTime()})
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	return objects, nil
+}
+
+type s3Lister struct {
+	client    s3.ListObjectsV2APIClient
+	bucket    string
+	keyPrefix string
+}
+
+// NewS3Lister creates a lister over the objects of an S3 store with the given bucket and key prefix
+func NewS3Lister(client s3.ListObjectsV2APIClient, bucket, keyPrefix string) Lister {
+	return &s3Lister{client: client, bucket: bucket, keyPrefix: keyPrefix}
+}
+
+func (l *s3Lister) List(ctx context.Context, prefix string) ([]*Object, error) {
+	// S3 store keys are joined to the key prefix (see go-utils s3 store)
+	s3Prefix := path.Join(l.keyPrefix, prefix)
+	if strings.HasSuffix(prefix, "/") {
+		s3Prefix += "/"
+	}
+
+	objects := make([]*Object, 0)
+	paginator := s3.NewListObjectsV2Paginator(l.client, &s3.ListObjectsV2Input{
+		Bucket: common.Ptr(l.bucket),
+		Prefix: common.Ptr(s3Prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list S3 objects: %w", err)
+		}
+
+		for _, o := range page.Contents {
+			key := "/" + strings.TrimPrefix(strings.TrimPrefix(common.Val(o.Key), strings.TrimPrefix(l.keyPrefix, "/")), "/")
+			objects = append(objects, &Object{Key: key, Size: common.Val(o.Size), LastModified: common.Val(o.LastModified)})
+		}
+	}
+
+	return objects, nil
+}
+
+type multiLister struct {
+	listers []Lister
+}
+
+// NewMultiLister creates a lister over the objects of several stores
+// Objects present in several stores are listed once, with the size reported by the first lister
+func NewMultiLister(listers ...Lister) Lister {
+	return &multiLister{listers: listers}
+}
+
+func (l *multiLister) List(ctx context.Context, prefix string) ([]*Object, error) {
+	seen := make(map[string]struct{})
+	objects := make([]*Object, 0)
+	for _, lister := range l.listers {
+		objs, err := lister.List(ctx, prefix)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, o := range objs {
+			if _, ok := seen[o.Key]; ok {
+				continue
+			}
+			seen[o.Key] = struct{}{}
+			objects = append(objects, o)
+		}
+	}
+
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+
+	return objects, nil
+}
+
+// coverPrefixes sorts prefixes and removes the ones contained in another prefix, so listed objects are listed once
+func coverPrefixes(prefixes []string) []string {
+	sorted := append([]string(nil), prefixes...)
+	sort.Strings(sorted)
+
+	res := make([]string, 0, len(sorted))
+	for _, prefix := range sorted {
+		if len(res) > 0 && strings.HasPrefix(prefix, res[len(res)-1]) {
+			continue
+		}
+		res = append(res, prefix)
+	}
+	return res
+}
+
+// listPrefixes lists the objects which key starts with one of the given prefixes, in lexical order of keys
+func listPrefixes(ctx context.Context, lister Lister, prefixes []string) ([]*Object, error) {
+	objects := make([]*Object, 0)
+	for _, prefix := range coverPrefixes(prefixes) {
+		objs, err := lister.List(ctx, prefix)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, objs...)
+	}
+	return objects, nil
+}
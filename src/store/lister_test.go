@@ -0,0 +1,108 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/kkrt-labs/go-utils/common"
+	filestore "github.com/kkrt-labs/go-utils/store/file"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileLister(t *testing.T) {
+	dir := t.TempDir()
+	s := filestore.New(dir)
+	modTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, key := range []string{"/1/10/zkpi.json", "/1/2/zkpi.json.gz", "/1/2/trace.json", "/10/2/zkpi.json"} {
+		require.NoError(t, s.Store(context.TODO(), key, bytes.NewReader([]byte("data")), nil))
+		require.NoError(t, os.Chtimes(filepath.Join(dir, key), modTime, modTime))
+	}
+
+	objects, err := NewFileLister(dir).List(context.TODO(), "/1/")
+	require.NoError(t, err)
+	for _, o := range objects {
+		o.LastModified = o.LastModified.UTC()
+	}
+	assert.Equal(t, []*Object{
+		{Key: "/1/10/zkpi.json", Size: 4, LastModified: modTime},
+		{Key: "/1/2/trace.json", Size: 4, LastModified: modTime},
+		{Key: "/1/2/zkpi.json.gz", Size: 4, LastModified: modTime},
+	}, objects)
+
+	objects, err = NewFileLister(dir).List(context.TODO(), "/2/")
+	require.NoError(t, err)
+	assert.Empty(t, objects)
+
+	// Prefixes may end within a directory name
+	objects, err = NewFileLister(dir).List(context.TODO(), "/1/1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/1/10/zkpi.json"}, keys(objects))
+}
+
+type testS3Client struct {
+	pages []*s3.ListObjectsV2Output
+	input []*s3.ListObjectsV2Input
+}
+
+func (c *testS3Client) ListObjectsV2(_ context.Context, in *s3.ListObjectsV2Input, _ ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	c.input = append(c.input, in)
+	page := c.pages[0]
+	c.pages = c.pages[1:]
+	return page, nil
+}
+
+func TestS3Lister(t *testing.T) {
+	modTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	client := &testS3Client{
+		pages: []*s3.ListObjectsV2Output{
+			{
+				Contents:              []s3types.Object{{Key: common.Ptr("prefix/1/2/zkpi.json"), Size: common.Ptr(int64(10))}},
+				IsTruncated:           common.Ptr(true),
+				NextContinuationToken: common.Ptr("next"),
+			},
+			{
+				Contents: []s3types.Object{{Key: common.Ptr("prefix/1/3/zkpi.json"), Size: common.Ptr(int64(20)), LastModified: common.Ptr(modTime)}},
+			},
+		},
+	}
+
+	objects, err := NewS3Lister(client, "bucket", "prefix").List(context.TODO(), "/1/")
+	require.NoError(t, err)
+	assert.Equal(t, []*Object{
+		{Key: "/1/2/zkpi.json", Size: 10},
+		{Key: "/1/3/zkpi.json", Size: 20, LastModified: modTime},
+	}, objects)
+
+	require.Len(t, client.input, 2)
+	assert.Equal(t, "bucket", common.Val(client.input[0].Bucket))
+	assert.Equal(t, "prefix/1/", common.Val(client.input[0].Prefix))
+	assert.Equal(t, "next", common.Val(client.input[1].ContinuationToken))
+}
+
+type testLister []*Object
+
+func (l testLister) List(_ context.Context, _ string) ([]*Object, error) {
+	return l, nil
+}
+
+func TestMultiLister(t *testing.T) {
+	lister := NewMultiLister(
+		testLister{{Key: "/1/3/zkpi.json", Size: 1}, {Key: "/1/2/zkpi.json", Size: 1}},
+		testLister{{Key: "/1/2/zkpi.json", Size: 2}, {Key: "/1/1/zkpi.json", Size: 2}},
+	)
+
+	objects, err := lister.List(context.TODO(), "/1/")
+	require.NoError(t, err)
+	assert.Equal(t, []*Object{
+		{Key: "/1/1/zkpi.json", Size: 2},
+		{Key: "/1/2/zkpi.json", Size: 1},
+		{Key: "/1/3/zkpi.json", Size: 1},
+	}, objects)
+}
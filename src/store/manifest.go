@@ -0,0 +1,344 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	store "github.com/kkrt-labs/go-utils/store"
+	input "github.com/kkrt-labs/zk-pig/src/prover-input"
+)
+
+// ErrIntegrity is returned when a stored prover input does not match its manifest
+var ErrIntegrity = fmt.Errorf("prover input integrity check failed")
+
+// Manifest is stored next to a prover input to detect tampering or truncation before decoding it
+type Manifest struct {
+	ChainID       uint64           `json:"chainId"`
+	BlockNumber   uint64           `json:"blockNumber"`
+	BlockHash     gethcommon.Hash  `json:"blockHash"`
+	PreStateRoot  *gethcommon.Hash `json:"preStateRoot,omitempty"` // State root of the parent block (unset if the parent header is not in the witness)
+	StateRoot     gethcommon.Hash  `json:"stateRoot"`              // State root of the block
+	ContentType   string           `json:"contentType"`
+	PayloadSize   int              `json:"payloadSize"`          // Size of the encoded payload (in bytes, before compression)
+	PayloadSHA256 hexutil.Bytes    `json:"payloadSha256"`        // SHA-256 of the encoded payload (before compression)
+	InputHash     gethcommon.Hash  `json:"inputHash"`            // Keccak256 of the canonical (JSON) encoding of the prover input (with its witness if deduplicated)
+	RefsSHA256    hexutil.Bytes    `json:"refsSha256,omitempty"` // SHA-256 of the witness references of a deduplicated prover input (see WithDedup)
+	Version       string           `json:"version"`              // Version of zk-pig that generated the prover input
+	InputVersion  string           `json:"inputVersion"`         // Version of the prover input layout
+	PublicKey     hexutil.Bytes    `json:"publicKey,omitempty"`
+	Signature     hexutil.Bytes    `json:"signature,omitempty"` // ed25519 signature of the manifest encoded without signature
+}
+
+// NewManifest creates the manifest of a prover input encoded in the given content type
+func NewManifest(in *input.ProverInput, contentType ContentType, payload []byte, version string) (*Manifest, error) {
+	digest := newPayloadDigest(contentType == ContentTypeJSON)
+	_, _ = digest.Write(payload)
+	return newManifest(in, contentType, digest, version)
+}
+
+// newManifest creates the manifest of a prover input whose encoded payload has been written to digest
+func newManifest(in *input.ProverInput, contentType ContentType, digest *payloadDigest, version string) (*Manifest, error) {
+	inputHash := digest.input
+	if inputHash == nil {
+		// Hash the canonical encoding as it is streamed
+		inputHash = crypto.NewKeccakState()
+		if err := EncodeProverInputTo(inputHash, in, ContentTypeJSON); err != nil {
+			return nil, err
+		}
+	}
+
+	header := in.Blocks[0].Header
+	m := &Manifest{
+		ChainID:       in.ChainConfig.ChainID.Uint64(),
+		BlockNumber:   header.Number.Uint64(),
+		BlockHash:     header.Hash(),
+		StateRoot:     header.Root,
+		ContentType:   contentType.String(),
+		PayloadSize:   digest.size,
+		PayloadSHA256: digest.sha256.Sum(nil),
+		InputHash:     gethcommon.BytesToHash(inputHash.Sum(nil)),
+		Version:       version,
+		InputVersion:  in.Version,
+	}
+
+	if in.Witness != nil {
+		for _, ancestor := range in.Witness.Ancestors {
+			if ancestor.Hash() == header.ParentHash {
+				m.PreStateRoot = &ancestor.Root
+				break
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// signingPayload returns the bytes covered by the signature of the manifest
+func (m *Manifest) signingPayload() ([]byte, error) {
+	unsigned := *m
+	unsigned.Signature = nil
+	return json.Marshal(&unsigned)
+}
+
+// Sign signs the manifest with an ed25519 private key
+func (m *Manifest) Sign(key ed25519.PrivateKey) error {
+	m.PublicKey = hexutil.Bytes(key.Public().(ed25519.PublicKey))
+	b, err := m.signingPayload()
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	m.Signature = ed25519.Sign(key, b)
+	return nil
+}
+
+// VerifySignature verifies the signature of the manifest with an ed25519 public key
+func (m *Manifest) VerifySignature(key ed25519.PublicKey) error {
+	if len(m.Signature) == 0 {
+		return fmt.Errorf("%w: manifest is not signed", ErrIntegrity)
+	}
+
+	b, err := m.signingPayload()
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if !ed25519.Verify(key, b, m.Signature) {
+		return fmt.Errorf("%w: invalid manifest signature", ErrIntegrity)
+	}
+
+	return nil
+}
+
+// VerifyPayload verifies that an encoded payload matches the manifest
+func (m *Manifest) VerifyPayload(payload []byte) error {
+	digest := newPayloadDigest(false)
+	_, _ = digest.Write(payload)
+	return m.verifyDigest(digest)
+}
+
+// verifyDigest verifies that a payload written to digest matches the manifest
+func (m *Manifest) verifyDigest(digest *payloadDigest) error {
+	if digest.size != m.PayloadSize {
+		return fmt.Errorf("%w: payload size %d does not match manifest size %d", ErrIntegrity, digest.size, m.PayloadSize)
+	}
+
+	payloadHash := digest.sha256.Sum(nil)
+	if !bytes.Equal(payloadHash, m.PayloadSHA256) {
+		return fmt.Errorf("%w: payload SHA-256 %x does not match manifest SHA-256 %x", ErrIntegrity, payloadHash, []byte(m.PayloadSHA256))
+	}
+
+	return nil
+}
+
+// verifyInputHash verifies that a loaded prover input (with its witness if deduplicated) matches the input hash of the manifest
+// inputHash is the hash of the payload computed while it was read if the payload is the canonical encoding of the prover input (nil otherwise)
+func (m *Manifest) verifyInputHash(in *input.ProverInput, inputHash hash.Hash) error {
+	if inputHash == nil {
+		inputHash = crypto.NewKeccakState()
+		if err := EncodeProverInputTo(inputHash, in, ContentTypeJSON); err != nil {
+			return err
+		}
+	}
+
+	if h := gethcommon.BytesToHash(inputHash.Sum(nil)); h != m.InputHash {
+		return fmt.Errorf("%w: input hash %s does not match manifest input hash %s", ErrIntegrity, h.Hex(), m.InputHash.Hex())
+	}
+
+	return nil
+}
+
+// verifyRefs verifies that the witness references of a deduplicated prover input match the manifest
+func (m *Manifest) verifyRefs(refs []byte) error {
+	if len(m.RefsSHA256) == 0 {
+		return fmt.Errorf("%w: witness references are not covered by the manifest", ErrIntegrity)
+	}
+
+	refsHash := sha256.Sum256(refs)
+	if !bytes.Equal(refsHash[:], m.RefsSHA256) {
+		return fmt.Errorf("%w: witness references SHA-256 %x does not match manifest SHA-256 %x", ErrIntegrity, refsHash[:], []byte(m.RefsSHA256))
+	}
+
+	return nil
+}
+
+// payloadDigest computes the size and hashes of an encoded payload as it is streamed
+type payloadDigest struct {
+	size   int
+	sha256 hash.Hash
+	input  hash.Hash // Keccak256 of the payload when it is the canonical (JSON) encoding of the prover input
+}
+
+func newPayloadDigest(canonical bool) *payloadDigest {
+	d := &payloadDigest{sha256: sha256.New()}
+	if canonical {
+		d.input = crypto.NewKeccakState()
+	}
+	return d
+}
+
+func (d *payloadDigest) Write(p []byte) (int, error) {
+	d.size += len(p)
+	d.sha256.Write(p)
+	if d.input != nil {
+		d.input.Write(p)
+	}
+	return len(p), nil
+}
+
+// VerifyInput verifies that a decoded prover input is for the block of the manifest
+func (m *Manifest) VerifyInput(in *input.ProverInput) error {
+	header := in.Blocks[0].Header
+	if header.Hash() != m.BlockHash {
+		return fmt.Errorf("%w: block %s does not match manifest block %s", ErrIntegrity, header.Hash().Hex(), m.BlockHash.Hex())
+	}
+	return nil
+}
+
+type manifestOptions struct {
+	version      string
+	signingKey   ed25519.PrivateKey
+	verifyingKey ed25519.PublicKey
+}
+
+// WithManifest writes a manifest next to every stored prover input and verifies it before decoding loaded prover inputs
+// Prover inputs stored without manifest are loaded without verification unless a verifying key is set.
+// version is the zk-pig version recorded in the manifests
+func WithManifest(version string) Option {
+	return func(o *options) {
+		if o.manifest == nil {
+			o.manifest = new(manifestOptions)
+		}
+		o.manifest.version = version
+	}
+}
+
+// WithManifestSigningKey signs the manifests with an ed25519 private key (requires WithManifest)
+func WithManifestSigningKey(key ed25519.PrivateKey) Option {
+	return func(o *options) {
+		if o.manifest != nil {
+			o.manifest.signingKey = key
+		}
+	}
+}
+
+// WithManifestVerifyingKey requires every loaded prover input to have a manifest signed by an ed25519 key (requires WithManifest)
+func WithManifestVerifyingKey(key ed25519.PublicKey) Option {
+	return func(o *options) {
+		if o.manifest != nil {
+			o.manifest.verifyingKey = key
+		}
+	}
+}
+
+// ParseEd25519PrivateKey parses a hex encoded ed25519 private key (64 bytes) or seed (32 bytes)
+func ParseEd25519PrivateKey(s string) (ed25519.PrivateKey, error) {
+	b, err := hexutil.Decode(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ed25519 private key: %w", err)
+	}
+
+	switch len(b) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(b), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(b), nil
+	default:
+		return nil, fmt.Errorf("invalid ed25519 private key: expected %d or %d bytes but got %d", ed25519.SeedSize, ed25519.PrivateKeySize, len(b))
+	}
+}
+
+// ParseEd25519PublicKey parses a hex encoded ed25519 public key
+func ParseEd25519PublicKey(s string) (ed25519.PublicKey, error) {
+	b, err := hexutil.Decode(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ed25519 public key: %w", err)
+	}
+	if len(b) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid ed25519 public key: expected %d bytes but got %d", ed25519.PublicKeySize, len(b))
+	}
+	return ed25519.PublicKey(b), nil
+}
+
+// manifestPath returns the path of the manifest of a prover input (e.g. "/1/1234/zkpi.json.manifest.json")
+func manifestPath(payloadPath string) string {
+	return payloadPath + manifestSuffix
+}
+
+const manifestSuffix = ".manifest.json"
+
+// storeManifest stores the manifest of a prover input
+// refs are the witness references of the prover input if it is deduplicated (nil otherwise),
+// in which case data is the prover input with its witness and digest has not hashed the input.
+func (s *proverInputStore) storeManifest(ctx context.Context, data *input.ProverInput, contentType ContentType, digest *payloadDigest, refs []byte, payloadPath string) error {
+	m, err := newManifest(data, contentType, digest, s.manifest.version)
+	if err != nil {
+		return err
+	}
+
+	if refs != nil {
+		refsHash := sha256.Sum256(refs)
+		m.RefsSHA256 = refsHash[:]
+	}
+
+	if s.manifest.signingKey != nil {
+		if err := m.Sign(s.manifest.signingKey); err != nil {
+			return err
+		}
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	headers := &store.Headers{
+		ContentType:     store.ContentTypeJSON,
+		ContentEncoding: store.ContentEncodingPlain,
+		KeyValue: map[string]string{
+			"chain.id":     fmt.Sprintf("%d", m.ChainID),
+			"block.number": fmt.Sprintf("%d", m.BlockNumber),
+		},
+	}
+	if err := s.store.Store(ctx, manifestPath(payloadPath), bytes.NewReader(b), headers); err != nil {
+		return fmt.Errorf("failed to store manifest: %w", err)
+	}
+
+	return nil
+}
+
+// loadManifest loads the manifest of a prover input and verifies its signature
+// It returns nil if the prover input has no manifest and no verifying key is set
+func (s *proverInputStore) loadManifest(ctx context.Context, payloadPath string) (*Manifest, error) {
+	reader, _, err := s.store.Load(ctx, manifestPath(payloadPath))
+	if err == nil && reader == nil {
+		err = store.ErrNotFound
+	}
+	if errors.Is(err, store.ErrNotFound) && s.manifest.verifyingKey == nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load manifest: %w", err)
+	}
+	defer reader.Close()
+
+	m := new(Manifest)
+	if err := json.NewDecoder(reader).Decode(m); err != nil {
+		return nil, fmt.Errorf("%w: failed to decode manifest: %v", ErrIntegrity, err)
+	}
+
+	if s.manifest.verifyingKey != nil {
+		if err := m.VerifySignature(s.manifest.verifyingKey); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
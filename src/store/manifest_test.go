@@ -0,0 +1,205 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"io"
+	"math/big"
+	"strings"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	store "github.com/kkrt-labs/go-utils/store"
+	memorystore "github.com/kkrt-labs/go-utils/store/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProverInputStoreManifest(t *testing.T) {
+	s := memorystore.New()
+	signingKey := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{1}, ed25519.SeedSize))
+	inputStore := NewProverInputStore(s, ContentTypeJSON, WithManifest("v0.0.1"), WithManifestSigningKey(signingKey))
+
+	in, _ := testLayoutInputs(10)
+	parent := &gethtypes.Header{Number: big.NewInt(9), Difficulty: big.NewInt(0), Root: gethcommon.HexToHash("0x09")}
+	in.Blocks[0].Header.ParentHash = parent.Hash()
+	in.Blocks[0].Header.Root = gethcommon.HexToHash("0x0a")
+	in.Witness.Ancestors = []*gethtypes.Header{parent}
+	require.NoError(t, inputStore.StoreProverInput(context.TODO(), in))
+
+	reader, _, err := s.Load(context.TODO(), "/1/10/zkpi.json.manifest.json")
+	require.NoError(t, err)
+	manifest := new(Manifest)
+	require.NoError(t, json.NewDecoder(reader).Decode(manifest))
+
+	assert.Equal(t, uint64(1), manifest.ChainID)
+	assert.Equal(t, uint64(10), manifest.BlockNumber)
+	assert.Equal(t, in.Blocks[0].Header.Hash(), manifest.BlockHash)
+	assert.Equal(t, gethcommon.HexToHash("0x09"), *manifest.PreStateRoot)
+	assert.Equal(t, gethcommon.HexToHash("0x0a"), manifest.StateRoot)
+	assert.Equal(t, "application/json", manifest.ContentType)
+	assert.Equal(t, "v0.0.1", manifest.Version)
+	assert.Len(t, manifest.PayloadSHA256, 32)
+	assert.NoError(t, manifest.VerifySignature(signingKey.Public().(ed25519.PublicKey)))
+
+	// The input hash does not depend on the content type
+	sszManifest, err := NewManifest(in, ContentTypeSSZ, []byte{}, "v0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, manifest.InputHash, sszManifest.InputHash)
+
+	verifyingStore := NewProverInputStore(s, ContentTypeJSON, WithManifest("v0.0.1"), WithManifestVerifyingKey(signingKey.Public().(ed25519.PublicKey)))
+	loaded, err := verifyingStore.LoadProverInput(context.TODO(), 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, in.Blocks[0].Header.Hash(), loaded.Blocks[0].Header.Hash())
+
+	// Signature from another key is rejected
+	otherKey := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{2}, ed25519.SeedSize))
+	_, err = NewProverInputStore(s, ContentTypeJSON, WithManifest("v0.0.1"), WithManifestVerifyingKey(otherKey.Public().(ed25519.PublicKey))).LoadProverInput(context.TODO(), 1, 10)
+	require.ErrorIs(t, err, ErrIntegrity)
+}
+
+func TestProverInputStoreManifestTampering(t *testing.T) {
+	in, _ := testLayoutInputs(10)
+
+	tests := []struct {
+		desc   string
+		tamper func(payload []byte) []byte
+	}{
+		{
+			desc:   "truncated payload",
+			tamper: func(payload []byte) []byte { return payload[:len(payload)/2] },
+		},
+		{
+			desc: "modified payload",
+			tamper: func(payload []byte) []byte {
+				return bytes.Replace(payload, []byte(`"version"`), []byte(`"VERSION"`), 1)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			s := memorystore.New()
+			inputStore := NewProverInputStore(s, ContentTypeJSON, WithManifest("v0.0.1"))
+			require.NoError(t, inputStore.StoreProverInput(context.TODO(), in))
+
+			reader, _, err := s.Load(context.TODO(), "/1/10/zkpi.json")
+			require.NoError(t, err)
+			payload, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			require.NoError(t, s.Store(context.TODO(), "/1/10/zkpi.json", bytes.NewReader(test.tamper(payload)), &store.Headers{ContentType: store.ContentTypeJSON}))
+
+			_, err = inputStore.LoadProverInput(context.TODO(), 1, 10)
+			require.ErrorIs(t, err, ErrIntegrity)
+		})
+	}
+}
+
+type objectStore = store.Store
+
+// countingStore counts the loads of prover input payloads
+type countingStore struct {
+	objectStore
+	loads int
+}
+
+func (s *countingStore) Load(ctx context.Context, key string) (io.ReadCloser, *store.Headers, error) {
+	if strings.HasSuffix(key, "zkpi.json") {
+		s.loads++
+	}
+	return s.objectStore.Load(ctx, key)
+}
+
+func TestProverInputStoreManifestSingleRead(t *testing.T) {
+	in, _ := testLayoutInputs(10)
+	s := &countingStore{objectStore: memorystore.New()}
+	inputStore := NewProverInputStore(s, ContentTypeJSON, WithManifest("v0.0.1"))
+	require.NoError(t, inputStore.StoreProverInput(context.TODO(), in))
+
+	// The payload is verified while it is decoded
+	_, err := inputStore.LoadProverInput(context.TODO(), 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, s.loads)
+}
+
+func TestProverInputStoreManifestInputHash(t *testing.T) {
+	s := memorystore.New()
+	inputStore := NewProverInputStore(s, ContentTypeProtobuf, WithManifest("v0.0.1"))
+	in, _ := testLayoutInputs(10)
+	require.NoError(t, inputStore.StoreProverInput(context.TODO(), in))
+
+	// The payload is replaced by another prover input for the same block, and the payload digest of the (unsigned) manifest is updated accordingly
+	tampered, _ := testLayoutInputs(10)
+	tampered.Witness.Codes = [][]byte{{0x60, 0x00}}
+	payload, err := EncodeProverInput(tampered, ContentTypeProtobuf)
+	require.NoError(t, err)
+	require.NoError(t, s.Store(context.TODO(), "/1/10/zkpi.protobuf", bytes.NewReader(payload), nil))
+
+	manifest, err := inputStore.(*proverInputStore).loadManifest(context.TODO(), "/1/10/zkpi.protobuf")
+	require.NoError(t, err)
+	tamperedManifest, err := NewManifest(tampered, ContentTypeProtobuf, payload, "v0.0.1")
+	require.NoError(t, err)
+	tamperedManifest.InputHash = manifest.InputHash
+	b, err := json.Marshal(tamperedManifest)
+	require.NoError(t, err)
+	require.NoError(t, s.Store(context.TODO(), "/1/10/zkpi.protobuf.manifest.json", bytes.NewReader(b), nil))
+
+	_, err = inputStore.LoadProverInput(context.TODO(), 1, 10)
+	require.ErrorIs(t, err, ErrIntegrity)
+	assert.ErrorContains(t, err, "input hash")
+}
+
+func TestProverInputStoreMissingManifest(t *testing.T) {
+	s := memorystore.New()
+	in, _ := testLayoutInputs(10)
+	require.NoError(t, NewProverInputStore(s, ContentTypeJSON).StoreProverInput(context.TODO(), in))
+
+	// Prover inputs stored without manifest are loaded unless a verifying key is set
+	_, err := NewProverInputStore(s, ContentTypeJSON, WithManifest("v0.0.1")).LoadProverInput(context.TODO(), 1, 10)
+	require.NoError(t, err)
+
+	key := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{1}, ed25519.SeedSize))
+	_, err = NewProverInputStore(s, ContentTypeJSON, WithManifest("v0.0.1"), WithManifestVerifyingKey(key.Public().(ed25519.PublicKey))).LoadProverInput(context.TODO(), 1, 10)
+	require.ErrorIs(t, err, store.ErrNotFound)
+}
+
+func TestParseEd25519Keys(t *testing.T) {
+	key := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{1}, ed25519.SeedSize))
+
+	parsed, err := ParseEd25519PrivateKey("0x" + gethcommon.Bytes2Hex(key.Seed()))
+	require.NoError(t, err)
+	assert.Equal(t, key, parsed)
+
+	parsed, err = ParseEd25519PrivateKey("0x" + gethcommon.Bytes2Hex(key))
+	require.NoError(t, err)
+	assert.Equal(t, key, parsed)
+
+	pub, err := ParseEd25519PublicKey("0x" + gethcommon.Bytes2Hex(key.Public().(ed25519.PublicKey)))
+	require.NoError(t, err)
+	assert.Equal(t, key.Public(), pub)
+
+	_, err = ParseEd25519PrivateKey("0x01")
+	assert.Error(t, err)
+	_, err = ParseEd25519PublicKey("0x01")
+	assert.Error(t, err)
+}
+
+func TestProverInputStoreManifestCompressed(t *testing.T) {
+	for _, encoding := range []ContentEncoding{ContentEncodingGzip, ContentEncodingZlib, ContentEncodingFlate} {
+		t.Run(encoding.String(), func(t *testing.T) {
+			s, err := NewCompressStore(memorystore.New(), encoding)
+			require.NoError(t, err)
+			inputStore := NewProverInputStore(s, ContentTypeJSON, WithManifest("v0.0.1"))
+
+			in, _ := testLayoutInputs(10)
+			require.NoError(t, inputStore.StoreProverInput(context.TODO(), in))
+
+			loaded, err := inputStore.LoadProverInput(context.TODO(), 1, 10)
+			require.NoError(t, err)
+			assert.Equal(t, in.Blocks[0].Header.Hash(), loaded.Blocks[0].Header.Hash())
+		})
+	}
+}
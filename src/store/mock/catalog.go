@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kkrt-labs/zk-pig/src/store (interfaces: ProverInputCatalog)
+//
+// Generated by this command:
+//
+//	mockgen -destination=./mock/catalog.go -package=mockstore github.com/kkrt-labs/zk-pig/src/store ProverInputCatalog
+//
+
+// Package mockstore is a generated GoMock package.
+package mockstore
+
+import (
+	context "context"
+	reflect "reflect"
+
+	input "github.com/kkrt-labs/zk-pig/src/prover-input"
+	store "github.com/kkrt-labs/zk-pig/src/store"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProverInputCatalog is a mock of ProverInputCatalog interface.
+type MockProverInputCatalog struct {
+	ctrl     *gomock.Controller
+	recorder *MockProverInputCatalogMockRecorder
+	isgomock struct{}
+}
+
+// MockProverInputCatalogMockRecorder is the mock recorder for MockProverInputCatalog.
+type MockProverInputCatalogMockRecorder struct {
+	mock *MockProverInputCatalog
+}
+
+// NewMockProverInputCatalog creates a new mock instance.
+func NewMockProverInputCatalog(ctrl *gomock.Controller) *MockProverInputCatalog {
+	mock := &MockProverInputCatalog{ctrl: ctrl}
+	mock.recorder = &MockProverInputCatalogMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProverInputCatalog) EXPECT() *MockProverInputCatalogMockRecorder {
+	return m.recorder
+}
+
+// AddProverInput mocks base method.
+func (m *MockProverInputCatalog) AddProverInput(ctx context.Context, in *input.ProverInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddProverInput", ctx, in)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddProverInput indicates an expected call of AddProverInput.
+func (mr *MockProverInputCatalogMockRecorder) AddProverInput(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddProverInput", reflect.TypeOf((*MockProverInputCatalog)(nil).AddProverInput), ctx, in)
+}
+
+// ListProverInputs mocks base method.
+func (m *MockProverInputCatalog) ListProverInputs(ctx context.Context, chainID, fromBlock, toBlock uint64) ([]*store.ProverInputEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListProverInputs", ctx, chainID, fromBlock, toBlock)
+	ret0, _ := ret[0].([]*store.ProverInputEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListProverInputs indicates an expected call of ListProverInputs.
+func (mr *MockProverInputCatalogMockRecorder) ListProverInputs(ctx, chainID, fromBlock, toBlock any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProverInputs", reflect.TypeOf((*MockProverInputCatalog)(nil).ListProverInputs), ctx, chainID, fromBlock, toBlock)
+}
@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kkrt-labs/zk-pig/src/store (interfaces: GarbageCollector)
+//
+// Generated by this command:
+//
+//	mockgen -destination=./mock/gc.go -package=mockstore github.com/kkrt-labs/zk-pig/src/store GarbageCollector
+//
+
+// Package mockstore is a generated GoMock package.
+package mockstore
+
+import (
+	context "context"
+	reflect "reflect"
+
+	store "github.com/kkrt-labs/zk-pig/src/store"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockGarbageCollector is a mock of GarbageCollector interface.
+type MockGarbageCollector struct {
+	ctrl     *gomock.Controller
+	recorder *MockGarbageCollectorMockRecorder
+	isgomock struct{}
+}
+
+// MockGarbageCollectorMockRecorder is the mock recorder for MockGarbageCollector.
+type MockGarbageCollectorMockRecorder struct {
+	mock *MockGarbageCollector
+}
+
+// NewMockGarbageCollector creates a new mock instance.
+func NewMockGarbageCollector(ctrl *gomock.Controller) *MockGarbageCollector {
+	mock := &MockGarbageCollector{ctrl: ctrl}
+	mock.recorder = &MockGarbageCollectorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGarbageCollector) EXPECT() *MockGarbageCollectorMockRecorder {
+	return m.recorder
+}
+
+// Collect mocks base method.
+func (m *MockGarbageCollector) Collect(ctx context.Context, chainID uint64, dryRun bool) (*store.GCReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Collect", ctx, chainID, dryRun)
+	ret0, _ := ret[0].(*store.GCReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Collect indicates an expected call of Collect.
+func (mr *MockGarbageCollectorMockRecorder) Collect(ctx, chainID, dryRun any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Collect", reflect.TypeOf((*MockGarbageCollector)(nil).Collect), ctx, chainID, dryRun)
+}
+
+// CollectRange mocks base method.
+func (m *MockGarbageCollector) CollectRange(ctx context.Context, chainID, fromBlock, toBlock uint64, dryRun bool) (*store.GCReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CollectRange", ctx, chainID, fromBlock, toBlock, dryRun)
+	ret0, _ := ret[0].(*store.GCReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CollectRange indicates an expected call of CollectRange.
+func (mr *MockGarbageCollectorMockRecorder) CollectRange(ctx, chainID, fromBlock, toBlock, dryRun any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CollectRange", reflect.TypeOf((*MockGarbageCollector)(nil).CollectRange), ctx, chainID, fromBlock, toBlock, dryRun)
+}
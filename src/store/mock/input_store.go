@@ -13,6 +13,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	common "github.com/ethereum/go-ethereum/common"
 	input "github.com/kkrt-labs/zk-pig/src/prover-input"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -56,6 +57,36 @@ func (mr *MockProverInputStoreMockRecorder) LoadProverInput(ctx, chainID, blockN
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadProverInput", reflect.TypeOf((*MockProverInputStore)(nil).LoadProverInput), ctx, chainID, blockNumber)
 }
 
+// LoadProverInputByHash mocks base method.
+func (m *MockProverInputStore) LoadProverInputByHash(ctx context.Context, chainID, blockNumber uint64, blockHash common.Hash) (*input.ProverInput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadProverInputByHash", ctx, chainID, blockNumber, blockHash)
+	ret0, _ := ret[0].(*input.ProverInput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadProverInputByHash indicates an expected call of LoadProverInputByHash.
+func (mr *MockProverInputStoreMockRecorder) LoadProverInputByHash(ctx, chainID, blockNumber, blockHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadProverInputByHash", reflect.TypeOf((*MockProverInputStore)(nil).LoadProverInputByHash), ctx, chainID, blockNumber, blockHash)
+}
+
+// MigrateProverInput mocks base method.
+func (m *MockProverInputStore) MigrateProverInput(ctx context.Context, chainID, blockNumber uint64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MigrateProverInput", ctx, chainID, blockNumber)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MigrateProverInput indicates an expected call of MigrateProverInput.
+func (mr *MockProverInputStoreMockRecorder) MigrateProverInput(ctx, chainID, blockNumber any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MigrateProverInput", reflect.TypeOf((*MockProverInputStore)(nil).MigrateProverInput), ctx, chainID, blockNumber)
+}
+
 // StoreProverInput mocks base method.
 func (m *MockProverInputStore) StoreProverInput(ctx context.Context, inputs *input.ProverInput) error {
 	m.ctrl.T.Helper()
@@ -13,6 +13,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	common "github.com/ethereum/go-ethereum/common"
 	steps "github.com/kkrt-labs/zk-pig/src/steps"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -56,6 +57,21 @@ func (mr *MockPreflightDataStoreMockRecorder) LoadPreflightData(ctx, chainID, bl
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadPreflightData", reflect.TypeOf((*MockPreflightDataStore)(nil).LoadPreflightData), ctx, chainID, blockNumber)
 }
 
+// LoadPreflightDataByHash mocks base method.
+func (m *MockPreflightDataStore) LoadPreflightDataByHash(ctx context.Context, chainID, blockNumber uint64, blockHash common.Hash) (*steps.PreflightData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadPreflightDataByHash", ctx, chainID, blockNumber, blockHash)
+	ret0, _ := ret[0].(*steps.PreflightData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadPreflightDataByHash indicates an expected call of LoadPreflightDataByHash.
+func (mr *MockPreflightDataStoreMockRecorder) LoadPreflightDataByHash(ctx, chainID, blockNumber, blockHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadPreflightDataByHash", reflect.TypeOf((*MockPreflightDataStore)(nil).LoadPreflightDataByHash), ctx, chainID, blockNumber, blockHash)
+}
+
 // StorePreflightData mocks base method.
 func (m *MockPreflightDataStore) StorePreflightData(ctx context.Context, inputs *steps.PreflightData) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,132 @@
+package store
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	store "github.com/kkrt-labs/go-utils/store"
+	"go.uber.org/multierr"
+)
+
+type multiStore struct {
+	stores []store.Store
+}
+
+// NewMultiStore creates a store writing objects to all the given stores and loading them from the first store holding them
+//
+// Unlike go-utils multi store, which passes the same reader to every store (so only the first store reads the object),
+// objects are streamed to every store through its own pipe, so each store reads the whole object.
+func NewMultiStore(stores ...store.Store) store.Store {
+	return &multiStore{stores: stores}
+}
+
+// Store streams the object to all stores concurrently
+// A store failing does not prevent the others from storing the object, errors of all stores are combined
+func (m *multiStore) Store(ctx context.Context, key string, reader io.Reader, headers *store.Headers) error {
+	if len(m.stores) == 1 {
+		return m.stores[0].Store(ctx, key, reader, headers)
+	}
+
+	var (
+		wg      sync.WaitGroup
+		errs    = make([]error, len(m.stores))
+		writers = make([]*io.PipeWriter, len(m.stores))
+	)
+	for i, s := range m.stores {
+		pr, pw := io.Pipe()
+		writers[i] = pw
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = s.Store(ctx, key, pr, cloneHeaders(headers))
+			if errs[i] != nil {
+				// Writes to the failed store are dropped
+				pr.CloseWithError(errs[i])
+				return
+			}
+			// Drain the object left unread by the store (e.g. no-op store) so other stores are not blocked
+			_, _ = io.Copy(io.Discard, pr)
+		}()
+	}
+
+	_, copyErr := io.Copy(&fanOutWriter{writers: writers, failed: make([]bool, len(writers))}, reader)
+	for _, pw := range writers {
+		pw.CloseWithError(copyErr)
+	}
+	wg.Wait()
+
+	if copyErr != nil {
+		return copyErr
+	}
+	return multierr.Combine(errs...)
+}
+
+// Load loads the object from the first store holding it
+func (m *multiStore) Load(ctx context.Context, key string) (io.ReadCloser, *store.Headers, error) {
+	errs := make([]error, 0, len(m.stores))
+	for _, s := range m.stores {
+		reader, headers, err := s.Load(ctx, key)
+		if err == nil && reader != nil {
+			return reader, headers, nil
+		}
+		errs = append(errs, err)
+	}
+
+	if err := multierr.Combine(errs...); err != nil {
+		return nil, nil, err
+	}
+	return nil, nil, store.ErrNotFound
+}
+
+func (m *multiStore) Delete(ctx context.Context, key string) error {
+	errs := make([]error, 0, len(m.stores))
+	for _, s := range m.stores {
+		errs = append(errs, s.Delete(ctx, key))
+	}
+	return multierr.Combine(errs...)
+}
+
+func (m *multiStore) Copy(ctx context.Context, srcKey, dstKey string) error {
+	errs := make([]error, 0, len(m.stores))
+	for _, s := range m.stores {
+		errs = append(errs, s.Copy(ctx, srcKey, dstKey))
+	}
+	return multierr.Combine(errs...)
+}
+
+// fanOutWriter writes to all writers, dropping the writers failing
+// It never fails so the object is read whole whatever stores fail
+type fanOutWriter struct {
+	writers []*io.PipeWriter
+	failed  []bool
+}
+
+func (w *fanOutWriter) Write(p []byte) (int, error) {
+	for i, pw := range w.writers {
+		if w.failed[i] {
+			continue
+		}
+		if _, err := pw.Write(p); err != nil {
+			w.failed[i] = true
+		}
+	}
+	return len(p), nil
+}
+
+// cloneHeaders copies headers so stores can not alter the headers of each other
+func cloneHeaders(headers *store.Headers) *store.Headers {
+	if headers == nil {
+		return nil
+	}
+
+	h := *headers
+	if headers.KeyValue != nil {
+		h.KeyValue = make(map[string]string, len(headers.KeyValue))
+		for k, v := range headers.KeyValue {
+			h.KeyValue[k] = v
+		}
+	}
+	return &h
+}
@@ -0,0 +1,69 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	store "github.com/kkrt-labs/go-utils/store"
+	filestore "github.com/kkrt-labs/go-utils/store/file"
+	mockstore "github.com/kkrt-labs/go-utils/store/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func loadAll(t *testing.T, s store.Store, key string) []byte {
+	reader, _, err := s.Load(context.TODO(), key)
+	require.NoError(t, err)
+	defer reader.Close()
+	b, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return b
+}
+
+func TestMultiStore(t *testing.T) {
+	dir1, dir2 := t.TempDir(), t.TempDir()
+	backend1, backend2 := filestore.New(dir1), filestore.New(dir2)
+
+	s, err := NewCompressStore(NewMultiStore(backend1, backend2), ContentEncodingGzip)
+	require.NoError(t, err)
+
+	// Larger than the pipe buffers so backends read concurrently
+	data := bytes.Repeat([]byte("zkpig"), 1<<20)
+	require.NoError(t, s.Store(context.TODO(), "/1/10/zkpi.json", bytes.NewReader(data), &store.Headers{ContentType: store.ContentTypeJSON}))
+
+	// Every backend holds the whole object
+	compressed := loadAll(t, backend1, "/1/10/zkpi.json.gz")
+	assert.NotEmpty(t, compressed)
+	assert.Equal(t, compressed, loadAll(t, backend2, "/1/10/zkpi.json.gz"))
+
+	single, err := NewCompressStore(backend2, ContentEncodingGzip)
+	require.NoError(t, err)
+	assert.Equal(t, data, loadAll(t, single, "/1/10/zkpi.json"))
+	assert.Equal(t, data, loadAll(t, s, "/1/10/zkpi.json"))
+
+	require.NoError(t, s.Delete(context.TODO(), "/1/10/zkpi.json"))
+	_, _, err = backend1.Load(context.TODO(), "/1/10/zkpi.json.gz")
+	assert.Error(t, err)
+	_, _, err = backend2.Load(context.TODO(), "/1/10/zkpi.json.gz")
+	assert.Error(t, err)
+}
+
+func TestMultiStoreFailingBackend(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := t.TempDir()
+	backend := filestore.New(dir)
+	failing := mockstore.NewMockStore(ctrl)
+	failing.EXPECT().Store(gomock.Any(), "/1/10/zkpi.json", gomock.Any(), gomock.Any()).Return(fmt.Errorf("test error"))
+
+	// Failing and no-op backends do not prevent the other backends from storing the whole object
+	data := bytes.Repeat([]byte("zkpig"), 1<<16)
+	err := NewMultiStore(failing, store.NewNoOpStore(), backend).Store(context.TODO(), "/1/10/zkpi.json", bytes.NewReader(data), nil)
+	assert.ErrorContains(t, err, "test error")
+	assert.Equal(t, data, loadAll(t, backend, "/1/10/zkpi.json"))
+}
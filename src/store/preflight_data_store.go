@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	gethcommon "github.com/ethereum/go-ethereum/common"
 	store "github.com/kkrt-labs/go-utils/store"
 	"github.com/kkrt-labs/zk-pig/src/steps"
 )
@@ -19,23 +20,29 @@ type PreflightDataStore interface {
 
 	// LoadPreflightData loads preflight data inputs for a block.
 	LoadPreflightData(ctx context.Context, chainID, blockNumber uint64) (*steps.PreflightData, error)
+
+	// LoadPreflightDataByHash loads preflight data for a block identified by its hash.
+	// It returns an error wrapping store.ErrNotFound if the stored preflight data is for another block (e.g. a reorged block).
+	LoadPreflightDataByHash(ctx context.Context, chainID, blockNumber uint64, blockHash gethcommon.Hash) (*steps.PreflightData, error)
 }
 
 // NewPreflightDataStore creates a new PreflightDataStore instance
-func NewPreflightDataStore(store store.Store) (PreflightDataStore, error) {
+func NewPreflightDataStore(store store.Store, opts ...Option) (PreflightDataStore, error) {
 	return &preflightDataStore{
-		store: store,
+		store:   store,
+		options: newOptions(opts...),
 	}, nil
 }
 
 type preflightDataStore struct {
 	store store.Store
+	*options
 }
 
 func (s *preflightDataStore) StorePreflightData(ctx context.Context, data *steps.PreflightData) error {
-	chainID := data.ChainConfig.ChainID.Uint64()
-	blockNumber := data.Block.Number.ToInt().Uint64()
-	path := s.path(chainID, blockNumber)
+	timestamp := uint64(data.Block.Time)
+	params := blockKeyParams(data.ChainConfig.ChainID.Uint64(), data.Block.Number.ToInt().Uint64(), data.Block.Hash, timestamp)
+	path := s.preflightDataKey.execute(params)
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(data); err != nil {
 		return fmt.Errorf("failed to encode JSON: %w", err)
@@ -45,17 +52,46 @@ func (s *preflightDataStore) StorePreflightData(ctx context.Context, data *steps
 		ContentType:     store.ContentTypeJSON,
 		ContentEncoding: store.ContentEncodingPlain,
 		KeyValue: map[string]string{
-			"chain.id":     fmt.Sprintf("%d", chainID),
-			"block.number": fmt.Sprintf("%d", blockNumber),
+			"chain.id":     fmt.Sprintf("%d", params.chainID),
+			"block.number": fmt.Sprintf("%d", params.blockNumber),
 		},
 	}
-	return s.store.Store(ctx, path, reader, &headers)
+	if err := s.store.Store(ctx, path, reader, &headers); err != nil {
+		return err
+	}
+
+	return storeIndex(ctx, s.store, s.preflightDataKey, latestPreflightData, params, timestamp)
 }
 
 func (s *preflightDataStore) LoadPreflightData(ctx context.Context, chainID, blockNumber uint64) (*steps.PreflightData, error) {
-	path := s.path(chainID, blockNumber)
+	params, err := resolve(ctx, s.store, s.preflightDataKey, latestPreflightData, chainID, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return s.load(ctx, params)
+}
+
+func (s *preflightDataStore) LoadPreflightDataByHash(ctx context.Context, chainID, blockNumber uint64, blockHash gethcommon.Hash) (*steps.PreflightData, error) {
+	params, err := resolveByHash(ctx, s.store, s.preflightDataKey, latestPreflightData, chainID, blockNumber, blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.load(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkBlockHash(data.Block.Hash, blockHash); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (s *preflightDataStore) load(ctx context.Context, params *keyParams) (*steps.PreflightData, error) {
 	data := &steps.PreflightData{}
-	reader, _, err := s.store.Load(ctx, path)
+	reader, _, err := s.store.Load(ctx, s.preflightDataKey.execute(params))
 	if err != nil {
 		return nil, err
 	}
@@ -67,10 +103,6 @@ func (s *preflightDataStore) LoadPreflightData(ctx context.Context, chainID, blo
 	return data, nil
 }
 
-func (s *preflightDataStore) path(chainID, blockNumber uint64) string {
-	return fmt.Sprintf("/%d/%d/preflight.json", chainID, blockNumber)
-}
-
 type noOpPreflightDataStore struct{}
 
 func (s *noOpPreflightDataStore) StorePreflightData(_ context.Context, _ *steps.PreflightData) error {
@@ -81,6 +113,10 @@ func (s *noOpPreflightDataStore) LoadPreflightData(_ context.Context, _, _ uint6
 	return nil, nil
 }
 
+func (s *noOpPreflightDataStore) LoadPreflightDataByHash(_ context.Context, _, _ uint64, _ gethcommon.Hash) (*steps.PreflightData, error) {
+	return nil, nil
+}
+
 func NewNoOpPreflightDataStore() PreflightDataStore {
 	return &noOpPreflightDataStore{}
 }
@@ -0,0 +1,81 @@
+package store
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/kkrt-labs/go-utils/aws"
+	"github.com/kkrt-labs/go-utils/common"
+	store "github.com/kkrt-labs/go-utils/store"
+	s3store "github.com/kkrt-labs/go-utils/store/s3"
+)
+
+// S3Client is the S3 API used by the S3 store
+type S3Client interface {
+	aws.S3ObjectClient
+	manager.UploadAPIClient
+}
+
+type s3Store struct {
+	objects   store.Store // loads, copies and deletes objects
+	uploader  *manager.Uploader
+	bucket    string
+	keyPrefix string
+}
+
+// NewS3Store creates a store over the objects of an S3 bucket with the given key prefix
+//
+// Objects are uploaded with the S3 upload manager, so bodies streamed while being compressed or encoded
+// (which length is unknown) are sent in parts, where go-utils S3 store sends them with PutObject which
+// requires the Content-Length of the body
+func NewS3Store(client S3Client, bucket, keyPrefix string) (store.Store, error) {
+	s, err := s3store.New(client, bucket, s3store.WithKeyPrefix(keyPrefix))
+	if err != nil {
+		return nil, err
+	}
+
+	return &s3Store{
+		objects:   s,
+		uploader:  manager.NewUploader(client),
+		bucket:    bucket,
+		keyPrefix: keyPrefix,
+	}, nil
+}
+
+func (s *s3Store) Store(ctx context.Context, key string, reader io.Reader, headers *store.Headers) error {
+	input := &s3.PutObjectInput{
+		Bucket: common.Ptr(s.bucket),
+		Key:    common.Ptr(filepath.Join(s.keyPrefix, key)), // same as go-utils S3 store
+		Body:   reader,
+	}
+
+	if headers != nil {
+		if headers.ContentEncoding != store.ContentEncodingPlain {
+			input.ContentEncoding = common.Ptr(headers.ContentEncoding.String())
+		}
+		if headers.ContentType != store.ContentTypeText {
+			input.ContentType = common.Ptr(headers.ContentType.String())
+		}
+		if headers.KeyValue != nil {
+			input.Metadata = headers.KeyValue
+		}
+	}
+
+	_, err := s.uploader.Upload(ctx, input)
+	return err
+}
+
+func (s *s3Store) Load(ctx context.Context, key string) (io.ReadCloser, *store.Headers, error) {
+	return s.objects.Load(ctx, key)
+}
+
+func (s *s3Store) Copy(ctx context.Context, srcKey, dstKey string) error {
+	return s.objects.Copy(ctx, srcKey, dstKey)
+}
+
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	return s.objects.Delete(ctx, key)
+}
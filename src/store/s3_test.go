@@ -0,0 +1,159 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/kkrt-labs/go-utils/common"
+	store "github.com/kkrt-labs/go-utils/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testS3UploadClient keeps uploaded objects in memory
+type testS3UploadClient struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	inputs  map[string]*s3.PutObjectInput
+	parts   map[int32][]byte
+	unsized int // bodies without known length
+}
+
+func newTestS3UploadClient() *testS3UploadClient {
+	return &testS3UploadClient{
+		objects: make(map[string][]byte),
+		inputs:  make(map[string]*s3.PutObjectInput),
+		parts:   make(map[int32][]byte),
+	}
+}
+
+func (c *testS3UploadClient) read(body io.Reader) ([]byte, error) {
+	// S3 requires the length of the bodies, which the SDK can only compute on seekable bodies
+	if _, ok := body.(io.Seeker); !ok {
+		c.unsized++
+	}
+	return io.ReadAll(body)
+}
+
+func (c *testS3UploadClient) PutObject(_ context.Context, in *s3.PutObjectInput, _ ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b, err := c.read(in.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.objects[*in.Key], c.inputs[*in.Key] = b, in
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (c *testS3UploadClient) CreateMultipartUpload(_ context.Context, in *s3.CreateMultipartUploadInput, _ ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inputs[*in.Key] = &s3.PutObjectInput{Key: in.Key, ContentEncoding: in.ContentEncoding, ContentType: in.ContentType, Metadata: in.Metadata}
+	return &s3.CreateMultipartUploadOutput{UploadId: common.Ptr("upload")}, nil
+}
+
+func (c *testS3UploadClient) UploadPart(_ context.Context, in *s3.UploadPartInput, _ ...func(*s3.Options)) (*s3.UploadPartOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b, err := c.read(in.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.parts[*in.PartNumber] = b
+	return &s3.UploadPartOutput{ETag: common.Ptr("etag")}, nil
+}
+
+func (c *testS3UploadClient) CompleteMultipartUpload(_ context.Context, in *s3.CompleteMultipartUploadInput, _ ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	numbers := make([]int32, 0, len(c.parts))
+	for n := range c.parts {
+		numbers = append(numbers, n)
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+	var b []byte
+	for _, n := range numbers {
+		b = append(b, c.parts[n]...)
+	}
+	c.objects[*in.Key], c.parts = b, make(map[int32][]byte)
+	return &s3.CompleteMultipartUploadOutput{}, nil
+}
+
+func (c *testS3UploadClient) AbortMultipartUpload(_ context.Context, _ *s3.AbortMultipartUploadInput, _ ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error) {
+	return &s3.AbortMultipartUploadOutput{}, nil
+}
+
+func (c *testS3UploadClient) GetObject(_ context.Context, in *s3.GetObjectInput, _ ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b, ok := c.objects[*in.Key]
+	if !ok {
+		return nil, &s3types.NoSuchKey{}
+	}
+	put := c.inputs[*in.Key]
+	return &s3.GetObjectOutput{
+		Body:            io.NopCloser(bytes.NewReader(b)),
+		ContentEncoding: put.ContentEncoding,
+		ContentType:     put.ContentType,
+		Metadata:        put.Metadata,
+	}, nil
+}
+
+func (c *testS3UploadClient) DeleteObject(_ context.Context, in *s3.DeleteObjectInput, _ ...func(*s3.Options)) (*s3.DeleteObjectOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.objects, *in.Key)
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+func (c *testS3UploadClient) CopyObject(_ context.Context, _ *s3.CopyObjectInput, _ ...func(*s3.Options)) (*s3.CopyObjectOutput, error) {
+	return &s3.CopyObjectOutput{}, nil
+}
+
+func (c *testS3UploadClient) ListObjectsV2(_ context.Context, _ *s3.ListObjectsV2Input, _ ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	return &s3.ListObjectsV2Output{}, nil
+}
+
+func TestS3StoreStreamedBody(t *testing.T) {
+	for _, test := range []struct {
+		desc string
+		size int
+	}{
+		{desc: "single part", size: 1024},
+		{desc: "multipart", size: 12 * 1024 * 1024},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			client := newTestS3UploadClient()
+			s, err := NewS3Store(client, "bucket", "prefix")
+			require.NoError(t, err)
+
+			data := make([]byte, test.size)
+			_, _ = rand.Read(data)
+
+			// Bodies encoded while being written have no known length
+			pr, pw := io.Pipe()
+			go func() { _, err := pw.Write(data); pw.CloseWithError(err) }()
+			headers := &store.Headers{ContentType: store.ContentTypeJSON, ContentEncoding: store.ContentEncodingGzip, KeyValue: map[string]string{"key": "value"}}
+			require.NoError(t, s.Store(context.TODO(), "/1/10/zkpi.json.gz", pr, headers))
+
+			assert.Zero(t, client.unsized, "bodies sent to S3 must have a known length")
+			assert.Equal(t, data, client.objects["prefix/1/10/zkpi.json.gz"])
+
+			reader, loaded, err := s.Load(context.TODO(), "/1/10/zkpi.json.gz")
+			require.NoError(t, err)
+			defer reader.Close()
+			b, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, data, b)
+			assert.Equal(t, headers, loaded)
+		})
+	}
+}
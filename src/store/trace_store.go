@@ -0,0 +1,91 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	store "github.com/kkrt-labs/go-utils/store"
+	"github.com/kkrt-labs/zk-pig/src/ethereum/evm"
+)
+
+// TraceStep is the step which block execution has been traced
+//
+// Both prepare and execute run the block, on different state databases, so their traces are stored separately.
+type TraceStep string
+
+const (
+	TraceStepPrepare TraceStep = "prepare"
+	TraceStepExecute TraceStep = "execute"
+)
+
+// TraceStore is a store for execution traces of blocks.
+type TraceStore interface {
+	// StoreTrace stores the execution trace of a block recorded during the given step.
+	StoreTrace(ctx context.Context, step TraceStep, trace *evm.Trace) error
+
+	// LoadTrace loads the execution trace of a block recorded during the given step.
+	// If traces of competing blocks have been stored at the same height, the last stored one is loaded.
+	LoadTrace(ctx context.Context, step TraceStep, chainID, blockNumber uint64) (*evm.Trace, error)
+}
+
+// NewTraceStore creates a new TraceStore instance
+// Traces are stored next to the prover input of the block (see WithProverInputKey)
+func NewTraceStore(s store.Store, opts ...Option) TraceStore {
+	return &traceStore{
+		store:   s,
+		options: newOptions(opts...),
+	}
+}
+
+type traceStore struct {
+	store store.Store
+	*options
+}
+
+func (s *traceStore) StoreTrace(ctx context.Context, step TraceStep, trace *evm.Trace) error {
+	chainID := trace.ChainID.Uint64()
+	blockNumber := trace.BlockNumber.Uint64()
+	params := blockKeyParams(chainID, blockNumber, trace.BlockHash, trace.Timestamp)
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(trace); err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	headers := store.Headers{
+		ContentType:     store.ContentTypeJSON,
+		ContentEncoding: store.ContentEncodingPlain,
+		KeyValue: map[string]string{
+			"chain.id":     fmt.Sprintf("%d", chainID),
+			"block.number": fmt.Sprintf("%d", blockNumber),
+			"trace.step":   string(step),
+		},
+	}
+	key := s.traceKey(step)
+	if err := s.store.Store(ctx, key.execute(params), bytes.NewReader(buf.Bytes()), &headers); err != nil {
+		return err
+	}
+
+	return storeIndex(ctx, s.store, key, latestTrace, params, trace.Timestamp)
+}
+
+func (s *traceStore) LoadTrace(ctx context.Context, step TraceStep, chainID, blockNumber uint64) (*evm.Trace, error) {
+	key := s.traceKey(step)
+	params, err := resolve(ctx, s.store, key, latestTrace, chainID, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, _, err := s.store.Load(ctx, key.execute(params))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	trace := new(evm.Trace)
+	if err := json.NewDecoder(reader).Decode(trace); err != nil {
+		return nil, err
+	}
+	return trace, nil
+}
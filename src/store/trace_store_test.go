@@ -0,0 +1,86 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	store "github.com/kkrt-labs/go-utils/store"
+	memorystore "github.com/kkrt-labs/go-utils/store/memory"
+	"github.com/kkrt-labs/zk-pig/src/ethereum/evm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTrace(blockHash gethcommon.Hash) *evm.Trace {
+	return &evm.Trace{
+		ChainID:     big.NewInt(1),
+		BlockNumber: big.NewInt(10),
+		BlockHash:   blockHash,
+		Timestamp:   1760659200, // 2025-10-17
+		Mode:        evm.TraceModeCall,
+		Transactions: []*evm.TxTrace{
+			{TxHash: gethcommon.Hash{0x2}, Result: json.RawMessage(`{"type":"CALL"}`)},
+		},
+	}
+}
+
+func TestTraceStore(t *testing.T) {
+	ctx := context.TODO()
+	s := memorystore.New()
+	traceStore := NewTraceStore(s)
+
+	trace := testTrace(gethcommon.Hash{0x1})
+
+	// Traces of prepare and execute are stored next to the prover input, keyed by block hash
+	for _, tc := range []struct {
+		step TraceStep
+		key  string
+	}{
+		{TraceStepPrepare, "/1/10/trace.prepare." + trace.BlockHash.Hex() + ".json"},
+		{TraceStepExecute, "/1/10/trace.execute." + trace.BlockHash.Hex() + ".json"},
+	} {
+		t.Run(string(tc.step), func(t *testing.T) {
+			require.NoError(t, traceStore.StoreTrace(ctx, tc.step, trace))
+
+			_, _, err := s.Load(ctx, tc.key)
+			require.NoError(t, err)
+
+			loaded, err := traceStore.LoadTrace(ctx, tc.step, 1, 10)
+			require.NoError(t, err)
+			assert.Equal(t, trace, loaded)
+		})
+	}
+
+	// The trace of a competing block at the same height does not overwrite the previous one
+	reorged := testTrace(gethcommon.Hash{0x3})
+	require.NoError(t, traceStore.StoreTrace(ctx, TraceStepPrepare, reorged))
+
+	_, _, err := s.Load(ctx, "/1/10/trace.prepare."+trace.BlockHash.Hex()+".json")
+	require.NoError(t, err)
+
+	loaded, err := traceStore.LoadTrace(ctx, TraceStepPrepare, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, reorged, loaded)
+}
+
+func TestTraceStoreKeyTemplate(t *testing.T) {
+	ctx := context.TODO()
+	s := memorystore.New()
+	traceStore := NewTraceStore(s, WithProverInputKey(MustParseKeyTemplate("/chain={chainID}/date={date}/{number}/{hash}/zkpi.{ext}", KeyExt)))
+
+	trace := testTrace(gethcommon.Hash{0x1})
+	require.NoError(t, traceStore.StoreTrace(ctx, TraceStepExecute, trace))
+
+	_, _, err := s.Load(ctx, "/chain=1/date=2025-10-17/10/"+trace.BlockHash.Hex()+"/trace.execute.json")
+	require.NoError(t, err)
+
+	loaded, err := traceStore.LoadTrace(ctx, TraceStepExecute, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, trace, loaded)
+
+	_, err = traceStore.LoadTrace(ctx, TraceStepExecute, 1, 11)
+	assert.ErrorIs(t, err, store.ErrNotFound)
+}
@@ -0,0 +1,35 @@
+package src
+
+import (
+	"github.com/kkrt-labs/go-utils/app"
+	"github.com/kkrt-labs/go-utils/common"
+	"github.com/kkrt-labs/zk-pig/src/telemetry"
+)
+
+var (
+	tracingComponentName = "tracing"
+)
+
+// TracerProvider returns the OpenTelemetry tracer provider
+// Spans are exported to the configured OTLP endpoint (if any)
+func (a *App) TracerProvider() *telemetry.Provider {
+	return provide(
+		a,
+		tracingComponentName,
+		func() (*telemetry.Provider, error) {
+			cfg := &telemetry.Config{
+				ServiceName:    "zkpig",
+				ServiceVersion: Version,
+			}
+			if tCfg := a.Config().Tracing; tCfg != nil {
+				cfg.Endpoint = common.Val(tCfg.Endpoint)
+				cfg.Insecure = common.Val(tCfg.Insecure)
+				if tCfg.ServiceName != nil && *tCfg.ServiceName != "" {
+					cfg.ServiceName = *tCfg.ServiceName
+				}
+			}
+			return telemetry.NewProvider(cfg)
+		},
+		app.WithComponentName(tracingComponentName),
+	)
+}
@@ -0,0 +1,28 @@
+package telemetry
+
+import (
+	"context"
+
+	"github.com/kkrt-labs/go-utils/jsonrpc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// JSONRPCWithTracing is a decorator that creates a client span named after the method for every JSON-RPC call
+func JSONRPCWithTracing(client jsonrpc.Client) jsonrpc.Client {
+	return jsonrpc.ClientFunc(func(ctx context.Context, req *jsonrpc.Request, res any) error {
+		ctx, span := Tracer().Start(
+			ctx,
+			req.Method,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("rpc.system", "jsonrpc"),
+				attribute.String("rpc.method", req.Method),
+			),
+		)
+		err := client.Call(ctx, req, res)
+		End(span, err)
+
+		return err
+	})
+}
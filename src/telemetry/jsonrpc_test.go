@@ -0,0 +1,32 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kkrt-labs/go-utils/jsonrpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestJSONRPCWithTracing(t *testing.T) {
+	recorder := newTestRecorder(t)
+
+	var callCtx context.Context
+	client := JSONRPCWithTracing(jsonrpc.ClientFunc(func(ctx context.Context, _ *jsonrpc.Request, _ any) error {
+		callCtx = ctx
+		return nil
+	}))
+
+	err := client.Call(context.TODO(), &jsonrpc.Request{Method: "eth_chainId"}, nil)
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "eth_chainId", spans[0].Name())
+	assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("rpc.method", "eth_chainId"))
+	assert.Equal(t, spans[0].SpanContext().SpanID(), trace.SpanContextFromContext(callCtx).SpanID())
+}
@@ -0,0 +1,95 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Extract returns a context carrying the trace context propagated in the given headers (if any)
+// Header names are case-insensitive
+func Extract(ctx context.Context, headers map[string]string) context.Context {
+	carrier := make(propagation.MapCarrier, len(headers))
+	for k, v := range headers {
+		carrier[strings.ToLower(k)] = v
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, carrier)
+}
+
+// HTTPMiddleware is an HTTP middleware that continues the trace propagated in the request headers (if any)
+// and creates a server span for every request
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := Tracer().Start(
+			ctx,
+			r.Method+" "+r.URL.Path,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("url.path", r.URL.Path),
+			),
+		)
+		defer span.End()
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// lambdaEvent holds the fields of a Lambda event that can carry a trace context
+// Headers are set on API Gateway and ALB events, trace context fields can be set on direct invocations
+type lambdaEvent struct {
+	Headers     map[string]string `json:"headers"`
+	TraceParent string            `json:"traceparent"`
+	TraceState  string            `json:"tracestate"`
+}
+
+type lambdaHandler struct {
+	handler lambda.Handler
+}
+
+// LambdaHandler wraps a Lambda handler function (see lambda.Start) so every invocation
+// continues the trace propagated in the event (if any) in a server span
+//
+// Spans are flushed at the end of every invocation as the execution environment may be frozen afterwards.
+func LambdaHandler(handler any, opts ...lambda.Option) lambda.Handler {
+	return &lambdaHandler{handler: lambda.NewHandlerWithOptions(handler, opts...)}
+}
+
+func (h *lambdaHandler) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	var event lambdaEvent
+	if err := json.Unmarshal(payload, &event); err == nil {
+		headers := make(map[string]string, len(event.Headers)+2)
+		for k, v := range event.Headers {
+			headers[k] = v
+		}
+		if event.TraceParent != "" {
+			headers["traceparent"] = event.TraceParent
+			headers["tracestate"] = event.TraceState
+		}
+		ctx = Extract(ctx, headers)
+	}
+
+	attrs := []attribute.KeyValue{attribute.String("faas.name", lambdacontext.FunctionName)}
+	if lc, ok := lambdacontext.FromContext(ctx); ok {
+		attrs = append(attrs, attribute.String("faas.invocation_id", lc.AwsRequestID))
+	}
+
+	ctx, span := Tracer().Start(ctx, "lambda.invoke", trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attrs...))
+	res, err := h.handler.Invoke(ctx, payload)
+	End(span, err)
+
+	if tp, ok := otel.GetTracerProvider().(interface{ ForceFlush(context.Context) error }); ok {
+		_ = tp.ForceFlush(ctx)
+	}
+
+	return res, err
+}
@@ -0,0 +1,113 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	testTraceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testTraceParent = "00-" + testTraceID + "-00f067aa0ba902b7-01"
+)
+
+func setTestPropagator(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator()) })
+}
+
+func TestExtract(t *testing.T) {
+	setTestPropagator(t)
+
+	ctx := Extract(context.TODO(), map[string]string{"Traceparent": testTraceParent})
+	sc := trace.SpanContextFromContext(ctx)
+	assert.True(t, sc.IsRemote())
+	assert.Equal(t, testTraceID, sc.TraceID().String())
+
+	ctx = Extract(context.TODO(), nil)
+	assert.False(t, trace.SpanContextFromContext(ctx).IsValid())
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	setTestPropagator(t)
+	recorder := newTestRecorder(t)
+
+	var reqCtx context.Context
+	h := HTTPMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		reqCtx = r.Context()
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/generate", http.NoBody)
+	req.Header.Set("traceparent", testTraceParent)
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "POST /generate", spans[0].Name())
+	assert.Equal(t, testTraceID, spans[0].SpanContext().TraceID().String())
+	assert.Equal(t, spans[0].SpanContext().SpanID(), trace.SpanContextFromContext(reqCtx).SpanID())
+}
+
+func TestLambdaHandler(t *testing.T) {
+	setTestPropagator(t)
+
+	testCases := []struct {
+		desc    string
+		payload string
+		traced  bool
+	}{
+		{
+			desc:    "API Gateway event",
+			payload: `{"headers":{"Traceparent":"` + testTraceParent + `"},"blockNumber":1}`,
+			traced:  true,
+		},
+		{
+			desc:    "Direct invocation",
+			payload: `{"traceparent":"` + testTraceParent + `","blockNumber":1}`,
+			traced:  true,
+		},
+		{
+			desc:    "No trace context",
+			payload: `{"blockNumber":1}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			recorder := newTestRecorder(t)
+
+			var event struct {
+				BlockNumber int `json:"blockNumber"`
+			}
+			var handlerCtx context.Context
+			h := LambdaHandler(func(ctx context.Context, e struct {
+				BlockNumber int `json:"blockNumber"`
+			}) (int, error) {
+				handlerCtx = ctx
+				event = e
+				return e.BlockNumber, nil
+			})
+
+			res, err := h.Invoke(context.TODO(), []byte(tc.payload))
+			require.NoError(t, err)
+			assert.Equal(t, "1", string(res))
+			assert.Equal(t, 1, event.BlockNumber)
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+			assert.Equal(t, "lambda.invoke", spans[0].Name())
+			assert.Equal(t, spans[0].SpanContext().SpanID(), trace.SpanContextFromContext(handlerCtx).SpanID())
+			assert.Equal(t, tc.traced, spans[0].Parent().IsRemote())
+			if tc.traced {
+				assert.Equal(t, testTraceID, spans[0].SpanContext().TraceID().String())
+			}
+		})
+	}
+}
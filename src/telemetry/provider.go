@@ -0,0 +1,89 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// Config is the configuration of the tracer provider
+type Config struct {
+	Endpoint       string // OTLP HTTP endpoint to export spans to (e.g. "localhost:4318"), spans are not exported if empty
+	Insecure       bool   // Export spans over plain HTTP
+	ServiceName    string
+	ServiceVersion string
+}
+
+// Provider is a service managing the OpenTelemetry tracer provider
+//
+// On start, it registers as the global tracer provider so instrumented components export their spans
+// and registers the W3C trace context propagator so incoming context can be extracted.
+// On stop, it flushes pending spans and shuts down the exporter.
+type Provider struct {
+	tp *sdktrace.TracerProvider
+}
+
+// NewProvider creates a new tracer provider
+// If no endpoint is configured, the provider only registers the propagator and spans are dropped
+func NewProvider(cfg *Config) (*Provider, error) {
+	if cfg.Endpoint == "" {
+		return &Provider{}, nil
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+
+	exporter, err := otlptracehttp.New(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	return NewProviderFromExporter(cfg, exporter), nil
+}
+
+// NewProviderFromExporter creates a new tracer provider exporting spans in batches to the given exporter
+func NewProviderFromExporter(cfg *Config, exporter sdktrace.SpanExporter) *Provider {
+	return &Provider{
+		tp: sdktrace.NewTracerProvider(
+			sdktrace.WithBatcher(exporter),
+			sdktrace.WithResource(resource.NewWithAttributes(
+				semconv.SchemaURL,
+				semconv.ServiceName(cfg.ServiceName),
+				semconv.ServiceVersion(cfg.ServiceVersion),
+			)),
+		),
+	}
+}
+
+// Start registers the tracer provider and propagator globally
+func (p *Provider) Start(_ context.Context) error {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if p.tp != nil {
+		otel.SetTracerProvider(p.tp)
+	}
+	return nil
+}
+
+// ForceFlush exports all pending spans
+func (p *Provider) ForceFlush(ctx context.Context) error {
+	if p.tp == nil {
+		return nil
+	}
+	return p.tp.ForceFlush(ctx)
+}
+
+// Stop flushes pending spans and shuts down the exporter
+func (p *Provider) Stop(ctx context.Context) error {
+	if p.tp == nil {
+		return nil
+	}
+	return p.tp.Shutdown(ctx)
+}
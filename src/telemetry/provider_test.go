@@ -0,0 +1,45 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kkrt-labs/go-utils/app/svc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestProviderImplementsService(t *testing.T) {
+	require.Implements(t, (*svc.Runnable)(nil), new(Provider))
+}
+
+func TestProvider(t *testing.T) {
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	exporter := tracetest.NewInMemoryExporter()
+	p := NewProviderFromExporter(&Config{ServiceName: "zkpig", ServiceVersion: "test"}, exporter)
+	require.NoError(t, p.Start(context.TODO()))
+
+	_, span := Tracer().Start(context.TODO(), "test")
+	span.End()
+
+	require.NoError(t, p.ForceFlush(context.TODO()))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "test", spans[0].Name)
+	assert.Contains(t, spans[0].Resource.String(), "service.name=zkpig")
+
+	require.NoError(t, p.Stop(context.TODO()))
+}
+
+func TestProviderWithoutEndpoint(t *testing.T) {
+	p, err := NewProvider(&Config{})
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.TODO()))
+	require.NoError(t, p.ForceFlush(context.TODO()))
+	require.NoError(t, p.Stop(context.TODO()))
+}
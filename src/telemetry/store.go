@@ -0,0 +1,54 @@
+package telemetry
+
+import (
+	"context"
+	"io"
+
+	"github.com/kkrt-labs/go-utils/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type traced struct {
+	store store.Store
+}
+
+// StoreWithTracing is a decorator that creates a span for every store operation
+func StoreWithTracing(s store.Store) store.Store {
+	return &traced{store: s}
+}
+
+func (s *traced) Store(ctx context.Context, key string, reader io.Reader, headers *store.Headers) error {
+	ctx, span := Tracer().Start(ctx, "store.store", trace.WithAttributes(attribute.String("store.key", key)))
+	err := s.store.Store(ctx, key, reader, headers)
+	End(span, err)
+	return err
+}
+
+func (s *traced) Load(ctx context.Context, key string) (io.ReadCloser, *store.Headers, error) {
+	ctx, span := Tracer().Start(ctx, "store.load", trace.WithAttributes(attribute.String("store.key", key)))
+	reader, headers, err := s.store.Load(ctx, key)
+	End(span, err)
+	return reader, headers, err
+}
+
+func (s *traced) Delete(ctx context.Context, key string) error {
+	ctx, span := Tracer().Start(ctx, "store.delete", trace.WithAttributes(attribute.String("store.key", key)))
+	err := s.store.Delete(ctx, key)
+	End(span, err)
+	return err
+}
+
+func (s *traced) Copy(ctx context.Context, srcKey, dstKey string) error {
+	ctx, span := Tracer().Start(
+		ctx,
+		"store.copy",
+		trace.WithAttributes(
+			attribute.String("store.src_key", srcKey),
+			attribute.String("store.dst_key", dstKey),
+		),
+	)
+	err := s.store.Copy(ctx, srcKey, dstKey)
+	End(span, err)
+	return err
+}
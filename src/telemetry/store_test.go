@@ -0,0 +1,39 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/kkrt-labs/go-utils/store"
+	"github.com/kkrt-labs/go-utils/store/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func TestStoreWithTracing(t *testing.T) {
+	recorder := newTestRecorder(t)
+
+	s := StoreWithTracing(memory.New())
+
+	err := s.Store(context.TODO(), "key", bytes.NewReader([]byte("value")), &store.Headers{})
+	require.NoError(t, err)
+
+	r, _, err := s.Load(context.TODO(), "key")
+	require.NoError(t, err)
+	r.Close()
+
+	_, _, err = s.Load(context.TODO(), "unknown")
+	require.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	assert.Equal(t, "store.store", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("store.key", "key"))
+	assert.Equal(t, "store.load", spans[1].Name())
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+	assert.Equal(t, "store.load", spans[2].Name())
+	assert.Equal(t, codes.Error, spans[2].Status().Code)
+}
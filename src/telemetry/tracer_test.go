@@ -0,0 +1,38 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// newTestRecorder registers a global tracer provider recording spans for the duration of the test
+func newTestRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+	return recorder
+}
+
+func TestEnd(t *testing.T) {
+	recorder := newTestRecorder(t)
+
+	_, span := Tracer().Start(context.TODO(), "ok")
+	End(span, nil)
+	_, span = Tracer().Start(context.TODO(), "failed")
+	End(span, fmt.Errorf("test error"))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "test error", spans[1].Status().Description)
+	assert.Len(t, spans[1].Events(), 1) // recorded error
+}